@@ -0,0 +1,121 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// _defaultRejection is the observation of rejected actions without message.
+const _defaultRejection = "The action was rejected, do not retry it."
+
+// Decision is the decision of an ApprovalHandler on an action.
+type Decision string
+
+const (
+	// DecisionApprove runs the action, with the edited input if any.
+	DecisionApprove Decision = "approve"
+	// DecisionReject doesn't run the action, and gives the message of the
+	// rejection to the agent as the observation.
+	DecisionReject Decision = "reject"
+	// DecisionSuspend suspends the run until the action is approved or
+	// rejected, see Executor.Resume.
+	DecisionSuspend Decision = "suspend"
+)
+
+// Approval is the decision of an ApprovalHandler on an action.
+type Approval struct {
+	Decision Decision `json:"decision"`
+	// Message is the observation of a rejected action, e.g. why it was
+	// rejected.
+	Message string `json:"message,omitempty"`
+	// ToolInput, if not empty, replaces the input of an approved action.
+	ToolInput string `json:"tool_input,omitempty"`
+}
+
+// ApprovalHandler is consulted by the executor before running each action
+// of the agent, to approve it, reject it, edit its input or suspend the run
+// until a decision is taken asynchronously.
+type ApprovalHandler func(ctx context.Context, action schema.AgentAction) (Approval, error)
+
+// RequireApproval returns an ApprovalHandler consulting the handler for the
+// actions of the tools, and approving the actions of the other tools.
+func RequireApproval(handler ApprovalHandler, toolNames ...string) ApprovalHandler {
+	return func(ctx context.Context, action schema.AgentAction) (Approval, error) {
+		for _, name := range toolNames {
+			if strings.EqualFold(name, action.Tool) {
+				return handler(ctx, action)
+			}
+		}
+		return Approval{Decision: DecisionApprove}, nil
+	}
+}
+
+// Suspension is the state of a run suspended by the ApprovalHandler of an
+// executor. It can be serialized to JSON, and the run continued with
+// Executor.Resume.
+type Suspension struct {
+	// Inputs are the inputs of the run.
+	Inputs map[string]string `json:"inputs"`
+	// Steps are the intermediate steps of the run, before the plan waiting
+	// for approval.
+	Steps []schema.AgentStep `json:"steps"`
+	// Actions are the actions of the plan waiting for approval.
+	Actions []schema.AgentAction `json:"actions"`
+	// Approvals are the decisions taken on the Actions when the run was
+	// suspended, nil for the actions whose decision is pending. They are for
+	// information only: Resume consults the ApprovalHandler again on every
+	// action, so a suspension can't approve actions by itself.
+	Approvals []*Approval `json:"approvals"`
+	// Iteration is the iteration of the plan waiting for approval.
+	Iteration int `json:"iteration"`
+}
+
+// SuspendedError is the error of a run suspended by the ApprovalHandler of
+// an executor. It wraps ErrSuspended.
+type SuspendedError struct {
+	Suspension Suspension
+}
+
+func (e *SuspendedError) Error() string {
+	pending := 0
+	for _, approval := range e.Suspension.Approvals {
+		if approval == nil {
+			pending++
+		}
+	}
+	return fmt.Sprintf("%s: %d pending actions", ErrSuspended, pending)
+}
+
+func (e *SuspendedError) Unwrap() error {
+	return ErrSuspended
+}
+
+// approve returns the decisions of the approval handler on the actions, nil
+// for the pending ones, and whether the run is suspended.
+func (e *Executor) approve(ctx context.Context, actions []schema.AgentAction) ([]*Approval, bool, error) {
+	decided := make([]*Approval, len(actions))
+	suspended := false
+	for i, action := range actions {
+		if e.ApprovalHandler == nil {
+			decided[i] = &Approval{Decision: DecisionApprove}
+			continue
+		}
+
+		approval, err := e.ApprovalHandler(ctx, action)
+		if err != nil {
+			return nil, false, fmt.Errorf("approve action %s: %w", action.Tool, err)
+		}
+		switch approval.Decision {
+		case DecisionApprove, DecisionReject:
+			decided[i] = &approval
+		case DecisionSuspend:
+			suspended = true
+		default:
+			return nil, false, fmt.Errorf("approve action %s: unknown decision %q", action.Tool, approval.Decision)
+		}
+	}
+	return decided, suspended, nil
+}
@@ -1,6 +1,10 @@
 package agents
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/tmc/langchaingo/schema"
+)
 
 var (
 	// ErrExecutorInputNotString is returned if an input to the executor call function is not a string.
@@ -14,6 +18,11 @@ var (
 	ErrUnknownAgentType = errors.New("unknown agent type")
 	// ErrInvalidOptions is returned if the options given to the initializer is invalid.
 	ErrInvalidOptions = errors.New("invalid options")
+	// ErrSuspended is returned, wrapped in a *SuspendedError, if a run is suspended by the approval
+	// handler of the executor.
+	ErrSuspended = errors.New("agent run suspended for approval")
+	// ErrInvalidSuspension is returned if the suspension given to resume a run is invalid.
+	ErrInvalidSuspension = errors.New("invalid suspension")
 
 	// ErrUnableToParseOutput is returned if the output of the llm is unparsable.
 	ErrUnableToParseOutput = errors.New("unable to parse agent output")
@@ -38,3 +47,28 @@ func NewParserErrorHandler(formatFunc func(string) string) *ParserErrorHandler {
 		Formatter: formatFunc,
 	}
 }
+
+// ToolErrorHandler is the struct used to handle errors returned by tools in the executor. If an
+// executor have a ToolErrorHandler, tool errors will be formatted using the formatter function and
+// added as the observation of the action, so that the agent can recover from them, for example by
+// calling the tool again with a different input. Errors caused by the cancellation of the context
+// are never handled.
+type ToolErrorHandler struct {
+	// The formatter function can be used to format the tool error. If nil the error message will be
+	// given as an observation directly.
+	Formatter func(action schema.AgentAction, err error) string
+}
+
+// NewToolErrorHandler creates a new tool error handler.
+func NewToolErrorHandler(formatFunc func(schema.AgentAction, error) string) *ToolErrorHandler {
+	return &ToolErrorHandler{
+		Formatter: formatFunc,
+	}
+}
+
+func (h *ToolErrorHandler) format(action schema.AgentAction, err error) string {
+	if h.Formatter == nil {
+		return err.Error()
+	}
+	return h.Formatter(action, err)
+}
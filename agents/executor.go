@@ -2,9 +2,11 @@ package agents
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/tmc/langchaingo/callbacks"
 	"github.com/tmc/langchaingo/chains"
@@ -20,9 +22,20 @@ type Executor struct {
 	Memory           schema.Memory
 	CallbacksHandler callbacks.Handler
 	ErrorHandler     *ParserErrorHandler
+	// ToolErrorHandler, if set, turns the errors returned by tools into
+	// observations instead of aborting the run.
+	ToolErrorHandler *ToolErrorHandler
 
 	MaxIterations           int
 	ReturnIntermediateSteps bool
+	// MaxConcurrency is the maximum number of actions from a single plan that
+	// are executed at the same time. Actions are executed one after the other
+	// if it is lower than 2.
+	MaxConcurrency int
+	// ApprovalHandler, if set, is consulted before running the actions of
+	// each plan. The actions of a plan only run once they are all approved
+	// or rejected.
+	ApprovalHandler ApprovalHandler
 }
 
 var (
@@ -44,20 +57,81 @@ func NewExecutor(agent Agent, opts ...Option) *Executor {
 		ReturnIntermediateSteps: options.returnIntermediateSteps,
 		CallbacksHandler:        options.callbacksHandler,
 		ErrorHandler:            options.errorHandler,
+		ToolErrorHandler:        options.toolErrorHandler,
+		MaxConcurrency:          options.maxConcurrency,
+		ApprovalHandler:         options.approvalHandler,
 	}
 }
 
+// Call runs the agent until it finishes. If the ApprovalHandler suspends the
+// run, a *SuspendedError is returned, whose Suspension resumes the run with
+// Resume.
 func (e *Executor) Call(ctx context.Context, inputValues map[string]any, _ ...chains.ChainCallOption) (map[string]any, error) { //nolint:lll
 	inputs, err := inputsToString(inputValues)
 	if err != nil {
 		return nil, err
 	}
+	return e.run(ctx, inputs, make([]schema.AgentStep, 0), 0, nil)
+}
+
+// Resume continues a run suspended by the ApprovalHandler, possibly in
+// another process, once its pending actions are decided: the ApprovalHandler
+// is consulted again on every action of the suspended plan, the approved
+// actions are run, and the agent until it finishes. As with chains.Call, the
+// callbacks are notified of the run and the outputs are saved to the memory
+// of the executor.
+func (e *Executor) Resume(ctx context.Context, suspension Suspension) (map[string]any, error) {
+	if len(suspension.Actions) == 0 {
+		return nil, fmt.Errorf("%w: no actions", ErrInvalidSuspension)
+	}
+	ctx = callbacks.StartRun(ctx, callbacks.RunChain, fmt.Sprintf("%T", e))
+
+	inputValues := make(map[string]any, len(suspension.Inputs))
+	for key, value := range suspension.Inputs {
+		inputValues[key] = value
+	}
+	if e.CallbacksHandler != nil {
+		e.CallbacksHandler.HandleChainStart(ctx, inputValues)
+	}
+
+	outputs, err := e.run(ctx, suspension.Inputs, suspension.Steps, suspension.Iteration, &suspension)
+	if err != nil {
+		if e.CallbacksHandler != nil {
+			e.CallbacksHandler.HandleChainError(ctx, err)
+		}
+		return outputs, err
+	}
+
+	if e.CallbacksHandler != nil {
+		e.CallbacksHandler.HandleChainEnd(ctx, outputs)
+	}
+	if e.Memory != nil {
+		if err := e.Memory.SaveContext(ctx, inputValues, outputs); err != nil {
+			return outputs, err
+		}
+	}
+	return outputs, nil
+}
+
+// run runs the agent from the iteration, starting with the actions of the
+// suspension if any.
+func (e *Executor) run(
+	ctx context.Context,
+	inputs map[string]string,
+	steps []schema.AgentStep,
+	iteration int,
+	suspension *Suspension,
+) (map[string]any, error) {
 	nameToTool := getNameToTool(e.Agent.GetTools())
 
-	steps := make([]schema.AgentStep, 0)
-	for i := 0; i < e.MaxIterations; i++ {
+	var err error
+	for i := iteration; i < e.MaxIterations; i++ {
 		var finish map[string]any
-		steps, finish, err = e.doIteration(ctx, steps, nameToTool, inputs)
+		if suspension != nil && i == iteration {
+			steps, err = e.doApprovedActions(ctx, steps, nameToTool, inputs, i, suspension.Actions)
+		} else {
+			steps, finish, err = e.doIteration(ctx, steps, nameToTool, inputs, i)
+		}
 		if finish != nil || err != nil {
 			return finish, err
 		}
@@ -79,6 +153,7 @@ func (e *Executor) doIteration( // nolint
 	steps []schema.AgentStep,
 	nameToTool map[string]tools.Tool,
 	inputs map[string]string,
+	iteration int,
 ) ([]schema.AgentStep, map[string]any, error) {
 	actions, finish, err := e.Agent.Plan(ctx, steps, inputs)
 	if errors.Is(err, ErrUnableToParseOutput) && e.ErrorHandler != nil {
@@ -106,43 +181,185 @@ func (e *Executor) doIteration( // nolint
 		return steps, e.getReturn(finish, steps), nil
 	}
 
-	for _, action := range actions {
-		steps, err = e.doAction(ctx, steps, nameToTool, action)
-		if err != nil {
-			return steps, nil, err
+	steps, err = e.doApprovedActions(ctx, steps, nameToTool, inputs, iteration, actions)
+	return steps, nil, err
+}
+
+// doApprovedActions executes the actions of a plan once approved, and
+// returns the steps with the steps of the actions. Rejected actions are observed as rejected, and the run is
+// suspended if a decision is pending.
+func (e *Executor) doApprovedActions(
+	ctx context.Context,
+	steps []schema.AgentStep,
+	nameToTool map[string]tools.Tool,
+	inputs map[string]string,
+	iteration int,
+	actions []schema.AgentAction,
+) ([]schema.AgentStep, error) {
+	approvals, suspended, err := e.approve(ctx, actions)
+	if err != nil {
+		return steps, err
+	}
+	if suspended {
+		return steps, &SuspendedError{Suspension: Suspension{
+			Inputs:    inputs,
+			Steps:     steps,
+			Actions:   actions,
+			Approvals: approvals,
+			Iteration: iteration,
+		}}
+	}
+
+	actionSteps := make([]schema.AgentStep, len(actions))
+	approved := make([]schema.AgentAction, 0, len(actions))
+	approvedIndexes := make([]int, 0, len(actions))
+	for i, action := range actions {
+		if approvals[i].Decision == DecisionReject {
+			observation := approvals[i].Message
+			if observation == "" {
+				observation = _defaultRejection
+			}
+			actionSteps[i] = schema.AgentStep{Action: action, Observation: observation}
+			continue
 		}
+		if approvals[i].ToolInput != "" {
+			action.ToolInput = approvals[i].ToolInput
+		}
+		approved = append(approved, action)
+		approvedIndexes = append(approvedIndexes, i)
 	}
 
-	return steps, nil, nil
+	approvedSteps, err := e.doActions(ctx, nameToTool, approved)
+	if err != nil {
+		return steps, err
+	}
+	for j, i := range approvedIndexes {
+		actionSteps[i] = approvedSteps[j]
+	}
+	return append(steps, actionSteps...), nil
+}
+
+// doActions executes the actions of a plan, concurrently if MaxConcurrency
+// allows it, and returns their steps in the order of the actions.
+func (e *Executor) doActions(
+	ctx context.Context,
+	nameToTool map[string]tools.Tool,
+	actions []schema.AgentAction,
+) ([]schema.AgentStep, error) {
+	steps := make([]schema.AgentStep, len(actions))
+	if e.MaxConcurrency < 2 || len(actions) < 2 {
+		for i, action := range actions {
+			step, err := e.doAction(ctx, nameToTool, action)
+			if err != nil {
+				return nil, err
+			}
+			steps[i] = step
+		}
+		return steps, nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make([]error, len(actions))
+	sem := make(chan struct{}, e.MaxConcurrency)
+	var wg sync.WaitGroup
+	for i, action := range actions {
+		// no action is started once an action failed, or the run is canceled.
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			steps[i], errs[i] = e.doAction(ctx, nameToTool, action)
+			if errs[i] != nil {
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// report the error that caused the cancellation of the other actions.
+	for _, err := range errs {
+		if err != nil && !errors.Is(err, context.Canceled) {
+			return nil, err
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return steps, nil
 }
 
 func (e *Executor) doAction(
 	ctx context.Context,
-	steps []schema.AgentStep,
 	nameToTool map[string]tools.Tool,
 	action schema.AgentAction,
-) ([]schema.AgentStep, error) {
+) (schema.AgentStep, error) {
 	if e.CallbacksHandler != nil {
 		e.CallbacksHandler.HandleAgentAction(ctx, action)
 	}
 
 	tool, ok := nameToTool[strings.ToUpper(action.Tool)]
 	if !ok {
-		return append(steps, schema.AgentStep{
+		return schema.AgentStep{
 			Action:      action,
 			Observation: fmt.Sprintf("%s is not a valid tool, try another one", action.Tool),
-		}), nil
+		}, nil
 	}
 
-	observation, err := tool.Call(ctx, action.ToolInput)
+	structured, isStructured := tool.(tools.StructuredTool)
+	if isStructured {
+		// let the agent fix arguments that don't match the schema of the tool.
+		if err := tools.ValidateArguments(structured, json.RawMessage(action.ToolInput)); err != nil {
+			return schema.AgentStep{
+				Action:      action,
+				Observation: err.Error(),
+			}, nil
+		}
+	}
+
+	// The tool runs in its own run, the parent of the runs it starts.
+	ctx = callbacks.StartRun(ctx, callbacks.RunTool, tool.Name())
+	if e.CallbacksHandler != nil {
+		e.CallbacksHandler.HandleToolStart(ctx, action.ToolInput)
+	}
+	var (
+		observation string
+		err         error
+	)
+	if isStructured {
+		observation, err = structured.CallStructured(ctx, json.RawMessage(action.ToolInput))
+	} else {
+		observation, err = tool.Call(ctx, action.ToolInput)
+	}
 	if err != nil {
-		return nil, err
+		if e.CallbacksHandler != nil {
+			e.CallbacksHandler.HandleToolError(ctx, err)
+		}
+		if e.ToolErrorHandler == nil || ctx.Err() != nil {
+			return schema.AgentStep{}, err
+		}
+		observation = e.ToolErrorHandler.format(action, err)
+	} else if e.CallbacksHandler != nil {
+		e.CallbacksHandler.HandleToolEnd(ctx, observation)
 	}
 
-	return append(steps, schema.AgentStep{
+	return schema.AgentStep{
 		Action:      action,
 		Observation: observation,
-	}), nil
+	}, nil
 }
 
 func (e *Executor) getReturn(finish *schema.AgentFinish, steps []schema.AgentStep) map[string]any {
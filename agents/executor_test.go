@@ -2,13 +2,20 @@ package agents_test
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/callbacks"
 	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/llms/openai"
 	"github.com/tmc/langchaingo/prompts"
 	"github.com/tmc/langchaingo/schema"
@@ -73,6 +80,364 @@ func TestExecutorWithErrorHandler(t *testing.T) {
 	}, a.recordedIntermediateSteps)
 }
 
+// oneShotAgent plans the given actions, then finishes once it has seen their
+// observations.
+type oneShotAgent struct {
+	actions []schema.AgentAction
+	tools   []tools.Tool
+
+	recordedIntermediateSteps []schema.AgentStep
+}
+
+func (a *oneShotAgent) Plan(
+	_ context.Context,
+	intermediateSteps []schema.AgentStep,
+	_ map[string]string,
+) ([]schema.AgentAction, *schema.AgentFinish, error) {
+	if len(intermediateSteps) == 0 {
+		return a.actions, nil, nil
+	}
+	a.recordedIntermediateSteps = intermediateSteps
+	return nil, &schema.AgentFinish{ReturnValues: map[string]any{"output": "done"}}, nil
+}
+
+func (a *oneShotAgent) GetInputKeys() []string  { return []string{"input"} }
+func (a *oneShotAgent) GetOutputKeys() []string { return []string{"output"} }
+func (a *oneShotAgent) GetTools() []tools.Tool  { return a.tools }
+
+// slowTool echoes its input after a delay and records the maximum number of
+// concurrent calls.
+type slowTool struct {
+	running, maxRunning atomic.Int32
+}
+
+func (t *slowTool) Name() string        { return "slow" }
+func (t *slowTool) Description() string { return "echoes its input slowly" }
+
+func (t *slowTool) Call(ctx context.Context, input string) (string, error) {
+	n := t.running.Add(1)
+	defer t.running.Add(-1)
+	for {
+		m := t.maxRunning.Load()
+		if n <= m || t.maxRunning.CompareAndSwap(m, n) {
+			break
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case <-time.After(20 * time.Millisecond):
+	}
+	if input == "fail" {
+		return "", errors.New("tool failed")
+	}
+	return "echo " + input, nil
+}
+
+// blockingTool records its concurrent calls, which block until released,
+// except the calls with the input "fail", which fail immediately.
+type blockingTool struct {
+	started chan string
+	release chan struct{}
+
+	running, maxRunning atomic.Int32
+}
+
+func newBlockingTool(calls int) *blockingTool {
+	return &blockingTool{started: make(chan string, calls), release: make(chan struct{})}
+}
+
+func (t *blockingTool) Name() string        { return "blocking" }
+func (t *blockingTool) Description() string { return "echoes its input once released" }
+
+func (t *blockingTool) Call(ctx context.Context, input string) (string, error) {
+	n := t.running.Add(1)
+	defer t.running.Add(-1)
+	for {
+		m := t.maxRunning.Load()
+		if n <= m || t.maxRunning.CompareAndSwap(m, n) {
+			break
+		}
+	}
+
+	t.started <- input
+	if input == "fail" {
+		return "", errors.New("tool failed")
+	}
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case <-t.release:
+	}
+	return "echo " + input, nil
+}
+
+type toolCallbacks struct {
+	callbacks.SimpleHandler
+
+	mu     sync.Mutex
+	events []string
+}
+
+func (h *toolCallbacks) record(event string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.events = append(h.events, event)
+}
+
+func (h *toolCallbacks) HandleToolStart(_ context.Context, input string) { h.record("start " + input) }
+func (h *toolCallbacks) HandleToolEnd(_ context.Context, output string)  { h.record("end " + output) }
+func (h *toolCallbacks) HandleToolError(_ context.Context, err error) {
+	h.record("error " + err.Error())
+}
+
+func TestExecutorParallelTools(t *testing.T) {
+	t.Parallel()
+
+	actions := make([]schema.AgentAction, 6)
+	for i := range actions {
+		actions[i] = schema.AgentAction{Tool: "blocking", ToolInput: string(rune('a' + i))}
+	}
+	tool := newBlockingTool(len(actions))
+	a := &oneShotAgent{actions: actions, tools: []tools.Tool{tool}}
+	handler := &toolCallbacks{}
+
+	executor := agents.NewExecutor(a,
+		agents.WithMaxConcurrency(3),
+		agents.WithCallbacksHandler(handler),
+	)
+	var result string
+	var err error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		result, err = chains.Run(context.Background(), executor, "go")
+	}()
+
+	// The first actions run concurrently, up to the limit, until released.
+	for range 3 {
+		<-tool.started
+	}
+	require.Equal(t, int32(3), tool.running.Load())
+	close(tool.release)
+	<-done
+
+	require.NoError(t, err)
+	require.Equal(t, "done", result)
+	require.Equal(t, int32(3), tool.maxRunning.Load())
+	require.Len(t, a.recordedIntermediateSteps, len(actions))
+	for i, step := range a.recordedIntermediateSteps {
+		require.Equal(t, actions[i], step.Action)
+		require.Equal(t, "echo "+actions[i].ToolInput, step.Observation)
+	}
+	require.Len(t, handler.events, 2*len(actions))
+	require.Contains(t, handler.events, "start a")
+	require.Contains(t, handler.events, "end echo f")
+}
+
+func TestExecutorParallelToolsFailure(t *testing.T) {
+	t.Parallel()
+
+	actions := []schema.AgentAction{
+		{Tool: "blocking", ToolInput: "a"},
+		{Tool: "blocking", ToolInput: "fail"},
+		{Tool: "blocking", ToolInput: "c"},
+		{Tool: "blocking", ToolInput: "d"},
+	}
+	tool := newBlockingTool(len(actions))
+	executor := agents.NewExecutor(&oneShotAgent{actions: actions, tools: []tools.Tool{tool}},
+		agents.WithMaxConcurrency(2))
+	_, err := chains.Run(context.Background(), executor, "go")
+	require.EqualError(t, err, "tool failed")
+
+	// The failure cancels the running action, and no other action starts.
+	close(tool.started)
+	var started []string
+	for input := range tool.started {
+		started = append(started, input)
+	}
+	require.ElementsMatch(t, []string{"a", "fail"}, started)
+}
+
+func TestExecutorToolErrors(t *testing.T) {
+	t.Parallel()
+
+	actions := []schema.AgentAction{
+		{Tool: "slow", ToolInput: "fail"},
+		{Tool: "slow", ToolInput: "ok"},
+	}
+
+	// Without a tool error handler the run is aborted.
+	executor := agents.NewExecutor(&oneShotAgent{actions: actions, tools: []tools.Tool{&slowTool{}}},
+		agents.WithMaxConcurrency(2))
+	_, err := chains.Run(context.Background(), executor, "go")
+	require.EqualError(t, err, "tool failed")
+
+	a := &oneShotAgent{actions: actions, tools: []tools.Tool{&slowTool{}}}
+	handler := &toolCallbacks{}
+	executor = agents.NewExecutor(a,
+		agents.WithCallbacksHandler(handler),
+		agents.WithToolErrorHandler(agents.NewToolErrorHandler(func(action schema.AgentAction, err error) string {
+			return action.Tool + " error: " + err.Error()
+		})),
+	)
+	result, err := chains.Run(context.Background(), executor, "go")
+	require.NoError(t, err)
+	require.Equal(t, "done", result)
+	require.Equal(t, []schema.AgentStep{
+		{Action: actions[0], Observation: "slow error: tool failed"},
+		{Action: actions[1], Observation: "echo ok"},
+	}, a.recordedIntermediateSteps)
+	require.Equal(t, []string{"start fail", "error tool failed", "start ok", "end echo ok"}, handler.events)
+}
+
+func TestExecutorApproval(t *testing.T) {
+	t.Parallel()
+
+	actions := []schema.AgentAction{
+		{Tool: "slow", ToolInput: "a"},
+		{Tool: "slow", ToolInput: "b"},
+		{Tool: "slow", ToolInput: "c"},
+		{Tool: "other", ToolInput: "d"},
+	}
+	a := &oneShotAgent{actions: actions, tools: []tools.Tool{&slowTool{}}}
+	var consulted []string
+	executor := agents.NewExecutor(a, agents.WithApprovalHandler(agents.RequireApproval(
+		func(_ context.Context, action schema.AgentAction) (agents.Approval, error) {
+			consulted = append(consulted, action.ToolInput)
+			switch action.ToolInput {
+			case "b":
+				return agents.Approval{Decision: agents.DecisionReject, Message: "not b"}, nil
+			case "c":
+				return agents.Approval{Decision: agents.DecisionApprove, ToolInput: "z"}, nil
+			}
+			return agents.Approval{Decision: agents.DecisionApprove}, nil
+		}, "SLOW")))
+
+	result, err := chains.Run(context.Background(), executor, "go")
+	require.NoError(t, err)
+	require.Equal(t, "done", result)
+	require.Equal(t, []string{"a", "b", "c"}, consulted)
+	require.Equal(t, []schema.AgentStep{
+		{Action: actions[0], Observation: "echo a"},
+		{Action: actions[1], Observation: "not b"},
+		{Action: schema.AgentAction{Tool: "slow", ToolInput: "z"}, Observation: "echo z"},
+		{Action: actions[3], Observation: "other is not a valid tool, try another one"},
+	}, a.recordedIntermediateSteps)
+}
+
+func TestExecutorSuspendAndResume(t *testing.T) {
+	t.Parallel()
+
+	actions := []schema.AgentAction{
+		{Tool: "slow", ToolInput: "a", ToolID: "call_1"},
+		{Tool: "slow", ToolInput: "b", ToolID: "call_2"},
+	}
+	// decisions are taken asynchronously, e.g. stored by a review UI.
+	var mu sync.Mutex
+	decisions := map[string]agents.Approval{}
+	handler := func(_ context.Context, action schema.AgentAction) (agents.Approval, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if approval, ok := decisions[action.ToolID]; ok {
+			return approval, nil
+		}
+		return agents.Approval{Decision: agents.DecisionSuspend}, nil
+	}
+	newExecutor := func(a *oneShotAgent) *agents.Executor {
+		return agents.NewExecutor(a, agents.WithApprovalHandler(handler))
+	}
+
+	tool := &slowTool{}
+	a := &oneShotAgent{actions: actions, tools: []tools.Tool{tool}}
+	decisions["call_1"] = agents.Approval{Decision: agents.DecisionApprove}
+	_, err := chains.Run(context.Background(), newExecutor(a), "go")
+	require.ErrorIs(t, err, agents.ErrSuspended)
+	var suspended *agents.SuspendedError
+	require.ErrorAs(t, err, &suspended)
+	require.Equal(t, actions, suspended.Suspension.Actions)
+	require.Equal(t, map[string]string{"input": "go"}, suspended.Suspension.Inputs)
+	require.Equal(t, int32(0), tool.maxRunning.Load(), "no action runs before all are decided")
+
+	// The suspension is serialized while waiting for the decisions.
+	data, err := json.Marshal(suspended.Suspension)
+	require.NoError(t, err)
+	var suspension agents.Suspension
+	require.NoError(t, json.Unmarshal(data, &suspension))
+
+	// Still pending.
+	a = &oneShotAgent{actions: actions, tools: []tools.Tool{tool}}
+	_, err = newExecutor(a).Resume(context.Background(), suspension)
+	require.ErrorIs(t, err, agents.ErrSuspended)
+
+	mu.Lock()
+	decisions["call_2"] = agents.Approval{Decision: agents.DecisionReject}
+	mu.Unlock()
+	result, err := newExecutor(a).Resume(context.Background(), suspension)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"output": "done"}, result)
+	require.Equal(t, []schema.AgentStep{
+		{Action: actions[0], Observation: "echo a"},
+		{Action: actions[1], Observation: "The action was rejected, do not retry it."},
+	}, a.recordedIntermediateSteps)
+
+	// The decisions of the suspension don't bypass the approval handler.
+	mu.Lock()
+	delete(decisions, "call_2")
+	mu.Unlock()
+	suspension.Approvals = []*agents.Approval{
+		{Decision: agents.DecisionApprove},
+		{Decision: agents.DecisionApprove, ToolInput: "c"},
+	}
+	a = &oneShotAgent{actions: actions, tools: []tools.Tool{tool}}
+	_, err = newExecutor(a).Resume(context.Background(), suspension)
+	require.ErrorIs(t, err, agents.ErrSuspended)
+	require.Nil(t, a.recordedIntermediateSteps)
+
+	suspension.Actions = nil
+	_, err = newExecutor(a).Resume(context.Background(), suspension)
+	require.ErrorIs(t, err, agents.ErrInvalidSuspension)
+}
+
+type chainCallbacks struct {
+	callbacks.SimpleHandler
+
+	events []string
+}
+
+func (h *chainCallbacks) HandleChainStart(context.Context, map[string]any) {
+	h.events = append(h.events, "start")
+}
+
+func (h *chainCallbacks) HandleChainEnd(context.Context, map[string]any) {
+	h.events = append(h.events, "end")
+}
+
+func (h *chainCallbacks) HandleChainError(context.Context, error) {
+	h.events = append(h.events, "error")
+}
+
+func TestExecutorResumeWithoutMemory(t *testing.T) {
+	t.Parallel()
+
+	actions := []schema.AgentAction{{Tool: "slow", ToolInput: "a"}}
+	handler := &chainCallbacks{}
+	executor := &agents.Executor{
+		Agent:            &oneShotAgent{actions: actions, tools: []tools.Tool{&slowTool{}}},
+		CallbacksHandler: handler,
+		MaxIterations:    3,
+	}
+
+	result, err := executor.Resume(context.Background(), agents.Suspension{
+		Inputs:  map[string]string{"input": "go"},
+		Actions: actions,
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"output": "done"}, result)
+	require.Equal(t, []string{"start", "end"}, handler.events)
+}
+
 func TestExecutorWithMRKLAgent(t *testing.T) {
 	t.Parallel()
 
@@ -141,3 +506,78 @@ func TestExecutorWithOpenAIFunctionAgent(t *testing.T) {
 	require.True(t, strings.Contains(result, "47") || strings.Contains(result, "49"),
 		"correct answer 47 or 49 not in response")
 }
+
+type weatherArgs struct {
+	City string `json:"city" description:"The city to get the weather for"`
+	Unit string `json:"unit,omitempty" enum:"celsius,fahrenheit"`
+}
+
+// functionCallingLLM returns the given function calls one after the other,
+// then a final answer, and records the functions it was given.
+type functionCallingLLM struct {
+	calls     []string
+	functions [][]llms.FunctionDefinition
+	messages  [][]llms.MessageContent
+}
+
+func (l *functionCallingLLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, l, prompt, options...)
+}
+
+func (l *functionCallingLLM) GenerateContent(
+	_ context.Context,
+	messages []llms.MessageContent,
+	options ...llms.CallOption,
+) (*llms.ContentResponse, error) {
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	l.functions = append(l.functions, opts.Functions)
+	l.messages = append(l.messages, messages)
+
+	if len(l.calls) == 0 {
+		return &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: "sunny"}}}, nil
+	}
+	call := &llms.FunctionCall{Name: "weather", Arguments: l.calls[0]}
+	l.calls = l.calls[1:]
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{
+		FuncCall:  call,
+		ToolCalls: []llms.ToolCall{{ID: "call_1", Type: "function", FunctionCall: call}},
+	}}}, nil
+}
+
+func TestExecutorWithStructuredTool(t *testing.T) {
+	t.Parallel()
+
+	var got []weatherArgs
+	weather := tools.NewStructured("weather", "Get the weather",
+		func(_ context.Context, args weatherArgs) (string, error) {
+			got = append(got, args)
+			return "sunny in " + args.City, nil
+		})
+
+	llm := &functionCallingLLM{calls: []string{
+		`{"unit":"kelvin"}`,
+		`{"city":"Paris","unit":"celsius"}`,
+	}}
+	a := agents.NewOpenAIFunctionsAgent(llm, []tools.Tool{weather})
+	executor := agents.NewExecutor(a, agents.WithReturnIntermediateSteps())
+
+	result, err := chains.Call(context.Background(), executor, map[string]any{"input": "weather in Paris?"})
+	require.NoError(t, err)
+	require.Equal(t, "sunny", result["output"])
+
+	require.Equal(t, []weatherArgs{{City: "Paris", Unit: "celsius"}}, got)
+	steps, ok := result["intermediateSteps"].([]schema.AgentStep)
+	require.True(t, ok)
+	require.Len(t, steps, 2)
+	require.ErrorContains(t, tools.ValidateArguments(weather, []byte(steps[0].Action.ToolInput)), `"city"`)
+	require.Contains(t, steps[0].Observation, tools.ErrInvalidArguments.Error())
+	require.Contains(t, steps[0].Observation, `missing required property "city"`)
+	require.Contains(t, steps[0].Observation, "/unit: value kelvin is not one of celsius, fahrenheit")
+	require.Equal(t, "sunny in Paris", steps[1].Observation)
+
+	require.Len(t, llm.functions, 3)
+	require.Equal(t, weather.Schema(), llm.functions[0][0].Parameters)
+}
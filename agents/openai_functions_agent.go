@@ -12,10 +12,16 @@ import (
 	"github.com/tmc/langchaingo/tools"
 )
 
-// agentScratchpad "agent_scratchpad" for the agent to put its thoughts in.
-const agentScratchpad = "agent_scratchpad"
+const (
+	// agentScratchpad "agent_scratchpad" for the agent to put its thoughts in.
+	agentScratchpad = "agent_scratchpad"
+	// _toolArgument is the argument of the tools without schema.
+	_toolArgument = "__arg1"
+)
 
 // OpenAIFunctionsAgent is an Agent driven by OpenAIs function powered API.
+// ToolCallingAgent works with the tool calling of all the providers and runs
+// the parallel tool calls of the models.
 type OpenAIFunctionsAgent struct {
 	// LLM is the llm used to call with the values. The llm should have an
 	// input called "agent_scratchpad" for the agent to put its thoughts in.
@@ -49,21 +55,47 @@ func NewOpenAIFunctionsAgent(llm llms.Model, tools []tools.Tool, opts ...Option)
 }
 
 func (o *OpenAIFunctionsAgent) functions() []llms.FunctionDefinition {
-	res := make([]llms.FunctionDefinition, 0)
+	res := make([]llms.FunctionDefinition, 0, len(o.Tools))
 	for _, tool := range o.Tools {
-		res = append(res, llms.FunctionDefinition{
+		res = append(res, functionDefinition(tool))
+	}
+	return res
+}
+
+// functionDefinition returns the definition of the tool for the model. The tools
+// without schema take their input in the single string argument "__arg1".
+func functionDefinition(tool tools.Tool) llms.FunctionDefinition {
+	if structured, ok := tool.(tools.StructuredTool); ok {
+		return llms.FunctionDefinition{
 			Name:        tool.Name(),
 			Description: tool.Description(),
-			Parameters: map[string]any{
-				"properties": map[string]any{
-					"__arg1": map[string]string{"title": "__arg1", "type": "string"},
-				},
-				"required": []string{"__arg1"},
-				"type":     "object",
+			Parameters:  structured.Schema(),
+		}
+	}
+	return llms.FunctionDefinition{
+		Name:        tool.Name(),
+		Description: tool.Description(),
+		Parameters: map[string]any{
+			"properties": map[string]any{
+				_toolArgument: map[string]string{"title": _toolArgument, "type": "string"},
 			},
-		})
+			"required": []string{_toolArgument},
+			"type":     "object",
+		},
 	}
-	return res
+}
+
+// parseToolInput returns the input of a tool from the JSON arguments of its call,
+// the "__arg1" argument if the tool has no schema.
+func parseToolInput(arguments string) (string, error) {
+	args := make(map[string]any)
+	if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+		return "", err
+	}
+	if arg, ok := args[_toolArgument].(string); ok {
+		return arg, nil
+	}
+	return arguments, nil
 }
 
 // Plan decides what action to take or returns the final result of the input.
@@ -208,20 +240,11 @@ func (o *OpenAIFunctionsAgent) ParseOutput(contentResp *llms.ContentResponse) (
 	functionCall := choice.FuncCall
 	functionName := functionCall.Name
 	toolInputStr := functionCall.Arguments
-	toolInputMap := make(map[string]any, 0)
-	err := json.Unmarshal([]byte(toolInputStr), &toolInputMap)
+	toolInput, err := parseToolInput(toolInputStr)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	toolInput := toolInputStr
-	if arg1, ok := toolInputMap["__arg1"]; ok {
-		toolInputCheck, ok := arg1.(string)
-		if ok {
-			toolInput = toolInputCheck
-		}
-	}
-
 	contentMsg := "\n"
 	if choice.Content != "" {
 		contentMsg = fmt.Sprintf("responded: %s\n", choice.Content)
@@ -13,6 +13,9 @@ type Options struct {
 	memory                  schema.Memory
 	callbacksHandler        callbacks.Handler
 	errorHandler            *ParserErrorHandler
+	toolErrorHandler        *ToolErrorHandler
+	approvalHandler         ApprovalHandler
+	maxConcurrency          int
 	maxIterations           int
 	returnIntermediateSteps bool
 	outputKey               string
@@ -20,7 +23,7 @@ type Options struct {
 	formatInstructions      string
 	promptSuffix            string
 
-	// openai
+	// tool calling and openai functions agents
 	systemMessage string
 	extraMessages []prompts.MessageFormatter
 }
@@ -62,6 +65,13 @@ func openAIFunctionsDefaultOptions() Options {
 	}
 }
 
+func toolCallingDefaultOptions() Options {
+	return Options{
+		systemMessage: "You are a helpful AI assistant.",
+		outputKey:     _defaultOutputKey,
+	}
+}
+
 func (co Options) getMrklPrompt(tools []tools.Tool) prompts.PromptTemplate {
 	if co.prompt.Template != "" {
 		return co.prompt
@@ -161,20 +171,57 @@ func WithParserErrorHandler(errorHandler *ParserErrorHandler) Option {
 	}
 }
 
-type OpenAIOption struct{}
+// WithToolErrorHandler is an option for setting a tool error handler to an executor. Errors
+// returned by tools are then given to the agent as observations instead of stopping the executor.
+func WithToolErrorHandler(errorHandler *ToolErrorHandler) Option {
+	return func(co *Options) {
+		co.toolErrorHandler = errorHandler
+	}
+}
 
-func NewOpenAIOption() OpenAIOption {
-	return OpenAIOption{}
+// WithMaxConcurrency is an option for executing up to n of the actions returned by a single agent
+// plan at the same time. The callbacks handler of the executor must then be safe for concurrent
+// use.
+func WithMaxConcurrency(n int) Option {
+	return func(co *Options) {
+		co.maxConcurrency = n
+	}
 }
 
-func (o OpenAIOption) WithSystemMessage(msg string) Option {
+// WithApprovalHandler is an option for consulting a handler before the executor runs each action
+// of the agent, to approve, reject or edit it, or to suspend the run until an asynchronous approval,
+// see Executor.Resume.
+func WithApprovalHandler(handler ApprovalHandler) Option {
+	return func(co *Options) {
+		co.approvalHandler = handler
+	}
+}
+
+// WithSystemMessage is an option for setting the system message of the tool calling agent.
+func WithSystemMessage(msg string) Option {
 	return func(co *Options) {
 		co.systemMessage = msg
 	}
 }
 
-func (o OpenAIOption) WithExtraMessages(extraMessages []prompts.MessageFormatter) Option {
+// WithExtraMessages is an option for adding messages to the prompt of the tool calling agent,
+// between the system message and the input.
+func WithExtraMessages(extraMessages []prompts.MessageFormatter) Option {
 	return func(co *Options) {
 		co.extraMessages = extraMessages
 	}
 }
+
+type OpenAIOption struct{}
+
+func NewOpenAIOption() OpenAIOption {
+	return OpenAIOption{}
+}
+
+func (o OpenAIOption) WithSystemMessage(msg string) Option {
+	return WithSystemMessage(msg)
+}
+
+func (o OpenAIOption) WithExtraMessages(extraMessages []prompts.MessageFormatter) Option {
+	return WithExtraMessages(extraMessages)
+}
@@ -0,0 +1,248 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+// ToolCallingAgent is an Agent driven by the native tool calling of the models, which works with
+// all the providers supporting llms.WithTools, e.g. openai, anthropic, googleai, mistral, bedrock
+// and ollama. All the tool calls of a response are returned as actions, and their results are
+// given back to the model as tool call responses with the IDs of the calls.
+type ToolCallingAgent struct {
+	// LLM is the model deciding which tools to call.
+	LLM llms.Model
+	// Prompt is the prompt of the model. It must have a messages placeholder called
+	// "agent_scratchpad" for the tool calls and their results.
+	Prompt prompts.FormatPrompter
+	// Tools is a list of the tools the agent can use.
+	Tools []tools.Tool
+	// Output key is the key where the final output is placed.
+	OutputKey string
+	// CallbacksHandler is the handler for callbacks.
+	CallbacksHandler callbacks.Handler
+}
+
+var _ Agent = (*ToolCallingAgent)(nil)
+
+// NewToolCallingAgent creates a new ToolCallingAgent. The prompt is made of the system message, the
+// extra messages and the input, see WithSystemMessage and WithExtraMessages.
+func NewToolCallingAgent(llm llms.Model, tools []tools.Tool, opts ...Option) *ToolCallingAgent {
+	options := toolCallingDefaultOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return &ToolCallingAgent{
+		LLM:              llm,
+		Prompt:           createOpenAIFunctionPrompt(options),
+		Tools:            tools,
+		OutputKey:        options.outputKey,
+		CallbacksHandler: options.callbacksHandler,
+	}
+}
+
+// Plan decides what actions to take or returns the final result of the input.
+func (a *ToolCallingAgent) Plan(
+	ctx context.Context,
+	intermediateSteps []schema.AgentStep,
+	inputs map[string]string,
+) ([]schema.AgentAction, *schema.AgentFinish, error) {
+	fullInputs := make(map[string]any, len(inputs)+1)
+	for key, value := range inputs {
+		fullInputs[key] = value
+	}
+	fullInputs[agentScratchpad] = a.constructScratchPad(intermediateSteps)
+
+	prompt, err := a.Prompt.FormatPrompt(fullInputs)
+	if err != nil {
+		return nil, nil, err
+	}
+	chatMessages := prompt.Messages()
+	messages := make([]llms.MessageContent, 0, len(chatMessages))
+	for _, msg := range chatMessages {
+		messages = append(messages, messageContent(msg))
+	}
+
+	options := []llms.CallOption{llms.WithTools(a.tools())}
+	if a.CallbacksHandler != nil {
+		options = append(options, llms.WithStreamingFunc(func(ctx context.Context, chunk []byte) error {
+			a.CallbacksHandler.HandleStreamingFunc(ctx, chunk)
+			return nil
+		}))
+	}
+	resp, err := a.LLM.GenerateContent(ctx, messages, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return a.parseOutput(resp)
+}
+
+func (a *ToolCallingAgent) GetInputKeys() []string {
+	chainInputs := a.Prompt.GetInputVariables()
+
+	// Remove inputs given in plan.
+	agentInput := make([]string, 0, len(chainInputs))
+	for _, v := range chainInputs {
+		if v == agentScratchpad {
+			continue
+		}
+		agentInput = append(agentInput, v)
+	}
+
+	return agentInput
+}
+
+func (a *ToolCallingAgent) GetOutputKeys() []string {
+	return []string{a.OutputKey}
+}
+
+func (a *ToolCallingAgent) GetTools() []tools.Tool {
+	return a.Tools
+}
+
+func (a *ToolCallingAgent) tools() []llms.Tool {
+	res := make([]llms.Tool, 0, len(a.Tools))
+	for _, tool := range a.Tools {
+		definition := functionDefinition(tool)
+		res = append(res, llms.Tool{Type: "function", Function: &definition})
+	}
+	return res
+}
+
+// parseOutput returns an action for each tool call of the response, or the finish if the model
+// called no tool.
+func (a *ToolCallingAgent) parseOutput(resp *llms.ContentResponse) (
+	[]schema.AgentAction, *schema.AgentFinish, error,
+) {
+	if len(resp.Choices) == 0 {
+		return nil, nil, fmt.Errorf("%w: no choices", ErrUnableToParseOutput)
+	}
+	choice := resp.Choices[0]
+
+	toolCalls := choice.ToolCalls
+	if len(toolCalls) == 0 && choice.FuncCall != nil {
+		toolCalls = []llms.ToolCall{{Type: "function", FunctionCall: choice.FuncCall}}
+	}
+	if len(toolCalls) == 0 {
+		return nil, &schema.AgentFinish{
+			ReturnValues: map[string]any{a.OutputKey: choice.Content},
+			Log:          choice.Content,
+		}, nil
+	}
+
+	// The actions of a response share their log, which is how the scratchpad groups them.
+	var log strings.Builder
+	if choice.Content != "" {
+		fmt.Fprintf(&log, "responded: %s\n", choice.Content)
+	}
+	for _, call := range toolCalls {
+		if call.FunctionCall == nil {
+			return nil, nil, fmt.Errorf("%w: tool call without function", ErrUnableToParseOutput)
+		}
+		fmt.Fprintf(&log, "Invoking: %s with %s (%s)\n", call.FunctionCall.Name, call.FunctionCall.Arguments, call.ID)
+	}
+
+	actions := make([]schema.AgentAction, 0, len(toolCalls))
+	for _, call := range toolCalls {
+		arguments := call.FunctionCall.Arguments
+		if arguments == "" {
+			arguments = "{}"
+		}
+		input, err := parseToolInput(arguments)
+		if err != nil {
+			// The tool is given the invalid arguments, and can tell the model what is wrong.
+			input = arguments
+		}
+		actions = append(actions, schema.AgentAction{
+			Tool:      call.FunctionCall.Name,
+			ToolInput: input,
+			Log:       log.String(),
+			ToolID:    call.ID,
+		})
+	}
+	return actions, nil, nil
+}
+
+// constructScratchPad returns the messages of the tool calls of the steps, the calls of a response
+// in an AI message followed by a tool message with the result of each call.
+func (a *ToolCallingAgent) constructScratchPad(steps []schema.AgentStep) []llms.ChatMessage {
+	messages := make([]llms.ChatMessage, 0, len(steps)+1)
+	for i := 0; i < len(steps); {
+		end := i + 1
+		for end < len(steps) && steps[end].Action.Log == steps[i].Action.Log {
+			end++
+		}
+
+		toolCalls := make([]llms.ToolCall, 0, end-i)
+		for _, step := range steps[i:end] {
+			toolCalls = append(toolCalls, llms.ToolCall{
+				ID:   step.Action.ToolID,
+				Type: "function",
+				FunctionCall: &llms.FunctionCall{
+					Name:      step.Action.Tool,
+					Arguments: a.toolArguments(step.Action),
+				},
+			})
+		}
+		messages = append(messages, llms.AIChatMessage{ToolCalls: toolCalls})
+		for _, step := range steps[i:end] {
+			messages = append(messages, llms.ToolChatMessage{
+				ID:      step.Action.ToolID,
+				Name:    step.Action.Tool,
+				Content: step.Observation,
+			})
+		}
+		i = end
+	}
+	return messages
+}
+
+// toolArguments returns the JSON arguments of the call of the action, whose input may have been
+// edited before it ran.
+func (a *ToolCallingAgent) toolArguments(action schema.AgentAction) string {
+	for _, tool := range a.Tools {
+		if _, ok := tool.(tools.StructuredTool); ok && strings.EqualFold(tool.Name(), action.Tool) &&
+			json.Valid([]byte(action.ToolInput)) {
+			return action.ToolInput
+		}
+	}
+	arguments, _ := json.Marshal(map[string]string{_toolArgument: action.ToolInput})
+	return string(arguments)
+}
+
+// messageContent converts a message of the prompt, keeping the tool calls of the AI messages and
+// the IDs of the tool messages.
+func messageContent(msg llms.ChatMessage) llms.MessageContent {
+	switch m := msg.(type) {
+	case llms.AIChatMessage:
+		parts := make([]llms.ContentPart, 0, len(m.ToolCalls)+1)
+		if m.Content != "" {
+			parts = append(parts, llms.TextContent{Text: m.Content})
+		}
+		for _, toolCall := range m.ToolCalls {
+			parts = append(parts, toolCall)
+		}
+		return llms.MessageContent{Role: llms.ChatMessageTypeAI, Parts: parts}
+	case llms.ToolChatMessage:
+		return llms.MessageContent{
+			Role: llms.ChatMessageTypeTool,
+			Parts: []llms.ContentPart{llms.ToolCallResponse{
+				ToolCallID: m.ID,
+				Name:       m.Name,
+				Content:    m.Content,
+			}},
+		}
+	default:
+		return llms.TextParts(msg.GetType(), msg.GetContent())
+	}
+}
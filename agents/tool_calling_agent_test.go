@@ -0,0 +1,123 @@
+package agents_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/fake"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+func weatherCall(id, arguments string) llms.ToolCall {
+	return llms.ToolCall{
+		ID:           id,
+		Type:         "function",
+		FunctionCall: &llms.FunctionCall{Name: "weather", Arguments: arguments},
+	}
+}
+
+func TestToolCallingAgent(t *testing.T) {
+	t.Parallel()
+
+	weather := tools.NewStructured("weather", "Get the weather",
+		func(_ context.Context, args weatherArgs) (string, error) {
+			return "sunny in " + args.City, nil
+		})
+	llm := fake.NewScriptedLLM(
+		fake.Response{
+			Content: "Checking both.",
+			ToolCalls: []llms.ToolCall{
+				weatherCall("call_paris", `{"city":"Paris"}`),
+				weatherCall("call_rome", `{"city":"Rome"}`),
+				{ID: "call_slow", Type: "function", FunctionCall: &llms.FunctionCall{Name: "slow", Arguments: `{"__arg1":"x"}`}},
+			},
+		},
+		fake.Response{ToolCalls: []llms.ToolCall{weatherCall("call_oslo", `{"city":"Oslo"}`)}},
+		fake.Response{Content: "Sunny everywhere."},
+	)
+	a := agents.NewToolCallingAgent(llm, []tools.Tool{weather, &slowTool{}},
+		agents.WithSystemMessage("You know the weather."))
+	executor := agents.NewExecutor(a, agents.WithReturnIntermediateSteps(), agents.WithMaxConcurrency(3))
+
+	result, err := chains.Call(context.Background(), executor, map[string]any{"input": "Weather in Paris and Rome?"})
+	require.NoError(t, err)
+	require.Equal(t, "Sunny everywhere.", result["output"])
+
+	steps, ok := result["intermediateSteps"].([]schema.AgentStep)
+	require.True(t, ok)
+	require.Len(t, steps, 4)
+	var ids, observations []string
+	for _, step := range steps {
+		ids = append(ids, step.Action.ToolID)
+		observations = append(observations, step.Observation)
+	}
+	require.Equal(t, []string{"call_paris", "call_rome", "call_slow", "call_oslo"}, ids)
+	require.Equal(t, []string{"sunny in Paris", "sunny in Rome", "echo x", "sunny in Oslo"}, observations)
+	require.Equal(t, `{"city":"Paris"}`, steps[0].Action.ToolInput)
+	require.Equal(t, "x", steps[2].Action.ToolInput)
+
+	calls := llm.Calls()
+	require.Len(t, calls, 3)
+	require.Len(t, calls[0].Options.Tools, 2)
+	require.Equal(t, weather.Schema(), calls[0].Options.Tools[0].Function.Parameters)
+	require.Empty(t, calls[0].Options.Functions)
+
+	// The parallel calls are in a single AI message, followed by their results.
+	require.Equal(t, []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeSystem, "You know the weather."),
+		llms.TextParts(llms.ChatMessageTypeHuman, "Weather in Paris and Rome?"),
+		{Role: llms.ChatMessageTypeAI, Parts: []llms.ContentPart{
+			weatherCall("call_paris", `{"city":"Paris"}`),
+			weatherCall("call_rome", `{"city":"Rome"}`),
+			llms.ToolCall{ID: "call_slow", Type: "function", FunctionCall: &llms.FunctionCall{Name: "slow", Arguments: `{"__arg1":"x"}`}},
+		}},
+		{Role: llms.ChatMessageTypeTool, Parts: []llms.ContentPart{
+			llms.ToolCallResponse{ToolCallID: "call_paris", Name: "weather", Content: "sunny in Paris"},
+		}},
+		{Role: llms.ChatMessageTypeTool, Parts: []llms.ContentPart{
+			llms.ToolCallResponse{ToolCallID: "call_rome", Name: "weather", Content: "sunny in Rome"},
+		}},
+		{Role: llms.ChatMessageTypeTool, Parts: []llms.ContentPart{
+			llms.ToolCallResponse{ToolCallID: "call_slow", Name: "slow", Content: "echo x"},
+		}},
+		{Role: llms.ChatMessageTypeAI, Parts: []llms.ContentPart{weatherCall("call_oslo", `{"city":"Oslo"}`)}},
+		{Role: llms.ChatMessageTypeTool, Parts: []llms.ContentPart{
+			llms.ToolCallResponse{ToolCallID: "call_oslo", Name: "weather", Content: "sunny in Oslo"},
+		}},
+	}, calls[2].Messages)
+}
+
+func TestToolCallingAgentEditedInput(t *testing.T) {
+	t.Parallel()
+
+	llm := fake.NewScriptedLLM(
+		fake.Response{ToolCalls: []llms.ToolCall{
+			{ID: "call_1", Type: "function", FunctionCall: &llms.FunctionCall{Name: "slow", Arguments: `{"__arg1":"x"}`}},
+		}},
+		fake.Response{Content: "done"},
+	)
+	a := agents.NewToolCallingAgent(llm, []tools.Tool{&slowTool{}})
+	executor := agents.NewExecutor(a, agents.WithApprovalHandler(
+		func(context.Context, schema.AgentAction) (agents.Approval, error) {
+			return agents.Approval{Decision: agents.DecisionApprove, ToolInput: `y "z"`}, nil
+		}))
+
+	result, err := chains.Run(context.Background(), executor, "go")
+	require.NoError(t, err)
+	require.Equal(t, "done", result)
+
+	// The call in the scratchpad is the one that ran.
+	messages := llm.Calls()[1].Messages
+	require.Equal(t, llms.ToolCall{
+		ID:           "call_1",
+		Type:         "function",
+		FunctionCall: &llms.FunctionCall{Name: "slow", Arguments: `{"__arg1":"y \"z\""}`},
+	}, messages[len(messages)-2].Parts[0])
+	require.Equal(t, llms.ToolCallResponse{ToolCallID: "call_1", Name: "slow", Content: `echo y "z"`},
+		messages[len(messages)-1].Parts[0])
+}
@@ -30,6 +30,13 @@ type Handler interface {
 	HandleStreamingFunc(ctx context.Context, chunk []byte)
 }
 
+// RouteHandler is an optional interface of the handlers, notified of each
+// backend tried by a router model, see llms/router. It is separate from
+// Handler so that the existing Handler implementations keep compiling.
+type RouteHandler interface {
+	HandleLLMRoute(ctx context.Context, backend string, err error)
+}
+
 // HandlerHaver is an interface used to get callbacks handler.
 type HandlerHaver interface {
 	GetCallbackHandler() Handler
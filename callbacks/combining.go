@@ -12,7 +12,10 @@ type CombiningHandler struct {
 	Callbacks []Handler
 }
 
-var _ Handler = CombiningHandler{}
+var (
+	_ Handler      = CombiningHandler{}
+	_ RouteHandler = CombiningHandler{}
+)
 
 func (l CombiningHandler) HandleText(ctx context.Context, text string) {
 	for _, handle := range l.Callbacks {
@@ -38,6 +41,15 @@ func (l CombiningHandler) HandleLLMGenerateContentEnd(ctx context.Context, res *
 	}
 }
 
+// HandleLLMRoute forwards the route to the handlers implementing RouteHandler.
+func (l CombiningHandler) HandleLLMRoute(ctx context.Context, backend string, err error) {
+	for _, handle := range l.Callbacks {
+		if handle, ok := handle.(RouteHandler); ok {
+			handle.HandleLLMRoute(ctx, backend, err)
+		}
+	}
+}
+
 func (l CombiningHandler) HandleChainStart(ctx context.Context, inputs map[string]any) {
 	for _, handle := range l.Callbacks {
 		handle.HandleChainStart(ctx, inputs)
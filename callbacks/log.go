@@ -13,7 +13,10 @@ import (
 // LogHandler is a callback handler that prints to the standard output.
 type LogHandler struct{}
 
-var _ Handler = LogHandler{}
+var (
+	_ Handler      = LogHandler{}
+	_ RouteHandler = LogHandler{}
+)
 
 func (l LogHandler) HandleLLMGenerateContentStart(_ context.Context, ms []llms.MessageContent) {
 	fmt.Println("Entering LLM with messages:")
@@ -67,6 +70,14 @@ func (l LogHandler) HandleLLMError(_ context.Context, err error) {
 	fmt.Println("Exiting LLM with error:", err)
 }
 
+func (l LogHandler) HandleLLMRoute(_ context.Context, backend string, err error) {
+	if err != nil {
+		fmt.Println("LLM backend failed:", backend, err)
+		return
+	}
+	fmt.Println("LLM call served by backend:", backend)
+}
+
 func (l LogHandler) HandleChainStart(_ context.Context, inputs map[string]any) {
 	fmt.Println("Entering chain with inputs:", formatChainValues(inputs))
 }
@@ -0,0 +1,297 @@
+// Package opentelemetry provides a `callbacks.Handler` tracing chains, tools
+// and models with OpenTelemetry, following the semantic conventions of
+// generative AI systems.
+//
+// The spans of a run are children of the span of its parent run, see
+// callbacks.StartRun, or of the span of the context of the root run. The
+// same handler is meant to be set on the chains, the agent executor and the
+// models:
+//
+//	handler, err := opentelemetry.NewHandler(opentelemetry.WithSystem("openai"))
+//	llm, err := openai.New(openai.WithCallback(handler))
+//	executor := agents.NewExecutor(agent, agents.WithCallbacksHandler(handler))
+package opentelemetry
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/tmc/langchaingo/callbacks/opentelemetry"
+
+// Attributes of the semantic conventions of generative AI systems, and of
+// the runs.
+const (
+	attrOperationName     = attribute.Key("gen_ai.operation.name")
+	attrSystem            = attribute.Key("gen_ai.system")
+	attrRequestModel      = attribute.Key("gen_ai.request.model")
+	attrResponseFinish    = attribute.Key("gen_ai.response.finish_reasons")
+	attrUsageInputTokens  = attribute.Key("gen_ai.usage.input_tokens")
+	attrUsageOutputTokens = attribute.Key("gen_ai.usage.output_tokens")
+	attrTokenType         = attribute.Key("gen_ai.token.type")
+	attrToolName          = attribute.Key("gen_ai.tool.name")
+	attrToolCallID        = attribute.Key("gen_ai.tool.call.id")
+	attrErrorType         = attribute.Key("error.type")
+	attrRunID             = attribute.Key("langchaingo.run.id")
+	attrRunParentID       = attribute.Key("langchaingo.run.parent_id")
+	attrRunType           = attribute.Key("langchaingo.run.type")
+)
+
+const (
+	operationChat        = "chat"
+	operationExecuteTool = "execute_tool"
+	operationChain       = "chain"
+
+	tokenTypeInput  = "input"
+	tokenTypeOutput = "output"
+	errorTypeLLM    = "llm_error"
+
+	metricOperationDuration = "gen_ai.client.operation.duration"
+	metricTokenUsage        = "gen_ai.client.token.usage"
+)
+
+// Handler is a `callbacks.Handler` emitting a span for each run of a chain,
+// a tool or a model, and recording the latency and the token usage of the
+// models. Callbacks called outside of runs are ignored.
+type Handler struct {
+	callbacks.SimpleHandler
+
+	tracer   trace.Tracer
+	duration metric.Float64Histogram
+	tokens   metric.Int64Histogram
+	system   string
+
+	mu   sync.Mutex
+	runs map[string]*runSpan
+}
+
+var _ callbacks.Handler = (*Handler)(nil)
+
+// runSpan is the span of a run.
+type runSpan struct {
+	span  trace.Span
+	start time.Time
+	// attrs are the attributes of the metrics of the run.
+	attrs []attribute.KeyValue
+}
+
+// NewHandler creates a new OpenTelemetry handler.
+func NewHandler(opts ...Option) (*Handler, error) {
+	o := options{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	meter := o.meterProvider.Meter(instrumentationName)
+	duration, err := meter.Float64Histogram(metricOperationDuration,
+		metric.WithDescription("GenAI operation duration."),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, fmt.Errorf("create duration histogram: %w", err)
+	}
+	tokens, err := meter.Int64Histogram(metricTokenUsage,
+		metric.WithDescription("Measures number of input and output tokens used."),
+		metric.WithUnit("{token}"))
+	if err != nil {
+		return nil, fmt.Errorf("create token usage histogram: %w", err)
+	}
+
+	return &Handler{
+		tracer:   o.tracerProvider.Tracer(instrumentationName),
+		duration: duration,
+		tokens:   tokens,
+		system:   o.system,
+		runs:     map[string]*runSpan{},
+	}, nil
+}
+
+// start starts the span of the run of ctx. describe returns the name of the
+// span and its attributes, which are also the attributes of its metrics.
+func (h *Handler) start(
+	ctx context.Context,
+	kind trace.SpanKind,
+	describe func(run callbacks.Run) (string, []attribute.KeyValue),
+) {
+	run, ok := callbacks.RunFromContext(ctx)
+	if !ok {
+		return
+	}
+	name, attrs := describe(run)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.runs[run.ID]; ok {
+		return
+	}
+	if parent, ok := h.runs[run.ParentID]; ok {
+		ctx = trace.ContextWithSpan(ctx, parent.span)
+	}
+	spanAttrs := append([]attribute.KeyValue{
+		attrRunID.String(run.ID),
+		attrRunType.String(string(run.Type)),
+	}, attrs...)
+	if run.ParentID != "" {
+		spanAttrs = append(spanAttrs, attrRunParentID.String(run.ParentID))
+	}
+	_, span := h.tracer.Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(spanAttrs...))
+	h.runs[run.ID] = &runSpan{span: span, start: time.Now(), attrs: slices.Clip(attrs)}
+}
+
+// end removes the span of the run of ctx, which the caller must end.
+func (h *Handler) end(ctx context.Context) (*runSpan, bool) {
+	run, ok := callbacks.RunFromContext(ctx)
+	if !ok {
+		return nil, false
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.runs[run.ID]
+	delete(h.runs, run.ID)
+	return s, ok
+}
+
+// endWithError ends the span of the run of ctx with an error.
+func (h *Handler) endWithError(ctx context.Context, err error) {
+	s, ok := h.end(ctx)
+	if !ok {
+		return
+	}
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+	s.span.End()
+}
+
+// HandleChainStart starts the span of a chain.
+func (h *Handler) HandleChainStart(ctx context.Context, _ map[string]any) {
+	h.start(ctx, trace.SpanKindInternal, func(run callbacks.Run) (string, []attribute.KeyValue) {
+		return operationChain + " " + run.Name, nil
+	})
+}
+
+// HandleChainEnd ends the span of a chain.
+func (h *Handler) HandleChainEnd(ctx context.Context, _ map[string]any) {
+	if s, ok := h.end(ctx); ok {
+		s.span.End()
+	}
+}
+
+// HandleChainError ends the span of a chain with its error.
+func (h *Handler) HandleChainError(ctx context.Context, err error) {
+	h.endWithError(ctx, err)
+}
+
+// HandleAgentAction adds an event of the action to the span of the agent.
+func (h *Handler) HandleAgentAction(ctx context.Context, action schema.AgentAction) {
+	run, ok := callbacks.RunFromContext(ctx)
+	if !ok {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if s, ok := h.runs[run.ID]; ok {
+		s.span.AddEvent("agent_action", trace.WithAttributes(
+			attrToolName.String(action.Tool),
+			attrToolCallID.String(action.ToolID),
+		))
+	}
+}
+
+// HandleToolStart starts the span of a tool.
+func (h *Handler) HandleToolStart(ctx context.Context, _ string) {
+	h.start(ctx, trace.SpanKindInternal, func(run callbacks.Run) (string, []attribute.KeyValue) {
+		return operationExecuteTool + " " + run.Name, []attribute.KeyValue{
+			attrOperationName.String(operationExecuteTool),
+			attrToolName.String(run.Name),
+		}
+	})
+}
+
+// HandleToolEnd ends the span of a tool.
+func (h *Handler) HandleToolEnd(ctx context.Context, _ string) {
+	if s, ok := h.end(ctx); ok {
+		s.span.End()
+	}
+}
+
+// HandleToolError ends the span of a tool with its error.
+func (h *Handler) HandleToolError(ctx context.Context, err error) {
+	h.endWithError(ctx, err)
+}
+
+// HandleLLMGenerateContentStart starts the span of a model.
+func (h *Handler) HandleLLMGenerateContentStart(ctx context.Context, _ []llms.MessageContent) {
+	h.start(ctx, trace.SpanKindClient, func(run callbacks.Run) (string, []attribute.KeyValue) {
+		attrs := []attribute.KeyValue{
+			attrOperationName.String(operationChat),
+			attrRequestModel.String(run.Name),
+		}
+		if h.system != "" {
+			attrs = append(attrs, attrSystem.String(h.system))
+		}
+		return operationChat + " " + run.Name, attrs
+	})
+}
+
+// HandleLLMGenerateContentEnd ends the span of a model and records its
+// latency and token usage.
+func (h *Handler) HandleLLMGenerateContentEnd(ctx context.Context, res *llms.ContentResponse) {
+	s, ok := h.end(ctx)
+	if !ok {
+		return
+	}
+	defer s.span.End()
+	h.duration.Record(ctx, time.Since(s.start).Seconds(), metric.WithAttributes(s.attrs...))
+	if res == nil {
+		return
+	}
+
+	var finishReasons []string
+	for _, choice := range res.Choices {
+		if choice != nil && choice.StopReason != "" {
+			finishReasons = append(finishReasons, choice.StopReason)
+		}
+	}
+	if len(finishReasons) > 0 {
+		s.span.SetAttributes(attrResponseFinish.StringSlice(finishReasons))
+	}
+	if res.Usage != nil {
+		s.span.SetAttributes(
+			attrUsageInputTokens.Int(res.Usage.PromptTokens),
+			attrUsageOutputTokens.Int(res.Usage.CompletionTokens),
+		)
+		h.tokens.Record(ctx, int64(res.Usage.PromptTokens),
+			metric.WithAttributes(append(s.attrs, attrTokenType.String(tokenTypeInput))...))
+		h.tokens.Record(ctx, int64(res.Usage.CompletionTokens),
+			metric.WithAttributes(append(s.attrs, attrTokenType.String(tokenTypeOutput))...))
+	}
+}
+
+// HandleLLMError ends the span of a model with its error and records its
+// latency.
+func (h *Handler) HandleLLMError(ctx context.Context, err error) {
+	s, ok := h.end(ctx)
+	if !ok {
+		return
+	}
+	h.duration.Record(ctx, time.Since(s.start).Seconds(),
+		metric.WithAttributes(append(s.attrs, attrErrorType.String(errorTypeLLM))...))
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+	s.span.End()
+}
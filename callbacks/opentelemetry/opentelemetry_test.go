@@ -0,0 +1,230 @@
+package opentelemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/anthropic"
+	"github.com/tmc/langchaingo/llms/openai"
+	"github.com/tmc/langchaingo/schema"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// measurement is a value recorded by a histogram of the testMeter.
+type measurement struct {
+	name  string
+	value float64
+	attrs attribute.Set
+}
+
+type testMeterProvider struct {
+	noop.MeterProvider
+	meter *testMeter
+}
+
+func (p testMeterProvider) Meter(string, ...metric.MeterOption) metric.Meter {
+	return p.meter
+}
+
+type testMeter struct {
+	noop.Meter
+	mu           sync.Mutex
+	measurements []measurement
+}
+
+func (m *testMeter) record(name string, value float64, opts []metric.RecordOption) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.measurements = append(m.measurements, measurement{
+		name:  name,
+		value: value,
+		attrs: metric.NewRecordConfig(opts).Attributes(),
+	})
+}
+
+func (m *testMeter) Float64Histogram(name string, _ ...metric.Float64HistogramOption) (metric.Float64Histogram, error) { //nolint:lll
+	return float64Histogram{meter: m, name: name}, nil
+}
+
+func (m *testMeter) Int64Histogram(name string, _ ...metric.Int64HistogramOption) (metric.Int64Histogram, error) {
+	return int64Histogram{meter: m, name: name}, nil
+}
+
+type float64Histogram struct {
+	noop.Float64Histogram
+	meter *testMeter
+	name  string
+}
+
+func (h float64Histogram) Record(_ context.Context, value float64, opts ...metric.RecordOption) {
+	h.meter.record(h.name, value, opts)
+}
+
+type int64Histogram struct {
+	noop.Int64Histogram
+	meter *testMeter
+	name  string
+}
+
+func (h int64Histogram) Record(_ context.Context, value int64, opts ...metric.RecordOption) {
+	h.meter.record(h.name, float64(value), opts)
+}
+
+func newTestHandler(t *testing.T) (*Handler, *tracetest.SpanRecorder, *testMeter) {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	meter := &testMeter{}
+	handler, err := NewHandler(
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))),
+		WithMeterProvider(testMeterProvider{meter: meter}),
+		WithSystem("openai"),
+	)
+	require.NoError(t, err)
+	return handler, recorder, meter
+}
+
+func spanAttr(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, attr := range span.Attributes() {
+		if attr.Key == key {
+			return attr.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestHandlerNestedRuns(t *testing.T) {
+	t.Parallel()
+	handler, recorder, meter := newTestHandler(t)
+
+	chainCtx := callbacks.StartRun(context.Background(), callbacks.RunChain, "*agents.Executor")
+	handler.HandleChainStart(chainCtx, map[string]any{"input": "weather?"})
+
+	llmCtx := callbacks.StartRun(chainCtx, callbacks.RunLLM, "gpt-4o")
+	handler.HandleLLMGenerateContentStart(llmCtx, nil)
+	handler.HandleLLMGenerateContentEnd(llmCtx, &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{StopReason: "tool_calls"}},
+		Usage:   llms.NewUsage(12, 5),
+	})
+
+	handler.HandleAgentAction(chainCtx, schema.AgentAction{Tool: "search", ToolID: "call_1"})
+	toolCtx := callbacks.StartRun(chainCtx, callbacks.RunTool, "search")
+	handler.HandleToolStart(toolCtx, `{"query":"weather"}`)
+	handler.HandleToolError(toolCtx, errors.New("timeout"))
+
+	handler.HandleChainEnd(chainCtx, map[string]any{"output": "sunny"})
+	require.Empty(t, handler.runs)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	llmSpan, toolSpan, chainSpan := spans[0], spans[1], spans[2]
+
+	require.Equal(t, "chain *agents.Executor", chainSpan.Name())
+	require.False(t, chainSpan.Parent().IsValid())
+	require.Len(t, chainSpan.Events(), 1)
+	require.Equal(t, "agent_action", chainSpan.Events()[0].Name)
+
+	require.Equal(t, "chat gpt-4o", llmSpan.Name())
+	require.Equal(t, trace.SpanKindClient, llmSpan.SpanKind())
+	require.Equal(t, chainSpan.SpanContext().SpanID(), llmSpan.Parent().SpanID())
+	require.Equal(t, "gpt-4o", spanAttr(llmSpan, attrRequestModel).AsString())
+	require.Equal(t, "openai", spanAttr(llmSpan, attrSystem).AsString())
+	require.Equal(t, int64(12), spanAttr(llmSpan, attrUsageInputTokens).AsInt64())
+	require.Equal(t, int64(5), spanAttr(llmSpan, attrUsageOutputTokens).AsInt64())
+	require.Equal(t, []string{"tool_calls"}, spanAttr(llmSpan, attrResponseFinish).AsStringSlice())
+
+	chainRun, _ := callbacks.RunFromContext(chainCtx)
+	require.Equal(t, "execute_tool search", toolSpan.Name())
+	require.Equal(t, chainSpan.SpanContext().SpanID(), toolSpan.Parent().SpanID())
+	require.Equal(t, "search", spanAttr(toolSpan, attrToolName).AsString())
+	require.Equal(t, chainRun.ID, spanAttr(toolSpan, attrRunParentID).AsString())
+	require.Equal(t, codes.Error, toolSpan.Status().Code)
+
+	require.Len(t, meter.measurements, 3)
+	require.Equal(t, metricOperationDuration, meter.measurements[0].name)
+	model, _ := meter.measurements[0].attrs.Value(attrRequestModel)
+	require.Equal(t, "gpt-4o", model.AsString())
+	for i, want := range []struct {
+		tokenType string
+		value     float64
+	}{{tokenTypeInput, 12}, {tokenTypeOutput, 5}} {
+		m := meter.measurements[i+1]
+		require.Equal(t, metricTokenUsage, m.name)
+		require.InDelta(t, want.value, m.value, 0)
+		tokenType, _ := m.attrs.Value(attrTokenType)
+		require.Equal(t, want.tokenType, tokenType.AsString())
+	}
+}
+
+func TestHandlerLLMError(t *testing.T) {
+	t.Parallel()
+	handler, recorder, meter := newTestHandler(t)
+
+	ctx := callbacks.StartRun(context.Background(), callbacks.RunLLM, "gpt-4o")
+	handler.HandleLLMGenerateContentStart(ctx, nil)
+	handler.HandleLLMError(ctx, errors.New("rate limited"))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	require.Equal(t, codes.Error, spans[0].Status().Code)
+	require.Len(t, meter.measurements, 1)
+	errorType, _ := meter.measurements[0].attrs.Value(attrErrorType)
+	require.Equal(t, errorTypeLLM, errorType.AsString())
+}
+
+func TestHandlerWithoutRun(t *testing.T) {
+	t.Parallel()
+	handler, recorder, _ := newTestHandler(t)
+
+	handler.HandleChainStart(context.Background(), nil)
+	handler.HandleChainEnd(context.Background(), nil)
+	require.Empty(t, recorder.Ended())
+}
+
+func TestHandlerModelRunsEnd(t *testing.T) {
+	t.Parallel()
+	handler, recorder, _ := newTestHandler(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/messages" {
+			http.Error(w, `{"error":{"message":"unavailable"}}`, http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"msg_1","type":"message","role":"assistant","model":"claude-3-5-sonnet",
+			"content":[{"type":"text","text":"Hello"}],"stop_reason":"end_turn",
+			"usage":{"input_tokens":3,"output_tokens":1}}`)
+	}))
+	t.Cleanup(server.Close)
+
+	anthropicLLM, err := anthropic.New(anthropic.WithToken("test"), anthropic.WithBaseURL(server.URL))
+	require.NoError(t, err)
+	anthropicLLM.CallbacksHandler = handler
+	_, err = anthropicLLM.Call(context.Background(), "Hi")
+	require.NoError(t, err)
+
+	openaiLLM, err := openai.New(openai.WithToken("test"), openai.WithBaseURL(server.URL),
+		openai.WithCallback(handler))
+	require.NoError(t, err)
+	_, err = openaiLLM.Call(context.Background(), "Hi")
+	require.Error(t, err)
+
+	require.Empty(t, handler.runs)
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	require.Equal(t, codes.Unset, spans[0].Status().Code)
+	require.Equal(t, codes.Error, spans[1].Status().Code)
+}
@@ -0,0 +1,39 @@
+package opentelemetry
+
+import (
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Option is a functional argument that configures the Handler.
+type Option func(*options)
+
+type options struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	system         string
+}
+
+// WithTracerProvider specifies the provider of the tracer of the spans. The
+// global provider is used by default.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(o *options) {
+		o.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider specifies the provider of the meter of the metrics. The
+// global provider is used by default.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(o *options) {
+		o.meterProvider = provider
+	}
+}
+
+// WithSystem specifies the gen_ai.system attribute of the spans and metrics
+// of models, e.g. "openai".
+func WithSystem(system string) Option {
+	return func(o *options) {
+		o.system = system
+	}
+}
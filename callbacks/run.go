@@ -0,0 +1,55 @@
+package callbacks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// RunType is the type of a Run.
+type RunType string
+
+const (
+	// RunChain is the run of a chain, started by chains.Call.
+	RunChain RunType = "chain"
+	// RunTool is the run of a tool, started by the agent executor.
+	RunTool RunType = "tool"
+	// RunLLM is the run of a model, started by the LLM adapters.
+	RunLLM RunType = "llm"
+)
+
+// Run identifies a run of a chain, a tool or a model, so that handlers can
+// correlate their callbacks. The run of a callback is found in its context
+// with RunFromContext, and the runs started within a run have its ID as
+// ParentID.
+type Run struct {
+	// ID is the unique ID of the run.
+	ID string
+	// ParentID is the ID of the run this run was started in, empty for root
+	// runs.
+	ParentID string
+	// Type is the type of the run.
+	Type RunType
+	// Name is the name of what runs: the type of the chain, the name of the
+	// tool or the model.
+	Name string
+}
+
+type runKey struct{}
+
+// StartRun returns a copy of ctx carrying a new run, a child of the run of
+// ctx, if any. The callbacks of the run must be called with the returned
+// context.
+func StartRun(ctx context.Context, runType RunType, name string) context.Context {
+	run := Run{ID: uuid.NewString(), Type: runType, Name: name}
+	if parent, ok := RunFromContext(ctx); ok {
+		run.ParentID = parent.ID
+	}
+	return context.WithValue(ctx, runKey{}, run)
+}
+
+// RunFromContext returns the run of ctx, started by StartRun.
+func RunFromContext(ctx context.Context) (Run, bool) {
+	run, ok := ctx.Value(runKey{}).(Run)
+	return run, ok
+}
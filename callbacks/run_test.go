@@ -0,0 +1,34 @@
+package callbacks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartRun(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	_, ok := RunFromContext(ctx)
+	require.False(t, ok)
+
+	chainCtx := StartRun(ctx, RunChain, "*chains.LLMChain")
+	chain, ok := RunFromContext(chainCtx)
+	require.True(t, ok)
+	require.NotEmpty(t, chain.ID)
+	require.Empty(t, chain.ParentID)
+	require.Equal(t, RunChain, chain.Type)
+	require.Equal(t, "*chains.LLMChain", chain.Name)
+
+	llmCtx := StartRun(chainCtx, RunLLM, "gpt-4o")
+	llm, ok := RunFromContext(llmCtx)
+	require.True(t, ok)
+	require.NotEqual(t, chain.ID, llm.ID)
+	require.Equal(t, chain.ID, llm.ParentID)
+
+	// The run of the parent context is unchanged.
+	run, _ := RunFromContext(chainCtx)
+	require.Equal(t, chain, run)
+}
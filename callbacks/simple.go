@@ -10,13 +10,17 @@ import (
 
 type SimpleHandler struct{}
 
-var _ Handler = SimpleHandler{}
+var (
+	_ Handler      = SimpleHandler{}
+	_ RouteHandler = SimpleHandler{}
+)
 
 func (SimpleHandler) HandleText(context.Context, string)                                   {}
 func (SimpleHandler) HandleLLMStart(context.Context, []string)                             {}
 func (SimpleHandler) HandleLLMGenerateContentStart(context.Context, []llms.MessageContent) {}
 func (SimpleHandler) HandleLLMGenerateContentEnd(context.Context, *llms.ContentResponse)   {}
 func (SimpleHandler) HandleLLMError(context.Context, error)                                {}
+func (SimpleHandler) HandleLLMRoute(context.Context, string, error)                        {}
 func (SimpleHandler) HandleChainStart(context.Context, map[string]any)                     {}
 func (SimpleHandler) HandleChainEnd(context.Context, map[string]any)                       {}
 func (SimpleHandler) HandleChainError(context.Context, error)                              {}
@@ -9,6 +9,7 @@ import (
 	"io"
 	"net/http"
 	"regexp"
+	"strings"
 
 	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/memory"
@@ -33,6 +34,9 @@ type APIChain struct {
 	RequestChain *LLMChain
 	AnswerChain  *LLMChain
 	Request      HTTPRequest
+	// Policy restricts the requests written by the model. The zero value only
+	// allows GET requests to the URLs of the API documentation.
+	Policy APIRequestPolicy
 }
 
 // NewAPIChain creates a new APIChain object.
@@ -80,7 +84,7 @@ func (a APIChain) Call(ctx context.Context, values map[string]any, opts ...Chain
 		Method  string            `json:"method"`
 		Headers map[string]string `json:"headers"`
 		URL     string            `json:"url"`
-		Body    map[string]string `json:"body"`
+		Body    json.RawMessage   `json:"body"`
 	}
 
 	err = json.Unmarshal([]byte(jsonString), &output)
@@ -88,7 +92,8 @@ func (a APIChain) Call(ctx context.Context, values map[string]any, opts ...Chain
 		return nil, err
 	}
 
-	apiResponse, err := a.runRequest(ctx, output.Method, output.URL, output.Headers, output.Body)
+	apiDocs, _ := values["api_docs"].(string)
+	apiResponse, err := a.runRequest(ctx, apiDocs, output.Method, output.URL, output.Headers, output.Body)
 	if err != nil {
 		return nil, err
 	}
@@ -129,22 +134,24 @@ func (a APIChain) GetOutputKeys() []string {
 	return []string{"answer"}
 }
 
+// runRequest runs the request written by the model, if allowed by the
+// policy, and returns the body of its response.
 func (a APIChain) runRequest(
 	ctx context.Context,
+	apiDocs string,
 	method string,
 	url string,
 	headers map[string]string,
-	body map[string]string,
+	body json.RawMessage,
 ) (string, error) {
 	var bodyReader io.Reader
 
-	if method == "POST" || method == "PUT" {
-		bodyBytes, err := json.Marshal(body)
-		if err != nil {
-			return "", err
-		}
-
-		bodyReader = bytes.NewBuffer(bodyBytes)
+	method = strings.ToUpper(method)
+	hasBody := len(body) > 0 && string(body) != "null"
+	if hasBody && (method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch) {
+		bodyReader = bytes.NewReader(body)
+	} else {
+		hasBody = false
 	}
 
 	// Create the new request defined by reqChain
@@ -152,23 +159,42 @@ func (a APIChain) runRequest(
 	if err != nil {
 		return "", err
 	}
+	if err := a.Policy.checkRequest(ctx, req, apiDocs); err != nil {
+		return "", err
+	}
 
-	// set request headers passed from reqChain
+	// set request headers passed from reqChain, then the headers of the
+	// policy, which the model can't override.
 	for key, value := range headers {
 		req.Header.Add(key, value)
 	}
+	if hasBody && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range a.Policy.Headers {
+		req.Header.Set(key, value)
+	}
 
-	resp, err := a.Request.Do(req)
+	client, release := a.Policy.client(a.Request, apiDocs)
+	defer release()
+	resp, err := client.Do(req.WithContext(withRequestHost(ctx, req.URL.Hostname())))
 	if err != nil {
 		return "", err
 	}
 
 	defer resp.Body.Close()
 
-	resBody, err := io.ReadAll(resp.Body)
+	maxBytes := a.Policy.MaxResponseBytes
+	if maxBytes <= 0 {
+		maxBytes = _apiDefaultMaxResponseBytes
+	}
+	resBody, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
 	if err != nil {
 		return "", err
 	}
+	if int64(len(resBody)) > maxBytes {
+		return "", fmt.Errorf("%w: more than %d bytes", ErrAPIResponseTooLarge, maxBytes)
+	}
 
 	return string(resBody), nil
 }
@@ -0,0 +1,253 @@
+package chains
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	_apiDefaultMaxResponseBytes = 1 << 20
+	_apiMaxRedirects            = 10
+	_apiDialTimeout             = 30 * time.Second
+)
+
+// _apiDocsURLRegexp matches the URLs of API documentations.
+var _apiDocsURLRegexp = regexp.MustCompile(`https?://[^\s"'<>()\[\]{}]+`) //nolint:gochecknoglobals
+
+// APIRequestPolicy restricts the HTTP requests an APIChain makes on behalf of
+// the model. The zero value allows GET requests to the URLs of the API
+// documentation, on public IP addresses, with responses of up to 1 MiB.
+type APIRequestPolicy struct {
+	// AllowedURLPrefixes are the URLs the requests can target, with the URLs
+	// under them, e.g. "https://api.open-meteo.com/v1/" allows
+	// "https://api.open-meteo.com/v1/forecast". Empty means the URLs of the
+	// API documentation, e.g. its base URL.
+	AllowedURLPrefixes []string
+	// AllowedMethods are the allowed HTTP methods. Empty means GET.
+	AllowedMethods []string
+	// AllowPrivateIPs allows requests to loopback, private, link-local and
+	// unspecified IP addresses, e.g. to APIs of the local network.
+	AllowPrivateIPs bool
+	// MaxResponseBytes is the maximum size of the response bodies. 0 means
+	// 1 MiB.
+	MaxResponseBytes int64
+	// Headers are set on all the requests, overriding the headers written by
+	// the model, e.g. to authenticate. The model never sees them.
+	Headers map[string]string
+	// Resolver resolves the hosts of the requests to check their IP
+	// addresses. nil means net.DefaultResolver.
+	Resolver interface {
+		LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+	}
+}
+
+// checkRequest checks that the request is allowed by the policy, given the
+// API documentation.
+func (p APIRequestPolicy) checkRequest(ctx context.Context, req *http.Request, apiDocs string) error {
+	methods := p.AllowedMethods
+	if len(methods) == 0 {
+		methods = []string{http.MethodGet}
+	}
+	if !containsFold(methods, req.Method) {
+		return fmt.Errorf("%w: method %s is not allowed", ErrAPIRequestNotAllowed, req.Method)
+	}
+	if err := p.checkURL(req.URL, apiDocs); err != nil {
+		return err
+	}
+	return p.checkHost(ctx, req.URL.Hostname())
+}
+
+// checkURL checks that the URL is under one of the allowed URL prefixes.
+func (p APIRequestPolicy) checkURL(u *url.URL, apiDocs string) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%w: scheme %q is not allowed", ErrAPIRequestNotAllowed, u.Scheme)
+	}
+	if u.User != nil {
+		return fmt.Errorf("%w: URLs with credentials are not allowed", ErrAPIRequestNotAllowed)
+	}
+	for _, segment := range strings.Split(u.Path, "/") {
+		if segment == "." || segment == ".." {
+			return fmt.Errorf("%w: URLs with dot segments are not allowed", ErrAPIRequestNotAllowed)
+		}
+	}
+
+	prefixes := p.AllowedURLPrefixes
+	if len(prefixes) == 0 {
+		for _, prefix := range _apiDocsURLRegexp.FindAllString(apiDocs, -1) {
+			prefixes = append(prefixes, strings.TrimRight(prefix, ".,;:"))
+		}
+	}
+	for _, prefix := range prefixes {
+		allowed, err := url.Parse(prefix)
+		if err != nil {
+			return fmt.Errorf("invalid allowed URL prefix %q: %w", prefix, err)
+		}
+		if matchesURLPrefix(u, allowed) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: URL %s is not allowed", ErrAPIRequestNotAllowed, u.Redacted())
+}
+
+// matchesURLPrefix reports whether the URL is the prefix URL or under it: the
+// scheme and the host are the same, and the path is in the path of the prefix.
+func matchesURLPrefix(u, prefix *url.URL) bool {
+	if !strings.EqualFold(u.Scheme, prefix.Scheme) ||
+		!strings.EqualFold(hostWithPort(u), hostWithPort(prefix)) {
+		return false
+	}
+	path := strings.TrimSuffix(prefix.Path, "/")
+	return u.Path == path || strings.HasPrefix(u.Path, path+"/")
+}
+
+// hostWithPort returns the host of a URL with its port, the default port of
+// its scheme if unspecified.
+func hostWithPort(u *url.URL) string {
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if strings.EqualFold(u.Scheme, "https") {
+			port = "443"
+		}
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// checkHost checks that all the IP addresses of the host are public, unless
+// private IP addresses are allowed.
+func (p APIRequestPolicy) checkHost(ctx context.Context, host string) error {
+	if p.AllowPrivateIPs {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return checkPublicIP(ip)
+	}
+
+	var resolver interface {
+		LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+	} = net.DefaultResolver
+	if p.Resolver != nil {
+		resolver = p.Resolver
+	}
+	addrs, err := resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("resolve %s: %w", host, err)
+	}
+	for _, addr := range addrs {
+		if err := checkPublicIP(addr.IP); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkPublicIP(ip net.IP) error {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return fmt.Errorf("%w: IP address %s is not public", ErrAPIRequestNotAllowed, ip)
+	}
+	return nil
+}
+
+// client returns the client of the requests of the chain. The redirects of
+// http.Client are checked against the policy, and unless private IP
+// addresses are allowed, the addresses connected to are checked too, which
+// the resolution of the host can't guarantee. The returned function releases
+// the client.
+func (p APIRequestPolicy) client(request HTTPRequest, apiDocs string) (HTTPRequest, func()) {
+	client, ok := request.(*http.Client)
+	if !ok {
+		return request, func() {}
+	}
+
+	c := *client
+	c.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if err := p.checkURL(req.URL, apiDocs); err != nil {
+			return err
+		}
+		addRequestHost(req.Context(), req.URL.Hostname())
+		if client.CheckRedirect != nil {
+			return client.CheckRedirect(req, via)
+		}
+		if len(via) >= _apiMaxRedirects {
+			return fmt.Errorf("stopped after %d redirects", _apiMaxRedirects)
+		}
+		return nil
+	}
+
+	transport := c.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	t, ok := transport.(*http.Transport)
+	if p.AllowPrivateIPs || !ok {
+		return &c, func() {}
+	}
+	t = t.Clone()
+	dialContext := t.DialContext
+	if dialContext == nil {
+		dialContext = (&net.Dialer{Timeout: _apiDialTimeout}).DialContext
+	}
+	checkedDialer := &net.Dialer{Timeout: _apiDialTimeout, Control: controlPublicIP}
+	t.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		// Connections to proxies aren't checked, but to the hosts of the
+		// requests.
+		host, _, err := net.SplitHostPort(addr)
+		if err == nil && isRequestHost(ctx, host) {
+			return checkedDialer.DialContext(ctx, network, addr)
+		}
+		return dialContext(ctx, network, addr)
+	}
+	c.Transport = t
+	return &c, t.CloseIdleConnections
+}
+
+type apiRequestHostsKey struct{}
+
+// withRequestHost returns a context recording the host of a request, and of
+// its redirects, see addRequestHost, whose connections are checked.
+func withRequestHost(ctx context.Context, host string) context.Context {
+	return context.WithValue(ctx, apiRequestHostsKey{}, &[]string{host})
+}
+
+// addRequestHost records the host of a redirect of the request of ctx.
+func addRequestHost(ctx context.Context, host string) {
+	if hosts, ok := ctx.Value(apiRequestHostsKey{}).(*[]string); ok {
+		*hosts = append(*hosts, host)
+	}
+}
+
+// isRequestHost reports whether the host is the host of the request of ctx,
+// or of one of its redirects.
+func isRequestHost(ctx context.Context, host string) bool {
+	hosts, ok := ctx.Value(apiRequestHostsKey{}).(*[]string)
+	return ok && containsFold(*hosts, host)
+}
+
+func controlPublicIP(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("%w: invalid IP address %s", ErrAPIRequestNotAllowed, host)
+	}
+	return checkPublicIP(ip)
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,12 +2,16 @@ package chains
 
 import (
 	"context"
+	"io"
+	"net"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms/fake"
 	"github.com/tmc/langchaingo/llms/openai"
 )
 
@@ -68,3 +72,140 @@ func TestAPI(t *testing.T) {
 	}
 	require.True(t, strings.Contains(answer, "Munich"), `result does not contain the keyword 'Munich'`)
 }
+
+// testResolver resolves all the hosts to a public IP address, or to the IP
+// addresses of the map.
+type testResolver map[string]string
+
+func (r testResolver) LookupIPAddr(_ context.Context, host string) ([]net.IPAddr, error) {
+	ip, ok := r[host]
+	if !ok {
+		ip = "93.184.216.34"
+	}
+	return []net.IPAddr{{IP: net.ParseIP(ip)}}, nil
+}
+
+// testRequester records the requests, and responds with the body.
+type testRequester struct {
+	requests []*http.Request
+	bodies   []string
+	body     string
+}
+
+func (r *testRequester) Do(req *http.Request) (*http.Response, error) {
+	r.requests = append(r.requests, req)
+	var body []byte
+	if req.Body != nil {
+		body, _ = io.ReadAll(req.Body)
+	}
+	r.bodies = append(r.bodies, string(body))
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(r.body))}, nil
+}
+
+func newTestAPIChain(request HTTPRequest, outputs ...string) APIChain {
+	llm := fake.NewScriptedLLM()
+	for _, output := range outputs {
+		llm.AddResponse(fake.Response{Content: output})
+	}
+	chain := NewAPIChain(llm, request)
+	chain.Policy.Resolver = testResolver{"internal.example.com": "10.0.0.1"}
+	return chain
+}
+
+func TestAPIChainPolicy(t *testing.T) {
+	t.Parallel()
+
+	requester := &testRequester{body: `{"temperature": 12}`}
+	chain := newTestAPIChain(requester,
+		`{"method": "post", "url": "https://api.open-meteo.com/v1/forecast", `+
+			`"headers": {"Authorization": "Bearer model"}, "body": {"location": {"lat": 48.1, "lon": 11.6}}}`,
+		"It's 12 degrees.")
+	chain.Policy.AllowedMethods = []string{"GET", "POST"}
+	chain.Policy.Headers = map[string]string{"Authorization": "Bearer secret"}
+
+	result, err := Call(context.Background(), chain, map[string]any{"api_docs": MeteoDocs, "input": "weather?"})
+	require.NoError(t, err)
+	require.Equal(t, "It's 12 degrees.", result["answer"])
+	require.Len(t, requester.requests, 1)
+	req := requester.requests[0]
+	require.Equal(t, http.MethodPost, req.Method)
+	require.Equal(t, "Bearer secret", req.Header.Get("Authorization"))
+	require.Equal(t, "application/json", req.Header.Get("Content-Type"))
+	require.JSONEq(t, `{"location": {"lat": 48.1, "lon": 11.6}}`, requester.bodies[0])
+
+	for _, request := range []string{
+		`{"method": "DELETE", "url": "https://api.open-meteo.com/v1/forecast"}`,
+		`{"method": "GET", "url": "https://evil.example.com/v1/forecast"}`,
+		`{"method": "GET", "url": "https://api.open-meteo.com.evil.example.com/"}`,
+		`{"method": "GET", "url": "http://api.open-meteo.com/v1/forecast"}`,
+		`{"method": "GET", "url": "https://api.open-meteo.com:8443/v1/forecast"}`,
+		`{"method": "GET", "url": "https://user@api.open-meteo.com/v1/forecast"}`,
+		`{"method": "GET", "url": "file:///etc/passwd"}`,
+	} {
+		chain := newTestAPIChain(requester, request)
+		_, err := Call(context.Background(), chain, map[string]any{"api_docs": MeteoDocs, "input": "weather?"})
+		require.ErrorIs(t, err, ErrAPIRequestNotAllowed, request)
+	}
+	require.Len(t, requester.requests, 1)
+}
+
+func TestAPIChainPolicyURLPrefixes(t *testing.T) {
+	t.Parallel()
+
+	for request, allowed := range map[string]bool{
+		`{"method": "GET", "url": "https://internal.example.com/v1/users"}`:     false,
+		`{"method": "GET", "url": "http://169.254.169.254/v1/meta-data"}`:       false,
+		`{"method": "GET", "url": "https://api.example.com/v1/users?id=1"}`:     true,
+		`{"method": "GET", "url": "https://api.example.com/v10/users"}`:         false,
+		`{"method": "GET", "url": "https://api.example.com/v1/../admin/users"}`: false,
+	} {
+		requester := &testRequester{}
+		chain := newTestAPIChain(requester, request, "answer")
+		chain.Policy.AllowedURLPrefixes = []string{
+			"https://api.example.com/v1/",
+			"https://internal.example.com/v1/",
+			"http://169.254.169.254/v1/",
+		}
+		_, err := Call(context.Background(), chain, map[string]any{"api_docs": "", "input": "users?"})
+		if allowed {
+			require.NoError(t, err, request)
+			continue
+		}
+		require.ErrorIs(t, err, ErrAPIRequestNotAllowed, request)
+		require.Empty(t, requester.requests, request)
+	}
+}
+
+func TestAPIChainPolicyResponseSize(t *testing.T) {
+	t.Parallel()
+
+	requester := &testRequester{body: strings.Repeat("a", 11)}
+	chain := newTestAPIChain(requester, `{"method": "GET", "url": "https://api.open-meteo.com/v1/forecast"}`)
+	chain.Policy.MaxResponseBytes = 10
+	_, err := Call(context.Background(), chain, map[string]any{"api_docs": MeteoDocs, "input": "weather?"})
+	require.ErrorIs(t, err, ErrAPIResponseTooLarge)
+}
+
+func TestAPIChainPolicyConnections(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/redirect" {
+			http.Redirect(w, r, "http://evil.example.com/", http.StatusFound)
+		}
+	}))
+	defer server.Close()
+	url := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+
+	// The resolver says localhost is public, but the connection is checked.
+	chain := newTestAPIChain(server.Client(), `{"method": "GET", "url": "`+url+`/v1/forecast"}`)
+	chain.Policy.Resolver = testResolver{}
+	_, err := Call(context.Background(), chain, map[string]any{"api_docs": "BASE URL: " + url, "input": "weather?"})
+	require.ErrorIs(t, err, ErrAPIRequestNotAllowed)
+
+	// Redirects are checked.
+	chain = newTestAPIChain(server.Client(), `{"method": "GET", "url": "`+url+`/v1/redirect"}`)
+	chain.Policy.AllowPrivateIPs = true
+	_, err = Call(context.Background(), chain, map[string]any{"api_docs": "BASE URL: " + url, "input": "weather?"})
+	require.ErrorIs(t, err, ErrAPIRequestNotAllowed)
+}
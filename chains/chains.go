@@ -26,8 +26,11 @@ type Chain interface {
 	GetOutputKeys() []string
 }
 
-// Call is the standard function used for executing chains.
+// Call is the standard function used for executing chains. The chain runs in
+// a new run of the context, see callbacks.StartRun.
 func Call(ctx context.Context, c Chain, inputValues map[string]any, options ...ChainCallOption) (map[string]any, error) { // nolint: lll
+	ctx = callbacks.StartRun(ctx, callbacks.RunChain, fmt.Sprintf("%T", c))
+
 	fullValues := make(map[string]any, 0)
 	for key, value := range inputValues {
 		fullValues[key] = value
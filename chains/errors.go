@@ -39,4 +39,11 @@ var (
 	ErrMultipleOutputsInPredict = errors.New("predict is not supported with a chain that returns multiple values")
 	// ErrChainInitialization is returned if a chain is not initialized appropriately.
 	ErrChainInitialization = errors.New("error initializing chain")
+
+	// ErrAPIRequestNotAllowed is returned if the request of an APIChain is not
+	// allowed by its APIRequestPolicy.
+	ErrAPIRequestNotAllowed = errors.New("api request not allowed")
+	// ErrAPIResponseTooLarge is returned if the response of an APIChain is
+	// larger than the maximum of its APIRequestPolicy.
+	ErrAPIResponseTooLarge = errors.New("api response too large")
 )
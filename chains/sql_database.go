@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/memory"
@@ -38,6 +39,8 @@ const (
 	_sqlChainDefaultInputKeyQuery      = "query"
 	_sqlChainDefaultInputKeyTableNames = "table_names_to_use"
 	_sqlChainDefaultOutputKey          = "result"
+	_sqlChainDefaultQueryTimeout       = 30 * time.Second
+	_sqlChainDefaultMaxRetries         = 2
 )
 
 // SQLDatabaseChain is a chain used for interacting with SQL Database.
@@ -46,19 +49,32 @@ type SQLDatabaseChain struct {
 	TopK      int
 	Database  *sqldatabase.SQLDatabase
 	OutputKey string
+	// Policy restricts the queries written by the model. The zero value only
+	// allows queries, without the functions known to have side effects.
+	Policy sqldatabase.QueryPolicy
+	// QueryTimeout is the timeout of each query. 0 means no timeout.
+	QueryTimeout time.Duration
+	// MaxRetries is the number of times the model is asked to correct a query
+	// rejected by the Policy or failed by the database, given the error.
+	MaxRetries int
 }
 
 // NewSQLDatabaseChain creates a new SQLDatabaseChain.
-// The topK is the max number of results to return.
+// The topK is the max number of results to return. Only queries are allowed,
+// without the functions known to have side effects, and their number of rows
+// is limited to topK.
 func NewSQLDatabaseChain(llm llms.Model, topK int, database *sqldatabase.SQLDatabase) *SQLDatabaseChain {
 	p := prompts.NewPromptTemplate(_defaultSQLTemplate+_defaultSQLSuffix,
 		[]string{"dialect", "top_k", "table_info", "input"})
 	c := NewLLMChain(llm, p)
 	return &SQLDatabaseChain{
-		LLMChain:  c,
-		TopK:      topK,
-		Database:  database,
-		OutputKey: _sqlChainDefaultOutputKey,
+		LLMChain:     c,
+		TopK:         topK,
+		Database:     database,
+		OutputKey:    _sqlChainDefaultOutputKey,
+		Policy:       sqldatabase.QueryPolicy{MaxRows: topK},
+		QueryTimeout: _sqlChainDefaultQueryTimeout,
+		MaxRetries:   _sqlChainDefaultMaxRetries,
 	}
 }
 
@@ -98,36 +114,45 @@ func (s SQLDatabaseChain) Call(ctx context.Context, inputs map[string]any, optio
 	const (
 		queryPrefixWith = "\nSQLQuery:"  //nolint:gosec
 		stopWord        = "\nSQLResult:" //nolint:gosec
+		errorPrefix     = "\nSQLError: " //nolint:gosec
 	)
+	input := query + queryPrefixWith
 	llmInputs := map[string]any{
-		"input":      query + queryPrefixWith,
+		"input":      input,
 		"top_k":      s.TopK,
 		"dialect":    s.Database.Dialect(),
 		"table_info": tableInfos,
 	}
 
-	// Predict sql query
+	// Predict sql query, and ask the model to correct it if it fails.
+	var sqlQuery, queryResult string
 	opt := append(options, WithStopWords([]string{stopWord})) //nolint:cyclop
-	out, err := Predict(ctx, s.LLMChain, llmInputs, opt...)
-	if err != nil {
-		return nil, err
-	}
-
-	sqlQuery := extractSQLQuery(out)
+	for attempt := 0; ; attempt++ {
+		llmInputs["input"] = input
+		out, err := Predict(ctx, s.LLMChain, llmInputs, opt...)
+		if err != nil {
+			return nil, err
+		}
 
-	if sqlQuery == "" {
-		return nil, fmt.Errorf("no sql query generated")
-	}
+		sqlQuery = extractSQLQuery(out)
+		if sqlQuery == "" {
+			return nil, fmt.Errorf("no sql query generated")
+		}
 
-	// Execute sql query
-	queryResult, err := s.Database.Query(ctx, sqlQuery)
-	if err != nil {
-		return nil, err
+		// Execute sql query
+		sqlQuery, queryResult, err = s.query(ctx, sqlQuery)
+		if err == nil {
+			break
+		}
+		if attempt >= s.MaxRetries || ctx.Err() != nil {
+			return nil, fmt.Errorf("sql query %q: %w", sqlQuery, err)
+		}
+		input += sqlQuery + errorPrefix + err.Error() + queryPrefixWith
 	}
 
 	// Generate answer
-	llmInputs["input"] = query + queryPrefixWith + sqlQuery + stopWord + queryResult
-	out, err = Predict(ctx, s.LLMChain, llmInputs, options...)
+	llmInputs["input"] = input + sqlQuery + stopWord + queryResult
+	out, err := Predict(ctx, s.LLMChain, llmInputs, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -142,6 +167,22 @@ func (s SQLDatabaseChain) Call(ctx context.Context, inputs map[string]any, optio
 	return map[string]any{s.OutputKey: out}, nil
 }
 
+// query checks the query against the policy and executes it, returning the
+// query as executed, e.g. with a LIMIT, and its result.
+func (s SQLDatabaseChain) query(ctx context.Context, sqlQuery string) (string, string, error) {
+	checked, err := s.Policy.Check(sqlQuery)
+	if err != nil {
+		return sqlQuery, "", err
+	}
+	if s.QueryTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.QueryTimeout)
+		defer cancel()
+	}
+	result, err := s.Database.Query(ctx, checked)
+	return checked, result, err
+}
+
 func (s SQLDatabaseChain) GetMemory() schema.Memory { //nolint:ireturn
 	return memory.NewSimple()
 }
@@ -174,8 +215,9 @@ func extractSQLQuery(rawOut string) string {
 			continue
 		}
 
-		// stop when we find SQLResult: or Answer:
-		if strings.HasPrefix(line, "SQLResult:") || strings.HasPrefix(line, "Answer:") {
+		// stop when we find SQLResult:, SQLError: or Answer:
+		if strings.HasPrefix(line, "SQLResult:") || strings.HasPrefix(line, "SQLError:") ||
+			strings.HasPrefix(line, "Answer:") {
 			break
 		}
 
@@ -2,10 +2,14 @@ package chains
 
 import (
 	"context"
+	"errors"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/fake"
 	"github.com/tmc/langchaingo/llms/openai"
 	"github.com/tmc/langchaingo/tools/sqldatabase"
 	"github.com/tmc/langchaingo/tools/sqldatabase/mysql"
@@ -46,6 +50,76 @@ func TestSQLDatabaseChain_Call(t *testing.T) {
 	t.Log(ret)
 }
 
+// testEngine is a sqldatabase.Engine with a users table, failing the queries
+// of unknown columns.
+type testEngine struct {
+	queries []string
+}
+
+func (e *testEngine) Dialect() string { return "sqlite3" }
+
+func (e *testEngine) Query(_ context.Context, query string, _ ...any) ([]string, [][]string, error) {
+	e.queries = append(e.queries, query)
+	if strings.Contains(query, "nme") {
+		return nil, nil, errors.New("no such column: nme")
+	}
+	return []string{"name"}, [][]string{{"Alice"}, {"Bob"}}, nil
+}
+
+func (e *testEngine) TableNames(context.Context) ([]string, error) { return []string{"users"}, nil }
+
+func (e *testEngine) TableInfo(context.Context, string) (string, error) {
+	return "CREATE TABLE users (id INTEGER, name TEXT, password TEXT)", nil
+}
+
+func (e *testEngine) Close() error { return nil }
+
+func newTestSQLDatabase(t *testing.T) (*sqldatabase.SQLDatabase, *testEngine) {
+	t.Helper()
+	engine := &testEngine{}
+	db, err := sqldatabase.NewSQLDatabase(engine, nil)
+	require.NoError(t, err)
+	db.SampleRowsNumber = 0
+	return db, engine
+}
+
+func TestSQLDatabaseChain_CallRetries(t *testing.T) {
+	t.Parallel()
+	db, engine := newTestSQLDatabase(t)
+	llm := fake.NewScriptedLLM(
+		fake.Response{Content: "DELETE FROM users"},
+		fake.Response{Content: "SELECT nme FROM users"},
+		fake.Response{Content: "SELECT name FROM users"},
+		fake.Response{Content: "Answer: Alice and Bob"},
+	)
+
+	chain := NewSQLDatabaseChain(llm, 5, db)
+	result, err := chain.Call(context.Background(), map[string]any{"query": "Who are the users?"})
+	require.NoError(t, err)
+	require.Equal(t, "Alice and Bob", result["result"])
+	require.Equal(t, []string{"SELECT nme FROM users LIMIT 5", "SELECT name FROM users LIMIT 5"}, engine.queries)
+
+	calls := llm.Calls()
+	require.Len(t, calls, 4)
+	prompt := calls[3].Messages[0].Parts[0].(llms.TextContent).Text
+	require.Contains(t, prompt, "SQLQuery:DELETE FROM users\nSQLError: query not allowed: DELETE statements are not allowed")
+	require.Contains(t, prompt, "SQLQuery:SELECT nme FROM users LIMIT 5\nSQLError: no such column: nme")
+	require.Contains(t, prompt, "SQLQuery:SELECT name FROM users LIMIT 5\nSQLResult:name\nAlice\nBob")
+}
+
+func TestSQLDatabaseChain_CallPolicy(t *testing.T) {
+	t.Parallel()
+	db, engine := newTestSQLDatabase(t)
+	llm := fake.NewScriptedLLM(fake.Response{Content: "SELECT password FROM users", Repeat: true})
+
+	chain := NewSQLDatabaseChain(llm, 5, db)
+	chain.Policy.AllowedColumns = map[string][]string{"users": {"id", "name"}}
+	_, err := chain.Call(context.Background(), map[string]any{"query": "What are the passwords?"})
+	require.ErrorIs(t, err, sqldatabase.ErrQueryNotAllowed)
+	require.Empty(t, engine.queries)
+	require.Len(t, llm.Calls(), 3)
+}
+
 func TestExtractSQLQuery(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,26 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/chains"
+)
+
+// ChainNode returns a node calling a chain, e.g. an agents.Executor, with
+// chains.Call. The inputs of the chain are given by inputs from the state,
+// and the update of the state is given by update from the outputs.
+func ChainNode[S any](
+	chain chains.Chain,
+	inputs func(state S) map[string]any,
+	update func(outputs map[string]any) (S, error),
+	options ...chains.ChainCallOption,
+) NodeFunc[S] {
+	return func(ctx context.Context, state S) (S, error) {
+		outputs, err := chains.Call(ctx, chain, inputs(state), options...)
+		if err != nil {
+			var zero S
+			return zero, err
+		}
+		return update(outputs)
+	}
+}
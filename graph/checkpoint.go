@@ -0,0 +1,79 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCheckpointNotFound is returned when a thread has no checkpoint.
+var ErrCheckpointNotFound = errors.New("checkpoint not found")
+
+// Checkpoint is the state of a run after one of its steps.
+type Checkpoint struct {
+	// ThreadID identifies the runs of a workflow sharing their state, see
+	// WithThreadID.
+	ThreadID string `json:"thread_id"`
+	// Step is the number of the step in the thread, from 1.
+	Step int `json:"step"`
+	// Node is the node of the step, Start for the checkpoint of the input of
+	// a run, and empty for the updates of Workflow.UpdateState.
+	Node string `json:"node"`
+	// Next is the node the run continues with, End if the run is done.
+	Next string `json:"next"`
+	// State is the JSON-encoded state after the step.
+	State json.RawMessage `json:"state"`
+	// CreatedAt is when the checkpoint was created.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Checkpointer is a store of checkpoints.
+type Checkpointer interface {
+	// Put saves a checkpoint.
+	Put(ctx context.Context, checkpoint Checkpoint) error
+	// Get returns the last checkpoint of a thread, or ErrCheckpointNotFound.
+	Get(ctx context.Context, threadID string) (Checkpoint, error)
+	// List returns the checkpoints of a thread, by step.
+	List(ctx context.Context, threadID string) ([]Checkpoint, error)
+}
+
+// MemoryCheckpointer is a Checkpointer keeping the checkpoints in memory.
+type MemoryCheckpointer struct {
+	mu      sync.Mutex
+	threads map[string][]Checkpoint
+}
+
+var _ Checkpointer = (*MemoryCheckpointer)(nil)
+
+// NewMemoryCheckpointer creates a new in-memory Checkpointer.
+func NewMemoryCheckpointer() *MemoryCheckpointer {
+	return &MemoryCheckpointer{threads: map[string][]Checkpoint{}}
+}
+
+// Put saves a checkpoint.
+func (m *MemoryCheckpointer) Put(_ context.Context, checkpoint Checkpoint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.threads[checkpoint.ThreadID] = append(m.threads[checkpoint.ThreadID], checkpoint)
+	return nil
+}
+
+// Get returns the last checkpoint of a thread.
+func (m *MemoryCheckpointer) Get(_ context.Context, threadID string) (Checkpoint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	checkpoints := m.threads[threadID]
+	if len(checkpoints) == 0 {
+		return Checkpoint{}, ErrCheckpointNotFound
+	}
+	return checkpoints[len(checkpoints)-1], nil
+}
+
+// List returns the checkpoints of a thread.
+func (m *MemoryCheckpointer) List(_ context.Context, threadID string) ([]Checkpoint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Checkpoint(nil), m.threads[threadID]...), nil
+}
@@ -0,0 +1,221 @@
+// Package graph runs stateful workflows of chains, agents and functions, as
+// graphs whose nodes update a typed state.
+//
+// Unlike chains.SequentialChain, the edges of a graph can be conditional,
+// and form cycles, e.g. an agent calling tools until it answers:
+//
+//	type State struct {
+//		graph.Reset
+//		Messages  []llms.MessageContent `graph:"append"`
+//		ToolCalls []llms.ToolCall
+//	}
+//
+//	callTools := func(ctx context.Context, s State) (State, error) {
+//		results := runTools(ctx, s.ToolCalls)
+//		// The tool calls are cleared, so that the agent is called again.
+//		return State{Reset: graph.Reset{"ToolCalls"}, Messages: results}, nil
+//	}
+//
+//	g := graph.New[State]()
+//	g.AddNode("agent", callModel)
+//	g.AddNode("tools", callTools)
+//	g.AddEdge(graph.Start, "agent")
+//	g.AddConditionalEdge("agent", func(_ context.Context, s State) (string, error) {
+//		if len(s.ToolCalls) > 0 {
+//			return "tools", nil
+//		}
+//		return graph.End, nil
+//	})
+//	g.AddEdge("tools", "agent")
+//	workflow, err := g.Compile(graph.WithCheckpointer(graph.NewMemoryCheckpointer()))
+//
+// Each node returns an update of the state, merged into the state by the
+// reducer of the graph, see MergeFields. With a Checkpointer, the state is
+// saved after each step of a run, so that the run can be paused, e.g. for a
+// human to review the state, and resumed, possibly by another process.
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+const (
+	// Start is the virtual node the runs start from: its edge leads to the
+	// first node of the graph.
+	Start = "__start__"
+	// End is the virtual node the runs end at.
+	End = "__end__"
+)
+
+var (
+	// ErrInvalidGraph is returned when compiling an invalid graph.
+	ErrInvalidGraph = errors.New("invalid graph")
+	// ErrMaxSteps is returned when a run exceeds the maximum number of steps,
+	// e.g. in an infinite loop.
+	ErrMaxSteps = errors.New("maximum number of steps exceeded")
+	// ErrInterrupted is returned when a run is interrupted before a node, see
+	// WithInterruptBefore. The run is resumed with Workflow.Resume.
+	ErrInterrupted = errors.New("run interrupted")
+	// ErrUnknownNode is returned when a conditional edge leads to an unknown
+	// node.
+	ErrUnknownNode = errors.New("unknown node")
+)
+
+// NodeFunc is a node of a graph. It returns an update of the state, merged
+// into the state by the reducer of the graph.
+type NodeFunc[S any] func(ctx context.Context, state S) (S, error)
+
+// ReducerFunc merges an update returned by a node into the state.
+type ReducerFunc[S any] func(state, update S) S
+
+// RouterFunc is a conditional edge of a graph. It returns the next node given
+// the state, End to end the run.
+type RouterFunc[S any] func(ctx context.Context, state S) (string, error)
+
+// Graph is a graph of nodes updating a state of type S, built with AddNode,
+// AddEdge and AddConditionalEdge, and compiled into a Workflow.
+//
+// Each node has a single outgoing edge, static or conditional, and the nodes
+// without outgoing edge lead to End.
+type Graph[S any] struct {
+	nodes   map[string]NodeFunc[S]
+	edges   map[string]string
+	routers map[string]RouterFunc[S]
+	reducer ReducerFunc[S]
+	errs    []error
+}
+
+// New creates a new empty graph.
+func New[S any]() *Graph[S] {
+	return &Graph[S]{
+		nodes:   map[string]NodeFunc[S]{},
+		edges:   map[string]string{},
+		routers: map[string]RouterFunc[S]{},
+		reducer: MergeFields[S],
+	}
+}
+
+// SetReducer sets the reducer of the graph, MergeFields by default.
+func (g *Graph[S]) SetReducer(reducer ReducerFunc[S]) *Graph[S] {
+	if reducer == nil {
+		g.errs = append(g.errs, fmt.Errorf("%w: nil reducer", ErrInvalidGraph))
+		return g
+	}
+	g.reducer = reducer
+	return g
+}
+
+// AddNode adds a node to the graph.
+func (g *Graph[S]) AddNode(name string, node NodeFunc[S]) *Graph[S] {
+	switch {
+	case name == "" || name == Start || name == End:
+		g.errs = append(g.errs, fmt.Errorf("%w: reserved node name %q", ErrInvalidGraph, name))
+	case node == nil:
+		g.errs = append(g.errs, fmt.Errorf("%w: nil node %q", ErrInvalidGraph, name))
+	case g.nodes[name] != nil:
+		g.errs = append(g.errs, fmt.Errorf("%w: duplicate node %q", ErrInvalidGraph, name))
+	default:
+		g.nodes[name] = node
+	}
+	return g
+}
+
+// AddEdge adds an edge from a node to the next one. The edge from Start
+// leads to the first node of the graph.
+func (g *Graph[S]) AddEdge(from, to string) *Graph[S] {
+	if g.hasEdge(from) {
+		g.errs = append(g.errs, fmt.Errorf("%w: node %q has several edges", ErrInvalidGraph, from))
+		return g
+	}
+	g.edges[from] = to
+	return g
+}
+
+// AddConditionalEdge adds an edge from a node to the node returned by the
+// router given the state.
+func (g *Graph[S]) AddConditionalEdge(from string, router RouterFunc[S]) *Graph[S] {
+	switch {
+	case router == nil:
+		g.errs = append(g.errs, fmt.Errorf("%w: nil router of node %q", ErrInvalidGraph, from))
+	case g.hasEdge(from):
+		g.errs = append(g.errs, fmt.Errorf("%w: node %q has several edges", ErrInvalidGraph, from))
+	default:
+		g.routers[from] = router
+	}
+	return g
+}
+
+func (g *Graph[S]) hasEdge(from string) bool {
+	_, static := g.edges[from]
+	_, conditional := g.routers[from]
+	return static || conditional
+}
+
+// Compile validates the graph and returns a workflow running it.
+func (g *Graph[S]) Compile(opts ...Option) (*Workflow[S], error) {
+	if err := g.validate(); err != nil {
+		return nil, err
+	}
+
+	o := Options{
+		MaxSteps: _defaultMaxSteps,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if len(o.InterruptBefore) > 0 && o.Checkpointer == nil {
+		return nil, fmt.Errorf("%w: interrupts require a checkpointer", ErrInvalidGraph)
+	}
+	for _, node := range o.InterruptBefore {
+		if g.nodes[node] == nil {
+			return nil, fmt.Errorf("%w: interrupt before unknown node %q", ErrInvalidGraph, node)
+		}
+	}
+
+	return &Workflow[S]{graph: g, options: o}, nil
+}
+
+func (g *Graph[S]) validate() error {
+	if len(g.errs) > 0 {
+		return errors.Join(g.errs...)
+	}
+	if _, ok := g.edges[Start]; !ok {
+		if _, ok := g.routers[Start]; !ok {
+			return fmt.Errorf("%w: no edge from %s", ErrInvalidGraph, Start)
+		}
+	}
+	for from, to := range g.edges {
+		if from != Start && g.nodes[from] == nil {
+			return fmt.Errorf("%w: edge from unknown node %q", ErrInvalidGraph, from)
+		}
+		if to != End && g.nodes[to] == nil {
+			return fmt.Errorf("%w: edge to unknown node %q", ErrInvalidGraph, to)
+		}
+	}
+	for from := range g.routers {
+		if from != Start && g.nodes[from] == nil {
+			return fmt.Errorf("%w: edge from unknown node %q", ErrInvalidGraph, from)
+		}
+	}
+	return nil
+}
+
+// next returns the node following a node given the state.
+func (g *Graph[S]) next(ctx context.Context, from string, state S) (string, error) {
+	if router, ok := g.routers[from]; ok {
+		to, err := router(ctx, state)
+		if err != nil {
+			return "", fmt.Errorf("route from %q: %w", from, err)
+		}
+		if to != End && g.nodes[to] == nil {
+			return "", fmt.Errorf("route from %q: %w %q", from, ErrUnknownNode, to)
+		}
+		return to, nil
+	}
+	if to, ok := g.edges[from]; ok {
+		return to, nil
+	}
+	return End, nil
+}
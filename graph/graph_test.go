@@ -0,0 +1,214 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/chains"
+)
+
+type testState struct {
+	Count    int               `json:"count"`
+	Done     bool              `json:"done"`
+	Steps    []string          `json:"steps"    graph:"append"`
+	Facts    map[string]string `json:"facts"    graph:"merge"`
+	Approved bool              `json:"approved"`
+}
+
+// newCountGraph returns a graph incrementing the count until it reaches limit.
+func newCountGraph(limit int) *Graph[testState] {
+	g := New[testState]()
+	g.AddNode("increment", func(_ context.Context, s testState) (testState, error) {
+		return testState{Count: s.Count + 1, Steps: []string{"increment"}}, nil
+	})
+	g.AddNode("finish", func(_ context.Context, _ testState) (testState, error) {
+		return testState{Done: true, Steps: []string{"finish"}}, nil
+	})
+	g.AddEdge(Start, "increment")
+	g.AddConditionalEdge("increment", func(_ context.Context, s testState) (string, error) {
+		if s.Count >= limit {
+			return "finish", nil
+		}
+		return "increment", nil
+	})
+	return g
+}
+
+func TestWorkflowLoop(t *testing.T) {
+	t.Parallel()
+
+	workflow, err := newCountGraph(3).Compile()
+	require.NoError(t, err)
+	state, err := workflow.Invoke(context.Background(), testState{Steps: []string{"input"}})
+	require.NoError(t, err)
+	require.Equal(t, 3, state.Count)
+	require.True(t, state.Done)
+	require.Equal(t, []string{"input", "increment", "increment", "increment", "finish"}, state.Steps)
+
+	workflow, err = newCountGraph(100).Compile(WithMaxSteps(10))
+	require.NoError(t, err)
+	state, err = workflow.Invoke(context.Background(), testState{})
+	require.ErrorIs(t, err, ErrMaxSteps)
+	require.Equal(t, 10, state.Count)
+}
+
+func TestWorkflowErrors(t *testing.T) {
+	t.Parallel()
+
+	g := New[testState]()
+	g.AddNode("fail", func(context.Context, testState) (testState, error) {
+		return testState{}, errors.New("boom")
+	})
+	g.AddEdge(Start, "fail")
+	workflow, err := g.Compile()
+	require.NoError(t, err)
+	_, err = workflow.Invoke(context.Background(), testState{})
+	require.ErrorContains(t, err, `node "fail": boom`)
+
+	g = New[testState]()
+	g.AddNode("route", func(context.Context, testState) (testState, error) { return testState{}, nil })
+	g.AddEdge(Start, "route")
+	g.AddConditionalEdge("route", func(context.Context, testState) (string, error) { return "nowhere", nil })
+	workflow, err = g.Compile()
+	require.NoError(t, err)
+	_, err = workflow.Invoke(context.Background(), testState{})
+	require.ErrorIs(t, err, ErrUnknownNode)
+}
+
+func TestCompile(t *testing.T) {
+	t.Parallel()
+
+	noop := func(context.Context, testState) (testState, error) { return testState{}, nil }
+	for name, g := range map[string]*Graph[testState]{
+		"no start":      New[testState]().AddNode("a", noop),
+		"unknown to":    New[testState]().AddNode("a", noop).AddEdge(Start, "b"),
+		"unknown from":  New[testState]().AddNode("a", noop).AddEdge(Start, "a").AddEdge("b", "a"),
+		"reserved name": New[testState]().AddNode(End, noop).AddEdge(Start, End),
+		"duplicate":     New[testState]().AddNode("a", noop).AddNode("a", noop).AddEdge(Start, "a"),
+		"several edges": New[testState]().AddNode("a", noop).AddEdge(Start, "a").AddEdge("a", End).AddEdge("a", "a"),
+	} {
+		_, err := g.Compile()
+		require.ErrorIs(t, err, ErrInvalidGraph, name)
+	}
+
+	_, err := newCountGraph(1).Compile(WithInterruptBefore("finish"))
+	require.ErrorIs(t, err, ErrInvalidGraph)
+	_, err = newCountGraph(1).Compile(WithCheckpointer(NewMemoryCheckpointer()), WithInterruptBefore("unknown"))
+	require.ErrorIs(t, err, ErrInvalidGraph)
+}
+
+func TestWorkflowInterruptAndResume(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	checkpointer := NewMemoryCheckpointer()
+	workflow, err := newCountGraph(2).Compile(
+		WithCheckpointer(checkpointer),
+		WithInterruptBefore("finish"),
+	)
+	require.NoError(t, err)
+
+	_, err = workflow.Invoke(ctx, testState{})
+	require.ErrorIs(t, err, ErrThreadIDRequired)
+
+	state, err := workflow.Invoke(ctx, testState{}, WithThreadID("thread"))
+	require.ErrorIs(t, err, ErrInterrupted)
+	require.Equal(t, 2, state.Count)
+	require.False(t, state.Done)
+
+	state, err = workflow.UpdateState(ctx, "thread", testState{Approved: true})
+	require.NoError(t, err)
+	require.True(t, state.Approved)
+
+	state, err = workflow.Resume(ctx, "thread")
+	require.NoError(t, err)
+	require.True(t, state.Done)
+	require.True(t, state.Approved)
+	require.Equal(t, []string{"increment", "increment", "finish"}, state.Steps)
+
+	checkpoints, err := checkpointer.List(ctx, "thread")
+	require.NoError(t, err)
+	var nodes []string
+	for i, checkpoint := range checkpoints {
+		require.Equal(t, i+1, checkpoint.Step)
+		nodes = append(nodes, checkpoint.Node+">"+checkpoint.Next)
+	}
+	require.Equal(t, []string{
+		Start + ">increment", "increment>increment", "increment>finish", ">finish", "finish>" + End,
+	}, nodes)
+
+	// The next run of the thread continues from its state.
+	state, err = workflow.Invoke(ctx, testState{Facts: map[string]string{"run": "2"}}, WithThreadID("thread"))
+	require.ErrorIs(t, err, ErrInterrupted)
+	require.Equal(t, 3, state.Count)
+	require.Equal(t, map[string]string{"run": "2"}, state.Facts)
+
+	state, err = workflow.Resume(ctx, "thread")
+	require.NoError(t, err)
+	require.Equal(t, 3, state.Count)
+
+	// Resuming a finished run returns its state.
+	state, err = workflow.Resume(ctx, "thread")
+	require.NoError(t, err)
+	require.True(t, state.Done)
+	_, err = workflow.Resume(ctx, "unknown")
+	require.ErrorIs(t, err, ErrCheckpointNotFound)
+}
+
+func TestMergeFields(t *testing.T) {
+	t.Parallel()
+
+	state := testState{Count: 1, Steps: []string{"a"}, Facts: map[string]string{"x": "1", "y": "1"}}
+	merged := MergeFields(state, testState{Steps: []string{"b"}, Facts: map[string]string{"y": "2"}})
+	require.Equal(t, testState{
+		Count: 1,
+		Steps: []string{"a", "b"},
+		Facts: map[string]string{"x": "1", "y": "2"},
+	}, merged)
+	require.Equal(t, map[string]string{"x": "1", "y": "1"}, state.Facts)
+
+	require.Equal(t, map[string]any{"a": 1, "b": 3},
+		MergeFields(map[string]any{"a": 1, "b": 2}, map[string]any{"b": 3}))
+	require.Equal(t, "b", MergeFields("a", "b"))
+	require.Equal(t, "a", MergeFields("a", ""))
+}
+
+func TestMergeFieldsReset(t *testing.T) {
+	t.Parallel()
+
+	type state struct {
+		Reset
+		Count int
+		Done  bool
+		Steps []string `graph:"append"`
+	}
+	merged := MergeFields(state{Count: 2, Done: true, Steps: []string{"a"}},
+		state{Reset: Reset{"Done", "Steps", "Unknown"}, Steps: []string{"b"}})
+	require.Equal(t, state{Count: 2, Steps: []string{"b"}}, merged)
+}
+
+func TestChainNode(t *testing.T) {
+	t.Parallel()
+
+	upper := chains.NewTransform(func(_ context.Context, inputs map[string]any, _ ...chains.ChainCallOption) (map[string]any, error) { //nolint:lll
+		text, _ := inputs["text"].(string)
+		return map[string]any{"upper": strings.ToUpper(text)}, nil
+	}, []string{"text"}, []string{"upper"})
+
+	g := New[map[string]any]()
+	g.AddNode("upper", ChainNode(upper,
+		func(state map[string]any) map[string]any { return map[string]any{"text": state["question"]} },
+		func(outputs map[string]any) (map[string]any, error) {
+			return map[string]any{"answer": outputs["upper"]}, nil
+		}))
+	g.AddEdge(Start, "upper")
+	workflow, err := g.Compile()
+	require.NoError(t, err)
+
+	state, err := workflow.Invoke(context.Background(), map[string]any{"question": "why?"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"question": "why?", "answer": "WHY?"}, state)
+}
@@ -0,0 +1,42 @@
+package graph
+
+const _defaultMaxSteps = 25
+
+// Option is a function that configures the Options of a workflow.
+type Option func(*Options)
+
+// Options are the options of a workflow.
+type Options struct {
+	// MaxSteps is the maximum number of nodes a run executes, 25 by default.
+	MaxSteps int
+	// Checkpointer saves the state of the runs after each step. Without
+	// Checkpointer, runs can't be interrupted and resumed.
+	Checkpointer Checkpointer
+	// InterruptBefore are the nodes before which the runs are interrupted.
+	InterruptBefore []string
+}
+
+// WithMaxSteps sets the maximum number of nodes a run executes, before
+// failing with ErrMaxSteps. A run resumed after an interruption has its own
+// maximum.
+func WithMaxSteps(maxSteps int) Option {
+	return func(o *Options) {
+		o.MaxSteps = maxSteps
+	}
+}
+
+// WithCheckpointer sets the store of the checkpoints of the runs.
+func WithCheckpointer(checkpointer Checkpointer) Option {
+	return func(o *Options) {
+		o.Checkpointer = checkpointer
+	}
+}
+
+// WithInterruptBefore interrupts the runs before the nodes, with
+// ErrInterrupted, e.g. for a human to review the state. It requires a
+// Checkpointer.
+func WithInterruptBefore(nodes ...string) Option {
+	return func(o *Options) {
+		o.InterruptBefore = append(o.InterruptBefore, nodes...)
+	}
+}
@@ -0,0 +1,63 @@
+package sqlite3
+
+import (
+	"database/sql"
+)
+
+// Option is a functional argument that configures the Options.
+type Option func(*Options) error
+
+// Options is a set of options for the SQLite checkpointer.
+type Options struct {
+	// DB is the database of the checkpoints. If nil, the database at
+	// DBAddress is opened.
+	DB *sql.DB
+	// DBAddress is the address or file path of the database, ":memory:" by
+	// default.
+	DBAddress string
+	// TableName is the name of the table of the checkpoints.
+	TableName string
+}
+
+// WithDB specifies the database of the checkpoints.
+func WithDB(db *sql.DB) Option {
+	return func(o *Options) error {
+		o.DB = db
+
+		return nil
+	}
+}
+
+// WithDBAddress specifies the address or file path of the database of the
+// checkpoints, e.g. "checkpoints.db".
+func WithDBAddress(addr string) Option {
+	return func(o *Options) error {
+		o.DBAddress = addr
+
+		return nil
+	}
+}
+
+// WithTableName specifies the name of the table of the checkpoints.
+func WithTableName(name string) Option {
+	return func(o *Options) error {
+		o.TableName = name
+
+		return nil
+	}
+}
+
+func applyOptions(opts ...Option) (*Options, error) {
+	o := &Options{
+		DBAddress: ":memory:",
+		TableName: DefaultTableName,
+	}
+
+	for _, opt := range opts {
+		if err := opt(o); err != nil {
+			return nil, err
+		}
+	}
+
+	return o, nil
+}
@@ -0,0 +1,140 @@
+// Package sqlite3 provides a `graph.Checkpointer` storing checkpoints in a
+// SQLite database, so that the runs of workflows can be resumed after a
+// restart, or by another process.
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3" // sqlite3 driver.
+	"github.com/tmc/langchaingo/graph"
+)
+
+// DefaultTableName is the default name of the table of the checkpoints.
+const DefaultTableName = "langchaingo_graph_checkpoints"
+
+// _schema creates the table of the checkpoints. created is a Unix time in
+// nanoseconds.
+const _schema = `CREATE TABLE IF NOT EXISTS %s (
+	thread_id TEXT NOT NULL,
+	step INTEGER NOT NULL,
+	node TEXT NOT NULL,
+	next TEXT NOT NULL,
+	state TEXT NOT NULL,
+	created INTEGER NOT NULL,
+	PRIMARY KEY (thread_id, step)
+);`
+
+// SQLite is a SQLite `graph.Checkpointer`.
+type SQLite struct {
+	Options Options
+	db      *sql.DB
+	ownDB   bool
+}
+
+var _ graph.Checkpointer = (*SQLite)(nil)
+
+// New creates a new SQLite `graph.Checkpointer`, creating its table if
+// needed. The database is opened at the address set by WithDBAddress, unless
+// one is provided by WithDB.
+func New(ctx context.Context, opts ...Option) (*SQLite, error) {
+	options, err := applyOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &SQLite{Options: *options, db: options.DB}
+	if s.db == nil {
+		s.db, err = sql.Open("sqlite3", options.DBAddress)
+		if err != nil {
+			return nil, fmt.Errorf("open checkpoint database: %w", err)
+		}
+		s.ownDB = true
+		if options.DBAddress == ":memory:" {
+			// Each connection has its own in-memory database.
+			s.db.SetMaxOpenConns(1)
+		}
+	}
+	if _, err := s.db.ExecContext(ctx, fmt.Sprintf(_schema, options.TableName)); err != nil {
+		s.Close()
+		return nil, fmt.Errorf("create checkpoint table: %w", err)
+	}
+
+	return s, nil
+}
+
+// Put saves a checkpoint, replacing the checkpoint of the same step.
+func (s *SQLite) Put(ctx context.Context, checkpoint graph.Checkpoint) error {
+	_, err := s.db.ExecContext(ctx,
+		fmt.Sprintf(`INSERT OR REPLACE INTO %s (thread_id, step, node, next, state, created)
+			VALUES (?, ?, ?, ?, ?, ?)`, s.Options.TableName),
+		checkpoint.ThreadID, checkpoint.Step, checkpoint.Node, checkpoint.Next,
+		string(checkpoint.State), checkpoint.CreatedAt.UnixNano(),
+	)
+	if err != nil {
+		return fmt.Errorf("insert checkpoint: %w", err)
+	}
+	return nil
+}
+
+// Get returns the last checkpoint of a thread.
+func (s *SQLite) Get(ctx context.Context, threadID string) (graph.Checkpoint, error) {
+	row := s.db.QueryRowContext(ctx,
+		fmt.Sprintf(`SELECT thread_id, step, node, next, state, created FROM %s
+			WHERE thread_id = ? ORDER BY step DESC LIMIT 1`, s.Options.TableName),
+		threadID,
+	)
+	checkpoint, err := scanCheckpoint(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return graph.Checkpoint{}, graph.ErrCheckpointNotFound
+	}
+	return checkpoint, err
+}
+
+// List returns the checkpoints of a thread, by step.
+func (s *SQLite) List(ctx context.Context, threadID string) ([]graph.Checkpoint, error) {
+	rows, err := s.db.QueryContext(ctx,
+		fmt.Sprintf(`SELECT thread_id, step, node, next, state, created FROM %s
+			WHERE thread_id = ? ORDER BY step`, s.Options.TableName),
+		threadID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query checkpoints: %w", err)
+	}
+	defer rows.Close()
+
+	var checkpoints []graph.Checkpoint
+	for rows.Next() {
+		checkpoint, err := scanCheckpoint(rows)
+		if err != nil {
+			return nil, err
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	return checkpoints, rows.Err()
+}
+
+// Close closes the database, unless it was provided by WithDB.
+func (s *SQLite) Close() error {
+	if !s.ownDB {
+		return nil
+	}
+	return s.db.Close()
+}
+
+func scanCheckpoint(row interface{ Scan(dest ...any) error }) (graph.Checkpoint, error) {
+	var checkpoint graph.Checkpoint
+	var state string
+	var created int64
+	err := row.Scan(&checkpoint.ThreadID, &checkpoint.Step, &checkpoint.Node, &checkpoint.Next, &state, &created)
+	if err != nil {
+		return graph.Checkpoint{}, err
+	}
+	checkpoint.State = []byte(state)
+	checkpoint.CreatedAt = time.Unix(0, created)
+	return checkpoint, nil
+}
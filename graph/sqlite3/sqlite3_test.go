@@ -0,0 +1,93 @@
+package sqlite3
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/graph"
+)
+
+func TestSQLite(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	rq := require.New(t)
+	path := filepath.Join(t.TempDir(), "checkpoints.db")
+
+	checkpointer, err := New(ctx, WithDBAddress(path), WithTableName("checkpoints"))
+	rq.NoError(err)
+
+	_, err = checkpointer.Get(ctx, "thread")
+	rq.ErrorIs(err, graph.ErrCheckpointNotFound)
+
+	created := time.Unix(1700000000, 0)
+	for step, next := range []string{"a", graph.End} {
+		rq.NoError(checkpointer.Put(ctx, graph.Checkpoint{
+			ThreadID:  "thread",
+			Step:      step + 1,
+			Node:      graph.Start,
+			Next:      next,
+			State:     json.RawMessage(`{"count":1}`),
+			CreatedAt: created,
+		}))
+	}
+	rq.NoError(checkpointer.Close())
+
+	// checkpoints persist across instances.
+	checkpointer, err = New(ctx, WithDBAddress(path), WithTableName("checkpoints"))
+	rq.NoError(err)
+	defer checkpointer.Close()
+
+	last, err := checkpointer.Get(ctx, "thread")
+	rq.NoError(err)
+	rq.Equal(2, last.Step)
+	rq.Equal(graph.End, last.Next)
+	rq.JSONEq(`{"count":1}`, string(last.State))
+	rq.True(created.Equal(last.CreatedAt))
+
+	checkpoints, err := checkpointer.List(ctx, "thread")
+	rq.NoError(err)
+	rq.Len(checkpoints, 2)
+	rq.Equal("a", checkpoints[0].Next)
+}
+
+func TestSQLiteResume(t *testing.T) {
+	t.Parallel()
+
+	type state struct {
+		Count int `json:"count"`
+	}
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "checkpoints.db")
+	compile := func() *graph.Workflow[state] {
+		checkpointer, err := New(ctx, WithDBAddress(path))
+		require.NoError(t, err)
+		t.Cleanup(func() { checkpointer.Close() })
+
+		g := graph.New[state]()
+		g.AddNode("increment", func(_ context.Context, s state) (state, error) {
+			return state{Count: s.Count + 1}, nil
+		})
+		g.AddNode("review", func(_ context.Context, s state) (state, error) {
+			return state{Count: s.Count * 10}, nil
+		})
+		g.AddEdge(graph.Start, "increment")
+		g.AddEdge("increment", "review")
+		workflow, err := g.Compile(graph.WithCheckpointer(checkpointer), graph.WithInterruptBefore("review"))
+		require.NoError(t, err)
+		return workflow
+	}
+
+	s, err := compile().Invoke(ctx, state{Count: 1}, graph.WithThreadID("thread"))
+	require.ErrorIs(t, err, graph.ErrInterrupted)
+	require.Equal(t, 2, s.Count)
+
+	// Another workflow, e.g. of another process, resumes the run.
+	s, err = compile().Resume(ctx, "thread")
+	require.NoError(t, err)
+	require.Equal(t, 20, s.Count)
+}
@@ -0,0 +1,102 @@
+package graph
+
+import (
+	"reflect"
+)
+
+// MergeFields is the default reducer of the graphs. For struct states, each
+// non-zero field of the update replaces the field of the state, unless the
+// field is tagged to be merged:
+//
+//	type State struct {
+//		Question string                          // replaced
+//		Messages []llms.MessageContent `graph:"append"` // appended
+//		Facts    map[string]string     `graph:"merge"`  // keys replaced
+//	}
+//
+// Since zero fields of the update are left unchanged, the fields are cleared
+// with a Reset field, see Reset. Map states are merged key by key, and the
+// other states are replaced by non-zero updates. Unexported fields are
+// ignored.
+func MergeFields[S any](state, update S) S {
+	s := reflect.ValueOf(&state).Elem()
+	u := reflect.ValueOf(update)
+	switch s.Kind() { //nolint:exhaustive
+	case reflect.Struct:
+		resetFields(s, u)
+		for i := 0; i < s.NumField(); i++ {
+			field := s.Type().Field(i)
+			if !field.IsExported() || field.Type == resetType {
+				continue
+			}
+			mergeValue(s.Field(i), u.Field(i), field.Tag.Get("graph"))
+		}
+	case reflect.Map:
+		mergeValue(s, u, "merge")
+	default:
+		mergeValue(s, u, "")
+	}
+	return state
+}
+
+// Reset is the names of the fields of a struct state that an update resets
+// to their zero value, before the other fields of the update are merged by
+// MergeFields. Embedded in the state, it lets nodes clear fields, e.g. the
+// tool calls once they are run, or replace the fields that are appended:
+//
+//	type State struct {
+//		graph.Reset
+//		ToolCalls []llms.ToolCall
+//		Messages  []llms.MessageContent `graph:"append"`
+//	}
+//
+//	return State{Reset: graph.Reset{"ToolCalls"}, Messages: results}, nil
+//
+// The Reset field of the state itself is always empty.
+type Reset []string
+
+//nolint:gochecknoglobals
+var resetType = reflect.TypeOf(Reset(nil))
+
+// resetFields resets the fields of the state listed by the Reset fields of
+// the update.
+func resetFields(state, update reflect.Value) {
+	for i := 0; i < state.NumField(); i++ {
+		if state.Type().Field(i).Type != resetType {
+			continue
+		}
+		for _, name := range update.Field(i).Interface().(Reset) {
+			field, ok := state.Type().FieldByName(name)
+			if ok && field.IsExported() && len(field.Index) == 1 {
+				value := state.Field(field.Index[0])
+				value.Set(reflect.Zero(value.Type()))
+			}
+		}
+	}
+}
+
+// mergeValue merges the update into the settable value, according to the
+// tag of its field.
+func mergeValue(value, update reflect.Value, tag string) {
+	if update.IsZero() {
+		return
+	}
+	switch {
+	case tag == "append" && value.Kind() == reflect.Slice:
+		// A new slice, so that the states don't share arrays.
+		merged := reflect.MakeSlice(value.Type(), 0, value.Len()+update.Len())
+		merged = reflect.AppendSlice(merged, value)
+		value.Set(reflect.AppendSlice(merged, update))
+	case tag == "merge" && value.Kind() == reflect.Map:
+		merged := reflect.MakeMapWithSize(value.Type(), value.Len()+update.Len())
+		for iter := value.MapRange(); iter.Next(); {
+			merged.SetMapIndex(iter.Key(), iter.Value())
+		}
+		for iter := update.MapRange(); iter.Next(); {
+			merged.SetMapIndex(iter.Key(), iter.Value())
+		}
+		value.Set(merged)
+	default:
+		value.Set(update)
+	}
+}
@@ -0,0 +1,185 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// ErrThreadIDRequired is returned when running a workflow interrupting its
+// runs without thread ID, see WithThreadID.
+var ErrThreadIDRequired = errors.New("thread ID required")
+
+// Workflow is a compiled graph, running its nodes from Start to End.
+type Workflow[S any] struct {
+	graph   *Graph[S]
+	options Options
+}
+
+// RunOption is a function that configures a run.
+type RunOption func(*runOptions)
+
+type runOptions struct {
+	threadID string
+}
+
+// WithThreadID sets the thread of a run. The runs of a thread share their
+// state, which is checkpointed after each step with the Checkpointer of the
+// workflow.
+func WithThreadID(threadID string) RunOption {
+	return func(o *runOptions) {
+		o.threadID = threadID
+	}
+}
+
+// Invoke runs the workflow from Start with the input state, and returns the
+// final state. The runs of a thread continue from the last state of the
+// thread, merged with the input.
+//
+// If the run is interrupted before a node, the state is returned with
+// ErrInterrupted, and the run is continued with Resume.
+func (w *Workflow[S]) Invoke(ctx context.Context, input S, opts ...RunOption) (S, error) {
+	o := w.runOptions(opts)
+	if len(w.options.InterruptBefore) > 0 && o.threadID == "" {
+		return input, ErrThreadIDRequired
+	}
+
+	state := input
+	step := 0
+	if w.checkpointed(o) {
+		last, err := w.options.Checkpointer.Get(ctx, o.threadID)
+		switch {
+		case errors.Is(err, ErrCheckpointNotFound):
+		case err != nil:
+			return input, fmt.Errorf("get checkpoint: %w", err)
+		default:
+			var lastState S
+			if err := json.Unmarshal(last.State, &lastState); err != nil {
+				return input, fmt.Errorf("decode checkpoint state: %w", err)
+			}
+			state = w.graph.reducer(lastState, input)
+			step = last.Step
+		}
+	}
+
+	next, err := w.graph.next(ctx, Start, state)
+	if err != nil {
+		return state, err
+	}
+	step++
+	if err := w.checkpoint(ctx, o, step, Start, next, state); err != nil {
+		return state, err
+	}
+	return w.run(ctx, o, state, next, step, false)
+}
+
+// Resume continues the interrupted or failed run of a thread from its last
+// checkpoint, and returns the final state. The state can be updated before,
+// with UpdateState.
+func (w *Workflow[S]) Resume(ctx context.Context, threadID string) (S, error) {
+	state, checkpoint, err := w.State(ctx, threadID)
+	if err != nil {
+		return state, err
+	}
+	return w.run(ctx, runOptions{threadID: threadID}, state, checkpoint.Next, checkpoint.Step, true)
+}
+
+// State returns the last state of a thread, and its checkpoint.
+func (w *Workflow[S]) State(ctx context.Context, threadID string) (S, Checkpoint, error) {
+	var state S
+	if w.options.Checkpointer == nil {
+		return state, Checkpoint{}, fmt.Errorf("%w: no checkpointer", ErrCheckpointNotFound)
+	}
+	checkpoint, err := w.options.Checkpointer.Get(ctx, threadID)
+	if err != nil {
+		return state, Checkpoint{}, err
+	}
+	if err := json.Unmarshal(checkpoint.State, &state); err != nil {
+		return state, Checkpoint{}, fmt.Errorf("decode checkpoint state: %w", err)
+	}
+	return state, checkpoint, nil
+}
+
+// UpdateState merges an update into the last state of a thread, e.g. the
+// corrections of a human reviewing an interrupted run, and checkpoints it.
+// The checkpoint of the update has no Node.
+func (w *Workflow[S]) UpdateState(ctx context.Context, threadID string, update S) (S, error) {
+	state, checkpoint, err := w.State(ctx, threadID)
+	if err != nil {
+		return state, err
+	}
+	state = w.graph.reducer(state, update)
+	o := runOptions{threadID: threadID}
+	return state, w.checkpoint(ctx, o, checkpoint.Step+1, "", checkpoint.Next, state)
+}
+
+// run runs the nodes from next, after the step. The interruption before the
+// first node of a resumed run is skipped.
+func (w *Workflow[S]) run(ctx context.Context, o runOptions, state S, next string, step int, resumed bool) (S, error) {
+	for steps := 0; next != End; steps++ {
+		if err := ctx.Err(); err != nil {
+			return state, err
+		}
+		if steps >= w.options.MaxSteps {
+			return state, fmt.Errorf("%w: %d", ErrMaxSteps, w.options.MaxSteps)
+		}
+		if slices.Contains(w.options.InterruptBefore, next) && !(resumed && steps == 0) {
+			return state, fmt.Errorf("%w before node %q", ErrInterrupted, next)
+		}
+
+		node := next
+		update, err := w.graph.nodes[node](ctx, state)
+		if err != nil {
+			return state, fmt.Errorf("node %q: %w", node, err)
+		}
+		state = w.graph.reducer(state, update)
+		next, err = w.graph.next(ctx, node, state)
+		if err != nil {
+			return state, err
+		}
+
+		step++
+		if err := w.checkpoint(ctx, o, step, node, next, state); err != nil {
+			return state, err
+		}
+	}
+	return state, nil
+}
+
+func (w *Workflow[S]) runOptions(opts []RunOption) runOptions {
+	var o runOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// checkpointed reports whether the runs with the options are checkpointed.
+func (w *Workflow[S]) checkpointed(o runOptions) bool {
+	return w.options.Checkpointer != nil && o.threadID != ""
+}
+
+func (w *Workflow[S]) checkpoint(ctx context.Context, o runOptions, step int, node, next string, state S) error {
+	if !w.checkpointed(o) {
+		return nil
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("encode checkpoint state: %w", err)
+	}
+	err = w.options.Checkpointer.Put(ctx, Checkpoint{
+		ThreadID:  o.threadID,
+		Step:      step,
+		Node:      node,
+		Next:      next,
+		State:     data,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("put checkpoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+// Package indexing keeps a vector store in sync with a set of source
+// documents.
+//
+// The Manager hashes the content and metadata of every document and uses a
+// RecordManager to remember which documents were written to the vector store
+// and when. Re-indexing the same documents only writes the ones that changed,
+// and the cleanup modes delete the documents that are no longer produced by
+// their source:
+//
+//   - CleanupNone never deletes documents.
+//   - CleanupIncremental deletes the stale documents of the sources that are
+//     being indexed, identified by a metadata key set with WithSourceIDKey.
+//   - CleanupFull deletes every document that was not part of the indexing
+//     run.
+//
+// Record managers are provided for memory, SQLite and PostgreSQL. The vector
+// store must implement vectorstores.DocumentUpserter, and
+// vectorstores.DocumentDeleter to clean up stale documents.
+package indexing
@@ -0,0 +1,292 @@
+package indexing
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+var (
+	// ErrInvalidCleanupMode is returned for an unknown cleanup mode.
+	ErrInvalidCleanupMode = errors.New("invalid cleanup mode")
+	// ErrMissingSourceIDKey is returned when CleanupIncremental is used
+	// without a source id key.
+	ErrMissingSourceIDKey = errors.New("incremental cleanup requires a source id key")
+	// ErrMissingSourceID is returned when a document has no source id and the
+	// cleanup mode requires one.
+	ErrMissingSourceID = errors.New("document has no source id")
+)
+
+// namespaceUUID is the namespace of the document ids derived from their hash.
+var namespaceUUID = uuid.MustParse("5b6f6f2e-6a39-4b8e-a47c-3f2b8d7e1c90")
+
+// Result counts what an indexing run did.
+type Result struct {
+	// NumAdded is the number of documents written to the vector store.
+	NumAdded int
+	// NumUpdated is the number of already indexed documents written again
+	// because of WithForceUpdate.
+	NumUpdated int
+	// NumSkipped is the number of documents that were already indexed, or
+	// duplicated in the run.
+	NumSkipped int
+	// NumDeleted is the number of stale documents deleted from the vector
+	// store.
+	NumDeleted int
+}
+
+// Loader loads documents from a source. It is implemented by the loaders of
+// the documentloaders package.
+type Loader interface {
+	Load(ctx context.Context) ([]schema.Document, error)
+	LoadAndSplit(ctx context.Context, splitter textsplitter.TextSplitter) ([]schema.Document, error)
+}
+
+// Manager indexes documents into a vector store, using a record manager to
+// skip the documents that are already indexed and to delete stale ones.
+type Manager struct {
+	store   vectorstores.VectorStore
+	records RecordManager
+}
+
+// NewManager creates a Manager writing to store and tracking the written
+// documents with records. The record manager must be dedicated to the vector
+// store, or to the collection or name space of the vector store being indexed.
+func NewManager(store vectorstores.VectorStore, records RecordManager) (*Manager, error) {
+	if _, ok := store.(vectorstores.DocumentUpserter); !ok {
+		return nil, fmt.Errorf("%w: vector store can't upsert documents", vectorstores.ErrNotSupported)
+	}
+	return &Manager{
+		store:   store,
+		records: records,
+	}, nil
+}
+
+// IndexLoader loads the documents of loader, splits them if a splitter is set
+// with WithSplitter, and indexes them. See Index.
+func (m *Manager) IndexLoader(ctx context.Context, loader Loader, opts ...Option) (Result, error) {
+	o := getOptions(opts...)
+
+	var (
+		docs []schema.Document
+		err  error
+	)
+	if o.splitter != nil {
+		docs, err = loader.LoadAndSplit(ctx, o.splitter)
+	} else {
+		docs, err = loader.Load(ctx)
+	}
+	if err != nil {
+		return Result{}, err
+	}
+	return m.Index(ctx, docs, opts...)
+}
+
+// Index writes the documents that are not indexed yet to the vector store,
+// and deletes the stale documents according to the cleanup mode.
+//
+// The ID of each document is replaced with a UUID derived from the hash of
+// its content and metadata, so the same document always gets the same ID.
+// The IDs returned by the vector store are recorded, so the stale documents
+// are deleted even from stores that rewrite the IDs, e.g. with a key prefix.
+func (m *Manager) Index(ctx context.Context, docs []schema.Document, opts ...Option) (Result, error) {
+	o := getOptions(opts...)
+	if err := m.validate(o); err != nil {
+		return Result{}, err
+	}
+
+	start, err := m.records.GetTime(ctx)
+	if err != nil {
+		return Result{}, fmt.Errorf("get record manager time: %w", err)
+	}
+
+	var result Result
+	for i := 0; i < len(docs); i += o.batchSize {
+		batch := docs[i:min(i+o.batchSize, len(docs))]
+		if err := m.indexBatch(ctx, o, batch, start, &result); err != nil {
+			return result, err
+		}
+	}
+
+	if o.cleanup == CleanupFull {
+		if err := m.cleanup(ctx, o, ListOptions{Before: start, Limit: o.batchSize}, &result); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+func (m *Manager) validate(o options) error {
+	switch o.cleanup {
+	case CleanupNone:
+		return nil
+	case CleanupIncremental:
+		if o.sourceIDKey == "" {
+			return ErrMissingSourceIDKey
+		}
+	case CleanupFull:
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidCleanupMode, o.cleanup)
+	}
+	if _, ok := m.store.(vectorstores.DocumentDeleter); !ok {
+		return fmt.Errorf("%w: vector store can't delete documents", vectorstores.ErrNotSupported)
+	}
+	return nil
+}
+
+func (m *Manager) indexBatch(ctx context.Context, o options, batch []schema.Document, start time.Time, result *Result) error {
+	keys := make([]string, 0, len(batch))
+	sourceIDs := make([]string, 0, len(batch))
+	hashed := make([]schema.Document, 0, len(batch))
+	seen := make(map[string]bool, len(batch))
+	for _, doc := range batch {
+		key, err := hashDocument(doc)
+		if err != nil {
+			return err
+		}
+		if seen[key] {
+			result.NumSkipped++
+			continue
+		}
+		seen[key] = true
+
+		sourceID, err := getSourceID(o, doc)
+		if err != nil {
+			return err
+		}
+		doc.ID = key
+		keys = append(keys, key)
+		sourceIDs = append(sourceIDs, sourceID)
+		hashed = append(hashed, doc)
+	}
+
+	exists, err := m.records.Exists(ctx, keys)
+	if err != nil {
+		return fmt.Errorf("check indexed documents: %w", err)
+	}
+
+	toIndex := make([]schema.Document, 0, len(hashed))
+	indexed := make([]int, 0, len(hashed))
+	for i, doc := range hashed {
+		switch {
+		case !exists[i]:
+			result.NumAdded++
+		case o.forceUpdate:
+			result.NumUpdated++
+		default:
+			result.NumSkipped++
+			continue
+		}
+		toIndex = append(toIndex, doc)
+		indexed = append(indexed, i)
+	}
+
+	storeIDs := make([]string, len(keys))
+	if len(toIndex) > 0 {
+		ids, err := vectorstores.UpsertDocuments(ctx, m.store, toIndex, o.storeOptions...)
+		if err != nil {
+			return fmt.Errorf("write documents: %w", err)
+		}
+		if len(ids) != len(toIndex) {
+			return fmt.Errorf("write documents: vector store returned %d ids for %d documents", len(ids), len(toIndex))
+		}
+		for i, id := range ids {
+			storeIDs[indexed[i]] = id
+		}
+	}
+
+	var groupIDs []string
+	if o.sourceIDKey != "" {
+		groupIDs = sourceIDs
+	}
+	if err := m.records.Update(ctx, keys, groupIDs, storeIDs, start); err != nil {
+		return fmt.Errorf("update records: %w", err)
+	}
+
+	if o.cleanup != CleanupIncremental {
+		return nil
+	}
+	sources := slices.Clone(sourceIDs)
+	slices.Sort(sources)
+	sources = slices.Compact(sources)
+	return m.cleanup(ctx, o, ListOptions{Before: start, GroupIDs: sources, Limit: o.batchSize}, result)
+}
+
+// cleanup deletes the documents whose records match list from the vector
+// store and the record manager.
+func (m *Manager) cleanup(ctx context.Context, o options, list ListOptions, result *Result) error {
+	for {
+		keys, err := m.records.ListKeys(ctx, list)
+		if err != nil {
+			return fmt.Errorf("list stale records: %w", err)
+		}
+		if len(keys) == 0 {
+			return nil
+		}
+		ids, err := m.records.GetStoreIDs(ctx, keys)
+		if err != nil {
+			return fmt.Errorf("get stale document ids: %w", err)
+		}
+		if err := vectorstores.DeleteDocuments(ctx, m.store, ids, o.storeOptions...); err != nil {
+			return fmt.Errorf("delete stale documents: %w", err)
+		}
+		if err := m.records.DeleteKeys(ctx, keys); err != nil {
+			return fmt.Errorf("delete stale records: %w", err)
+		}
+		result.NumDeleted += len(keys)
+	}
+}
+
+// hashDocument returns a UUID derived from the content and metadata of doc.
+func hashDocument(doc schema.Document) (string, error) {
+	metadata, err := json.Marshal(doc.Metadata)
+	if err != nil {
+		return "", fmt.Errorf("hash document metadata: %w", err)
+	}
+	h := sha256.New()
+	h.Write([]byte(doc.PageContent))
+	h.Write([]byte{0})
+	h.Write(metadata)
+	return uuid.NewSHA1(namespaceUUID, []byte(hex.EncodeToString(h.Sum(nil)))).String(), nil
+}
+
+func getSourceID(o options, doc schema.Document) (string, error) {
+	if o.sourceIDKey == "" {
+		return "", nil
+	}
+	v, ok := doc.Metadata[o.sourceIDKey]
+	if !ok || v == nil {
+		if o.cleanup == CleanupIncremental {
+			return "", fmt.Errorf("%w: metadata key %q is missing", ErrMissingSourceID, o.sourceIDKey)
+		}
+		return "", nil
+	}
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	return fmt.Sprint(v), nil
+}
+
+func getOptions(opts ...Option) options {
+	o := options{
+		cleanup:   CleanupNone,
+		batchSize: defaultBatchSize,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.batchSize <= 0 {
+		o.batchSize = defaultBatchSize
+	}
+	return o
+}
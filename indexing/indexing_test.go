@@ -0,0 +1,231 @@
+package indexing
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+	"github.com/tmc/langchaingo/vectorstores"
+	"github.com/tmc/langchaingo/vectorstores/inmemory"
+)
+
+// countingEmbedder embeds texts by their length and counts the embedded texts.
+type countingEmbedder struct {
+	embedded int
+}
+
+func (e *countingEmbedder) EmbedDocuments(_ context.Context, texts []string) ([][]float32, error) {
+	e.embedded += len(texts)
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = []float32{float32(len(text)), 1}
+	}
+	return vectors, nil
+}
+
+func (e *countingEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	return []float32{float32(len(text)), 1}, nil
+}
+
+type testLoader []schema.Document
+
+func (l testLoader) Load(context.Context) ([]schema.Document, error) {
+	return l, nil
+}
+
+func (l testLoader) LoadAndSplit(ctx context.Context, splitter textsplitter.TextSplitter) ([]schema.Document, error) {
+	return textsplitter.SplitDocuments(splitter, l)
+}
+
+func doc(content, source string) schema.Document {
+	return schema.Document{PageContent: content, Metadata: map[string]any{"source": source}}
+}
+
+func newTestManager(t *testing.T) (*Manager, *inmemory.Store, *countingEmbedder, *MemoryRecordManager) {
+	t.Helper()
+
+	e := &countingEmbedder{}
+	store, err := inmemory.New(inmemory.WithEmbedder(e))
+	require.NoError(t, err)
+
+	records := NewMemoryRecordManager()
+	now := time.Unix(1700000000, 0)
+	records.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	m, err := NewManager(store, records)
+	require.NoError(t, err)
+	return m, store, e, records
+}
+
+func storedContents(t *testing.T, store *inmemory.Store) []string {
+	t.Helper()
+
+	docs, err := store.SimilaritySearch(context.Background(), "", 100)
+	require.NoError(t, err)
+	contents := make([]string, len(docs))
+	for i, d := range docs {
+		contents[i] = d.PageContent
+	}
+	sort.Strings(contents)
+	return contents
+}
+
+func TestIndexCleanupNone(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	m, store, e, _ := newTestManager(t)
+
+	docs := []schema.Document{doc("tokyo", "a"), doc("paris", "a"), doc("tokyo", "a")}
+	result, err := m.Index(ctx, docs)
+	require.NoError(t, err)
+	require.Equal(t, Result{NumAdded: 2, NumSkipped: 1}, result)
+
+	result, err = m.Index(ctx, docs)
+	require.NoError(t, err)
+	require.Equal(t, Result{NumSkipped: 3}, result)
+	require.Equal(t, 2, e.embedded)
+
+	result, err = m.Index(ctx, docs[:1], WithForceUpdate())
+	require.NoError(t, err)
+	require.Equal(t, Result{NumUpdated: 1}, result)
+	require.Equal(t, 3, e.embedded)
+
+	result, err = m.Index(ctx, []schema.Document{doc("london", "b")})
+	require.NoError(t, err)
+	require.Equal(t, Result{NumAdded: 1}, result)
+	require.Equal(t, []string{"london", "paris", "tokyo"}, storedContents(t, store))
+}
+
+func TestIndexCleanupIncremental(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	m, store, _, _ := newTestManager(t)
+	opts := []Option{WithCleanup(CleanupIncremental), WithSourceIDKey("source"), WithBatchSize(2)}
+
+	_, err := m.Index(ctx, []schema.Document{doc("tokyo", "a")}, WithCleanup(CleanupIncremental))
+	require.ErrorIs(t, err, ErrMissingSourceIDKey)
+	_, err = m.Index(ctx, []schema.Document{{PageContent: "tokyo"}}, opts...)
+	require.ErrorIs(t, err, ErrMissingSourceID)
+
+	result, err := m.Index(ctx, []schema.Document{
+		doc("tokyo", "a"), doc("kyoto", "a"), doc("paris", "b"),
+	}, opts...)
+	require.NoError(t, err)
+	require.Equal(t, Result{NumAdded: 3}, result)
+
+	// Source a changed, source b is not re-indexed so it is left alone.
+	result, err = m.Index(ctx, []schema.Document{doc("tokyo", "a"), doc("osaka", "a")}, opts...)
+	require.NoError(t, err)
+	require.Equal(t, Result{NumAdded: 1, NumSkipped: 1, NumDeleted: 1}, result)
+	require.Equal(t, []string{"osaka", "paris", "tokyo"}, storedContents(t, store))
+}
+
+func TestIndexCleanupFull(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	m, store, _, records := newTestManager(t)
+	opts := []Option{WithCleanup(CleanupFull), WithBatchSize(1)}
+
+	result, err := m.IndexLoader(ctx, testLoader{
+		doc("tokyo", "a"), doc("kyoto", "a"), doc("paris", "b"),
+	}, opts...)
+	require.NoError(t, err)
+	require.Equal(t, Result{NumAdded: 3}, result)
+
+	result, err = m.IndexLoader(ctx, testLoader{doc("tokyo", "a"), doc("london", "c")}, opts...)
+	require.NoError(t, err)
+	require.Equal(t, Result{NumAdded: 1, NumSkipped: 1, NumDeleted: 2}, result)
+	require.Equal(t, []string{"london", "tokyo"}, storedContents(t, store))
+
+	keys, err := records.ListKeys(ctx, ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+
+	docs, err := store.GetDocuments(ctx, keys)
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+}
+
+// prefixStore stores the documents under their ID with a prefix, as
+// redisvector does.
+type prefixStore struct {
+	*inmemory.Store
+}
+
+func (s prefixStore) UpsertDocuments(ctx context.Context, docs []schema.Document, options ...vectorstores.Option) ([]string, error) {
+	prefixed := make([]schema.Document, len(docs))
+	for i, d := range docs {
+		d.ID = "doc:" + d.ID
+		prefixed[i] = d
+	}
+	return s.Store.UpsertDocuments(ctx, prefixed, options...)
+}
+
+func TestIndexCleanupWithRewrittenIDs(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	_, store, _, records := newTestManager(t)
+	m, err := NewManager(prefixStore{store}, records)
+	require.NoError(t, err)
+	opts := []Option{WithCleanup(CleanupIncremental), WithSourceIDKey("source")}
+
+	_, err = m.Index(ctx, []schema.Document{doc("tokyo", "a"), doc("kyoto", "a")}, opts...)
+	require.NoError(t, err)
+
+	result, err := m.Index(ctx, []schema.Document{doc("tokyo", "a")}, opts...)
+	require.NoError(t, err)
+	require.Equal(t, Result{NumSkipped: 1, NumDeleted: 1}, result)
+	require.Equal(t, []string{"tokyo"}, storedContents(t, store))
+
+	result, err = m.Index(ctx, nil, WithCleanup(CleanupFull))
+	require.NoError(t, err)
+	require.Equal(t, Result{NumDeleted: 1}, result)
+	require.Empty(t, storedContents(t, store))
+}
+
+func TestIndexLoaderWithSplitter(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	m, store, _, _ := newTestManager(t)
+
+	splitter := textsplitter.NewRecursiveCharacter(
+		textsplitter.WithChunkSize(5),
+		textsplitter.WithChunkOverlap(0),
+		textsplitter.WithSeparators([]string{" "}),
+	)
+	result, err := m.IndexLoader(ctx, testLoader{doc("tokyo paris", "a")},
+		WithSplitter(splitter),
+		WithVectorStoreOptions(vectorstores.WithNameSpace("cities")))
+	require.NoError(t, err)
+	require.Equal(t, Result{NumAdded: 2}, result)
+	require.Equal(t, 2, store.Len("cities"))
+}
+
+func TestNewManagerRequiresUpsert(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewManager(addOnlyStore{}, NewMemoryRecordManager())
+	require.ErrorIs(t, err, vectorstores.ErrNotSupported)
+}
+
+type addOnlyStore struct{}
+
+func (addOnlyStore) AddDocuments(context.Context, []schema.Document, ...vectorstores.Option) ([]string, error) {
+	return nil, nil
+}
+
+func (addOnlyStore) SimilaritySearch(context.Context, string, int, ...vectorstores.Option) ([]schema.Document, error) {
+	return nil, nil
+}
@@ -0,0 +1,86 @@
+package indexing
+
+import (
+	"github.com/tmc/langchaingo/textsplitter"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+const defaultBatchSize = 100
+
+// CleanupMode decides which stale documents are deleted from the vector store
+// by an indexing run.
+type CleanupMode string
+
+const (
+	// CleanupNone never deletes documents.
+	CleanupNone CleanupMode = "none"
+	// CleanupIncremental deletes the documents of the indexed sources that
+	// were not part of the run, as each batch is indexed. It requires a
+	// source id key.
+	CleanupIncremental CleanupMode = "incremental"
+	// CleanupFull deletes every document that was not part of the run once
+	// all documents are indexed. The run must be given every document of the
+	// vector store, or the missing ones are deleted.
+	CleanupFull CleanupMode = "full"
+)
+
+// Option is a function that configures an indexing run.
+type Option func(*options)
+
+type options struct {
+	cleanup      CleanupMode
+	sourceIDKey  string
+	batchSize    int
+	forceUpdate  bool
+	splitter     textsplitter.TextSplitter
+	storeOptions []vectorstores.Option
+}
+
+// WithCleanup sets the cleanup mode. Defaults to CleanupNone.
+func WithCleanup(mode CleanupMode) Option {
+	return func(o *options) {
+		o.cleanup = mode
+	}
+}
+
+// WithSourceIDKey sets the metadata key identifying the source a document
+// was loaded from, such as "source" for file based loaders. Required with
+// CleanupIncremental.
+func WithSourceIDKey(key string) Option {
+	return func(o *options) {
+		o.sourceIDKey = key
+	}
+}
+
+// WithBatchSize sets the number of documents written to the vector store at
+// once. Defaults to 100.
+func WithBatchSize(size int) Option {
+	return func(o *options) {
+		o.batchSize = size
+	}
+}
+
+// WithForceUpdate writes every document to the vector store, even the ones
+// that are already indexed. It is useful to re-embed the documents with a new
+// embedder.
+func WithForceUpdate() Option {
+	return func(o *options) {
+		o.forceUpdate = true
+	}
+}
+
+// WithSplitter sets the text splitter used by IndexLoader to split the loaded
+// documents.
+func WithSplitter(splitter textsplitter.TextSplitter) Option {
+	return func(o *options) {
+		o.splitter = splitter
+	}
+}
+
+// WithVectorStoreOptions sets the options passed to the vector store, such as
+// its name space.
+func WithVectorStoreOptions(opts ...vectorstores.Option) Option {
+	return func(o *options) {
+		o.storeOptions = opts
+	}
+}
@@ -0,0 +1,177 @@
+package indexing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ErrClockDrift is returned by RecordManager.Update when the clock of the
+// record manager is behind the time the indexing run started at.
+var ErrClockDrift = errors.New("record manager time is behind the indexing start time")
+
+// RecordManager keeps track of the documents written to a vector store. Each
+// record is identified by a key, the hash of a document, belongs to a group,
+// the source the document was loaded from, and holds the ID the vector store
+// gave the document.
+type RecordManager interface {
+	// GetTime returns the current time of the record manager. The times of
+	// the records are compared with it, so it must be taken from the same
+	// clock.
+	GetTime(ctx context.Context) (time.Time, error)
+	// Update inserts or updates the records with the given keys, setting
+	// their group, store ID and update time. groupIDs is either nil or holds
+	// one group per key; an empty group means the record has none. storeIDs
+	// is either nil or holds one store ID per key; an empty store ID keeps the
+	// one of the record. It returns ErrClockDrift if the current time is
+	// before timeAtLeast.
+	Update(ctx context.Context, keys, groupIDs, storeIDs []string, timeAtLeast time.Time) error
+	// Exists reports whether a record exists for each of the keys.
+	Exists(ctx context.Context, keys []string) ([]bool, error)
+	// ListKeys returns the keys of the records matching the options.
+	ListKeys(ctx context.Context, opts ListOptions) ([]string, error)
+	// GetStoreIDs returns the store ID of the record of each key, or the key
+	// itself if the record has no store ID or doesn't exist.
+	GetStoreIDs(ctx context.Context, keys []string) ([]string, error)
+	// DeleteKeys deletes the records with the given keys.
+	DeleteKeys(ctx context.Context, keys []string) error
+}
+
+// ListOptions filters the keys returned by RecordManager.ListKeys.
+type ListOptions struct {
+	// Before only matches records updated before this time, if set.
+	Before time.Time
+	// GroupIDs only matches records in one of these groups, if set.
+	GroupIDs []string
+	// Limit is the maximum number of keys returned, if positive.
+	Limit int
+}
+
+func checkGroupIDs(keys, groupIDs, storeIDs []string) error {
+	if groupIDs != nil && len(groupIDs) != len(keys) {
+		return fmt.Errorf("got %d group ids for %d keys", len(groupIDs), len(keys))
+	}
+	if storeIDs != nil && len(storeIDs) != len(keys) {
+		return fmt.Errorf("got %d store ids for %d keys", len(storeIDs), len(keys))
+	}
+	return nil
+}
+
+// MemoryRecordManager is a RecordManager that keeps the records in memory.
+// It is meant for tests and for vector stores that don't outlive the process,
+// such as an in-memory vector store. It is safe for concurrent use.
+type MemoryRecordManager struct {
+	mu      sync.RWMutex
+	records map[string]memoryRecord
+	now     func() time.Time
+}
+
+type memoryRecord struct {
+	groupID   string
+	storeID   string
+	updatedAt time.Time
+}
+
+var _ RecordManager = (*MemoryRecordManager)(nil)
+
+// NewMemoryRecordManager creates an empty MemoryRecordManager.
+func NewMemoryRecordManager() *MemoryRecordManager {
+	return &MemoryRecordManager{
+		records: map[string]memoryRecord{},
+		now:     time.Now,
+	}
+}
+
+// GetTime returns the current time.
+func (m *MemoryRecordManager) GetTime(_ context.Context) (time.Time, error) {
+	return m.now(), nil
+}
+
+// Update inserts or updates the records with the given keys.
+func (m *MemoryRecordManager) Update(_ context.Context, keys, groupIDs, storeIDs []string, timeAtLeast time.Time) error {
+	if err := checkGroupIDs(keys, groupIDs, storeIDs); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	if now.Before(timeAtLeast) {
+		return ErrClockDrift
+	}
+	for i, key := range keys {
+		r := memoryRecord{storeID: m.records[key].storeID, updatedAt: now}
+		if groupIDs != nil {
+			r.groupID = groupIDs[i]
+		}
+		if storeIDs != nil && storeIDs[i] != "" {
+			r.storeID = storeIDs[i]
+		}
+		m.records[key] = r
+	}
+	return nil
+}
+
+// Exists reports whether a record exists for each of the keys.
+func (m *MemoryRecordManager) Exists(_ context.Context, keys []string) ([]bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	exists := make([]bool, len(keys))
+	for i, key := range keys {
+		_, exists[i] = m.records[key]
+	}
+	return exists, nil
+}
+
+// ListKeys returns the keys of the records matching the options, sorted.
+func (m *MemoryRecordManager) ListKeys(_ context.Context, opts ListOptions) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	keys := []string{}
+	for key, r := range m.records {
+		if !opts.Before.IsZero() && !r.updatedAt.Before(opts.Before) {
+			continue
+		}
+		if opts.GroupIDs != nil && !slices.Contains(opts.GroupIDs, r.groupID) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	if opts.Limit > 0 && len(keys) > opts.Limit {
+		keys = keys[:opts.Limit]
+	}
+	return keys, nil
+}
+
+// GetStoreIDs returns the store ID of the record of each key.
+func (m *MemoryRecordManager) GetStoreIDs(_ context.Context, keys []string) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	storeIDs := make([]string, len(keys))
+	for i, key := range keys {
+		storeIDs[i] = key
+		if r, ok := m.records[key]; ok && r.storeID != "" {
+			storeIDs[i] = r.storeID
+		}
+	}
+	return storeIDs, nil
+}
+
+// DeleteKeys deletes the records with the given keys.
+func (m *MemoryRecordManager) DeleteKeys(_ context.Context, keys []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, key := range keys {
+		delete(m.records, key)
+	}
+	return nil
+}
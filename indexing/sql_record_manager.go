@@ -0,0 +1,327 @@
+package indexing
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultRecordsTableName is the default name of the table holding the
+// records of a SQLRecordManager.
+const DefaultRecordsTableName = "langchaingo_index_records"
+
+// maxQueryKeys limits the number of keys bound in a single statement, to stay
+// below the parameter limits of the databases.
+const maxQueryKeys = 500
+
+// ErrInvalidNamespace is returned when a SQLRecordManager is created without
+// a namespace.
+var ErrInvalidNamespace = errors.New("missing namespace")
+
+// Dialect is the SQL dialect spoken by the database of a SQLRecordManager.
+type Dialect int
+
+const (
+	// SQLite is the dialect of SQLite, e.g. with the github.com/mattn/go-sqlite3
+	// driver.
+	SQLite Dialect = iota
+	// Postgres is the dialect of PostgreSQL, e.g. with the
+	// github.com/jackc/pgx/v5/stdlib driver.
+	Postgres
+)
+
+// SQLRecordManager is a RecordManager that stores the records in a SQLite or
+// PostgreSQL table. Several record managers can share the table by using
+// different namespaces, typically one per vector store collection.
+type SQLRecordManager struct {
+	db        *sql.DB
+	dialect   Dialect
+	namespace string
+	tableName string
+}
+
+var _ RecordManager = (*SQLRecordManager)(nil)
+
+// SQLRecordManagerOption is a function that configures a SQLRecordManager.
+type SQLRecordManagerOption func(m *SQLRecordManager)
+
+// WithTableName sets the name of the table holding the records. Defaults to
+// DefaultRecordsTableName.
+func WithTableName(name string) SQLRecordManagerOption {
+	return func(m *SQLRecordManager) {
+		m.tableName = name
+	}
+}
+
+// NewSQLRecordManager creates a SQLRecordManager for the records of namespace,
+// and creates its table if it doesn't exist.
+func NewSQLRecordManager(ctx context.Context,
+	db *sql.DB,
+	dialect Dialect,
+	namespace string,
+	opts ...SQLRecordManagerOption,
+) (*SQLRecordManager, error) {
+	if namespace == "" {
+		return nil, ErrInvalidNamespace
+	}
+	m := &SQLRecordManager{
+		db:        db,
+		dialect:   dialect,
+		namespace: namespace,
+		tableName: DefaultRecordsTableName,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	if err := m.createTable(ctx); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *SQLRecordManager) createTable(ctx context.Context) error {
+	timeType := "REAL"
+	if m.dialect == Postgres {
+		timeType = "DOUBLE PRECISION"
+	}
+	stmts := []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	key TEXT NOT NULL,
+	namespace TEXT NOT NULL,
+	group_id TEXT,
+	store_id TEXT,
+	updated_at %s NOT NULL,
+	PRIMARY KEY (namespace, key)
+)`, m.tableName, timeType),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %[1]s_updated_at_idx ON %[1]s (namespace, updated_at)`, m.tableName),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %[1]s_group_id_idx ON %[1]s (namespace, group_id)`, m.tableName),
+	}
+	for _, stmt := range stmts {
+		if _, err := m.db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("create records table: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetTime returns the current time of the database server.
+func (m *SQLRecordManager) GetTime(ctx context.Context) (time.Time, error) {
+	var ts float64
+	if err := m.db.QueryRowContext(ctx, m.nowExpr()).Scan(&ts); err != nil {
+		return time.Time{}, err
+	}
+	return fromEpoch(ts), nil
+}
+
+// Update inserts or updates the records with the given keys.
+func (m *SQLRecordManager) Update(ctx context.Context, keys, groupIDs, storeIDs []string, timeAtLeast time.Time) error {
+	if err := checkGroupIDs(keys, groupIDs, storeIDs); err != nil {
+		return err
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	var now float64
+	if err := tx.QueryRowContext(ctx, m.nowExpr()).Scan(&now); err != nil {
+		return err
+	}
+	if fromEpoch(now).Before(timeAtLeast) {
+		return ErrClockDrift
+	}
+
+	stmt, err := tx.PrepareContext(ctx, fmt.Sprintf(`INSERT INTO %[1]s (key, namespace, group_id, store_id, updated_at)
+VALUES (%s, %s, %s, %s, %s)
+ON CONFLICT (namespace, key) DO UPDATE SET group_id = excluded.group_id,
+	store_id = COALESCE(excluded.store_id, %[1]s.store_id), updated_at = excluded.updated_at`,
+		m.tableName, m.placeholder(1), m.placeholder(2), m.placeholder(3), m.placeholder(4), m.placeholder(5)))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for i, key := range keys {
+		var groupID sql.NullString
+		if groupIDs != nil && groupIDs[i] != "" {
+			groupID = sql.NullString{String: groupIDs[i], Valid: true}
+		}
+		var storeID sql.NullString
+		if storeIDs != nil && storeIDs[i] != "" {
+			storeID = sql.NullString{String: storeIDs[i], Valid: true}
+		}
+		if _, err := stmt.ExecContext(ctx, key, m.namespace, groupID, storeID, now); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Exists reports whether a record exists for each of the keys.
+func (m *SQLRecordManager) Exists(ctx context.Context, keys []string) ([]bool, error) {
+	found := make(map[string]bool, len(keys))
+	for start := 0; start < len(keys); start += maxQueryKeys {
+		chunk := keys[start:min(start+maxQueryKeys, len(keys))]
+
+		args := []any{m.namespace}
+		query := fmt.Sprintf("SELECT key FROM %s WHERE namespace = %s AND key IN (%s)",
+			m.tableName, m.placeholder(1), m.placeholders(&args, chunk))
+		existing, err := m.queryKeys(ctx, query, args)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range existing {
+			found[key] = true
+		}
+	}
+
+	exists := make([]bool, len(keys))
+	for i, key := range keys {
+		exists[i] = found[key]
+	}
+	return exists, nil
+}
+
+// ListKeys returns the keys of the records matching the options, sorted.
+func (m *SQLRecordManager) ListKeys(ctx context.Context, opts ListOptions) ([]string, error) {
+	args := []any{m.namespace}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "SELECT key FROM %s WHERE namespace = %s", m.tableName, m.placeholder(1))
+	if !opts.Before.IsZero() {
+		args = append(args, toEpoch(opts.Before))
+		fmt.Fprintf(&sb, " AND updated_at < %s", m.placeholder(len(args)))
+	}
+	if opts.GroupIDs != nil {
+		if len(opts.GroupIDs) == 0 {
+			return []string{}, nil
+		}
+		fmt.Fprintf(&sb, " AND group_id IN (%s)", m.placeholders(&args, opts.GroupIDs))
+	}
+	sb.WriteString(" ORDER BY key")
+	if opts.Limit > 0 {
+		sb.WriteString(" LIMIT " + strconv.Itoa(opts.Limit))
+	}
+	return m.queryKeys(ctx, sb.String(), args)
+}
+
+// GetStoreIDs returns the store ID of the record of each key.
+func (m *SQLRecordManager) GetStoreIDs(ctx context.Context, keys []string) ([]string, error) {
+	found := make(map[string]string, len(keys))
+	for start := 0; start < len(keys); start += maxQueryKeys {
+		chunk := keys[start:min(start+maxQueryKeys, len(keys))]
+
+		args := []any{m.namespace}
+		query := fmt.Sprintf("SELECT key, store_id FROM %s WHERE namespace = %s AND key IN (%s) AND store_id IS NOT NULL",
+			m.tableName, m.placeholder(1), m.placeholders(&args, chunk))
+		if err := m.queryRows(ctx, query, args, func(rows *sql.Rows) error {
+			var key, storeID string
+			if err := rows.Scan(&key, &storeID); err != nil {
+				return err
+			}
+			found[key] = storeID
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	storeIDs := make([]string, len(keys))
+	for i, key := range keys {
+		storeIDs[i] = key
+		if storeID := found[key]; storeID != "" {
+			storeIDs[i] = storeID
+		}
+	}
+	return storeIDs, nil
+}
+
+// DeleteKeys deletes the records with the given keys.
+func (m *SQLRecordManager) DeleteKeys(ctx context.Context, keys []string) error {
+	for start := 0; start < len(keys); start += maxQueryKeys {
+		chunk := keys[start:min(start+maxQueryKeys, len(keys))]
+
+		args := []any{m.namespace}
+		query := fmt.Sprintf("DELETE FROM %s WHERE namespace = %s AND key IN (%s)",
+			m.tableName, m.placeholder(1), m.placeholders(&args, chunk))
+		if _, err := m.db.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *SQLRecordManager) queryKeys(ctx context.Context, query string, args []any) ([]string, error) {
+	keys := []string{}
+	err := m.queryRows(ctx, query, args, func(rows *sql.Rows) error {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return err
+		}
+		keys = append(keys, key)
+		return nil
+	})
+	return keys, err
+}
+
+// queryRows runs query and calls scan for each row of the result.
+func (m *SQLRecordManager) queryRows(ctx context.Context, query string, args []any, scan func(*sql.Rows) error) error {
+	rows, err := m.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// nowExpr returns a query selecting the current time of the database, in
+// seconds since the Unix epoch.
+func (m *SQLRecordManager) nowExpr() string {
+	if m.dialect == Postgres {
+		return "SELECT EXTRACT(EPOCH FROM clock_timestamp())::double precision"
+	}
+	return "SELECT (julianday('now') - 2440587.5) * 86400.0"
+}
+
+// placeholder returns the placeholder of the n-th query argument.
+func (m *SQLRecordManager) placeholder(n int) string {
+	if m.dialect == Postgres {
+		return "$" + strconv.Itoa(n)
+	}
+	return "?"
+}
+
+// placeholders appends values to args and returns their comma separated
+// placeholders.
+func (m *SQLRecordManager) placeholders(args *[]any, values []string) string {
+	ps := make([]string, len(values))
+	for i, v := range values {
+		*args = append(*args, v)
+		ps[i] = m.placeholder(len(*args))
+	}
+	return strings.Join(ps, ", ")
+}
+
+func toEpoch(t time.Time) float64 {
+	return float64(t.UnixMicro()) / 1e6
+}
+
+// fromEpoch converts seconds since the Unix epoch to a time, truncated to the
+// microsecond so that converting it back never gives a later time.
+func fromEpoch(ts float64) time.Time {
+	sec, frac := math.Modf(ts)
+	return time.Unix(int64(sec), int64(math.Floor(frac*1e6))*int64(time.Microsecond))
+}
@@ -0,0 +1,81 @@
+package indexing
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3" // sqlite3 driver.
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLRecordManager(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "records.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	_, err = NewSQLRecordManager(ctx, db, SQLite, "")
+	require.ErrorIs(t, err, ErrInvalidNamespace)
+
+	m, err := NewSQLRecordManager(ctx, db, SQLite, "docs")
+	require.NoError(t, err)
+	other, err := NewSQLRecordManager(ctx, db, SQLite, "other")
+	require.NoError(t, err)
+
+	start, err := m.GetTime(ctx)
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now(), start, time.Minute)
+
+	require.NoError(t, m.Update(ctx, []string{"k1", "k2", "k3"}, []string{"a", "a", ""}, []string{"s1", "", "s3"}, start))
+	require.NoError(t, other.Update(ctx, []string{"k1"}, nil, nil, start))
+	require.ErrorIs(t, m.Update(ctx, []string{"k1"}, nil, nil, start.Add(time.Hour)), ErrClockDrift)
+
+	exists, err := m.Exists(ctx, []string{"k3", "missing", "k1"})
+	require.NoError(t, err)
+	require.Equal(t, []bool{true, false, true}, exists)
+
+	storeIDs, err := m.GetStoreIDs(ctx, []string{"k1", "k2", "missing"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"s1", "k2", "missing"}, storeIDs)
+
+	keys, err := m.ListKeys(ctx, ListOptions{GroupIDs: []string{"a"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"k1", "k2"}, keys)
+
+	keys, err = m.ListKeys(ctx, ListOptions{Limit: 2})
+	require.NoError(t, err)
+	require.Equal(t, []string{"k1", "k2"}, keys)
+
+	keys, err = m.ListKeys(ctx, ListOptions{Before: start})
+	require.NoError(t, err)
+	require.Empty(t, keys)
+
+	time.Sleep(5 * time.Millisecond)
+	later, err := m.GetTime(ctx)
+	require.NoError(t, err)
+	require.NoError(t, m.Update(ctx, []string{"k2"}, []string{"b"}, nil, later))
+
+	keys, err = m.ListKeys(ctx, ListOptions{Before: later})
+	require.NoError(t, err)
+	require.Equal(t, []string{"k1", "k3"}, keys)
+
+	// An update without store id keeps the recorded one.
+	require.NoError(t, m.Update(ctx, []string{"k3"}, nil, nil, later))
+	storeIDs, err = m.GetStoreIDs(ctx, []string{"k3"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"s3"}, storeIDs)
+
+	require.NoError(t, m.DeleteKeys(ctx, []string{"k1"}))
+	keys, err = m.ListKeys(ctx, ListOptions{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"k2", "k3"}, keys)
+
+	keys, err = other.ListKeys(ctx, ListOptions{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"k1"}, keys)
+}
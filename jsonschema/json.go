@@ -1,10 +1,20 @@
-// Package jsonschema provides very simple functionality for representing a JSON schema as a
+// Package jsonschema provides simple functionality for representing a JSON schema as a
 // (nested) struct. This struct can be used with the chat completion "function call" feature.
+// Definitions can be written by hand or derived from Go types with Reflect, and JSON documents,
+// such as tool arguments or structured outputs, can be checked against them with Validate.
 // For more complicated schemas, it is recommended to use a dedicated JSON schema library
 // and/or pass in the schema in []byte format.
 package jsonschema
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidDefinition is returned when marshaling or unmarshaling a definition
+// which can't be represented.
+var ErrInvalidDefinition = errors.New("invalid definition")
 
 type DataType string
 
@@ -34,16 +44,123 @@ type Definition struct {
 	Required []string `json:"required,omitempty"`
 	// Items specifies which data type an array contains, if the schema type is Array.
 	Items *Definition `json:"items,omitempty"`
+	// AdditionalProperties describes the properties of an object that are not listed in
+	// Properties. It is either a bool, false forbidding them, or a Definition (or *Definition)
+	// they must match; other values are rejected by MarshalJSON and Validate.
+	AdditionalProperties any `json:"additionalProperties,omitempty"`
+	// Nullable allows the value to be null, in addition to the values of Type. It is encoded
+	// as a type array, e.g. ["string", "null"], or as an anyOf alternative for definitions
+	// without type.
+	Nullable bool `json:"-"`
+	// Format is the semantic format of a string, such as "date-time", "date", "time", "email",
+	// "uri" or "uuid".
+	Format string `json:"format,omitempty"`
+	// Pattern is a regular expression a string must match.
+	Pattern string `json:"pattern,omitempty"`
+	// MinLength and MaxLength bound the length of a string, in characters.
+	MinLength *int `json:"minLength,omitempty"`
+	MaxLength *int `json:"maxLength,omitempty"`
+	// Minimum and Maximum bound a number, inclusively.
+	Minimum *float64 `json:"minimum,omitempty"`
+	Maximum *float64 `json:"maximum,omitempty"`
+	// ExclusiveMinimum and ExclusiveMaximum bound a number, exclusively.
+	ExclusiveMinimum *float64 `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum *float64 `json:"exclusiveMaximum,omitempty"`
+	// MinItems and MaxItems bound the length of an array.
+	MinItems *int `json:"minItems,omitempty"`
+	MaxItems *int `json:"maxItems,omitempty"`
+	// AnyOf requires the value to match at least one of the definitions.
+	AnyOf []Definition `json:"anyOf,omitempty"`
+	// OneOf requires the value to match exactly one of the definitions.
+	OneOf []Definition `json:"oneOf,omitempty"`
+	// Ref references another definition: "#" for the root definition, or "#/$defs/name" for a
+	// definition in the Defs of the root definition.
+	Ref string `json:"$ref,omitempty"`
+	// Defs holds definitions referenced with Ref. It is only used in the root definition.
+	Defs map[string]Definition `json:"$defs,omitempty"`
 }
 
 func (d Definition) MarshalJSON() ([]byte, error) {
+	switch d.AdditionalProperties.(type) {
+	case nil, bool, Definition, *Definition:
+	default:
+		return nil, fmt.Errorf("%w: additionalProperties of type %T", ErrInvalidDefinition, d.AdditionalProperties)
+	}
+	if d.Nullable && d.Type == "" {
+		// Without a type to extend, null is an alternative to the definition.
+		inner := d
+		inner.Nullable = false
+		inner.Description = ""
+		inner.Defs = nil
+		return json.Marshal(Definition{
+			Description: d.Description,
+			AnyOf:       []Definition{inner, {Type: Null}},
+			Defs:        d.Defs,
+		})
+	}
 	if d.Properties == nil {
 		d.Properties = make(map[string]Definition)
 	}
+	var typ any
+	if d.Type != "" {
+		typ = d.Type
+	}
+	if d.Nullable {
+		typ = []DataType{d.Type, Null}
+	}
 	type Alias Definition
 	return json.Marshal(struct {
+		Type any `json:"type,omitempty"`
 		Alias
 	}{
+		Type:  typ,
 		Alias: (Alias)(d),
 	})
 }
+
+func (d *Definition) UnmarshalJSON(data []byte) error {
+	type Alias Definition
+	v := struct {
+		Type                 json.RawMessage `json:"type"`
+		AdditionalProperties json.RawMessage `json:"additionalProperties"`
+		*Alias
+	}{
+		Alias: (*Alias)(d),
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	if len(v.Type) > 0 {
+		var types []DataType
+		if err := json.Unmarshal(v.Type, &d.Type); err != nil {
+			if err := json.Unmarshal(v.Type, &types); err != nil {
+				return fmt.Errorf("%w: type %s", ErrInvalidDefinition, v.Type)
+			}
+		}
+		for _, typ := range types {
+			switch {
+			case typ == Null:
+				d.Nullable = true
+			case d.Type == "":
+				d.Type = typ
+			default:
+				return fmt.Errorf("%w: several types %s", ErrInvalidDefinition, v.Type)
+			}
+		}
+	}
+
+	if len(v.AdditionalProperties) > 0 {
+		var allowed bool
+		var additional Definition
+		switch {
+		case json.Unmarshal(v.AdditionalProperties, &allowed) == nil:
+			d.AdditionalProperties = allowed
+		case json.Unmarshal(v.AdditionalProperties, &additional) == nil:
+			d.AdditionalProperties = additional
+		default:
+			return fmt.Errorf("%w: additionalProperties %s", ErrInvalidDefinition, v.AdditionalProperties)
+		}
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/stretchr/testify/require"
 	"github.com/tmc/langchaingo/jsonschema"
 )
 
@@ -167,6 +168,33 @@ func TestDefinition_MarshalJSON(t *testing.T) { //nolint:funlen
          "properties":{}
       }
    }
+}`,
+		},
+		{
+			name: "Test with nullable Definition",
+			def: jsonschema.Definition{
+				Type:     jsonschema.String,
+				Nullable: true,
+			},
+			want: `{
+   "type":["string","null"],
+   "properties":{}
+}`,
+		},
+		{
+			name: "Test with nullable reference",
+			def: jsonschema.Definition{
+				Ref:         "#/$defs/node",
+				Description: "A node",
+				Nullable:    true,
+			},
+			want: `{
+   "description":"A node",
+   "anyOf":[
+      {"$ref":"#/$defs/node","properties":{}},
+      {"type":"null","properties":{}}
+   ],
+   "properties":{}
 }`,
 		},
 	}
@@ -195,6 +223,45 @@ func TestDefinition_MarshalJSON(t *testing.T) { //nolint:funlen
 	}
 }
 
+func TestDefinition_MarshalJSONInvalidAdditionalProperties(t *testing.T) {
+	t.Parallel()
+
+	_, err := json.Marshal(jsonschema.Definition{
+		Type:                 jsonschema.Object,
+		AdditionalProperties: map[string]any{"type": "string"},
+	})
+	require.ErrorIs(t, err, jsonschema.ErrInvalidDefinition)
+}
+
+func TestDefinition_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	want := jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"name":   {Type: jsonschema.String, Nullable: true},
+			"labels": {Type: jsonschema.Object, AdditionalProperties: jsonschema.Definition{Type: jsonschema.String}},
+		},
+		AdditionalProperties: false,
+	}
+	data, err := json.Marshal(want)
+	require.NoError(t, err)
+
+	var got jsonschema.Definition
+	require.NoError(t, json.Unmarshal(data, &got))
+	require.Equal(t, structToMap(t, want), structToMap(t, got))
+	require.True(t, got.Properties["name"].Nullable)
+	require.Equal(t, jsonschema.String, got.Properties["name"].Type)
+	require.Equal(t, false, got.AdditionalProperties)
+	require.Equal(t, jsonschema.Definition{Type: jsonschema.String, Properties: map[string]jsonschema.Definition{}},
+		got.Properties["labels"].AdditionalProperties)
+
+	err = json.Unmarshal([]byte(`{"type":["string","number"]}`), &got)
+	require.ErrorIs(t, err, jsonschema.ErrInvalidDefinition)
+	err = json.Unmarshal([]byte(`{"additionalProperties":1}`), &got)
+	require.ErrorIs(t, err, jsonschema.ErrInvalidDefinition)
+}
+
 func structToMap(t *testing.T, v any) map[string]any {
 	t.Helper()
 	gotBytes, err := json.Marshal(v)
@@ -0,0 +1,265 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+	timeType       = reflect.TypeOf(time.Time{})
+	marshalerType  = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// Reflect returns the Definition of the JSON encoding of T, which is usually
+// a struct.
+//
+// Struct fields are named after their `json` tag, and fields tagged with "-"
+// or unexported are skipped. Fields are required unless their `json` tag has
+// the omitempty option; a `required:"true"` or `required:"false"` tag
+// overrides this. Pointer fields without the omitempty option are nullable.
+//
+// The following tags add keywords to the definition of a field:
+//
+//   - description: the description of the field.
+//   - enum: the allowed values, separated by commas.
+//   - format: the format of a string, e.g. "email".
+//   - pattern: a regular expression a string must match.
+//   - minLength, maxLength: bounds of the length of a string.
+//   - minimum, maximum: inclusive bounds of a number.
+//   - minItems, maxItems: bounds of the length of an array.
+//
+// time.Time values are strings with the "date-time" format. Recursive types
+// are described with references to definitions in Defs. Reflect panics if a
+// tag has an invalid value.
+func Reflect[T any]() Definition {
+	return ReflectType(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+// ReflectType returns the Definition of the JSON encoding of values of type
+// t. See Reflect.
+func ReflectType(t reflect.Type) Definition {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	r := &reflector{
+		root:      t,
+		stack:     map[reflect.Type]bool{},
+		recursive: map[reflect.Type]bool{},
+		defs:      map[string]Definition{},
+	}
+	def := r.reflectType(t)
+	if len(r.defs) > 0 {
+		def.Defs = r.defs
+	}
+	return def
+}
+
+type reflector struct {
+	root      reflect.Type
+	stack     map[reflect.Type]bool
+	recursive map[reflect.Type]bool
+	defs      map[string]Definition
+}
+
+func (r *reflector) reflectType(t reflect.Type) Definition {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == rawMessageType:
+		return Definition{}
+	case t == timeType:
+		return Definition{Type: String, Format: "date-time"}
+	case t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType):
+		return Definition{}
+	}
+
+	switch t.Kind() { //nolint:exhaustive
+	case reflect.Bool:
+		return Definition{Type: Boolean}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Definition{Type: Integer}
+	case reflect.Float32, reflect.Float64:
+		return Definition{Type: Number}
+	case reflect.String:
+		return Definition{Type: String}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// []byte is encoded as a base64 string.
+			return Definition{Type: String}
+		}
+		items := r.reflectType(t.Elem())
+		return Definition{Type: Array, Items: &items}
+	case reflect.Map:
+		values := r.reflectType(t.Elem())
+		if isEmpty(values) {
+			return Definition{Type: Object}
+		}
+		return Definition{Type: Object, AdditionalProperties: values}
+	case reflect.Struct:
+		return r.reflectNamedStruct(t)
+	default:
+		// interfaces and other kinds accept any value.
+		return Definition{}
+	}
+}
+
+// reflectNamedStruct reflects a struct type, replacing recursive uses of it
+// with references.
+func (r *reflector) reflectNamedStruct(t reflect.Type) Definition {
+	if r.stack[t] {
+		if t == r.root {
+			return Definition{Ref: "#"}
+		}
+		r.recursive[t] = true
+		return Definition{Ref: "#/$defs/" + defName(t)}
+	}
+
+	r.stack[t] = true
+	def := r.reflectStruct(t)
+	delete(r.stack, t)
+
+	if r.recursive[t] {
+		r.defs[defName(t)] = def
+		return Definition{Ref: "#/$defs/" + defName(t)}
+	}
+	return def
+}
+
+func (r *reflector) reflectStruct(t reflect.Type) Definition {
+	def := Definition{
+		Type:       Object,
+		Properties: map[string]Definition{},
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, omitempty, ok := fieldName(f)
+		if !ok {
+			continue
+		}
+
+		// Embedded structs without a name have their fields promoted.
+		if f.Anonymous && !hasName(f) {
+			ft := f.Type
+			for ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				embedded := r.reflectStruct(ft)
+				for k, v := range embedded.Properties {
+					def.Properties[k] = v
+				}
+				def.Required = append(def.Required, embedded.Required...)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+
+		prop := r.reflectType(f.Type)
+		if f.Type.Kind() == reflect.Pointer && !omitempty {
+			prop.Nullable = true
+		}
+		applyTags(&prop, f)
+		def.Properties[name] = prop
+
+		required := !omitempty
+		if v, ok := f.Tag.Lookup("required"); ok {
+			required = v == "true"
+		}
+		if required {
+			def.Required = append(def.Required, name)
+		}
+	}
+	return def
+}
+
+// applyTags sets the keywords given by the tags of a struct field.
+func applyTags(def *Definition, f reflect.StructField) {
+	if v, ok := f.Tag.Lookup("description"); ok {
+		def.Description = v
+	}
+	if v, ok := f.Tag.Lookup("enum"); ok {
+		def.Enum = strings.Split(v, ",")
+	}
+	if v, ok := f.Tag.Lookup("format"); ok {
+		def.Format = v
+	}
+	if v, ok := f.Tag.Lookup("pattern"); ok {
+		def.Pattern = v
+	}
+	def.MinLength = intTag(f, "minLength", def.MinLength)
+	def.MaxLength = intTag(f, "maxLength", def.MaxLength)
+	def.MinItems = intTag(f, "minItems", def.MinItems)
+	def.MaxItems = intTag(f, "maxItems", def.MaxItems)
+	def.Minimum = floatTag(f, "minimum", def.Minimum)
+	def.Maximum = floatTag(f, "maximum", def.Maximum)
+}
+
+func intTag(f reflect.StructField, key string, def *int) *int {
+	v, ok := f.Tag.Lookup(key)
+	if !ok {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		panic(fmt.Sprintf("jsonschema: invalid %s tag %q on field %s: %v", key, v, f.Name, err))
+	}
+	return &n
+}
+
+func floatTag(f reflect.StructField, key string, def *float64) *float64 {
+	v, ok := f.Tag.Lookup(key)
+	if !ok {
+		return def
+	}
+	n, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		panic(fmt.Sprintf("jsonschema: invalid %s tag %q on field %s: %v", key, v, f.Name, err))
+	}
+	return &n
+}
+
+// fieldName returns the JSON name of a struct field, and whether it has the
+// omitempty option. ok is false if the field is not encoded.
+func fieldName(f reflect.StructField) (name string, omitempty bool, ok bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false, false
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = f.Name
+	}
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == "omitempty" || opt == "omitzero" {
+			omitempty = true
+		}
+	}
+	return name, omitempty, true
+}
+
+func hasName(f reflect.StructField) bool {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	return name != ""
+}
+
+// defName returns the name of the definition of a type in Defs.
+func defName(t reflect.Type) string {
+	if t.Name() != "" {
+		return t.Name()
+	}
+	return strings.NewReplacer(" ", "", "/", "_").Replace(t.String())
+}
+
+func isEmpty(def Definition) bool {
+	return reflect.DeepEqual(def, Definition{})
+}
@@ -0,0 +1,145 @@
+package jsonschema_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/jsonschema"
+)
+
+type address struct {
+	City    string `json:"city" description:"The city name"`
+	Country string `json:"country,omitempty" enum:"US,FR,JP"`
+}
+
+type person struct {
+	address
+
+	Name     string            `json:"name"`
+	Age      int               `json:"age,omitempty" required:"true"`
+	Height   float64           `json:"height,omitempty"`
+	Tags     []string          `json:"tags"`
+	Home     *address          `json:"home,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Friends  []person          `json:"friends,omitempty"`
+	Verified bool
+	Ignored  string `json:"-"`
+	internal string
+}
+
+func TestReflect(t *testing.T) {
+	t.Parallel()
+
+	addressDef := jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"city":    {Type: jsonschema.String, Description: "The city name"},
+			"country": {Type: jsonschema.String, Enum: []string{"US", "FR", "JP"}},
+		},
+		Required: []string{"city"},
+	}
+
+	def := jsonschema.Reflect[person]()
+	require.Equal(t, jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"city":     addressDef.Properties["city"],
+			"country":  addressDef.Properties["country"],
+			"name":     {Type: jsonschema.String},
+			"age":      {Type: jsonschema.Integer},
+			"height":   {Type: jsonschema.Number},
+			"tags":     {Type: jsonschema.Array, Items: &jsonschema.Definition{Type: jsonschema.String}},
+			"home":     addressDef,
+			"labels":   {Type: jsonschema.Object, AdditionalProperties: jsonschema.Definition{Type: jsonschema.String}},
+			"friends":  {Type: jsonschema.Array, Items: &jsonschema.Definition{Ref: "#"}},
+			"Verified": {Type: jsonschema.Boolean},
+		},
+		Required: []string{"city", "name", "age", "tags", "Verified"},
+	}, def)
+
+	require.Equal(t, jsonschema.Definition{Type: jsonschema.String}, jsonschema.Reflect[*string]())
+}
+
+type node struct {
+	Value    string  `json:"value" minLength:"1" maxLength:"8" pattern:"^[a-z]+$"`
+	Children []*node `json:"children,omitempty" maxItems:"2"`
+}
+
+type tree struct {
+	Root      node       `json:"root"`
+	Parent    *tree      `json:"parent"`
+	Score     float64    `json:"score" minimum:"0" maximum:"1"`
+	Email     string     `json:"email" format:"email"`
+	Created   time.Time  `json:"created"`
+	Anything  any        `json:"anything,omitempty"`
+	RawConfig []byte     `json:"raw_config,omitempty"`
+	Updated   *time.Time `json:"updated,omitempty"`
+}
+
+func TestReflectKeywords(t *testing.T) {
+	t.Parallel()
+
+	one, eight, two := 1, 8, 2
+	zero, maximum := 0.0, 1.0
+	def := jsonschema.Reflect[tree]()
+	require.Equal(t, jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"root":       {Ref: "#/$defs/node"},
+			"parent":     {Ref: "#", Nullable: true},
+			"score":      {Type: jsonschema.Number, Minimum: &zero, Maximum: &maximum},
+			"email":      {Type: jsonschema.String, Format: "email"},
+			"created":    {Type: jsonschema.String, Format: "date-time"},
+			"anything":   {},
+			"raw_config": {Type: jsonschema.String},
+			"updated":    {Type: jsonschema.String, Format: "date-time"},
+		},
+		Required: []string{"root", "parent", "score", "email", "created"},
+		Defs: map[string]jsonschema.Definition{
+			"node": {
+				Type: jsonschema.Object,
+				Properties: map[string]jsonschema.Definition{
+					"value": {Type: jsonschema.String, MinLength: &one, MaxLength: &eight, Pattern: "^[a-z]+$"},
+					"children": {
+						Type:     jsonschema.Array,
+						Items:    &jsonschema.Definition{Ref: "#/$defs/node"},
+						MaxItems: &two,
+					},
+				},
+				Required: []string{"value"},
+			},
+		},
+	}, def)
+
+	require.NoError(t, jsonschema.Validate(def, []byte(`{
+		"root": {"value": "a", "children": [{"value": "b"}, {"value": "c", "children": []}]},
+		"parent": {
+			"root": {"value": "p"}, "parent": null, "score": 1,
+			"email": "p@example.com", "created": "2024-01-02T03:04:05Z"
+		},
+		"score": 0.5,
+		"email": "ann@example.com",
+		"created": "2024-01-02T03:04:05+01:00"
+	}`)))
+
+	err := jsonschema.Validate(def, []byte(`{
+		"root": {"value": "", "children": [{"value": "B"}, {"value": "c"}, {"value": "d"}]},
+		"parent": null,
+		"score": 2,
+		"email": "not an email",
+		"created": "yesterday"
+	}`))
+	require.ErrorContains(t, err, "/created: value \"yesterday\" is not a valid date-time")
+	require.ErrorContains(t, err, "/email: value \"not an email\" is not a valid email")
+	require.ErrorContains(t, err, "/root/children: array has 3 items, more than the maximum of 2")
+	require.ErrorContains(t, err, "/root/children/0/value: value \"B\" does not match the pattern")
+	require.ErrorContains(t, err, "/root/value: string has 0 characters, less than the minimum of 1")
+	require.ErrorContains(t, err, "/score: value 2 is greater than the maximum of 1")
+
+	require.Panics(t, func() {
+		jsonschema.Reflect[struct {
+			N int `json:"n" minimum:"zero"`
+		}]()
+	})
+}
@@ -0,0 +1,339 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ValidationError describes a value that does not match its definition.
+type ValidationError struct {
+	// Path locates the value in the validated document, e.g. "/items/0/name".
+	// It is empty for the document itself.
+	Path string
+	// Message describes why the value does not match.
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// Validate checks that the JSON document data matches the definition. It
+// returns the *ValidationError of every mismatch found, joined with
+// errors.Join, or an error if data is not valid JSON.
+func Validate(def Definition, data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	if dec.More() {
+		return errors.New("invalid JSON: unexpected data after the top-level value")
+	}
+	return ValidateValue(def, v)
+}
+
+// ValidateValue checks that a value decoded from JSON matches the definition.
+// See Validate.
+func ValidateValue(def Definition, v any) error {
+	var errs []error
+	validator{root: def}.validate(def, v, "", &errs)
+	return errors.Join(errs...)
+}
+
+// maxRefDepth bounds the number of references followed without consuming any
+// of the validated value, to stop on definitions referencing themselves.
+const maxRefDepth = 32
+
+type validator struct {
+	root Definition
+}
+
+// resolve returns the definition referenced by ref.
+func (r validator) resolve(ref string) (Definition, bool) {
+	if ref == "#" {
+		return r.root, true
+	}
+	name, ok := strings.CutPrefix(ref, "#/$defs/")
+	if !ok {
+		return Definition{}, false
+	}
+	def, ok := r.root.Defs[name]
+	return def, ok
+}
+
+func (r validator) validate(def Definition, v any, path string, errs *[]error) {
+	fail := func(format string, args ...any) {
+		*errs = append(*errs, &ValidationError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// Nullable applies to the referenced definition too.
+	nullable := def.Nullable
+	for depth := 0; def.Ref != ""; depth++ {
+		ref, ok := r.resolve(def.Ref)
+		if !ok || depth == maxRefDepth {
+			fail("unresolved reference %q", def.Ref)
+			return
+		}
+		def = ref
+		nullable = nullable || def.Nullable
+	}
+
+	if v == nil && nullable {
+		return
+	}
+	if def.Type != "" && !hasType(def.Type, v) {
+		fail("expected %s, got %s", def.Type, typeOf(v))
+		return
+	}
+
+	if len(def.Enum) > 0 && !slices.Contains(def.Enum, enumString(v)) {
+		fail("value %s is not one of %s", enumString(v), strings.Join(def.Enum, ", "))
+	}
+
+	if len(def.AnyOf) > 0 && r.matches(def.AnyOf, v) == 0 {
+		fail("value does not match any of the allowed definitions")
+	}
+	if len(def.OneOf) > 0 {
+		if n := r.matches(def.OneOf, v); n != 1 {
+			fail("value matches %d of the definitions instead of exactly one", n)
+		}
+	}
+
+	switch value := v.(type) {
+	case map[string]any:
+		r.validateObject(def, value, path, errs)
+	case []any:
+		if def.MinItems != nil && len(value) < *def.MinItems {
+			fail("array has %d items, less than the minimum of %d", len(value), *def.MinItems)
+		}
+		if def.MaxItems != nil && len(value) > *def.MaxItems {
+			fail("array has %d items, more than the maximum of %d", len(value), *def.MaxItems)
+		}
+		if def.Items != nil {
+			for i, item := range value {
+				r.validate(*def.Items, item, fmt.Sprintf("%s/%d", path, i), errs)
+			}
+		}
+	case string:
+		n := utf8.RuneCountInString(value)
+		if def.MinLength != nil && n < *def.MinLength {
+			fail("string has %d characters, less than the minimum of %d", n, *def.MinLength)
+		}
+		if def.MaxLength != nil && n > *def.MaxLength {
+			fail("string has %d characters, more than the maximum of %d", n, *def.MaxLength)
+		}
+		if def.Pattern != "" {
+			re, err := regexp.Compile(def.Pattern)
+			switch {
+			case err != nil:
+				fail("invalid pattern %q: %v", def.Pattern, err)
+			case !re.MatchString(value):
+				fail("value %q does not match the pattern %q", value, def.Pattern)
+			}
+		}
+		if def.Format != "" && !hasFormat(def.Format, value) {
+			fail("value %q is not a valid %s", value, def.Format)
+		}
+	default:
+		if f, ok := toFloat(v); ok {
+			validateNumber(def, f, fail)
+		}
+	}
+}
+
+func (r validator) validateObject(def Definition, value map[string]any, path string, errs *[]error) {
+	for _, name := range def.Required {
+		if _, ok := value[name]; !ok {
+			*errs = append(*errs, &ValidationError{
+				Path:    path,
+				Message: fmt.Sprintf("missing required property %q", name),
+			})
+		}
+	}
+	names := make([]string, 0, len(value))
+	for name := range value {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		propPath := path + "/" + escapePointer(name)
+		if prop, ok := def.Properties[name]; ok {
+			r.validate(prop, value[name], propPath, errs)
+			continue
+		}
+		switch additional := def.AdditionalProperties.(type) {
+		case bool:
+			if !additional {
+				*errs = append(*errs, &ValidationError{Path: propPath, Message: "additional property is not allowed"})
+			}
+		case Definition:
+			r.validate(additional, value[name], propPath, errs)
+		case *Definition:
+			if additional != nil {
+				r.validate(*additional, value[name], propPath, errs)
+			}
+		case nil:
+		default:
+			*errs = append(*errs, &ValidationError{
+				Path:    propPath,
+				Message: fmt.Sprintf("invalid additionalProperties of type %T", additional),
+			})
+		}
+	}
+}
+
+// matches returns the number of definitions matching v.
+func (r validator) matches(defs []Definition, v any) int {
+	n := 0
+	for _, def := range defs {
+		var errs []error
+		r.validate(def, v, "", &errs)
+		if len(errs) == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+func validateNumber(def Definition, f float64, fail func(format string, args ...any)) {
+	if def.Minimum != nil && f < *def.Minimum {
+		fail("value %v is less than the minimum of %v", f, *def.Minimum)
+	}
+	if def.Maximum != nil && f > *def.Maximum {
+		fail("value %v is greater than the maximum of %v", f, *def.Maximum)
+	}
+	if def.ExclusiveMinimum != nil && f <= *def.ExclusiveMinimum {
+		fail("value %v is not greater than the exclusive minimum of %v", f, *def.ExclusiveMinimum)
+	}
+	if def.ExclusiveMaximum != nil && f >= *def.ExclusiveMaximum {
+		fail("value %v is not less than the exclusive maximum of %v", f, *def.ExclusiveMaximum)
+	}
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// hasFormat reports whether s has the given format. Unknown formats match any
+// string.
+func hasFormat(format, s string) bool {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, s)
+		return err == nil
+	case "date":
+		_, err := time.Parse(time.DateOnly, s)
+		return err == nil
+	case "time":
+		_, err := time.Parse("15:04:05Z07:00", s)
+		if err != nil {
+			_, err = time.Parse(time.TimeOnly, s)
+		}
+		return err == nil
+	case "email":
+		addr, err := mail.ParseAddress(s)
+		return err == nil && addr.Address == s
+	case "uri":
+		u, err := url.Parse(s)
+		return err == nil && u.Scheme != ""
+	case "uuid":
+		return uuidPattern.MatchString(s)
+	default:
+		return true
+	}
+}
+
+func hasType(t DataType, v any) bool {
+	switch t {
+	case Object:
+		_, ok := v.(map[string]any)
+		return ok
+	case Array:
+		_, ok := v.([]any)
+		return ok
+	case String:
+		_, ok := v.(string)
+		return ok
+	case Boolean:
+		_, ok := v.(bool)
+		return ok
+	case Null:
+		return v == nil
+	case Number:
+		_, ok := toFloat(v)
+		return ok
+	case Integer:
+		f, ok := toFloat(v)
+		return ok && f == math.Trunc(f)
+	default:
+		return true
+	}
+}
+
+func typeOf(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number, float64, float32, int, int64:
+		return "number"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+// enumString returns the string a value is compared with in an enum.
+func enumString(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// escapePointer escapes a property name for use in a JSON pointer.
+func escapePointer(name string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+}
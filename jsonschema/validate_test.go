@@ -0,0 +1,140 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/jsonschema"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	def := jsonschema.Reflect[person]()
+
+	tests := []struct {
+		name string
+		data string
+		errs []string
+	}{
+		{
+			name: "valid",
+			data: `{"city":"Paris","country":"FR","name":"Ann","age":30,"tags":[],"Verified":true,` +
+				`"friends":[{"city":"Rome","name":"Bob","age":5,"tags":["x"],"Verified":false}],"extra":1}`,
+		},
+		{
+			name: "missing and wrong types",
+			data: `{"city":"Paris","country":"DE","name":3,"age":1.5,"tags":["a",2]}`,
+			errs: []string{
+				`missing required property "Verified"`,
+				`/age: expected integer, got number`,
+				`/country: value DE is not one of US, FR, JP`,
+				`/name: expected string, got number`,
+				`/tags/1: expected string, got number`,
+			},
+		},
+		{
+			name: "invalid friend",
+			data: `{"city":"Paris","name":"Ann","age":30,"tags":[],"Verified":true,` +
+				`"friends":[{"name":"Bob","labels":{"a":1}}]}`,
+			errs: []string{
+				`/friends/0: missing required property "city"`,
+				`/friends/0: missing required property "age"`,
+				`/friends/0: missing required property "tags"`,
+				`/friends/0: missing required property "Verified"`,
+				`/friends/0/labels/a: expected string, got number`,
+			},
+		},
+		{
+			name: "not an object",
+			data: `[]`,
+			errs: []string{"expected object, got array"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := jsonschema.Validate(def, []byte(tt.data))
+			if len(tt.errs) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			var joined interface{ Unwrap() []error }
+			require.ErrorAs(t, err, &joined)
+			msgs := make([]string, 0, len(joined.Unwrap()))
+			for _, e := range joined.Unwrap() {
+				var verr *jsonschema.ValidationError
+				require.True(t, errors.As(e, &verr))
+				msgs = append(msgs, verr.Error())
+			}
+			require.Equal(t, tt.errs, msgs)
+		})
+	}
+
+	require.ErrorContains(t, jsonschema.Validate(def, []byte(`{"city":`)), "invalid JSON")
+}
+
+func TestValidateKeywords(t *testing.T) {
+	t.Parallel()
+
+	zero, ten := 0.0, 10.0
+	def := jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"id":    {Type: jsonschema.String, Format: "uuid"},
+			"count": {Type: jsonschema.Integer, ExclusiveMinimum: &zero, ExclusiveMaximum: &ten},
+			"note":  {Type: jsonschema.String, Nullable: true},
+			"value": {AnyOf: []jsonschema.Definition{{Type: jsonschema.String}, {Type: jsonschema.Number}}},
+			"shape": {OneOf: []jsonschema.Definition{
+				{Type: jsonschema.Object, Required: []string{"radius"}},
+				{Type: jsonschema.Object, Required: []string{"side"}},
+			}},
+			"link": {Ref: "#/$defs/link"},
+		},
+		AdditionalProperties: false,
+		Defs: map[string]jsonschema.Definition{
+			"link": {Type: jsonschema.String, Format: "uri"},
+		},
+	}
+
+	require.NoError(t, jsonschema.Validate(def, []byte(`{
+		"id": "123e4567-e89b-12d3-a456-426614174000",
+		"count": 5,
+		"note": null,
+		"value": 1.5,
+		"shape": {"radius": 1},
+		"link": "https://example.com/a"
+	}`)))
+
+	err := jsonschema.Validate(def, []byte(`{
+		"id": "123",
+		"count": 10,
+		"value": true,
+		"shape": {"radius": 1, "side": 2},
+		"link": "example",
+		"extra": 1
+	}`))
+	var joined interface{ Unwrap() []error }
+	require.ErrorAs(t, err, &joined)
+	msgs := make([]string, 0, len(joined.Unwrap()))
+	for _, e := range joined.Unwrap() {
+		msgs = append(msgs, e.Error())
+	}
+	require.Equal(t, []string{
+		`/count: value 10 is not less than the exclusive maximum of 10`,
+		`/extra: additional property is not allowed`,
+		`/id: value "123" is not a valid uuid`,
+		`/link: value "example" is not a valid uri`,
+		`/shape: value matches 2 of the definitions instead of exactly one`,
+		`/value: value does not match any of the allowed definitions`,
+	}, msgs)
+
+	require.ErrorContains(t,
+		jsonschema.Validate(jsonschema.Definition{Ref: "#/$defs/missing"}, []byte(`1`)),
+		`unresolved reference "#/$defs/missing"`)
+	require.ErrorContains(t,
+		jsonschema.Validate(jsonschema.Definition{Type: jsonschema.Object, AdditionalProperties: "no"}, []byte(`{"a":1}`)),
+		`/a: invalid additionalProperties of type string`)
+}
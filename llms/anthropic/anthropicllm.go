@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
 
 	"github.com/tmc/langchaingo/callbacks"
 	"github.com/tmc/langchaingo/llms"
@@ -21,6 +22,7 @@ var (
 	ErrInvalidContentType       = errors.New("invalid content type")
 	ErrUnsupportedMessageType   = errors.New("unsupported message type")
 	ErrUnsupportedContentType   = errors.New("unsupported content type")
+	ErrThinkingOptions          = errors.New("option incompatible with extended thinking")
 )
 
 const (
@@ -34,7 +36,10 @@ type LLM struct {
 	client           *anthropicclient.Client
 }
 
-var _ llms.Model = (*LLM)(nil)
+var (
+	_ llms.Model                   = (*LLM)(nil)
+	_ llms.ResponseSchemaSupporter = (*LLM)(nil)
+)
 
 // New returns a new Anthropic LLM.
 func New(opts ...Option) (*LLM, error) {
@@ -76,19 +81,35 @@ func (o *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOptio
 
 // GenerateContent implements the Model interface.
 func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
-	if o.CallbacksHandler != nil {
-		o.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
-	}
-
 	opts := &llms.CallOptions{}
 	for _, opt := range options {
 		opt(opts)
 	}
 
+	model := opts.Model
+	if model == "" {
+		model = o.client.Model
+	}
+	ctx = callbacks.StartRun(ctx, callbacks.RunLLM, model)
+	if o.CallbacksHandler != nil {
+		o.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
+	}
+
+	var resp *llms.ContentResponse
+	var err error
 	if o.client.UseLegacyTextCompletionsAPI {
-		return generateCompletionsContent(ctx, o, messages, opts)
+		resp, err = generateCompletionsContent(ctx, o, messages, opts)
+	} else {
+		resp, err = generateMessagesContent(ctx, o, messages, opts)
+	}
+	if o.CallbacksHandler != nil {
+		if err != nil {
+			o.CallbacksHandler.HandleLLMError(ctx, err)
+		} else {
+			o.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, resp)
+		}
 	}
-	return generateMessagesContent(ctx, o, messages, opts)
+	return resp, err
 }
 
 func generateCompletionsContent(ctx context.Context, o *LLM, messages []llms.MessageContent, opts *llms.CallOptions) (*llms.ContentResponse, error) {
@@ -113,9 +134,6 @@ func generateCompletionsContent(ctx context.Context, o *LLM, messages []llms.Mes
 		StreamingFunc: opts.StreamingFunc,
 	})
 	if err != nil {
-		if o.CallbacksHandler != nil {
-			o.CallbacksHandler.HandleLLMError(ctx, err)
-		}
 		return nil, fmt.Errorf("anthropic: failed to create completion: %w", err)
 	}
 
@@ -130,83 +148,177 @@ func generateCompletionsContent(ctx context.Context, o *LLM, messages []llms.Mes
 }
 
 func generateMessagesContent(ctx context.Context, o *LLM, messages []llms.MessageContent, opts *llms.CallOptions) (*llms.ContentResponse, error) {
-	chatMessages, systemPrompt, err := processMessages(messages)
+	chatMessages, system, err := processMessages(messages)
 	if err != nil {
 		return nil, fmt.Errorf("anthropic: failed to process messages: %w", err)
 	}
 
 	tools := toolsToTools(opts.Tools)
+	// Response schemas are implemented by forcing the use of a tool taking the
+	// response as input.
+	var toolChoice *anthropicclient.ToolChoice
+	if rs := opts.ResponseSchema; rs != nil {
+		description := rs.Description
+		if description == "" {
+			description = "Respond with a document matching the input schema."
+		}
+		tools = append(tools, anthropicclient.Tool{
+			Name:        rs.Name,
+			Description: description,
+			InputSchema: rs.Schema,
+		})
+		toolChoice = &anthropicclient.ToolChoice{Type: "tool", Name: rs.Name}
+	}
+
+	temperature := opts.Temperature
+	var thinking *anthropicclient.Thinking
+	if opts.ThinkingBudget > 0 {
+		if err := checkThinkingOptions(opts); err != nil {
+			return nil, err
+		}
+		thinking = &anthropicclient.Thinking{Type: "enabled", BudgetTokens: opts.ThinkingBudget}
+		temperature = 1
+	}
+
 	result, err := o.client.CreateMessage(ctx, &anthropicclient.MessageRequest{
-		Model:         opts.Model,
-		Messages:      chatMessages,
-		System:        systemPrompt,
-		MaxTokens:     opts.MaxTokens,
-		StopWords:     opts.StopWords,
-		Temperature:   opts.Temperature,
-		TopP:          opts.TopP,
-		Tools:         tools,
-		StreamingFunc: opts.StreamingFunc,
+		Model:                  opts.Model,
+		Messages:               chatMessages,
+		System:                 system,
+		MaxTokens:              opts.MaxTokens,
+		StopWords:              opts.StopWords,
+		Temperature:            temperature,
+		TopP:                   opts.TopP,
+		Tools:                  tools,
+		ToolChoice:             toolChoice,
+		Thinking:               thinking,
+		StreamingFunc:          opts.StreamingFunc,
+		StreamingReasoningFunc: opts.StreamingReasoningFunc,
+		StreamingEventFunc:     opts.StreamingEventFunc,
 	})
 	if err != nil {
-		if o.CallbacksHandler != nil {
-			o.CallbacksHandler.HandleLLMError(ctx, err)
-		}
 		return nil, fmt.Errorf("anthropic: failed to create message: %w", err)
 	}
 	if result == nil {
 		return nil, ErrEmptyResponse
 	}
 
-	choices := make([]*llms.ContentChoice, len(result.Content))
-	for i, content := range result.Content {
-		switch content.GetType() {
-		case "text":
-			if textContent, ok := content.(*anthropicclient.TextContent); ok {
-				choices[i] = &llms.ContentChoice{
-					Content:    textContent.Text,
-					StopReason: result.StopReason,
-					GenerationInfo: map[string]any{
-						"InputTokens":  result.Usage.InputTokens,
-						"OutputTokens": result.Usage.OutputTokens,
-					},
-				}
-			} else {
-				return nil, fmt.Errorf("anthropic: %w for text message", ErrInvalidContentType)
+	choice, err := choiceFromResponse(result, opts)
+	if err != nil {
+		return nil, err
+	}
+	resp := &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{choice},
+		Usage:   usageFromResponse(result),
+	}
+	return resp, nil
+}
+
+// checkThinkingOptions returns an error if the options can't be used with
+// extended thinking: the temperature can only be 1, the top P between 0.95 and
+// 1, the max tokens must leave room for a response after the thinking budget,
+// and the model can't be forced to use a tool, as it is for response schemas.
+// An unset max tokens defaults to the default max tokens plus the budget.
+func checkThinkingOptions(opts *llms.CallOptions) error {
+	switch {
+	case opts.Temperature != 0 && opts.Temperature != 1:
+		return fmt.Errorf("anthropic: %w: temperature %v", ErrThinkingOptions, opts.Temperature)
+	case opts.TopP != 0 && (opts.TopP < 0.95 || opts.TopP > 1):
+		return fmt.Errorf("anthropic: %w: top P %v", ErrThinkingOptions, opts.TopP)
+	case opts.MaxTokens != 0 && opts.MaxTokens <= opts.ThinkingBudget:
+		return fmt.Errorf("anthropic: %w: max tokens %d not above the thinking budget %d",
+			ErrThinkingOptions, opts.MaxTokens, opts.ThinkingBudget)
+	case opts.ResponseSchema != nil:
+		return fmt.Errorf("anthropic: %w: response schema", ErrThinkingOptions)
+	}
+	return nil
+}
+
+// choiceFromResponse converts the content blocks of a response to a single
+// choice: text blocks make up its content, tool use blocks its tool calls and
+// thinking blocks its reasoning content and thinking parts.
+func choiceFromResponse(result *anthropicclient.MessageResponsePayload, opts *llms.CallOptions) (*llms.ContentChoice, error) {
+	var content, reasoning strings.Builder
+	var schemaContent *string
+	var toolCalls []llms.ToolCall
+	var signatures []string
+	var thinking []llms.ThinkingContent
+	for _, c := range result.Content {
+		switch c := c.(type) {
+		case *anthropicclient.TextContent:
+			content.WriteString(c.Text)
+		case *anthropicclient.ToolUseContent:
+			argumentsJSON, err := json.Marshal(c.Input)
+			if err != nil {
+				return nil, fmt.Errorf("anthropic: failed to marshal tool use arguments: %w", err)
 			}
-		case "tool_use":
-			if toolUseContent, ok := content.(*anthropicclient.ToolUseContent); ok {
-				argumentsJSON, err := json.Marshal(toolUseContent.Input)
-				if err != nil {
-					return nil, fmt.Errorf("anthropic: failed to marshal tool use arguments: %w", err)
-				}
-				choices[i] = &llms.ContentChoice{
-					ToolCalls: []llms.ToolCall{
-						{
-							ID: toolUseContent.ID,
-							FunctionCall: &llms.FunctionCall{
-								Name:      toolUseContent.Name,
-								Arguments: string(argumentsJSON),
-							},
-						},
-					},
-					StopReason: result.StopReason,
-					GenerationInfo: map[string]any{
-						"InputTokens":  result.Usage.InputTokens,
-						"OutputTokens": result.Usage.OutputTokens,
-					},
-				}
-			} else {
-				return nil, fmt.Errorf("anthropic: %w for tool use message", ErrInvalidContentType)
+			if opts.ResponseSchema != nil && c.Name == opts.ResponseSchema.Name {
+				arguments := string(argumentsJSON)
+				schemaContent = &arguments
+				continue
 			}
+			toolCalls = append(toolCalls, llms.ToolCall{
+				ID:   c.ID,
+				Type: "function",
+				FunctionCall: &llms.FunctionCall{
+					Name:      c.Name,
+					Arguments: string(argumentsJSON),
+				},
+			})
+		case *anthropicclient.ThinkingContent:
+			reasoning.WriteString(c.Thinking)
+			signatures = append(signatures, c.Signature)
+			thinking = append(thinking, llms.ThinkingContent{Thinking: c.Thinking, Signature: c.Signature})
+		case *anthropicclient.RedactedThinkingContent:
+			// Redacted thinking is encrypted and can't be shown, but it must
+			// be sent back like the other thinking blocks.
+			thinking = append(thinking, llms.ThinkingContent{RedactedData: c.Data})
 		default:
-			return nil, fmt.Errorf("anthropic: %w: %v", ErrUnsupportedContentType, content.GetType())
+			return nil, fmt.Errorf("anthropic: %w: %v", ErrUnsupportedContentType, c.GetType())
 		}
 	}
 
-	resp := &llms.ContentResponse{
-		Choices: choices,
+	choice := &llms.ContentChoice{
+		Content:          content.String(),
+		StopReason:       result.StopReason,
+		ToolCalls:        toolCalls,
+		ReasoningContent: reasoning.String(),
+		Thinking:         thinking,
+		GenerationInfo: map[string]any{
+			"InputTokens":              result.Usage.InputTokens,
+			"OutputTokens":             result.Usage.OutputTokens,
+			"CacheCreationInputTokens": result.Usage.CacheCreationInputTokens,
+			"CacheReadInputTokens":     result.Usage.CacheReadInputTokens,
+		},
 	}
-	return resp, nil
+	// The response to a schema is the input of its tool.
+	if schemaContent != nil {
+		choice.Content = *schemaContent
+	}
+	if len(toolCalls) > 0 {
+		choice.FuncCall = toolCalls[0].FunctionCall
+	}
+	if len(signatures) > 0 {
+		choice.GenerationInfo["ThinkingSignatures"] = signatures
+	}
+	return choice, nil
+}
+
+// SupportsResponseSchema implements llms.ResponseSchemaSupporter: response
+// schemas are given to the model as a tool it is forced to use, and the input
+// of the tool is returned as the content of the response.
+func (o *LLM) SupportsResponseSchema() bool {
+	return !o.client.UseLegacyTextCompletionsAPI
+}
+
+// usageFromResponse converts the usage reported by the messages API. Anthropic
+// reports cache reads and writes separately from input tokens, so they are
+// added back to obtain the total prompt size.
+func usageFromResponse(result *anthropicclient.MessageResponsePayload) *llms.Usage {
+	promptTokens := result.Usage.InputTokens + result.Usage.CacheCreationInputTokens + result.Usage.CacheReadInputTokens
+	usage := llms.NewUsage(promptTokens, result.Usage.OutputTokens)
+	usage.CachedTokens = result.Usage.CacheReadInputTokens
+	usage.CacheWriteTokens = result.Usage.CacheCreationInputTokens
+	return usage
 }
 
 func toolsToTools(tools []llms.Tool) []anthropicclient.Tool {
@@ -221,9 +333,13 @@ func toolsToTools(tools []llms.Tool) []anthropicclient.Tool {
 	return toolReq
 }
 
-func processMessages(messages []llms.MessageContent) ([]anthropicclient.ChatMessage, string, error) {
+// processMessages converts the messages to the messages of the API and its
+// system prompt: a string, or text blocks if some of them are cache
+// breakpoints.
+func processMessages(messages []llms.MessageContent) ([]anthropicclient.ChatMessage, any, error) {
+	messages = mergeToolMessages(messages)
 	chatMessages := make([]anthropicclient.ChatMessage, 0, len(messages))
-	systemPrompt := ""
+	var system []*anthropicclient.TextContent
 	for _, msg := range messages {
 		if len(msg.Parts) == 0 {
 			continue
@@ -232,41 +348,95 @@ func processMessages(messages []llms.MessageContent) ([]anthropicclient.ChatMess
 		case llms.ChatMessageTypeSystem:
 			content, err := handleSystemMessage(msg)
 			if err != nil {
-				return nil, "", fmt.Errorf("anthropic: failed to handle system message: %w", err)
+				return nil, nil, fmt.Errorf("anthropic: failed to handle system message: %w", err)
 			}
-			systemPrompt += content
+			system = append(system, content...)
 		case llms.ChatMessageTypeHuman:
 			chatMessage, err := handleHumanMessage(msg)
 			if err != nil {
-				return nil, "", fmt.Errorf("anthropic: failed to handle human message: %w", err)
+				return nil, nil, fmt.Errorf("anthropic: failed to handle human message: %w", err)
 			}
 			chatMessages = append(chatMessages, chatMessage)
 		case llms.ChatMessageTypeAI:
 			chatMessage, err := handleAIMessage(msg)
 			if err != nil {
-				return nil, "", fmt.Errorf("anthropic: failed to handle AI message: %w", err)
+				return nil, nil, fmt.Errorf("anthropic: failed to handle AI message: %w", err)
 			}
 			chatMessages = append(chatMessages, chatMessage)
 		case llms.ChatMessageTypeTool:
 			chatMessage, err := handleToolMessage(msg)
 			if err != nil {
-				return nil, "", fmt.Errorf("anthropic: failed to handle tool message: %w", err)
+				return nil, nil, fmt.Errorf("anthropic: failed to handle tool message: %w", err)
 			}
 			chatMessages = append(chatMessages, chatMessage)
 		case llms.ChatMessageTypeGeneric, llms.ChatMessageTypeFunction:
-			return nil, "", fmt.Errorf("anthropic: %w: %v", ErrUnsupportedMessageType, msg.Role)
+			return nil, nil, fmt.Errorf("anthropic: %w: %v", ErrUnsupportedMessageType, msg.Role)
 		default:
-			return nil, "", fmt.Errorf("anthropic: %w: %v", ErrUnsupportedMessageType, msg.Role)
+			return nil, nil, fmt.Errorf("anthropic: %w: %v", ErrUnsupportedMessageType, msg.Role)
+		}
+	}
+	return chatMessages, systemPrompt(system), nil
+}
+
+// mergeToolMessages merges the consecutive tool messages, e.g. with the
+// results of parallel tool calls in a message each, as the results must be in
+// a single user message.
+func mergeToolMessages(messages []llms.MessageContent) []llms.MessageContent {
+	merged := make([]llms.MessageContent, 0, len(messages))
+	for _, msg := range messages {
+		n := len(merged)
+		if n > 0 && msg.Role == llms.ChatMessageTypeTool && merged[n-1].Role == llms.ChatMessageTypeTool {
+			parts := make([]llms.ContentPart, 0, len(merged[n-1].Parts)+len(msg.Parts))
+			parts = append(parts, merged[n-1].Parts...)
+			merged[n-1].Parts = append(parts, msg.Parts...)
+			continue
+		}
+		merged = append(merged, msg)
+	}
+	return merged
+}
+
+// systemPrompt returns the system prompt made of the text blocks.
+func systemPrompt(blocks []*anthropicclient.TextContent) any {
+	if len(blocks) == 0 {
+		return nil
+	}
+	var sb strings.Builder
+	for _, block := range blocks {
+		if block.CacheControl != nil {
+			return blocks
 		}
+		sb.WriteString(block.Text)
 	}
-	return chatMessages, systemPrompt, nil
+	return sb.String()
 }
 
-func handleSystemMessage(msg llms.MessageContent) (string, error) {
-	if textContent, ok := msg.Parts[0].(llms.TextContent); ok {
-		return textContent.Text, nil
+func handleSystemMessage(msg llms.MessageContent) ([]*anthropicclient.TextContent, error) {
+	blocks := make([]*anthropicclient.TextContent, 0, len(msg.Parts))
+	for _, part := range msg.Parts {
+		textContent, ok := part.(llms.TextContent)
+		if !ok {
+			return nil, fmt.Errorf("anthropic: %w for system message", ErrInvalidContentType)
+		}
+		blocks = append(blocks, &anthropicclient.TextContent{
+			Type:         "text",
+			Text:         textContent.Text,
+			CacheControl: cacheControl(textContent.CacheControl),
+		})
 	}
-	return "", fmt.Errorf("anthropic: %w for system message", ErrInvalidContentType)
+	return blocks, nil
+}
+
+// cacheControl converts the cache control of a content part.
+func cacheControl(cc *llms.CacheControl) *anthropicclient.CacheControl {
+	if cc == nil {
+		return nil
+	}
+	typ := cc.Type
+	if typ == "" {
+		typ = "ephemeral"
+	}
+	return &anthropicclient.CacheControl{Type: typ, TTL: cc.TTL}
 }
 
 func handleHumanMessage(msg llms.MessageContent) (anthropicclient.ChatMessage, error) {
@@ -276,8 +446,9 @@ func handleHumanMessage(msg llms.MessageContent) (anthropicclient.ChatMessage, e
 		switch p := part.(type) {
 		case llms.TextContent:
 			contents = append(contents, &anthropicclient.TextContent{
-				Type: "text",
-				Text: p.Text,
+				Type:         "text",
+				Text:         p.Text,
+				CacheControl: cacheControl(p.CacheControl),
 			})
 		case llms.BinaryContent:
 			contents = append(contents, &anthropicclient.ImageContent{
@@ -287,6 +458,7 @@ func handleHumanMessage(msg llms.MessageContent) (anthropicclient.ChatMessage, e
 					MediaType: p.MIMEType,
 					Data:      base64.StdEncoding.EncodeToString(p.Data),
 				},
+				CacheControl: cacheControl(p.CacheControl),
 			})
 		default:
 			return anthropicclient.ChatMessage{}, fmt.Errorf("anthropic: unsupported human message part type: %T", part)
@@ -303,35 +475,66 @@ func handleHumanMessage(msg llms.MessageContent) (anthropicclient.ChatMessage, e
 	}, nil
 }
 
+// handleAIMessage converts an AI message, whose parts are thinking, text and
+// tool calls in the order they were generated. The thinking parts of the
+// previous response must be sent back when continuing a tool use with
+// extended thinking.
 func handleAIMessage(msg llms.MessageContent) (anthropicclient.ChatMessage, error) {
-	if toolCall, ok := msg.Parts[0].(llms.ToolCall); ok {
-		var inputStruct map[string]interface{}
-		err := json.Unmarshal([]byte(toolCall.FunctionCall.Arguments), &inputStruct)
-		if err != nil {
-			return anthropicclient.ChatMessage{}, fmt.Errorf("anthropic: failed to unmarshal tool call arguments: %w", err)
-		}
-		toolUse := anthropicclient.ToolUseContent{
-			Type:  "tool_use",
-			ID:    toolCall.ID,
-			Name:  toolCall.FunctionCall.Name,
-			Input: inputStruct,
+	contents := make([]anthropicclient.Content, 0, len(msg.Parts))
+	for _, part := range msg.Parts {
+		switch p := part.(type) {
+		case llms.TextContent:
+			// Empty text blocks are rejected by the API.
+			if p.Text == "" {
+				continue
+			}
+			contents = append(contents, &anthropicclient.TextContent{
+				Type:         "text",
+				Text:         p.Text,
+				CacheControl: cacheControl(p.CacheControl),
+			})
+		case llms.ThinkingContent:
+			if p.RedactedData != "" {
+				contents = append(contents, &anthropicclient.RedactedThinkingContent{
+					Type: "redacted_thinking",
+					Data: p.RedactedData,
+				})
+				continue
+			}
+			contents = append(contents, &anthropicclient.ThinkingContent{
+				Type:      "thinking",
+				Thinking:  p.Thinking,
+				Signature: p.Signature,
+			})
+		case llms.ToolCall:
+			if p.FunctionCall == nil {
+				return anthropicclient.ChatMessage{}, fmt.Errorf("anthropic: %w: tool call without function", ErrInvalidContentType)
+			}
+			inputStruct := map[string]interface{}{}
+			if p.FunctionCall.Arguments != "" {
+				if err := json.Unmarshal([]byte(p.FunctionCall.Arguments), &inputStruct); err != nil {
+					return anthropicclient.ChatMessage{}, fmt.Errorf("anthropic: failed to unmarshal tool call arguments: %w", err)
+				}
+			}
+			contents = append(contents, &anthropicclient.ToolUseContent{
+				Type:         "tool_use",
+				ID:           p.ID,
+				Name:         p.FunctionCall.Name,
+				Input:        inputStruct,
+				CacheControl: cacheControl(p.CacheControl),
+			})
+		default:
+			return anthropicclient.ChatMessage{}, fmt.Errorf("anthropic: %w for AI message", ErrInvalidContentType)
 		}
+	}
+	if len(contents) == 0 {
+		return anthropicclient.ChatMessage{}, fmt.Errorf("anthropic: no valid content in AI message")
+	}
 
-		return anthropicclient.ChatMessage{
-			Role:    RoleAssistant,
-			Content: []anthropicclient.Content{toolUse},
-		}, nil
-	}
-	if textContent, ok := msg.Parts[0].(llms.TextContent); ok {
-		return anthropicclient.ChatMessage{
-			Role: RoleAssistant,
-			Content: []anthropicclient.Content{&anthropicclient.TextContent{
-				Type: "text",
-				Text: textContent.Text,
-			}},
-		}, nil
-	}
-	return anthropicclient.ChatMessage{}, fmt.Errorf("anthropic: %w for AI message", ErrInvalidContentType)
+	return anthropicclient.ChatMessage{
+		Role:    RoleAssistant,
+		Content: contents,
+	}, nil
 }
 
 type ToolResult struct {
@@ -340,18 +543,25 @@ type ToolResult struct {
 	Content   string `json:"content"`
 }
 
+// handleToolMessage converts a tool message, which may hold the results of
+// several tool calls of the previous AI message.
 func handleToolMessage(msg llms.MessageContent) (anthropicclient.ChatMessage, error) {
-	if toolCallResponse, ok := msg.Parts[0].(llms.ToolCallResponse); ok {
-		toolContent := anthropicclient.ToolResultContent{
-			Type:      "tool_result",
-			ToolUseID: toolCallResponse.ToolCallID,
-			Content:   toolCallResponse.Content,
+	contents := make([]anthropicclient.Content, 0, len(msg.Parts))
+	for _, part := range msg.Parts {
+		toolCallResponse, ok := part.(llms.ToolCallResponse)
+		if !ok {
+			return anthropicclient.ChatMessage{}, fmt.Errorf("anthropic: %w for tool message", ErrInvalidContentType)
 		}
-
-		return anthropicclient.ChatMessage{
-			Role:    RoleUser,
-			Content: []anthropicclient.Content{toolContent},
-		}, nil
+		contents = append(contents, &anthropicclient.ToolResultContent{
+			Type:         "tool_result",
+			ToolUseID:    toolCallResponse.ToolCallID,
+			Content:      toolCallResponse.Content,
+			CacheControl: cacheControl(toolCallResponse.CacheControl),
+		})
 	}
-	return anthropicclient.ChatMessage{}, fmt.Errorf("anthropic: %w for tool message", ErrInvalidContentType)
+
+	return anthropicclient.ChatMessage{
+		Role:    RoleUser,
+		Content: contents,
+	}, nil
 }
@@ -0,0 +1,200 @@
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// mockMessagesServer serves /messages with the given response and records the
+// bodies of the requests it receives.
+func mockMessagesServer(t *testing.T, response string, requests *[]string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/messages", r.URL.Path)
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		*requests = append(*requests, string(body))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, response)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGenerateContentMultipleToolCalls(t *testing.T) {
+	t.Parallel()
+
+	var requests []string
+	server := mockMessagesServer(t, `{
+		"id": "msg_01", "type": "message", "role": "assistant", "model": "claude-3-7-sonnet-20250219",
+		"content": [
+			{"type": "thinking", "thinking": "Both cities are needed.", "signature": "sig"},
+			{"type": "redacted_thinking", "data": "encrypted"},
+			{"type": "text", "text": "Let me check both."},
+			{"type": "tool_use", "id": "toolu_3", "name": "weather", "input": {"city": "Berlin"}},
+			{"type": "tool_use", "id": "toolu_4", "name": "weather", "input": {"city": "Madrid"}}
+		],
+		"stop_reason": "tool_use",
+		"usage": {"input_tokens": 50, "output_tokens": 80, "cache_creation_input_tokens": 1000, "cache_read_input_tokens": 3000}
+	}`, &requests)
+
+	llm, err := New(WithToken("test"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	messages := []llms.MessageContent{
+		{Role: llms.ChatMessageTypeSystem, Parts: []llms.ContentPart{
+			llms.WithCacheControl(llms.TextPart("You know the weather."), llms.CacheControl{}),
+		}},
+		llms.TextParts(llms.ChatMessageTypeHuman, "Weather in Paris and Rome?"),
+		{Role: llms.ChatMessageTypeAI, Parts: []llms.ContentPart{
+			llms.ThinkingContent{Thinking: "Paris and Rome.", Signature: "sig0"},
+			llms.ThinkingContent{RedactedData: "encrypted0"},
+			llms.TextPart("Checking."),
+			llms.ToolCall{ID: "toolu_1", Type: "function", FunctionCall: &llms.FunctionCall{Name: "weather", Arguments: `{"city":"Paris"}`}},
+			llms.ToolCall{ID: "toolu_2", Type: "function", FunctionCall: &llms.FunctionCall{Name: "weather", Arguments: `{"city":"Rome"}`}},
+		}},
+		{Role: llms.ChatMessageTypeTool, Parts: []llms.ContentPart{
+			llms.ToolCallResponse{ToolCallID: "toolu_1", Name: "weather", Content: "sunny"},
+			llms.WithCacheControl(llms.ToolCallResponse{ToolCallID: "toolu_2", Name: "weather", Content: "rainy"},
+				llms.CacheControl{Type: "ephemeral", TTL: "1h"}),
+		}},
+		llms.TextParts(llms.ChatMessageTypeHuman, "And Berlin and Madrid?"),
+	}
+	resp, err := llm.GenerateContent(context.Background(), messages, llms.WithThinkingBudget(1024), llms.WithTopP(0.95))
+	require.NoError(t, err)
+
+	require.Len(t, requests, 1)
+	assert.JSONEq(t, `{
+		"model": "claude-3-5-sonnet-20240620",
+		"system": [{"type": "text", "text": "You know the weather.", "cache_control": {"type": "ephemeral"}}],
+		"messages": [
+			{"role": "user", "content": [{"type": "text", "text": "Weather in Paris and Rome?"}]},
+			{"role": "assistant", "content": [
+				{"type": "thinking", "thinking": "Paris and Rome.", "signature": "sig0"},
+				{"type": "redacted_thinking", "data": "encrypted0"},
+				{"type": "text", "text": "Checking."},
+				{"type": "tool_use", "id": "toolu_1", "name": "weather", "input": {"city": "Paris"}},
+				{"type": "tool_use", "id": "toolu_2", "name": "weather", "input": {"city": "Rome"}}
+			]},
+			{"role": "user", "content": [
+				{"type": "tool_result", "tool_use_id": "toolu_1", "content": "sunny"},
+				{"type": "tool_result", "tool_use_id": "toolu_2", "content": "rainy", "cache_control": {"type": "ephemeral", "ttl": "1h"}}
+			]},
+			{"role": "user", "content": [{"type": "text", "text": "And Berlin and Madrid?"}]}
+		],
+		"max_tokens": 2048,
+		"temperature": 1,
+		"top_p": 0.95,
+		"thinking": {"type": "enabled", "budget_tokens": 1024}
+	}`, requests[0])
+
+	require.Len(t, resp.Choices, 1)
+	choice := resp.Choices[0]
+	assert.Equal(t, "Let me check both.", choice.Content)
+	assert.Equal(t, "Both cities are needed.", choice.ReasoningContent)
+	assert.Equal(t, []llms.ThinkingContent{
+		{Thinking: "Both cities are needed.", Signature: "sig"},
+		{RedactedData: "encrypted"},
+	}, choice.Thinking)
+	assert.Equal(t, "tool_use", choice.StopReason)
+	assert.Equal(t, []llms.ToolCall{
+		{ID: "toolu_3", Type: "function", FunctionCall: &llms.FunctionCall{Name: "weather", Arguments: `{"city":"Berlin"}`}},
+		{ID: "toolu_4", Type: "function", FunctionCall: &llms.FunctionCall{Name: "weather", Arguments: `{"city":"Madrid"}`}},
+	}, choice.ToolCalls)
+	assert.Equal(t, choice.ToolCalls[0].FunctionCall, choice.FuncCall)
+
+	assert.Equal(t, &llms.Usage{
+		PromptTokens:     4050,
+		CompletionTokens: 80,
+		TotalTokens:      4130,
+		CachedTokens:     3000,
+		CacheWriteTokens: 1000,
+	}, resp.Usage)
+}
+
+func TestGenerateContentThinkingOptions(t *testing.T) {
+	t.Parallel()
+
+	var requests []string
+	server := mockMessagesServer(t, `{}`, &requests)
+	llm, err := New(WithToken("test"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		options []llms.CallOption
+	}{
+		{"temperature", []llms.CallOption{llms.WithTemperature(0.2)}},
+		{"top P", []llms.CallOption{llms.WithTopP(0.5)}},
+		{"max tokens", []llms.CallOption{llms.WithMaxTokens(1024)}},
+		{"response schema", []llms.CallOption{llms.WithResponseSchema(&llms.ResponseSchema{Name: "answer"})}},
+	}
+	for _, tt := range tests {
+		options := append([]llms.CallOption{llms.WithThinkingBudget(1024)}, tt.options...)
+		_, err := llm.GenerateContent(context.Background(), []llms.MessageContent{
+			llms.TextParts(llms.ChatMessageTypeHuman, "Hi"),
+		}, options...)
+		require.ErrorIs(t, err, ErrThinkingOptions, tt.name)
+	}
+	assert.Empty(t, requests)
+}
+
+func TestGenerateContentToolMessages(t *testing.T) {
+	t.Parallel()
+
+	var requests []string
+	server := mockMessagesServer(t, `{
+		"id": "msg_01", "type": "message", "role": "assistant", "model": "claude-3-5-sonnet-20240620",
+		"content": [{"type": "text", "text": "Sunny and rainy."}],
+		"stop_reason": "end_turn",
+		"usage": {"input_tokens": 50, "output_tokens": 5}
+	}`, &requests)
+
+	llm, err := New(WithToken("test"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	// The results of the parallel tool calls are in a tool message each, as
+	// with openai.
+	messages := []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "Weather in Paris and Rome?"),
+		{Role: llms.ChatMessageTypeAI, Parts: []llms.ContentPart{
+			llms.ToolCall{ID: "toolu_1", Type: "function", FunctionCall: &llms.FunctionCall{Name: "weather", Arguments: `{"city":"Paris"}`}},
+			llms.ToolCall{ID: "toolu_2", Type: "function", FunctionCall: &llms.FunctionCall{Name: "weather", Arguments: `{"city":"Rome"}`}},
+		}},
+		{Role: llms.ChatMessageTypeTool, Parts: []llms.ContentPart{
+			llms.ToolCallResponse{ToolCallID: "toolu_1", Name: "weather", Content: "sunny"},
+		}},
+		{Role: llms.ChatMessageTypeTool, Parts: []llms.ContentPart{
+			llms.ToolCallResponse{ToolCallID: "toolu_2", Name: "weather", Content: "rainy"},
+		}},
+	}
+	_, err = llm.GenerateContent(context.Background(), messages)
+	require.NoError(t, err)
+
+	require.Len(t, requests, 1)
+	var payload struct {
+		Messages json.RawMessage `json:"messages"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(requests[0]), &payload))
+	assert.JSONEq(t, `[
+		{"role": "user", "content": [{"type": "text", "text": "Weather in Paris and Rome?"}]},
+		{"role": "assistant", "content": [
+			{"type": "tool_use", "id": "toolu_1", "name": "weather", "input": {"city": "Paris"}},
+			{"type": "tool_use", "id": "toolu_2", "name": "weather", "input": {"city": "Rome"}}
+		]},
+		{"role": "user", "content": [
+			{"type": "tool_result", "tool_use_id": "toolu_1", "content": "sunny"},
+			{"type": "tool_result", "tool_use_id": "toolu_2", "content": "rainy"}
+		]}
+	]`, string(payload.Messages))
+}
@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+
+	"github.com/tmc/langchaingo/llms"
 )
 
 const (
@@ -124,32 +126,42 @@ func (c *Client) CreateCompletion(ctx context.Context, r *CompletionRequest) (*C
 }
 
 type MessageRequest struct {
-	Model       string        `json:"model"`
-	Messages    []ChatMessage `json:"messages"`
-	System      string        `json:"system,omitempty"`
-	Temperature float64       `json:"temperature"`
-	MaxTokens   int           `json:"max_tokens,omitempty"`
-	TopP        float64       `json:"top_p,omitempty"`
-	Tools       []Tool        `json:"tools,omitempty"`
-	StopWords   []string      `json:"stop_sequences,omitempty"`
-	Stream      bool          `json:"stream,omitempty"`
-
-	StreamingFunc func(ctx context.Context, chunk []byte) error `json:"-"`
+	Model    string        `json:"model"`
+	Messages []ChatMessage `json:"messages"`
+	// System is the system prompt, either a string or text content blocks
+	// when parts of it are cached.
+	System      any         `json:"system,omitempty"`
+	Temperature float64     `json:"temperature"`
+	MaxTokens   int         `json:"max_tokens,omitempty"`
+	TopP        float64     `json:"top_p,omitempty"`
+	Tools       []Tool      `json:"tools,omitempty"`
+	ToolChoice  *ToolChoice `json:"tool_choice,omitempty"`
+	StopWords   []string    `json:"stop_sequences,omitempty"`
+	Stream      bool        `json:"stream,omitempty"`
+	Thinking    *Thinking   `json:"thinking,omitempty"`
+
+	StreamingFunc          func(ctx context.Context, chunk []byte) error                 `json:"-"`
+	StreamingReasoningFunc func(ctx context.Context, reasoningChunk, chunk []byte) error `json:"-"`
+	StreamingEventFunc     func(ctx context.Context, event llms.StreamEvent) error       `json:"-"`
 }
 
 // CreateMessage creates message for the messages api.
 func (c *Client) CreateMessage(ctx context.Context, r *MessageRequest) (*MessageResponsePayload, error) {
 	resp, err := c.createMessage(ctx, &messagePayload{
-		Model:         r.Model,
-		Messages:      r.Messages,
-		System:        r.System,
-		Temperature:   r.Temperature,
-		MaxTokens:     r.MaxTokens,
-		StopWords:     r.StopWords,
-		TopP:          r.TopP,
-		Tools:         r.Tools,
-		Stream:        r.Stream,
-		StreamingFunc: r.StreamingFunc,
+		Model:                  r.Model,
+		Messages:               r.Messages,
+		System:                 r.System,
+		Temperature:            r.Temperature,
+		MaxTokens:              r.MaxTokens,
+		StopWords:              r.StopWords,
+		TopP:                   r.TopP,
+		Tools:                  r.Tools,
+		ToolChoice:             r.ToolChoice,
+		Stream:                 r.Stream,
+		Thinking:               r.Thinking,
+		StreamingFunc:          r.StreamingFunc,
+		StreamingReasoningFunc: r.StreamingReasoningFunc,
+		StreamingEventFunc:     r.StreamingEventFunc,
 	})
 	if err != nil {
 		return nil, err
@@ -198,11 +210,10 @@ type errorMessage struct {
 }
 
 func (c *Client) decodeError(resp *http.Response) error {
-	msg := fmt.Sprintf("API returned unexpected status code: %d", resp.StatusCode)
-
+	// No need to check the error here: if it fails, we'll just return the
+	// status code.
 	var errResp errorMessage
-	if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil {
-		return errors.New(msg) // nolint:goerr113
-	}
-	return fmt.Errorf("%s: %s", msg, errResp.Error.Message) // nolint:goerr113
+	_ = json.NewDecoder(resp.Body).Decode(&errResp)
+
+	return llms.NewAPIError(resp, errResp.Error.Message)
 }
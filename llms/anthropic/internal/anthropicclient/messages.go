@@ -11,6 +11,8 @@ import (
 	"log"
 	"net/http"
 	"strings"
+
+	"github.com/tmc/langchaingo/llms"
 )
 
 var (
@@ -23,6 +25,8 @@ var (
 	ErrInvalidDeltaTextField   = fmt.Errorf("invalid delta text field type")
 	ErrContentIndexOutOfRange  = fmt.Errorf("content index out of range")
 	ErrFailedCastToTextContent = fmt.Errorf("failed to cast content to TextContent")
+	ErrFailedCastToToolUse     = fmt.Errorf("failed to cast content to ToolUseContent")
+	ErrFailedCastToThinking    = fmt.Errorf("failed to cast content to ThinkingContent")
 	ErrInvalidFieldType        = fmt.Errorf("invalid field type")
 )
 
@@ -34,15 +38,42 @@ type ChatMessage struct {
 type messagePayload struct {
 	Model       string        `json:"model"`
 	Messages    []ChatMessage `json:"messages"`
-	System      string        `json:"system,omitempty"`
+	System      any           `json:"system,omitempty"`
 	MaxTokens   int           `json:"max_tokens,omitempty"`
 	StopWords   []string      `json:"stop_sequences,omitempty"`
 	Stream      bool          `json:"stream,omitempty"`
 	Temperature float64       `json:"temperature"`
 	Tools       []Tool        `json:"tools,omitempty"`
+	ToolChoice  *ToolChoice   `json:"tool_choice,omitempty"`
 	TopP        float64       `json:"top_p,omitempty"`
+	Thinking    *Thinking     `json:"thinking,omitempty"`
+
+	StreamingFunc          func(ctx context.Context, chunk []byte) error                 `json:"-"`
+	StreamingReasoningFunc func(ctx context.Context, reasoningChunk, chunk []byte) error `json:"-"`
+	StreamingEventFunc     func(ctx context.Context, event llms.StreamEvent) error       `json:"-"`
+}
 
-	StreamingFunc func(ctx context.Context, chunk []byte) error `json:"-"`
+// streaming reports whether the response is streamed to one of the streaming
+// functions of the payload.
+func (p *messagePayload) streaming() bool {
+	return p.StreamingFunc != nil || p.StreamingReasoningFunc != nil || p.StreamingEventFunc != nil
+}
+
+// Thinking configures the extended thinking of the model.
+type Thinking struct {
+	// Type is "enabled" or "disabled".
+	Type string `json:"type"`
+	// BudgetTokens is the number of tokens the model may spend thinking. It
+	// must be less than the max tokens of the request.
+	BudgetTokens int `json:"budget_tokens,omitempty"`
+}
+
+// CacheControl marks a content block as a prompt cache breakpoint.
+type CacheControl struct {
+	// Type is "ephemeral".
+	Type string `json:"type"`
+	// TTL is the lifetime of the cache entry, "5m" or "1h".
+	TTL string `json:"ttl,omitempty"`
 }
 
 // Tool used for the request message payload.
@@ -52,14 +83,23 @@ type Tool struct {
 	InputSchema any    `json:"input_schema,omitempty"`
 }
 
+// ToolChoice controls how the model uses the tools of the request.
+type ToolChoice struct {
+	// Type is "auto", "any" or "tool".
+	Type string `json:"type"`
+	// Name is the name of the tool to use, if Type is "tool".
+	Name string `json:"name,omitempty"`
+}
+
 // Content can be TextContent or ToolUseContent depending on the type.
 type Content interface {
 	GetType() string
 }
 
 type TextContent struct {
-	Type string `json:"type"`
-	Text string `json:"text"`
+	Type         string        `json:"type"`
+	Text         string        `json:"text"`
+	CacheControl *CacheControl `json:"cache_control,omitempty"`
 }
 
 func (tc TextContent) GetType() string {
@@ -67,8 +107,9 @@ func (tc TextContent) GetType() string {
 }
 
 type ImageContent struct {
-	Type   string      `json:"type"`
-	Source ImageSource `json:"source"`
+	Type         string        `json:"type"`
+	Source       ImageSource   `json:"source"`
+	CacheControl *CacheControl `json:"cache_control,omitempty"`
 }
 
 func (ic ImageContent) GetType() string {
@@ -82,10 +123,15 @@ type ImageSource struct {
 }
 
 type ToolUseContent struct {
-	Type  string                 `json:"type"`
-	ID    string                 `json:"id"`
-	Name  string                 `json:"name"`
-	Input map[string]interface{} `json:"input"`
+	Type         string                 `json:"type"`
+	ID           string                 `json:"id"`
+	Name         string                 `json:"name"`
+	Input        map[string]interface{} `json:"input"`
+	CacheControl *CacheControl          `json:"cache_control,omitempty"`
+
+	// inputJSON accumulates the partial JSON of the input of streamed tool
+	// uses, until the content block is complete.
+	inputJSON strings.Builder
 }
 
 func (tuc ToolUseContent) GetType() string {
@@ -93,15 +139,39 @@ func (tuc ToolUseContent) GetType() string {
 }
 
 type ToolResultContent struct {
-	Type      string `json:"type"`
-	ToolUseID string `json:"tool_use_id"`
-	Content   string `json:"content"`
+	Type         string        `json:"type"`
+	ToolUseID    string        `json:"tool_use_id"`
+	Content      string        `json:"content"`
+	CacheControl *CacheControl `json:"cache_control,omitempty"`
 }
 
 func (trc ToolResultContent) GetType() string {
 	return trc.Type
 }
 
+// ThinkingContent is the reasoning of the model before its response. The
+// signature lets the API verify the thinking when it is sent back.
+type ThinkingContent struct {
+	Type      string `json:"type"`
+	Thinking  string `json:"thinking"`
+	Signature string `json:"signature,omitempty"`
+}
+
+func (tc ThinkingContent) GetType() string {
+	return tc.Type
+}
+
+// RedactedThinkingContent is reasoning of the model encrypted for safety
+// reasons.
+type RedactedThinkingContent struct {
+	Type string `json:"type"`
+	Data string `json:"data"`
+}
+
+func (rtc RedactedThinkingContent) GetType() string {
+	return rtc.Type
+}
+
 type MessageResponsePayload struct {
 	Content      []Content `json:"content"`
 	ID           string    `json:"id"`
@@ -111,8 +181,10 @@ type MessageResponsePayload struct {
 	StopSequence string    `json:"stop_sequence"`
 	Type         string    `json:"type"`
 	Usage        struct {
-		InputTokens  int `json:"input_tokens"`
-		OutputTokens int `json:"output_tokens"`
+		InputTokens              int `json:"input_tokens"`
+		OutputTokens             int `json:"output_tokens"`
+		CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+		CacheReadInputTokens     int `json:"cache_read_input_tokens"`
 	} `json:"usage"`
 }
 
@@ -149,6 +221,18 @@ func (m *MessageResponsePayload) UnmarshalJSON(data []byte) error {
 				return err
 			}
 			m.Content = append(m.Content, tuc)
+		case "thinking":
+			tc := &ThinkingContent{}
+			if err := json.Unmarshal(raw, tc); err != nil {
+				return err
+			}
+			m.Content = append(m.Content, tc)
+		case "redacted_thinking":
+			rtc := &RedactedThinkingContent{}
+			if err := json.Unmarshal(raw, rtc); err != nil {
+				return err
+			}
+			m.Content = append(m.Content, rtc)
 		default:
 			return fmt.Errorf("unknown content type: %s\n%v", typeStruct.Type, string(raw))
 		}
@@ -162,6 +246,11 @@ func (c *Client) setMessageDefaults(payload *messagePayload) {
 	if payload.MaxTokens == 0 {
 		payload.MaxTokens = 2048
 	}
+	// The thinking budget is part of the max tokens, so the default max
+	// tokens must leave room for the response.
+	if payload.Thinking != nil && payload.MaxTokens <= payload.Thinking.BudgetTokens {
+		payload.MaxTokens += payload.Thinking.BudgetTokens
+	}
 
 	if len(payload.StopWords) == 0 {
 		payload.StopWords = nil
@@ -178,7 +267,7 @@ func (c *Client) setMessageDefaults(payload *messagePayload) {
 	default:
 		payload.Model = defaultModel
 	}
-	if payload.StreamingFunc != nil {
+	if payload.streaming() {
 		payload.Stream = true
 	}
 }
@@ -201,7 +290,7 @@ func (c *Client) createMessage(ctx context.Context, payload *messagePayload) (*M
 		return nil, c.decodeError(resp)
 	}
 
-	if payload.StreamingFunc != nil {
+	if payload.streaming() {
 		return parseStreamingMessageResponse(ctx, resp, payload)
 	}
 
@@ -272,11 +361,11 @@ func processStreamEvent(ctx context.Context, event map[string]interface{}, paylo
 	case "message_start":
 		return handleMessageStartEvent(event, response)
 	case "content_block_start":
-		return handleContentBlockStartEvent(event, response)
+		return handleContentBlockStartEvent(ctx, event, response, payload)
 	case "content_block_delta":
 		return handleContentBlockDeltaEvent(ctx, event, response, payload)
 	case "content_block_stop":
-		// Nothing to do here
+		return handleContentBlockStopEvent(event, response)
 	case "message_delta":
 		return handleMessageDeltaEvent(event, response)
 	case "message_stop":
@@ -312,31 +401,69 @@ func handleMessageStartEvent(event map[string]interface{}, response MessageRespo
 	response.Role = getString(message, "role")
 	response.Type = getString(message, "type")
 	response.Usage.InputTokens = int(inputTokens)
+	if cacheCreationTokens, ok := usage["cache_creation_input_tokens"].(float64); ok {
+		response.Usage.CacheCreationInputTokens = int(cacheCreationTokens)
+	}
+	if cacheReadTokens, ok := usage["cache_read_input_tokens"].(float64); ok {
+		response.Usage.CacheReadInputTokens = int(cacheReadTokens)
+	}
 
 	return response, nil
 }
 
-func handleContentBlockStartEvent(event map[string]interface{}, response MessageResponsePayload) (MessageResponsePayload, error) {
+func handleContentBlockStartEvent(ctx context.Context, event map[string]interface{}, response MessageResponsePayload, payload *messagePayload) (MessageResponsePayload, error) {
 	indexValue, ok := event["index"].(float64)
 	if !ok {
 		return response, ErrInvalidIndexField
 	}
 	index := int(indexValue)
 
-	var eventType string
+	var eventType, id, name, data string
 	if cb, ok := event["content_block"].(map[string]any); ok {
-		typ, _ := cb["type"].(string)
-		eventType = typ
+		eventType, _ = cb["type"].(string)
+		id, _ = cb["id"].(string)
+		name, _ = cb["name"].(string)
+		data, _ = cb["data"].(string)
 	}
 
 	if len(response.Content) <= index {
-		response.Content = append(response.Content, &TextContent{
-			Type: eventType,
+		var content Content
+		switch eventType {
+		case "tool_use":
+			content = &ToolUseContent{Type: eventType, ID: id, Name: name}
+		case "thinking":
+			content = &ThinkingContent{Type: eventType}
+		case "redacted_thinking":
+			content = &RedactedThinkingContent{Type: eventType, Data: data}
+		default:
+			content = &TextContent{Type: eventType}
+		}
+		response.Content = append(response.Content, content)
+	}
+	if eventType == "tool_use" && payload.StreamingEventFunc != nil {
+		err := payload.StreamingEventFunc(ctx, llms.StreamEvent{
+			Type:     llms.StreamEventToolCall,
+			ToolCall: &llms.ToolCallDelta{Index: toolCallIndex(response, index), ID: id, Name: name},
 		})
+		if err != nil {
+			return response, fmt.Errorf("streaming event func returned an error: %w", err)
+		}
 	}
 	return response, nil
 }
 
+// toolCallIndex returns the position of the tool call of the content block
+// at index among the tool calls of the response.
+func toolCallIndex(response MessageResponsePayload, index int) int {
+	n := 0
+	for _, content := range response.Content[:index] {
+		if content.GetType() == "tool_use" {
+			n++
+		}
+	}
+	return n
+}
+
 func handleContentBlockDeltaEvent(ctx context.Context, event map[string]interface{}, response MessageResponsePayload, payload *messagePayload) (MessageResponsePayload, error) {
 	indexValue, ok := event["index"].(float64)
 	if !ok {
@@ -368,7 +495,68 @@ func handleContentBlockDeltaEvent(ctx context.Context, event map[string]interfac
 		textContent.Text += text
 	}
 
-	if payload.StreamingFunc != nil {
+	if deltaType == "input_json_delta" {
+		partialJSON, _ := delta["partial_json"].(string)
+		if len(response.Content) <= index {
+			return response, ErrContentIndexOutOfRange
+		}
+		toolUse, ok := response.Content[index].(*ToolUseContent)
+		if !ok {
+			return response, ErrFailedCastToToolUse
+		}
+		toolUse.inputJSON.WriteString(partialJSON)
+	}
+
+	if deltaType == "thinking_delta" || deltaType == "signature_delta" {
+		if len(response.Content) <= index {
+			return response, ErrContentIndexOutOfRange
+		}
+		thinking, ok := response.Content[index].(*ThinkingContent)
+		if !ok {
+			return response, ErrFailedCastToThinking
+		}
+		text, _ := delta["thinking"].(string)
+		signature, _ := delta["signature"].(string)
+		thinking.Thinking += text
+		thinking.Signature += signature
+	}
+
+	if payload.StreamingEventFunc != nil {
+		var event llms.StreamEvent
+		switch deltaType {
+		case "text_delta":
+			text, _ := delta["text"].(string)
+			event = llms.StreamEvent{Type: llms.StreamEventText, Text: text}
+		case "thinking_delta":
+			text, _ := delta["thinking"].(string)
+			event = llms.StreamEvent{Type: llms.StreamEventReasoning, Text: text}
+		case "input_json_delta":
+			partialJSON, _ := delta["partial_json"].(string)
+			if partialJSON == "" {
+				return response, nil
+			}
+			event = llms.StreamEvent{
+				Type:     llms.StreamEventToolCall,
+				ToolCall: &llms.ToolCallDelta{Index: toolCallIndex(response, index), Arguments: partialJSON},
+			}
+		default:
+			return response, nil
+		}
+		if err := payload.StreamingEventFunc(ctx, event); err != nil {
+			return response, fmt.Errorf("streaming event func returned an error: %w", err)
+		}
+		return response, nil
+	}
+
+	if payload.StreamingReasoningFunc != nil && deltaType == "thinking_delta" {
+		text, _ := delta["thinking"].(string)
+		err := payload.StreamingReasoningFunc(ctx, []byte(text), nil)
+		if err != nil {
+			return response, fmt.Errorf("streaming reasoning func returned an error: %w", err)
+		}
+	}
+
+	if payload.StreamingFunc != nil && deltaType == "text_delta" {
 		text, ok := delta["text"].(string)
 		if !ok {
 			return response, ErrInvalidDeltaTextField
@@ -381,6 +569,30 @@ func handleContentBlockDeltaEvent(ctx context.Context, event map[string]interfac
 	return response, nil
 }
 
+func handleContentBlockStopEvent(event map[string]interface{}, response MessageResponsePayload) (MessageResponsePayload, error) {
+	indexValue, ok := event["index"].(float64)
+	if !ok {
+		return response, ErrInvalidIndexField
+	}
+	index := int(indexValue)
+	if len(response.Content) <= index {
+		return response, ErrContentIndexOutOfRange
+	}
+
+	toolUse, ok := response.Content[index].(*ToolUseContent)
+	if !ok {
+		return response, nil
+	}
+	// Tools without parameters are streamed without any input delta.
+	toolUse.Input = map[string]interface{}{}
+	if toolUse.inputJSON.Len() > 0 {
+		if err := json.Unmarshal([]byte(toolUse.inputJSON.String()), &toolUse.Input); err != nil {
+			return response, fmt.Errorf("failed to unmarshal tool use input: %w", err)
+		}
+	}
+	return response, nil
+}
+
 func handleMessageDeltaEvent(event map[string]interface{}, response MessageResponsePayload) (MessageResponsePayload, error) {
 	delta, ok := event["delta"].(map[string]interface{})
 	if !ok {
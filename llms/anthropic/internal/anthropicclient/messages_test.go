@@ -0,0 +1,93 @@
+package anthropicclient
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestParseStreamingMessageResponse_ToolUse(t *testing.T) {
+	t.Parallel()
+	f, err := os.Open("testdata/tool_use.sse")
+	require.NoError(t, err)
+	defer f.Close()
+	r := &http.Response{StatusCode: http.StatusOK, Body: f}
+
+	var events []llms.StreamEvent
+	payload := &messagePayload{
+		Stream: true,
+		StreamingEventFunc: func(_ context.Context, event llms.StreamEvent) error {
+			events = append(events, event)
+			return nil
+		},
+	}
+
+	resp, err := parseStreamingMessageResponse(context.Background(), r, payload)
+	require.NoError(t, err)
+	assert.Equal(t, []llms.StreamEvent{
+		{Type: llms.StreamEventText, Text: "Let me check "},
+		{Type: llms.StreamEventText, Text: "the weather."},
+		{Type: llms.StreamEventToolCall, ToolCall: &llms.ToolCallDelta{Index: 0, ID: "toolu_01", Name: "weather"}},
+		{Type: llms.StreamEventToolCall, ToolCall: &llms.ToolCallDelta{Index: 0, Arguments: `{"city": "Pa`}},
+		{Type: llms.StreamEventToolCall, ToolCall: &llms.ToolCallDelta{Index: 0, Arguments: `ris"}`}},
+		{Type: llms.StreamEventToolCall, ToolCall: &llms.ToolCallDelta{Index: 1, ID: "toolu_02", Name: "time"}},
+	}, events)
+
+	assert.Equal(t, "tool_use", resp.StopReason)
+	assert.Equal(t, 472, resp.Usage.InputTokens)
+	assert.Equal(t, 89, resp.Usage.OutputTokens)
+	require.Len(t, resp.Content, 3)
+	assert.Equal(t, "Let me check the weather.", resp.Content[0].(*TextContent).Text)
+
+	weather, ok := resp.Content[1].(*ToolUseContent)
+	require.True(t, ok)
+	assert.Equal(t, "toolu_01", weather.ID)
+	assert.Equal(t, "weather", weather.Name)
+	assert.Equal(t, map[string]interface{}{"city": "Paris"}, weather.Input)
+
+	clock, ok := resp.Content[2].(*ToolUseContent)
+	require.True(t, ok)
+	assert.Equal(t, "time", clock.Name)
+	assert.Empty(t, clock.Input)
+	assert.NotNil(t, clock.Input)
+}
+
+func TestParseStreamingMessageResponse_Thinking(t *testing.T) {
+	t.Parallel()
+	f, err := os.Open("testdata/thinking.sse")
+	require.NoError(t, err)
+	defer f.Close()
+	r := &http.Response{StatusCode: http.StatusOK, Body: f}
+
+	var reasoning, text string
+	payload := &messagePayload{
+		Stream: true,
+		StreamingReasoningFunc: func(_ context.Context, reasoningChunk, _ []byte) error {
+			reasoning += string(reasoningChunk)
+			return nil
+		},
+		StreamingFunc: func(_ context.Context, chunk []byte) error {
+			text += string(chunk)
+			return nil
+		},
+	}
+
+	resp, err := parseStreamingMessageResponse(context.Background(), r, payload)
+	require.NoError(t, err)
+	assert.Equal(t, "27 * 453 = 12231", reasoning)
+	assert.Equal(t, "12,231", text)
+
+	require.Len(t, resp.Content, 2)
+	assert.Equal(t, &ThinkingContent{
+		Type:      "thinking",
+		Thinking:  "27 * 453 = 12231",
+		Signature: "EqQBCgIYAhIM1gbcDa9GJwZA2b3hGgxBdjrkzLoky3dl1pkiMOYds",
+	}, resp.Content[0])
+	assert.Equal(t, "12,231", resp.Content[1].(*TextContent).Text)
+	assert.Equal(t, 2048, resp.Usage.CacheReadInputTokens)
+}
@@ -60,10 +60,6 @@ func (l *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOptio
 
 // GenerateContent implements llms.Model.
 func (l *LLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
-	if l.CallbacksHandler != nil {
-		l.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
-	}
-
 	opts := llms.CallOptions{
 		Model: l.modelID,
 	}
@@ -71,8 +67,16 @@ func (l *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 		opt(&opts)
 	}
 
+	ctx = callbacks.StartRun(ctx, callbacks.RunLLM, opts.Model)
+	if l.CallbacksHandler != nil {
+		l.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
+	}
+
 	m, err := processMessages(messages)
 	if err != nil {
+		if l.CallbacksHandler != nil {
+			l.CallbacksHandler.HandleLLMError(ctx, err)
+		}
 		return nil, err
 	}
 
@@ -110,6 +114,24 @@ func processMessages(messages []llms.MessageContent) ([]bedrockclient.Message, e
 					MimeType: part.MIMEType,
 					Type:     "image",
 				})
+			case llms.ToolCall:
+				if part.FunctionCall == nil {
+					return nil, errors.New("tool call without function call")
+				}
+				bedrockMsgs = append(bedrockMsgs, bedrockclient.Message{
+					Role:       m.Role,
+					Type:       "tool_call",
+					ToolCallID: part.ID,
+					ToolName:   part.FunctionCall.Name,
+					ToolArgs:   part.FunctionCall.Arguments,
+				})
+			case llms.ToolCallResponse:
+				bedrockMsgs = append(bedrockMsgs, bedrockclient.Message{
+					Role:       m.Role,
+					Content:    part.Content,
+					Type:       "tool_result",
+					ToolCallID: part.ToolCallID,
+				})
 			default:
 				return nil, errors.New("unsupported message type")
 			}
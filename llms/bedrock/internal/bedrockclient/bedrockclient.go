@@ -22,10 +22,18 @@ type Client struct {
 type Message struct {
 	Role    llms.ChatMessageType
 	Content string
-	// Type may be "text" or "image"
+	// Type may be "text", "image", "tool_call" or "tool_result"
 	Type string
 	// MimeType is the MIME type
 	MimeType string
+
+	// ToolCallID is the ID of the tool call of "tool_call" messages,
+	// or of the answered tool call of "tool_result" messages.
+	ToolCallID string
+	// ToolName is the name of the called tool of "tool_call" messages.
+	ToolName string
+	// ToolArgs is the JSON encoded arguments of "tool_call" messages.
+	ToolArgs string
 }
 
 func getProvider(modelID string) string {
@@ -119,6 +119,7 @@ func createAi21Completion(ctx context.Context, client *bedrockruntime.Client, mo
 	}
 
 	choices := make([]*llms.ContentChoice, len(output.Completions))
+	outputTokens := 0
 	for i, completion := range output.Completions {
 		choices[i] = &llms.ContentChoice{
 			Content:    completion.Data.Text,
@@ -129,7 +130,11 @@ func createAi21Completion(ctx context.Context, client *bedrockruntime.Client, mo
 				"output_tokens": len(completion.Data.Tokens),
 			},
 		}
+		outputTokens += len(completion.Data.Tokens)
 	}
 
-	return &llms.ContentResponse{Choices: choices}, nil
+	return &llms.ContentResponse{
+		Choices: choices,
+		Usage:   llms.NewUsage(len(output.Prompt.Tokens), outputTokens),
+	}, nil
 }
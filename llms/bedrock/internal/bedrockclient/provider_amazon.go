@@ -101,6 +101,7 @@ func createAmazonCompletion(ctx context.Context,
 	}
 
 	contentChoices := make([]*llms.ContentChoice, len(output.Results))
+	outputTokens := 0
 
 	for i, result := range output.Results {
 		contentChoices[i] = &llms.ContentChoice{
@@ -111,9 +112,11 @@ func createAmazonCompletion(ctx context.Context,
 				"output_tokens": result.TokenCount,
 			},
 		}
+		outputTokens += result.TokenCount
 	}
 
 	return &llms.ContentResponse{
 		Choices: contentChoices,
+		Usage:   llms.NewUsage(output.InputTextTokenCount, outputTokens),
 	}, nil
 }
@@ -32,12 +32,41 @@ type anthropicBinGenerationInputSource struct {
 // anthropicTextGenerationInputContent is a single message in the input.
 type anthropicTextGenerationInputContent struct {
 	// The type of the content. Required.
-	// One of: "text", "image"
+	// One of: "text", "image", "tool_use", "tool_result"
 	Type string `json:"type"`
 	// The source of the content. Required if type is "image"
 	Source *anthropicBinGenerationInputSource `json:"source,omitempty"`
 	// The text content. Required if type is "text"
 	Text string `json:"text,omitempty"`
+	// The ID of the tool use. Required if type is "tool_use"
+	ID string `json:"id,omitempty"`
+	// The name of the used tool. Required if type is "tool_use"
+	Name string `json:"name,omitempty"`
+	// The input of the tool. Required if type is "tool_use"
+	Input json.RawMessage `json:"input,omitempty"`
+	// The ID of the answered tool use. Required if type is "tool_result"
+	ToolUseID string `json:"tool_use_id,omitempty"`
+	// The result of the tool. Optional, used if type is "tool_result"
+	Content string `json:"content,omitempty"`
+}
+
+// anthropicTool is a tool the model may use.
+type anthropicTool struct {
+	// The name of the tool. Required
+	Name string `json:"name"`
+	// The description of the tool. Optional
+	Description string `json:"description,omitempty"`
+	// The JSON schema of the input of the tool. Required
+	InputSchema any `json:"input_schema"`
+}
+
+// anthropicToolChoice is how the model should use the tools.
+type anthropicToolChoice struct {
+	// The type of the choice. Required
+	// One of: "auto", "any", "tool"
+	Type string `json:"type"`
+	// The name of the tool to use. Required if type is "tool"
+	Name string `json:"name,omitempty"`
 }
 
 type anthropicTextGenerationInputMessage struct {
@@ -69,6 +98,10 @@ type anthropicTextGenerationInput struct {
 	TopK int `json:"top_k,omitempty"`
 	// Sequences that will cause the model to stop generating tokens. Optional
 	StopSequences []string `json:"stop_sequences,omitempty"`
+	// The tools the model may use. Optional
+	Tools []anthropicTool `json:"tools,omitempty"`
+	// How the model should use the tools. Optional, default = auto
+	ToolChoice *anthropicToolChoice `json:"tool_choice,omitempty"`
 }
 
 // anthropicTextGenerationOutput is the generated output.
@@ -80,13 +113,16 @@ type anthropicTextGenerationOutput struct {
 	// This will always be "assistant".
 	Role string `json:"role"`
 	// This is an array of content blocks, each of which has a type that determines its shape.
-	// Currently, the only type in responses is "text".
+	// One of: "text", "tool_use"
 	Content []struct {
-		Type string `json:"type"`
-		Text string `json:"text"`
+		Type  string          `json:"type"`
+		Text  string          `json:"text"`
+		ID    string          `json:"id"`
+		Name  string          `json:"name"`
+		Input json.RawMessage `json:"input"`
 	} `json:"content"`
 	// The reason for the completion of the generation.
-	// One of: ["end_turn", "max_tokens", "stop_sequence"]
+	// One of: ["end_turn", "max_tokens", "stop_sequence", "tool_use"]
 	StopReason string `json:"stop_reason"`
 	// Which custom stop sequence was matched, if any.
 	StopSequence string `json:"stop_sequence"`
@@ -101,6 +137,7 @@ const (
 	AnthropicCompletionReasonEndTurn      = "end_turn"
 	AnthropicCompletionReasonMaxTokens    = "max_tokens"
 	AnthropicCompletionReasonStopSequence = "stop_sequence"
+	AnthropicCompletionReasonToolUse      = "tool_use"
 )
 
 // The latest version of the model.
@@ -117,8 +154,10 @@ const (
 
 // Type attribute for the anthropic message.
 const (
-	AnthropicMessageTypeText  = "text"
-	AnthropicMessageTypeImage = "image"
+	AnthropicMessageTypeText       = "text"
+	AnthropicMessageTypeImage      = "image"
+	AnthropicMessageTypeToolUse    = "tool_use"
+	AnthropicMessageTypeToolResult = "tool_result"
 )
 
 func createAnthropicCompletion(ctx context.Context,
@@ -141,6 +180,8 @@ func createAnthropicCompletion(ctx context.Context,
 		TopP:             options.TopP,
 		TopK:             options.TopK,
 		StopSequences:    options.StopWords,
+		Tools:            anthropicTools(options.Tools),
+		ToolChoice:       anthropicToolChoiceFromOption(options.ToolChoice),
 	}
 
 	body, err := json.Marshal(input)
@@ -148,7 +189,7 @@ func createAnthropicCompletion(ctx context.Context,
 		return nil, err
 	}
 
-	if options.StreamingFunc != nil {
+	if options.StreamingFunc != nil || options.StreamingEventFunc != nil {
 		modelInput := &bedrockruntime.InvokeModelWithResponseStreamInput{
 			ModelId:     aws.String(modelID),
 			Accept:      aws.String("*/*"),
@@ -177,31 +218,105 @@ func createAnthropicCompletion(ctx context.Context,
 
 	if len(output.Content) == 0 {
 		return nil, errors.New("no results")
-	} else if stopReason := output.StopReason; stopReason != AnthropicCompletionReasonEndTurn && stopReason != AnthropicCompletionReasonStopSequence {
-		return nil, errors.New("completed due to " + stopReason + ". Maybe try increasing max tokens")
 	}
-	Contentchoices := make([]*llms.ContentChoice, len(output.Content))
-	for i, c := range output.Content {
-		Contentchoices[i] = &llms.ContentChoice{
-			Content:    c.Text,
-			StopReason: output.StopReason,
-			GenerationInfo: map[string]interface{}{
-				"input_tokens":  output.Usage.InputTokens,
-				"output_tokens": output.Usage.OutputTokens,
-			},
+	switch output.StopReason {
+	case AnthropicCompletionReasonEndTurn, AnthropicCompletionReasonStopSequence, AnthropicCompletionReasonToolUse:
+	default:
+		return nil, errors.New("completed due to " + output.StopReason + ". Maybe try increasing max tokens")
+	}
+
+	// The text and tool uses of the content blocks make up a single choice,
+	// as with the other providers supporting tools.
+	choice := &llms.ContentChoice{
+		StopReason: output.StopReason,
+		GenerationInfo: map[string]interface{}{
+			"input_tokens":  output.Usage.InputTokens,
+			"output_tokens": output.Usage.OutputTokens,
+		},
+	}
+	for _, c := range output.Content {
+		switch c.Type {
+		case AnthropicMessageTypeText:
+			choice.Content += c.Text
+		case AnthropicMessageTypeToolUse:
+			choice.ToolCalls = append(choice.ToolCalls, llms.ToolCall{
+				ID:   c.ID,
+				Type: "function",
+				FunctionCall: &llms.FunctionCall{
+					Name:      c.Name,
+					Arguments: toolArguments(c.Input),
+				},
+			})
 		}
 	}
+	if len(choice.ToolCalls) > 0 {
+		choice.FuncCall = choice.ToolCalls[0].FunctionCall
+	}
 	return &llms.ContentResponse{
-		Choices: Contentchoices,
+		Choices: []*llms.ContentChoice{choice},
+		Usage:   llms.NewUsage(output.Usage.InputTokens, output.Usage.OutputTokens),
 	}, nil
 }
 
+// anthropicTools converts the tools of the call options.
+func anthropicTools(tools []llms.Tool) []anthropicTool {
+	var out []anthropicTool
+	for _, tool := range tools {
+		if tool.Function == nil {
+			continue
+		}
+		out = append(out, anthropicTool{
+			Name:        tool.Function.Name,
+			Description: tool.Function.Description,
+			InputSchema: tool.Function.Parameters,
+		})
+	}
+	return out
+}
+
+// anthropicToolChoiceFromOption converts the tool choice of the call options,
+// which is either "auto", "required", "none" or a llms.ToolChoice.
+func anthropicToolChoiceFromOption(choice any) *anthropicToolChoice {
+	switch c := choice.(type) {
+	case string:
+		switch c {
+		case "auto":
+			return &anthropicToolChoice{Type: "auto"}
+		case "required", "any":
+			return &anthropicToolChoice{Type: "any"}
+		}
+	case llms.ToolChoice:
+		if c.Function != nil {
+			return &anthropicToolChoice{Type: "tool", Name: c.Function.Name}
+		}
+	case *llms.ToolChoice:
+		if c != nil && c.Function != nil {
+			return &anthropicToolChoice{Type: "tool", Name: c.Function.Name}
+		}
+	}
+	return nil
+}
+
+// toolArguments returns the JSON encoded arguments of a tool use input.
+func toolArguments(input json.RawMessage) string {
+	if len(input) == 0 {
+		return "{}"
+	}
+	return string(input)
+}
+
 type streamingCompletionResponseChunk struct {
-	Type  string `json:"type"`
-	Index int    `json:"index"`
+	Type         string `json:"type"`
+	Index        int    `json:"index"`
+	ContentBlock struct {
+		Type string `json:"type"`
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"content_block"`
 	Delta struct {
 		Type         string `json:"type"`
 		Text         string `json:"text"`
+		PartialJSON  string `json:"partial_json"`
 		StopReason   string `json:"stop_reason"`
 		StopSequence any    `json:"stop_sequence"`
 	} `json:"delta"`
@@ -240,7 +355,7 @@ func parseStreamingCompletionResponse(ctx context.Context, client *bedrockruntim
 	}
 	defer stream.Close()
 
-	contentchoices := []*llms.ContentChoice{{GenerationInfo: map[string]interface{}{}}}
+	var accumulated anthropicStream
 	for e := range stream.Events() {
 		if err = stream.Err(); err != nil {
 			return nil, err
@@ -252,18 +367,8 @@ func parseStreamingCompletionResponse(ctx context.Context, client *bedrockruntim
 			if err != nil {
 				return nil, err
 			}
-
-			switch resp.Type {
-			case "message_start":
-				contentchoices[0].GenerationInfo["input_tokens"] = resp.Message.Usage.InputTokens
-			case "content_block_delta":
-				if err = options.StreamingFunc(ctx, []byte(resp.Delta.Text)); err != nil {
-					return nil, err
-				}
-				contentchoices[0].Content += resp.Delta.Text
-			case "message_delta":
-				contentchoices[0].StopReason = resp.Delta.StopReason
-				contentchoices[0].GenerationInfo["output_tokens"] = resp.Usage.OutputTokens
+			if err := accumulated.handleChunk(ctx, resp, options); err != nil {
+				return nil, err
 			}
 		}
 	}
@@ -271,38 +376,139 @@ func parseStreamingCompletionResponse(ctx context.Context, client *bedrockruntim
 		return nil, err
 	}
 
+	return accumulated.response(), nil
+}
+
+// anthropicStream accumulates the chunks of a streamed response.
+type anthropicStream struct {
+	choice       *llms.ContentChoice
+	inputTokens  int
+	outputTokens int
+	// toolCalls maps the index of the tool use content blocks to the
+	// position of their tool call in the choice.
+	toolCalls map[int]int
+}
+
+func (s *anthropicStream) handleChunk(ctx context.Context, resp streamingCompletionResponseChunk, options llms.CallOptions) error {
+	if s.choice == nil {
+		s.choice = &llms.ContentChoice{GenerationInfo: map[string]interface{}{}}
+		s.toolCalls = map[int]int{}
+	}
+
+	var event llms.StreamEvent
+	switch resp.Type {
+	case "message_start":
+		s.inputTokens = resp.Message.Usage.InputTokens
+		s.choice.GenerationInfo["input_tokens"] = s.inputTokens
+		return nil
+	case "content_block_start":
+		if resp.ContentBlock.Type != AnthropicMessageTypeToolUse {
+			return nil
+		}
+		s.toolCalls[resp.Index] = len(s.choice.ToolCalls)
+		s.choice.ToolCalls = append(s.choice.ToolCalls, llms.ToolCall{
+			ID:           resp.ContentBlock.ID,
+			Type:         "function",
+			FunctionCall: &llms.FunctionCall{Name: resp.ContentBlock.Name},
+		})
+		event = llms.StreamEvent{
+			Type: llms.StreamEventToolCall,
+			ToolCall: &llms.ToolCallDelta{
+				Index: s.toolCalls[resp.Index],
+				ID:    resp.ContentBlock.ID,
+				Name:  resp.ContentBlock.Name,
+			},
+		}
+	case "content_block_delta":
+		switch resp.Delta.Type {
+		case "input_json_delta":
+			i, ok := s.toolCalls[resp.Index]
+			if !ok {
+				return errors.New("input delta of an unknown tool use")
+			}
+			s.choice.ToolCalls[i].FunctionCall.Arguments += resp.Delta.PartialJSON
+			if resp.Delta.PartialJSON == "" {
+				return nil
+			}
+			event = llms.StreamEvent{
+				Type:     llms.StreamEventToolCall,
+				ToolCall: &llms.ToolCallDelta{Index: i, Arguments: resp.Delta.PartialJSON},
+			}
+		default:
+			s.choice.Content += resp.Delta.Text
+			if options.StreamingEventFunc == nil {
+				return options.StreamingFunc(ctx, []byte(resp.Delta.Text))
+			}
+			event = llms.StreamEvent{Type: llms.StreamEventText, Text: resp.Delta.Text}
+		}
+	case "content_block_stop":
+		// Tools without parameters are streamed without any input delta.
+		if i, ok := s.toolCalls[resp.Index]; ok && s.choice.ToolCalls[i].FunctionCall.Arguments == "" {
+			s.choice.ToolCalls[i].FunctionCall.Arguments = "{}"
+		}
+		return nil
+	case "message_delta":
+		s.choice.StopReason = resp.Delta.StopReason
+		s.outputTokens = resp.Usage.OutputTokens
+		s.choice.GenerationInfo["output_tokens"] = s.outputTokens
+		return nil
+	default:
+		return nil
+	}
+
+	if options.StreamingEventFunc == nil {
+		return nil
+	}
+	return options.StreamingEventFunc(ctx, event)
+}
+
+// response returns the response made of the accumulated chunks.
+func (s *anthropicStream) response() *llms.ContentResponse {
+	if s.choice == nil {
+		s.choice = &llms.ContentChoice{GenerationInfo: map[string]interface{}{}}
+	}
+	if len(s.choice.ToolCalls) > 0 {
+		s.choice.FuncCall = s.choice.ToolCalls[0].FunctionCall
+	}
 	return &llms.ContentResponse{
-		Choices: contentchoices,
-	}, nil
+		Choices: []*llms.ContentChoice{s.choice},
+		Usage:   llms.NewUsage(s.inputTokens, s.outputTokens),
+	}
 }
 
 // process the input messages to anthropic supported input
 // returns the input content and system prompt.
 func processInputMessagesAnthropic(messages []Message) ([]*anthropicTextGenerationInputMessage, string, error) {
+	// Consecutive messages with the same anthropic role are merged, as tool
+	// results are sent in user messages and roles must alternate.
 	chunkedMessages := make([][]Message, 0, len(messages))
+	chunkRoles := make([]string, 0, len(messages))
 	currentChunk := make([]Message, 0, len(messages))
-	var lastRole llms.ChatMessageType
+	var lastRole string
 	for _, message := range messages {
-		if message.Role != lastRole {
+		role, err := getAnthropicRole(message.Role)
+		if err != nil {
+			return nil, "", err
+		}
+		if role != lastRole {
 			if len(currentChunk) > 0 {
 				chunkedMessages = append(chunkedMessages, currentChunk)
+				chunkRoles = append(chunkRoles, lastRole)
 			}
 			currentChunk = make([]Message, 0, len(messages))
 		}
 		currentChunk = append(currentChunk, message)
-		lastRole = message.Role
+		lastRole = role
 	}
 	if len(currentChunk) > 0 {
 		chunkedMessages = append(chunkedMessages, currentChunk)
+		chunkRoles = append(chunkRoles, lastRole)
 	}
 
 	inputContents := make([]*anthropicTextGenerationInputMessage, 0, len(messages))
 	var systemPrompt string
-	for _, chunk := range chunkedMessages {
-		role, err := getAnthropicRole(chunk[0].Role)
-		if err != nil {
-			return nil, "", err
-		}
+	for i, chunk := range chunkedMessages {
+		role := chunkRoles[i]
 		if role == AnthropicSystem {
 			if systemPrompt != "" {
 				return nil, "", errors.New("multiple system prompts")
@@ -337,11 +543,9 @@ func getAnthropicRole(role llms.ChatMessageType) (string, error) {
 	case llms.ChatMessageTypeAI:
 		return AnthropicRoleAssistant, nil
 
-	case llms.ChatMessageTypeGeneric:
-		fallthrough
-	case llms.ChatMessageTypeHuman:
+	case llms.ChatMessageTypeGeneric, llms.ChatMessageTypeHuman, llms.ChatMessageTypeTool:
 		return AnthropicRoleUser, nil
-	case llms.ChatMessageTypeFunction, llms.ChatMessageTypeTool:
+	case llms.ChatMessageTypeFunction:
 		fallthrough
 	default:
 		return "", errors.New("role not supported")
@@ -365,6 +569,19 @@ func getAnthropicInputContent(message Message) anthropicTextGenerationInputConte
 				Data:      base64.StdEncoding.EncodeToString([]byte(message.Content)),
 			},
 		}
+	case "tool_call":
+		c = anthropicTextGenerationInputContent{
+			Type:  AnthropicMessageTypeToolUse,
+			ID:    message.ToolCallID,
+			Name:  message.ToolName,
+			Input: json.RawMessage(toolArguments(json.RawMessage(message.ToolArgs))),
+		}
+	case "tool_result":
+		c = anthropicTextGenerationInputContent{
+			Type:      AnthropicMessageTypeToolResult,
+			ToolUseID: message.ToolCallID,
+			Content:   message.Content,
+		}
 	}
 	return c
 }
@@ -0,0 +1,87 @@
+package bedrockclient
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestAnthropicStreamToolUse(t *testing.T) {
+	t.Parallel()
+	f, err := os.Open("testdata/anthropic_tool_use.jsonl")
+	require.NoError(t, err)
+	defer f.Close()
+
+	var events []llms.StreamEvent
+	options := llms.CallOptions{
+		StreamingEventFunc: func(_ context.Context, event llms.StreamEvent) error {
+			events = append(events, event)
+			return nil
+		},
+	}
+
+	var stream anthropicStream
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var chunk streamingCompletionResponseChunk
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &chunk))
+		require.NoError(t, stream.handleChunk(context.Background(), chunk, options))
+	}
+	require.NoError(t, scanner.Err())
+
+	assert.Equal(t, []llms.StreamEvent{
+		{Type: llms.StreamEventText, Text: "Checking the weather."},
+		{Type: llms.StreamEventToolCall, ToolCall: &llms.ToolCallDelta{Index: 0, ID: "toolu_bdrk_01", Name: "weather"}},
+		{Type: llms.StreamEventToolCall, ToolCall: &llms.ToolCallDelta{Index: 0, Arguments: `{"city":`}},
+		{Type: llms.StreamEventToolCall, ToolCall: &llms.ToolCallDelta{Index: 0, Arguments: ` "Paris"}`}},
+		{Type: llms.StreamEventToolCall, ToolCall: &llms.ToolCallDelta{Index: 1, ID: "toolu_bdrk_02", Name: "time"}},
+	}, events)
+
+	resp := stream.response()
+	require.Len(t, resp.Choices, 1)
+	choice := resp.Choices[0]
+	assert.Equal(t, "Checking the weather.", choice.Content)
+	assert.Equal(t, AnthropicCompletionReasonToolUse, choice.StopReason)
+	assert.Equal(t, []llms.ToolCall{
+		{ID: "toolu_bdrk_01", Type: "function", FunctionCall: &llms.FunctionCall{Name: "weather", Arguments: `{"city": "Paris"}`}},
+		{ID: "toolu_bdrk_02", Type: "function", FunctionCall: &llms.FunctionCall{Name: "time", Arguments: `{}`}},
+	}, choice.ToolCalls)
+	assert.Equal(t, choice.ToolCalls[0].FunctionCall, choice.FuncCall)
+	assert.Equal(t, 380, resp.Usage.PromptTokens)
+	assert.Equal(t, 74, resp.Usage.CompletionTokens)
+}
+
+func TestProcessInputMessagesAnthropicTools(t *testing.T) {
+	t.Parallel()
+	messages := []Message{
+		{Role: llms.ChatMessageTypeHuman, Type: "text", Content: "Weather in Paris and Rome?"},
+		{Role: llms.ChatMessageTypeAI, Type: "tool_call", ToolCallID: "toolu_1", ToolName: "weather", ToolArgs: `{"city":"Paris"}`},
+		{Role: llms.ChatMessageTypeAI, Type: "tool_call", ToolCallID: "toolu_2", ToolName: "weather", ToolArgs: `{"city":"Rome"}`},
+		{Role: llms.ChatMessageTypeTool, Type: "tool_result", ToolCallID: "toolu_1", Content: "sunny"},
+		{Role: llms.ChatMessageTypeTool, Type: "tool_result", ToolCallID: "toolu_2", Content: "rainy"},
+		{Role: llms.ChatMessageTypeHuman, Type: "text", Content: "Thanks!"},
+	}
+
+	inputs, system, err := processInputMessagesAnthropic(messages)
+	require.NoError(t, err)
+	assert.Empty(t, system)
+	require.Len(t, inputs, 3)
+	assert.Equal(t, AnthropicRoleAssistant, inputs[1].Role)
+	require.Len(t, inputs[1].Content, 2)
+	assert.Equal(t, AnthropicMessageTypeToolUse, inputs[1].Content[0].Type)
+	assert.JSONEq(t, `{"city":"Rome"}`, string(inputs[1].Content[1].Input))
+
+	// Tool results and the following text are merged in a single user message.
+	assert.Equal(t, AnthropicRoleUser, inputs[2].Role)
+	require.Len(t, inputs[2].Content, 3)
+	assert.Equal(t, anthropicTextGenerationInputContent{
+		Type: AnthropicMessageTypeToolResult, ToolUseID: "toolu_2", Content: "rainy",
+	}, inputs[2].Content[1])
+	assert.Equal(t, "Thanks!", inputs[2].Content[2].Text)
+}
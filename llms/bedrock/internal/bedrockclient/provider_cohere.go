@@ -3,9 +3,13 @@ package bedrockclient
 import (
 	"context"
 	"encoding/json"
+	"strconv"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
 	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
 	"github.com/tmc/langchaingo/llms"
 )
 
@@ -115,7 +119,27 @@ func createCohereCompletion(ctx context.Context,
 		}
 	}
 
+	// The body of the Cohere models has no token counts.
 	return &llms.ContentResponse{
 		Choices: choices,
+		Usage:   headerUsage(resp.ResultMetadata),
 	}, nil
 }
+
+// headerUsage returns the token usage reported by Bedrock in the headers of
+// the response, or nil if the headers are missing.
+func headerUsage(metadata middleware.Metadata) *llms.Usage {
+	resp, ok := awsmiddleware.GetRawResponse(metadata).(*smithyhttp.Response)
+	if !ok {
+		return nil
+	}
+	inputTokens, err := strconv.Atoi(resp.Header.Get("X-Amzn-Bedrock-Input-Token-Count"))
+	if err != nil {
+		return nil
+	}
+	outputTokens, err := strconv.Atoi(resp.Header.Get("X-Amzn-Bedrock-Output-Token-Count"))
+	if err != nil {
+		return nil
+	}
+	return llms.NewUsage(inputTokens, outputTokens)
+}
@@ -17,6 +17,30 @@ type Backend interface {
 	Put(ctx context.Context, key string, response *llms.ContentResponse)
 }
 
+// PromptBackend is implemented by backends matching calls by their prompt
+// rather than only by their key, e.g. semantic caches. The Cacher calls
+// GetPrompt and PutPrompt instead of Get and Put on them.
+type PromptBackend interface {
+	Backend
+	// GetPrompt gets the response of a call matching the prompt. If none is
+	// found, return `nil`.
+	GetPrompt(ctx context.Context, prompt Prompt) *llms.ContentResponse
+	// PutPrompt puts the response of the call of the prompt into the cache.
+	PutPrompt(ctx context.Context, prompt Prompt, response *llms.ContentResponse)
+}
+
+// Prompt is a call to the model, as seen by a PromptBackend.
+type Prompt struct {
+	// Key is the key of the call, see Backend.
+	Key string
+	// Scope is the hash of the options of the call, e.g. the model, the
+	// temperature and the tools. Responses should only be reused by calls of
+	// the same scope.
+	Scope string
+	// Messages are the messages of the call.
+	Messages []llms.MessageContent
+}
+
 // Cacher is an LLM wrapper that caches the responses from the LLM.
 type Cacher struct {
 	llm   llms.Model
@@ -59,13 +83,21 @@ func (c *Cacher) GenerateContent(ctx context.Context, messages []llms.MessageCon
 	if err != nil {
 		return nil, err
 	}
+	get := func() *llms.ContentResponse { return c.cache.Get(ctx, key) }
+	put := func(response *llms.ContentResponse) { c.cache.Put(ctx, key, response) }
+	if backend, ok := c.cache.(PromptBackend); ok {
+		scope, err := hashKeyForCache(nil, opts)
+		if err != nil {
+			return nil, err
+		}
+		prompt := Prompt{Key: key, Scope: scope, Messages: messages}
+		get = func() *llms.ContentResponse { return backend.GetPrompt(ctx, prompt) }
+		put = func(response *llms.ContentResponse) { backend.PutPrompt(ctx, prompt, response) }
+	}
 
-	if response := c.cache.Get(ctx, key); response != nil {
-		if opts.StreamingFunc != nil && len(response.Choices) > 0 {
-			// only stream the first choice.
-			if err := opts.StreamingFunc(ctx, []byte(response.Choices[0].Content)); err != nil {
-				return nil, err
-			}
+	if response := get(); response != nil {
+		if err := llms.StreamResponse(ctx, opts, response); err != nil {
+			return nil, err
 		}
 
 		return response, nil
@@ -76,7 +108,7 @@ func (c *Cacher) GenerateContent(ctx context.Context, messages []llms.MessageCon
 		return nil, err
 	}
 
-	c.cache.Put(ctx, key, response)
+	put(response)
 
 	return response, nil
 }
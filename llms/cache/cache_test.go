@@ -37,6 +37,14 @@ func TestCache_hashKeyForCache(t *testing.T) {
 			v2:          []llms.MessageContent{{}},
 			shouldMatch: false,
 		},
+		{
+			name: "different cache control",
+			v1: []llms.MessageContent{{Role: llms.ChatMessageTypeHuman, Parts: []llms.ContentPart{
+				llms.WithCacheControl(llms.TextPart("hi"), llms.CacheControl{Type: "ephemeral"}),
+			}}},
+			v2:          []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "hi")},
+			shouldMatch: false,
+		},
 	}
 	mustHashKeyForCache := func(messages []llms.MessageContent, options ...llms.CallOption) string {
 		var opts llms.CallOptions
@@ -172,3 +180,54 @@ func TestCache_Call_Streaming(t *testing.T) {
 	rq.True(mockCache.hit)
 	rq.True(stream)
 }
+
+func TestCache_Replay(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	rq := require.New(t)
+
+	exp := &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{
+			Content:          "world",
+			ReasoningContent: "greeting",
+			ToolCalls: []llms.ToolCall{{
+				ID:           "call_1",
+				Type:         "function",
+				FunctionCall: &llms.FunctionCall{Name: "wave", Arguments: "{}"},
+			}},
+		}},
+	}
+	mockCache := newMockCache()
+	llm := New(newMockLLM(exp, nil), mockCache)
+	_, err := llm.GenerateContent(ctx, []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "hello")})
+	rq.NoError(err)
+
+	var events []llms.StreamEvent
+	_, err = llm.GenerateContent(ctx, []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "hello")},
+		llms.WithStreamingEventFunc(func(_ context.Context, event llms.StreamEvent) error {
+			events = append(events, event)
+			return nil
+		}))
+	rq.NoError(err)
+	rq.True(mockCache.hit)
+	rq.Equal([]llms.StreamEvent{
+		{Type: llms.StreamEventReasoning, Text: "greeting"},
+		{Type: llms.StreamEventText, Text: "world"},
+		{Type: llms.StreamEventToolCall, ToolCall: &llms.ToolCallDelta{ID: "call_1", Name: "wave", Arguments: "{}"}},
+	}, events)
+
+	var reasoning, text string
+	_, err = llm.GenerateContent(ctx, []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "hello")},
+		llms.WithStreamingReasoningFunc(func(_ context.Context, reasoningChunk, _ []byte) error {
+			reasoning += string(reasoningChunk)
+			return nil
+		}),
+		llms.WithStreamingFunc(func(_ context.Context, chunk []byte) error {
+			text += string(chunk)
+			return nil
+		}))
+	rq.NoError(err)
+	rq.Equal("greeting", reasoning)
+	rq.Equal("world", text)
+}
@@ -0,0 +1,98 @@
+// Package filesystem provides a `cache.Backend` storing responses as JSON
+// files in a directory, so that they persist across restarts.
+package filesystem
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/cache"
+)
+
+// Filesystem is a `cache.Backend` storing each response in a file of a
+// directory.
+type Filesystem struct {
+	Options Options
+	dir     string
+}
+
+var _ cache.Backend = (*Filesystem)(nil)
+
+// New creates a new filesystem `cache.Backend` storing responses in dir,
+// which is created if needed.
+func New(dir string, opts ...Option) (*Filesystem, error) {
+	options, err := applyOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("create cache directory: %w", err)
+	}
+
+	return &Filesystem{
+		Options: *options,
+		dir:     dir,
+	}, nil
+}
+
+// Get a value from the cache. If the key is not found or has expired, return
+// `nil`.
+func (f *Filesystem) Get(_ context.Context, key string) *llms.ContentResponse {
+	path := f.path(key)
+	// errors are ignored, instead we return `nil` and pretend the key
+	// wasn't found.
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+	if f.Options.Expiration > 0 && time.Since(info.ModTime()) > f.Options.Expiration {
+		_ = os.Remove(path)
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var response llms.ContentResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil
+	}
+
+	return &response
+}
+
+// Put a value into the cache. The file is replaced atomically, so that
+// concurrent processes never read partial files.
+func (f *Filesystem) Put(_ context.Context, key string, response *llms.ContentResponse) {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(f.dir, ".*.tmp")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return
+	}
+	if err := tmp.Close(); err != nil {
+		return
+	}
+	_ = os.Rename(tmp.Name(), f.path(key))
+}
+
+// path returns the path of the file of a key. Keys are hashed so that any key
+// is a valid file name.
+func (f *Filesystem) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(f.dir, hex.EncodeToString(sum[:])+".json")
+}
@@ -0,0 +1,51 @@
+package filesystem
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestFilesystem(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	rq := require.New(t)
+	dir := t.TempDir()
+
+	cache, err := New(dir, WithExpiration(time.Hour))
+	rq.NoError(err)
+
+	rq.Nil(cache.Get(ctx, "key1"), "empty cache should be empty")
+
+	val := &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{
+			Content:    "value",
+			StopReason: "stop",
+			ToolCalls: []llms.ToolCall{{
+				ID:           "call_1",
+				Type:         "function",
+				FunctionCall: &llms.FunctionCall{Name: "search", Arguments: `{"q":"go"}`},
+			}},
+		}},
+		Usage: &llms.Usage{PromptTokens: 3, CompletionTokens: 5, TotalTokens: 8},
+	}
+	cache.Put(ctx, "key1", val)
+	rq.Equal(val, cache.Get(ctx, "key1"))
+	cache.Put(ctx, "key/2", val)
+	rq.Equal(val, cache.Get(ctx, "key/2"))
+
+	// responses persist across instances.
+	cache, err = New(dir, WithExpiration(time.Hour))
+	rq.NoError(err)
+	rq.Equal(val, cache.Get(ctx, "key1"))
+
+	old := time.Now().Add(-2 * time.Hour)
+	rq.NoError(os.Chtimes(cache.path("key1"), old, old))
+	rq.Nil(cache.Get(ctx, "key1"), "value should have expired")
+	rq.NoFileExists(cache.path("key1"))
+}
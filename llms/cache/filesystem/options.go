@@ -0,0 +1,34 @@
+package filesystem
+
+import "time"
+
+// Option is a functional argument that configures the Options.
+type Option func(*Options) error
+
+// Options is a set of options for the filesystem cache.
+type Options struct {
+	// Expiration is the time-to-live of the responses, forever if zero.
+	Expiration time.Duration
+}
+
+// WithExpiration specifies the time-to-live of the responses added to the
+// cache. Expired responses are removed when read.
+func WithExpiration(expiration time.Duration) Option {
+	return func(o *Options) error {
+		o.Expiration = expiration
+
+		return nil
+	}
+}
+
+func applyOptions(opts ...Option) (*Options, error) {
+	o := new(Options)
+
+	for _, opt := range opts {
+		if err := opt(o); err != nil {
+			return nil, err
+		}
+	}
+
+	return o, nil
+}
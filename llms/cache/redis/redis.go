@@ -0,0 +1,82 @@
+// Package redis provides a `cache.Backend` storing responses in Redis, so
+// that they persist across restarts and are shared by processes.
+package redis
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/redis/rueidis"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/cache"
+)
+
+// Redis is a Redis `cache.Backend`.
+type Redis struct {
+	Options   Options
+	client    rueidis.Client
+	ownClient bool
+}
+
+var _ cache.Backend = (*Redis)(nil)
+
+// New creates a new Redis `cache.Backend`. The client is created from the URL
+// set by WithURL, unless one is provided by WithClient.
+func New(opts ...Option) (*Redis, error) {
+	options, err := applyOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Redis{Options: *options, client: options.Client}
+	if r.client == nil {
+		clientOption, err := rueidis.ParseURL(options.URL)
+		if err != nil {
+			return nil, err
+		}
+		r.client, err = rueidis.NewClient(clientOption)
+		if err != nil {
+			return nil, err
+		}
+		r.ownClient = true
+	}
+
+	return r, nil
+}
+
+// Get a value from the cache. If the key is not found, return `nil`.
+func (r *Redis) Get(ctx context.Context, key string) *llms.ContentResponse {
+	// errors are ignored, instead we return `nil` and pretend the key
+	// wasn't found.
+	data, err := r.client.Do(ctx, r.client.B().Get().Key(r.Options.Prefix+key).Build()).AsBytes()
+	if err != nil {
+		return nil
+	}
+	var response llms.ContentResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil
+	}
+
+	return &response
+}
+
+// Put a value into the cache. Redis removes it once expired.
+func (r *Redis) Put(ctx context.Context, key string, response *llms.ContentResponse) {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return
+	}
+	set := r.client.B().Set().Key(r.Options.Prefix + key).Value(rueidis.BinaryString(data))
+	if r.Options.Expiration > 0 {
+		_ = r.client.Do(ctx, set.Px(r.Options.Expiration).Build()).Error()
+		return
+	}
+	_ = r.client.Do(ctx, set.Build()).Error()
+}
+
+// Close closes the client, unless it was provided by WithClient.
+func (r *Redis) Close() {
+	if r.ownClient {
+		r.client.Close()
+	}
+}
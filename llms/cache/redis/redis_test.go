@@ -0,0 +1,70 @@
+package redis
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	tcredis "github.com/testcontainers/testcontainers-go/modules/redis"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func getRedisURL(t *testing.T) string {
+	t.Helper()
+
+	if uri := os.Getenv("REDIS_URL"); uri != "" {
+		return uri
+	}
+
+	ctx := context.Background()
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        "docker.io/redis:7.2",
+			ExposedPorts: []string{"6379/tcp"},
+			WaitingFor:   wait.ForLog("* Ready to accept connections"),
+		},
+		Started: true,
+	})
+	if err != nil && strings.Contains(err.Error(), "Cannot connect to the Docker daemon") {
+		t.Skip("Docker not available")
+	}
+	require.NoError(t, err)
+
+	redisContainer := &tcredis.RedisContainer{Container: container}
+	t.Cleanup(func() {
+		require.NoError(t, redisContainer.Terminate(context.Background()))
+	})
+
+	uri, err := redisContainer.ConnectionString(ctx)
+	require.NoError(t, err)
+	return uri
+}
+
+func TestRedis(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	rq := require.New(t)
+
+	cache, err := New(WithURL(getRedisURL(t)), WithPrefix(t.Name()+":"), WithExpiration(time.Second))
+	rq.NoError(err)
+	t.Cleanup(cache.Close)
+
+	rq.Nil(cache.Get(ctx, "key1"), "empty cache should be empty")
+
+	val := &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{
+			Content: "value",
+		}},
+	}
+	cache.Put(ctx, "key1", val)
+	rq.Equal(val, cache.Get(ctx, "key1"))
+
+	time.Sleep(2 * time.Second)
+	rq.Nil(cache.Get(ctx, "key1"), "value should have expired")
+}
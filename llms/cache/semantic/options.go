@@ -0,0 +1,95 @@
+package semantic
+
+import (
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+const (
+	_defaultThreshold  = 0.95
+	_defaultMaxEntries = 1000
+)
+
+// Option is a functional argument that configures the Options.
+type Option func(*Options) error
+
+// Options is a set of options for the semantic cache.
+type Options struct {
+	// Threshold is the minimum cosine similarity of the embeddings of
+	// prompts for a cached response to be returned.
+	Threshold float32
+	// MaxEntries is the maximum number of responses of the cache, the oldest
+	// being evicted first.
+	MaxEntries int
+	// Expiration is the time-to-live of the responses, forever if zero.
+	Expiration time.Duration
+	// PromptText returns the text embedded for the messages of a call.
+	PromptText func(messages []llms.MessageContent) string
+}
+
+// WithThreshold specifies the minimum cosine similarity of the embeddings of
+// prompts for a cached response to be returned, 0.95 by default. Lower
+// thresholds return more responses to prompts that differ in meaning.
+func WithThreshold(threshold float32) Option {
+	return func(o *Options) error {
+		if threshold <= 0 || threshold > 1 {
+			return ErrInvalidThreshold
+		}
+		o.Threshold = threshold
+
+		return nil
+	}
+}
+
+// WithMaxEntries specifies the maximum number of responses of the cache,
+// 1000 by default.
+func WithMaxEntries(maxEntries int) Option {
+	return func(o *Options) error {
+		o.MaxEntries = maxEntries
+
+		return nil
+	}
+}
+
+// WithExpiration specifies the time-to-live of the responses added to the
+// cache.
+func WithExpiration(expiration time.Duration) Option {
+	return func(o *Options) error {
+		o.Expiration = expiration
+
+		return nil
+	}
+}
+
+// WithPromptText specifies the text embedded for the messages of a call. By
+// default, it is the text and the tool parts of all the messages, one part
+// per line, and empty after tool responses; it can be e.g. the last message
+// only, for prompts whose history doesn't matter. Prompts with an empty text
+// are only found by their exact key.
+func WithPromptText(promptText func(messages []llms.MessageContent) string) Option {
+	return func(o *Options) error {
+		o.PromptText = promptText
+
+		return nil
+	}
+}
+
+func applyOptions(opts ...Option) (*Options, error) {
+	o := &Options{
+		Threshold:  _defaultThreshold,
+		MaxEntries: _defaultMaxEntries,
+		PromptText: promptText,
+	}
+
+	for _, opt := range opts {
+		if err := opt(o); err != nil {
+			return nil, err
+		}
+	}
+	if o.MaxEntries <= 0 {
+		o.MaxEntries = _defaultMaxEntries
+	}
+
+	return o, nil
+}
@@ -0,0 +1,221 @@
+// Package semantic provides a `cache.Backend` returning the response of a
+// similar prompt: prompts are embedded, and a cached response is returned
+// when the cosine similarity of the embeddings exceeds a threshold. It
+// avoids calls for prompts that are rephrased but ask the same thing.
+package semantic
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/cache"
+)
+
+// ErrInvalidThreshold is returned by New when the similarity threshold isn't
+// in (0, 1].
+var ErrInvalidThreshold = errors.New("similarity threshold must be in (0, 1]")
+
+// Semantic is a semantic `cache.Backend`. Responses are kept in memory.
+type Semantic struct {
+	Options  Options
+	embedder embeddings.Embedder
+
+	mu      sync.RWMutex
+	entries []*entry
+	// pending are the embeddings of the prompts of the last misses, to be
+	// reused when their responses are put.
+	pending map[string][]float32
+}
+
+type entry struct {
+	key      string
+	scope    string
+	vector   []float32
+	response *llms.ContentResponse
+	created  time.Time
+}
+
+var _ cache.PromptBackend = (*Semantic)(nil)
+
+// New creates a new semantic `cache.Backend` embedding the prompts with the
+// embedder.
+func New(embedder embeddings.Embedder, opts ...Option) (*Semantic, error) {
+	if embedder == nil {
+		return nil, errors.New("semantic cache: missing embedder")
+	}
+	options, err := applyOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Semantic{
+		Options:  *options,
+		embedder: embedder,
+		pending:  make(map[string][]float32),
+	}, nil
+}
+
+// Get a value from the cache by its exact key. If the key is not found,
+// return `nil`.
+func (s *Semantic) Get(_ context.Context, key string) *llms.ContentResponse {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, e := range s.entries {
+		if e.key == key && !s.expired(e) {
+			return e.response
+		}
+	}
+
+	return nil
+}
+
+// Put a value into the cache. Without its prompt, it is only found by its
+// exact key.
+func (s *Semantic) Put(_ context.Context, key string, response *llms.ContentResponse) {
+	s.add(&entry{key: key, response: response, created: time.Now()})
+}
+
+// GetPrompt gets the response of the prompt of the same scope most similar to
+// the given one, if their similarity exceeds the threshold. If none is found,
+// return `nil`.
+func (s *Semantic) GetPrompt(ctx context.Context, prompt cache.Prompt) *llms.ContentResponse {
+	if response := s.Get(ctx, prompt.Key); response != nil {
+		return response
+	}
+	text := s.Options.PromptText(prompt.Messages)
+	if text == "" {
+		return nil
+	}
+	// errors are ignored, instead we return `nil` and pretend the prompt
+	// wasn't found.
+	vector, err := s.embedder.EmbedQuery(ctx, text)
+	if err != nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.pending) >= s.Options.MaxEntries {
+		clear(s.pending)
+	}
+	s.pending[prompt.Key] = vector
+
+	var best *entry
+	var bestScore float32
+	for _, e := range s.entries {
+		if e.scope != prompt.Scope || e.vector == nil || s.expired(e) {
+			continue
+		}
+		if score := cosineSimilarity(vector, e.vector); score >= s.Options.Threshold && score > bestScore {
+			best, bestScore = e, score
+		}
+	}
+	if best == nil {
+		return nil
+	}
+
+	return best.response
+}
+
+// PutPrompt puts the response of the prompt into the cache.
+func (s *Semantic) PutPrompt(ctx context.Context, prompt cache.Prompt, response *llms.ContentResponse) {
+	s.mu.Lock()
+	vector, ok := s.pending[prompt.Key]
+	delete(s.pending, prompt.Key)
+	s.mu.Unlock()
+
+	if !ok {
+		text := s.Options.PromptText(prompt.Messages)
+		if text != "" {
+			vector, _ = s.embedder.EmbedQuery(ctx, text)
+		}
+	}
+	s.add(&entry{
+		key:      prompt.Key,
+		scope:    prompt.Scope,
+		vector:   vector,
+		response: response,
+		created:  time.Now(),
+	})
+}
+
+// add adds an entry, replacing the entry of the same key and evicting the
+// oldest entries beyond the maximum.
+func (s *Semantic) add(e *entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = slices.DeleteFunc(s.entries, func(old *entry) bool {
+		return old.key == e.key || s.expired(old)
+	})
+	s.entries = append(s.entries, e)
+	if n := len(s.entries) - s.Options.MaxEntries; n > 0 {
+		s.entries = slices.Delete(s.entries, 0, n)
+	}
+}
+
+func (s *Semantic) expired(e *entry) bool {
+	return s.Options.Expiration > 0 && time.Since(e.created) > s.Options.Expiration
+}
+
+// promptText returns the text, tool call and tool response parts of the
+// messages, one per line. It returns an empty text, which is only found by
+// its exact key, if the last message has tool parts: the response to tool
+// results depends on their exact values, e.g. numbers, which similar
+// embeddings don't guarantee.
+func promptText(messages []llms.MessageContent) string {
+	if len(messages) > 0 && slices.ContainsFunc(messages[len(messages)-1].Parts, isToolPart) {
+		return ""
+	}
+	var lines []string
+	for _, m := range messages {
+		for _, part := range m.Parts {
+			switch part := part.(type) {
+			case llms.TextContent:
+				if part.Text != "" {
+					lines = append(lines, part.Text)
+				}
+			case llms.ToolCall:
+				if part.FunctionCall != nil {
+					lines = append(lines, part.FunctionCall.Name+" "+part.FunctionCall.Arguments)
+				}
+			case llms.ToolCallResponse:
+				lines = append(lines, part.Name+" "+part.Content)
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func isToolPart(part llms.ContentPart) bool {
+	switch part.(type) {
+	case llms.ToolCall, llms.ToolCallResponse:
+		return true
+	}
+	return false
+}
+
+func cosineSimilarity(a, b []float32) float32 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return float32(dot / (math.Sqrt(normA) * math.Sqrt(normB)))
+}
@@ -0,0 +1,135 @@
+package semantic
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/cache"
+)
+
+// letterEmbedder embeds texts as the counts of their letters, so that texts
+// differing only in case and punctuation have the same embedding.
+type letterEmbedder struct {
+	calls int
+}
+
+func (e *letterEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i], _ = e.EmbedQuery(ctx, text)
+	}
+	return vectors, nil
+}
+
+func (e *letterEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	e.calls++
+	vector := make([]float32, 26)
+	for _, r := range strings.ToLower(text) {
+		if r >= 'a' && r <= 'z' {
+			vector[r-'a']++
+		}
+	}
+	return vector, nil
+}
+
+type countingLLM struct {
+	calls int
+}
+
+func (m *countingLLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func (m *countingLLM) GenerateContent(_ context.Context, messages []llms.MessageContent, _ ...llms.CallOption) (*llms.ContentResponse, error) { //nolint:lll
+	m.calls++
+	text := messages[len(messages)-1].Parts[0].(llms.TextContent).Text
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: "answer to " + text}}}, nil
+}
+
+func TestSemantic(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	rq := require.New(t)
+
+	embedder := &letterEmbedder{}
+	backend, err := New(embedder, WithThreshold(0.99))
+	rq.NoError(err)
+	llm := &countingLLM{}
+	cached := cache.New(llm, backend)
+
+	answer, err := cached.Call(ctx, "What is the capital of France?")
+	rq.NoError(err)
+	rq.Equal("answer to What is the capital of France?", answer)
+	rq.Equal(1, llm.calls)
+	rq.Equal(1, embedder.calls, "the embedding of the miss should be reused")
+
+	// A similar prompt gets the cached response.
+	var streamed string
+	answer, err = cached.Call(ctx, "what is the capital of france", llms.WithStreamingFunc(
+		func(_ context.Context, chunk []byte) error {
+			streamed += string(chunk)
+			return nil
+		}))
+	rq.NoError(err)
+	rq.Equal("answer to What is the capital of France?", answer)
+	rq.Equal(answer, streamed)
+	rq.Equal(1, llm.calls)
+
+	// A different prompt, or the same prompt with other options, doesn't.
+	answer, err = cached.Call(ctx, "Tell me a joke")
+	rq.NoError(err)
+	rq.Equal("answer to Tell me a joke", answer)
+	rq.Equal(2, llm.calls)
+
+	_, err = cached.Call(ctx, "What is the capital of France?", llms.WithTemperature(0.5))
+	rq.NoError(err)
+	rq.Equal(3, llm.calls)
+}
+
+func TestSemanticMaxEntries(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	rq := require.New(t)
+
+	backend, err := New(&letterEmbedder{}, WithMaxEntries(2))
+	rq.NoError(err)
+	val := &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: "value"}}}
+	backend.Put(ctx, "key1", val)
+	backend.Put(ctx, "key2", val)
+	backend.Put(ctx, "key3", val)
+	rq.Nil(backend.Get(ctx, "key1"), "oldest value should have been evicted")
+	rq.Equal(val, backend.Get(ctx, "key3"))
+
+	_, err = New(&letterEmbedder{}, WithThreshold(1.5))
+	rq.ErrorIs(err, ErrInvalidThreshold)
+}
+
+func TestPromptTextToolParts(t *testing.T) {
+	t.Parallel()
+
+	call := llms.ToolCall{ID: "call_1", Type: "function", FunctionCall: &llms.FunctionCall{
+		Name: "weather", Arguments: `{"city":"Paris"}`,
+	}}
+	messages := []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "What's the weather in Paris?"),
+		{Role: llms.ChatMessageTypeAI, Parts: []llms.ContentPart{call}},
+		{Role: llms.ChatMessageTypeTool, Parts: []llms.ContentPart{llms.ToolCallResponse{
+			ToolCallID: "call_1", Name: "weather", Content: "21°C",
+		}}},
+	}
+	// The response to a tool result depends on its exact value.
+	require.Empty(t, promptText(messages))
+
+	messages = append(messages,
+		llms.TextParts(llms.ChatMessageTypeAI, "It's sunny."),
+		llms.TextParts(llms.ChatMessageTypeHuman, "And tomorrow?"),
+	)
+	require.Equal(t, "What's the weather in Paris?\n"+
+		`weather {"city":"Paris"}`+"\n"+
+		"weather 21°C\nIt's sunny.\nAnd tomorrow?", promptText(messages))
+}
@@ -0,0 +1,109 @@
+// Package sqlite3 provides a `cache.Backend` storing responses in a SQLite
+// database, so that they persist across restarts and are shared by processes.
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3" // sqlite3 driver.
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/cache"
+)
+
+// DefaultTableName is the default name of the table of the responses.
+const DefaultTableName = "langchaingo_llm_cache"
+
+// _schema creates the table of the responses. created is a Unix time in
+// nanoseconds.
+const _schema = `CREATE TABLE IF NOT EXISTS %s (
+	key TEXT PRIMARY KEY,
+	response TEXT NOT NULL,
+	created INTEGER NOT NULL
+);`
+
+// SQLite is a SQLite `cache.Backend`.
+type SQLite struct {
+	Options Options
+	db      *sql.DB
+	ownDB   bool
+}
+
+var _ cache.Backend = (*SQLite)(nil)
+
+// New creates a new SQLite `cache.Backend`, creating its table if needed. The
+// database is opened at the address set by WithDBAddress, unless one is
+// provided by WithDB.
+func New(ctx context.Context, opts ...Option) (*SQLite, error) {
+	options, err := applyOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &SQLite{Options: *options, db: options.DB}
+	if s.db == nil {
+		s.db, err = sql.Open("sqlite3", options.DBAddress)
+		if err != nil {
+			return nil, fmt.Errorf("open cache database: %w", err)
+		}
+		s.ownDB = true
+		if options.DBAddress == ":memory:" {
+			// Each connection has its own in-memory database.
+			s.db.SetMaxOpenConns(1)
+		}
+	}
+	if _, err := s.db.ExecContext(ctx, fmt.Sprintf(_schema, options.TableName)); err != nil {
+		s.Close()
+		return nil, fmt.Errorf("create cache table: %w", err)
+	}
+
+	return s, nil
+}
+
+// Get a value from the cache. If the key is not found or has expired, return
+// `nil`.
+func (s *SQLite) Get(ctx context.Context, key string) *llms.ContentResponse {
+	var data string
+	var created int64
+	// errors are ignored, instead we return `nil` and pretend the key
+	// wasn't found.
+	err := s.db.QueryRowContext(ctx,
+		fmt.Sprintf("SELECT response, created FROM %s WHERE key = ?", s.Options.TableName), key,
+	).Scan(&data, &created)
+	if err != nil {
+		return nil
+	}
+	if s.Options.Expiration > 0 && time.Since(time.Unix(0, created)) > s.Options.Expiration {
+		_, _ = s.db.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE key = ?", s.Options.TableName), key)
+		return nil
+	}
+	var response llms.ContentResponse
+	if err := json.Unmarshal([]byte(data), &response); err != nil {
+		return nil
+	}
+
+	return &response
+}
+
+// Put a value into the cache.
+func (s *SQLite) Put(ctx context.Context, key string, response *llms.ContentResponse) {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return
+	}
+	_, _ = s.db.ExecContext(ctx,
+		fmt.Sprintf("INSERT OR REPLACE INTO %s (key, response, created) VALUES (?, ?, ?)", s.Options.TableName),
+		key, string(data), time.Now().UnixNano(),
+	)
+}
+
+// Close closes the database, unless it was provided by WithDB.
+func (s *SQLite) Close() error {
+	if !s.ownDB {
+		return nil
+	}
+	return s.db.Close()
+}
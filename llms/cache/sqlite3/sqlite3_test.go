@@ -0,0 +1,48 @@
+package sqlite3
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestSQLite(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	rq := require.New(t)
+	path := filepath.Join(t.TempDir(), "cache.db")
+
+	cache, err := New(ctx, WithDBAddress(path), WithTableName("responses"))
+	rq.NoError(err)
+
+	rq.Nil(cache.Get(ctx, "key1"), "empty cache should be empty")
+
+	val := &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{
+			Content:    "value",
+			StopReason: "stop",
+		}},
+	}
+	cache.Put(ctx, "key1", val)
+	rq.Equal(val, cache.Get(ctx, "key1"))
+
+	val2 := &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: "value2"}}}
+	cache.Put(ctx, "key1", val2)
+	rq.Equal(val2, cache.Get(ctx, "key1"))
+	rq.NoError(cache.Close())
+
+	// responses persist across instances.
+	cache, err = New(ctx, WithDBAddress(path), WithTableName("responses"), WithExpiration(time.Hour))
+	rq.NoError(err)
+	t.Cleanup(func() { cache.Close() })
+	rq.Equal(val2, cache.Get(ctx, "key1"))
+
+	_, err = cache.db.ExecContext(ctx, "UPDATE responses SET created = ?", time.Now().Add(-2*time.Hour).UnixNano())
+	rq.NoError(err)
+	rq.Nil(cache.Get(ctx, "key1"), "value should have expired")
+}
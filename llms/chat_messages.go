@@ -116,6 +116,8 @@ func (m FunctionChatMessage) GetName() string          { return m.Name }
 type ToolChatMessage struct {
 	// ID is the ID of the tool call.
 	ID string `json:"tool_call_id"`
+	// Name is the name of the called tool.
+	Name string `json:"name,omitempty"`
 	// Content is the content of the tool message.
 	Content string `json:"content"`
 }
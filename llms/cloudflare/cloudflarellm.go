@@ -52,16 +52,29 @@ func (o *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOptio
 }
 
 // GenerateContent implements the Model interface.
-func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { // nolint: lll, cyclop, funlen, goerr113
+func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { // nolint: lll
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	ctx = callbacks.StartRun(ctx, callbacks.RunLLM, o.options.model)
 	if o.CallbacksHandler != nil {
 		o.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
 	}
 
-	opts := llms.CallOptions{}
-	for _, opt := range options {
-		opt(&opts)
+	resp, err := o.generateContent(ctx, messages, opts)
+	if o.CallbacksHandler != nil {
+		if err != nil {
+			o.CallbacksHandler.HandleLLMError(ctx, err)
+		} else {
+			o.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, resp)
+		}
 	}
+	return resp, err
+}
 
+func (o *LLM) generateContent(ctx context.Context, messages []llms.MessageContent, opts llms.CallOptions) (*llms.ContentResponse, error) { // nolint: lll, cyclop, funlen, goerr113
 	// Our input is a sequence of Message, each of which potentially has
 	// a sequence of Part that is text.
 	// We have to convert it to a format Cloudflare understands: []Message, which
@@ -129,9 +142,12 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 	}
 
 	response := &llms.ContentResponse{Choices: choices}
-
-	if o.CallbacksHandler != nil {
-		o.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, response)
+	if usage := res.Result.Usage; usage != nil {
+		response.Usage = &llms.Usage{
+			PromptTokens:     usage.PromptTokens,
+			CompletionTokens: usage.CompletionTokens,
+			TotalTokens:      usage.TotalTokens,
+		}
 	}
 
 	return response, nil
@@ -94,6 +94,7 @@ func (c *Client) GenerateContent(ctx context.Context, request *GenerateContentRe
 		return &generateResponse, nil
 	}
 
+	var usage *Usage
 	scanner := bufio.NewScanner(response.Body)
 	// increase the buffer size to avoid running out of space
 	scanBuf := make([]byte, 0, maxBufferSize)
@@ -131,12 +132,15 @@ func (c *Client) GenerateContent(ctx context.Context, request *GenerateContentRe
 			}, nil
 		}
 
+		if streamingResponse.Usage != nil {
+			usage = streamingResponse.Usage
+		}
 		if err = request.StreamingFunc(ctx, bts); err != nil {
 			return nil, err
 		}
 	}
 
-	return &GenerateContentResponse{}, nil
+	return &GenerateContentResponse{Result: GenerateContentResult{Usage: usage}}, nil
 }
 
 // Summarize summarizes the given input text.
@@ -48,7 +48,8 @@ func TestClient_GenerateContent(t *testing.T) { // nolint:funlen
 				httpClient: &mockHTTPClient{
 					response: &http.Response{
 						StatusCode: http.StatusOK,
-						Body:       io.NopCloser(strings.NewReader(`{"result": {"response": "response"}}`)),
+						Body: io.NopCloser(strings.NewReader(
+							`{"result": {"response": "response", "usage": {"prompt_tokens": 5, "completion_tokens": 2, "total_tokens": 7}}}`)),
 					},
 				},
 				accountID:          "accountID",
@@ -67,10 +68,9 @@ func TestClient_GenerateContent(t *testing.T) { // nolint:funlen
 				},
 			},
 			want: &GenerateContentResponse{
-				Result: struct {
-					Response string `json:"response"`
-				}{
+				Result: GenerateContentResult{
 					Response: "response",
+					Usage:    &Usage{PromptTokens: 5, CompletionTokens: 2, TotalTokens: 7},
 				},
 			},
 		},
@@ -106,9 +106,7 @@ func TestClient_GenerateContent(t *testing.T) { // nolint:funlen
 				},
 			},
 			want: &GenerateContentResponse{
-				Result: struct {
-					Response string `json:"response"`
-				}{
+				Result: GenerateContentResult{
 					Response: "",
 				},
 			},
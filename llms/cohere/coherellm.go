@@ -31,15 +31,16 @@ func (o *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOptio
 // GenerateContent implements the Model interface.
 func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint: lll, cyclop, whitespace
 
-	if o.CallbacksHandler != nil {
-		o.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
-	}
-
 	opts := &llms.CallOptions{}
 	for _, opt := range options {
 		opt(opts)
 	}
 
+	ctx = callbacks.StartRun(ctx, callbacks.RunLLM, opts.Model)
+	if o.CallbacksHandler != nil {
+		o.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
+	}
+
 	// Assume we get a single text message
 	msg0 := messages[0]
 	part := msg0.Parts[0]
@@ -60,6 +61,12 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 			},
 		},
 	}
+	if result.InputTokens > 0 || result.OutputTokens > 0 {
+		resp.Usage = llms.NewUsage(result.InputTokens, result.OutputTokens)
+	}
+	if o.CallbacksHandler != nil {
+		o.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, resp)
+	}
 	return resp, nil
 }
 
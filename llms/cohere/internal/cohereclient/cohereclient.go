@@ -70,6 +70,9 @@ type GenerationRequest struct {
 
 type Generation struct {
 	Text string `json:"text"`
+	// InputTokens and OutputTokens are the billed tokens of the generation.
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
 }
 
 type generateRequestPayload struct {
@@ -84,6 +87,12 @@ type generateResponsePayload struct {
 		ID   string `json:"id,omitempty"`
 		Text string `json:"text,omitempty"`
 	} `json:"generations,omitempty"`
+	Meta struct {
+		BilledUnits struct {
+			InputTokens  int `json:"input_tokens"`
+			OutputTokens int `json:"output_tokens"`
+		} `json:"billed_units"`
+	} `json:"meta"`
 }
 
 func (c *Client) CreateGeneration(ctx context.Context, r *GenerationRequest) (*Generation, error) {
@@ -134,6 +143,8 @@ func (c *Client) CreateGeneration(ctx context.Context, r *GenerationRequest) (*G
 
 	var generation Generation
 	generation.Text = response.Generations[0].Text
+	generation.InputTokens = response.Meta.BilledUnits.InputTokens
+	generation.OutputTokens = response.Meta.BilledUnits.OutputTokens
 
 	return &generation, nil
 }
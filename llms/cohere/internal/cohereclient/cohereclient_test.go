@@ -0,0 +1,32 @@
+package cohereclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateGeneration(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/generate", r.URL.Path)
+		_, _ = io.WriteString(w, `{
+			"id": "gen",
+			"generations": [{"id": "1", "text": "Hello!"}],
+			"meta": {"billed_units": {"input_tokens": 3, "output_tokens": 2}}
+		}`)
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := New("token", server.URL, "command")
+	require.NoError(t, err)
+	generation, err := c.CreateGeneration(context.Background(), &GenerationRequest{Prompt: "Hi"})
+	require.NoError(t, err)
+	require.Equal(t, &Generation{Text: "Hello!", InputTokens: 3, OutputTokens: 2}, generation)
+}
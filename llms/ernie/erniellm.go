@@ -66,15 +66,32 @@ func (o *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOptio
 // GenerateContent implements the Model interface.
 func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint: lll, cyclop, whitespace
 
+	opts := &llms.CallOptions{}
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	model := string(o.model)
+	if model == "" {
+		model = opts.Model
+	}
+	ctx = callbacks.StartRun(ctx, callbacks.RunLLM, model)
 	if o.CallbacksHandler != nil {
 		o.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
 	}
 
-	opts := &llms.CallOptions{}
-	for _, opt := range options {
-		opt(opts)
+	resp, err := o.generateContent(ctx, messages, opts)
+	if o.CallbacksHandler != nil {
+		if err != nil {
+			o.CallbacksHandler.HandleLLMError(ctx, err)
+		} else {
+			o.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, resp)
+		}
 	}
+	return resp, err
+}
 
+func (o *LLM) generateContent(ctx context.Context, messages []llms.MessageContent, opts *llms.CallOptions) (*llms.ContentResponse, error) { //nolint: lll
 	// Assume we get a single text message
 	msg0 := messages[0]
 	part := msg0.Parts[0]
@@ -87,17 +104,11 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 		Stream:        opts.StreamingFunc != nil,
 	})
 	if err != nil {
-		if o.CallbacksHandler != nil {
-			o.CallbacksHandler.HandleLLMError(ctx, err)
-		}
 		return nil, err
 	}
 	if result.ErrorCode > 0 {
 		err = fmt.Errorf("%w, error_code:%v, erro_msg:%v, id:%v",
 			ErrCodeResponse, result.ErrorCode, result.ErrorMsg, result.ID)
-		if o.CallbacksHandler != nil {
-			o.CallbacksHandler.HandleLLMError(ctx, err)
-		}
 		return nil, err
 	}
 
@@ -107,11 +118,12 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 				Content: result.Result,
 			},
 		},
+		Usage: &llms.Usage{
+			PromptTokens:     result.Usage.PromptTokens,
+			CompletionTokens: result.Usage.CompletionTokens,
+			TotalTokens:      result.Usage.TotalTokens,
+		},
 	}
-	if o.CallbacksHandler != nil {
-		o.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, resp)
-	}
-
 	return resp, nil
 }
 
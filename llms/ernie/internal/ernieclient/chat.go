@@ -5,7 +5,6 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -171,16 +170,12 @@ func (c *Client) createChat(ctx context.Context, payload *ChatRequest) (*ChatRes
 	defer r.Body.Close()
 
 	if r.StatusCode != http.StatusOK {
-		msg := fmt.Sprintf("API returned unexpected status code: %d", r.StatusCode)
-
 		// No need to check the error here: if it fails, we'll just return the
 		// status code.
 		var errResp errorMessage
-		if err := json.NewDecoder(r.Body).Decode(&errResp); err != nil {
-			return nil, errors.New(msg) // nolint:goerr113
-		}
+		_ = json.NewDecoder(r.Body).Decode(&errResp)
 
-		return nil, fmt.Errorf("%s: %s", msg, errResp.Error.Message) // nolint:goerr113
+		return nil, llms.NewAPIError(r, errResp.Error.Message)
 	}
 	if payload.StreamingFunc != nil {
 		return parseStreamingChatResponse(ctx, r, payload)
@@ -0,0 +1,136 @@
+package llms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// APIError is an error returned by a provider API in response to a request.
+// Provider clients return it for failed HTTP responses so that callers can
+// classify failures without parsing error messages.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Message is the error message returned by the provider, if any.
+	Message string
+	// RetryAfter is the delay the provider asked for before retrying, taken
+	// from the Retry-After header. It is zero if the header was not set.
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("API returned unexpected status code: %d", e.StatusCode)
+	if e.Message == "" {
+		return msg
+	}
+	return msg + ": " + e.Message
+}
+
+// Retryable reports whether the request may succeed if retried: rate limits,
+// timeouts and server-side failures are retryable, other client errors are
+// not.
+func (e *APIError) Retryable() bool {
+	return IsRetryableStatus(e.StatusCode)
+}
+
+// NewAPIError creates an APIError from a failed HTTP response.
+func NewAPIError(resp *http.Response, message string) *APIError {
+	return &APIError{
+		StatusCode: resp.StatusCode,
+		Message:    message,
+		RetryAfter: ParseRetryAfter(resp.Header, time.Now()),
+	}
+}
+
+// IsRetryableStatus reports whether a request that failed with the given HTTP
+// status code may succeed if retried.
+func IsRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusRequestTimeout,
+		http.StatusConflict,
+		http.StatusTooEarly,
+		http.StatusTooManyRequests:
+		return true
+	}
+	return code >= http.StatusInternalServerError
+}
+
+// IsRetryable reports whether err is a transient failure that may succeed if
+// the request is retried. Errors implementing `Retryable() bool` classify
+// themselves; errors exposing an HTTP status code through `HTTPStatusCode()`
+// or `HTTPCode()` (as the AWS and Google SDKs do) are classified by status.
+// Network timeouts and dropped connections are retryable, while cancellation
+// and deadline expiry of the caller's context are not.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var retryable interface{ Retryable() bool }
+	if errors.As(err, &retryable) {
+		return retryable.Retryable()
+	}
+	var awsErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &awsErr) {
+		return IsRetryableStatus(awsErr.HTTPStatusCode())
+	}
+	var googleErr interface{ HTTPCode() int }
+	if errors.As(err, &googleErr) && googleErr.HTTPCode() > 0 {
+		return IsRetryableStatus(googleErr.HTTPCode())
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE)
+}
+
+// RetryAfter returns the delay the provider asked for before retrying the
+// request that failed with err, if any.
+func RetryAfter(err error) (time.Duration, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter, true
+	}
+	return 0, false
+}
+
+// ParseRetryAfter parses the retry delay from response headers. It
+// understands the millisecond `retry-after-ms` header sent by some providers
+// and the standard Retry-After header in both its delay-seconds and HTTP-date
+// forms. It returns zero if no valid delay is set.
+func ParseRetryAfter(header http.Header, now time.Time) time.Duration {
+	if v := header.Get("Retry-After-Ms"); v != "" {
+		if ms, err := strconv.ParseFloat(v, 64); err == nil && ms > 0 {
+			return time.Duration(ms * float64(time.Millisecond))
+		}
+	}
+	v := header.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(v); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
@@ -0,0 +1,85 @@
+package llms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type statusCodeError int
+
+func (e statusCodeError) Error() string       { return "status error" }
+func (e statusCodeError) HTTPStatusCode() int { return int(e) }
+
+func TestIsRetryable(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"rate limited", &APIError{StatusCode: http.StatusTooManyRequests}, true},
+		{"server error", &APIError{StatusCode: http.StatusBadGateway}, true},
+		{"bad request", &APIError{StatusCode: http.StatusBadRequest}, false},
+		{"wrapped", fmt.Errorf("generate: %w", &APIError{StatusCode: http.StatusServiceUnavailable}), true},
+		{"sdk status code", statusCodeError(http.StatusTooManyRequests), true},
+		{"sdk client error", statusCodeError(http.StatusUnauthorized), false},
+		{"connection reset", fmt.Errorf("read: %w", syscall.ECONNRESET), true},
+		{"unexpected eof", io.ErrUnexpectedEOF, true},
+		{"canceled", context.Canceled, false},
+		{"other", errors.New("boom"), false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, IsRetryable(tc.err))
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+	}{
+		{"none", http.Header{}, 0},
+		{"seconds", http.Header{"Retry-After": {"3"}}, 3 * time.Second},
+		{"milliseconds", http.Header{"Retry-After-Ms": {"250"}, "Retry-After": {"1"}}, 250 * time.Millisecond},
+		{"date", http.Header{"Retry-After": {now.Add(time.Minute).Format(http.TimeFormat)}}, time.Minute},
+		{"past date", http.Header{"Retry-After": {now.Add(-time.Minute).Format(http.TimeFormat)}}, 0},
+		{"invalid", http.Header{"Retry-After": {"soon"}}, 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, ParseRetryAfter(tc.header, now))
+		})
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	t.Parallel()
+
+	err := NewAPIError(&http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Header:     http.Header{"Retry-After": {"2"}},
+	}, "rate limit exceeded")
+	assert.Equal(t, "API returned unexpected status code: 429: rate limit exceeded", err.Error())
+
+	d, ok := RetryAfter(fmt.Errorf("call: %w", err))
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Second, d)
+}
@@ -0,0 +1,214 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// ErrNoResponse is returned by a ScriptedLLM when none of its responses
+// matches a call.
+var ErrNoResponse = errors.New("no scripted response matches the call")
+
+// Matcher reports whether a scripted response answers a call, given its
+// messages.
+type Matcher func(messages []llms.MessageContent) bool
+
+// Response is a scripted response of a ScriptedLLM.
+type Response struct {
+	// Match restricts the response to the calls it matches. Responses
+	// without Match answer any call.
+	Match Matcher
+	// Repeat keeps the response once it was used, so that it answers every
+	// matching call.
+	Repeat bool
+
+	// Content is the text of the response.
+	Content string
+	// ReasoningContent is the reasoning of the response.
+	ReasoningContent string
+	// ToolCalls are the tool calls of the response.
+	ToolCalls []llms.ToolCall
+	// StopReason is the reason the model stopped generating output.
+	StopReason string
+	// Usage is the token usage of the response.
+	Usage *llms.Usage
+	// Chunks are the chunks of Content streamed to the streaming functions of
+	// the call. By default, Content is streamed as a single chunk.
+	Chunks []string
+	// Err is the error returned instead of the response, after streaming its
+	// chunks.
+	Err error
+}
+
+// Call is a call received by a ScriptedLLM.
+type Call struct {
+	Messages []llms.MessageContent
+	Options  llms.CallOptions
+}
+
+// ScriptedLLM is a fake model answering calls with scripted responses,
+// including tool calls, streamed chunks and errors. Each call is answered by
+// the first unused response matching it, and the calls are recorded for
+// assertions.
+type ScriptedLLM struct {
+	mu        sync.Mutex
+	responses []Response
+	calls     []Call
+}
+
+var _ llms.Model = (*ScriptedLLM)(nil)
+
+// NewScriptedLLM creates a fake model answering calls with the responses.
+func NewScriptedLLM(responses ...Response) *ScriptedLLM {
+	return &ScriptedLLM{responses: responses}
+}
+
+// AddResponse adds responses after the scripted responses.
+func (f *ScriptedLLM) AddResponse(responses ...Response) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responses = append(f.responses, responses...)
+}
+
+// Calls returns the calls received by the model.
+func (f *ScriptedLLM) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Call(nil), f.calls...)
+}
+
+// Remaining returns the number of responses that weren't used, repeated
+// responses excluded.
+func (f *ScriptedLLM) Remaining() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, response := range f.responses {
+		if !response.Repeat {
+			n++
+		}
+	}
+	return n
+}
+
+// Call the model with a prompt.
+func (f *ScriptedLLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, f, prompt, options...)
+}
+
+// GenerateContent answers the call with the first unused response matching
+// it, streaming its chunks to the streaming functions of the options.
+func (f *ScriptedLLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint:lll
+	var opts llms.CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	response, err := f.next(messages, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	choice := &llms.ContentChoice{
+		Content:          response.Content,
+		ReasoningContent: response.ReasoningContent,
+		StopReason:       response.StopReason,
+		ToolCalls:        response.ToolCalls,
+	}
+	if len(response.ToolCalls) > 0 {
+		choice.FuncCall = response.ToolCalls[0].FunctionCall
+	}
+	resp := &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{choice},
+		Usage:   response.Usage,
+	}
+	if err := llms.StreamResponse(ctx, opts, resp, response.Chunks...); err != nil {
+		return nil, err
+	}
+	if response.Err != nil {
+		return nil, response.Err
+	}
+	return resp, nil
+}
+
+// next records the call and returns the response answering it.
+func (f *ScriptedLLM) next(messages []llms.MessageContent, opts llms.CallOptions) (Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, Call{Messages: messages, Options: opts})
+	for i, response := range f.responses {
+		if response.Match != nil && !response.Match(messages) {
+			continue
+		}
+		if !response.Repeat {
+			f.responses = append(f.responses[:i:i], f.responses[i+1:]...)
+		}
+		return response, nil
+	}
+	return Response{}, ErrNoResponse
+}
+
+// LastMessageContains matches calls whose last message contains substr, in
+// a text part or a tool result.
+func LastMessageContains(substr string) Matcher {
+	return func(messages []llms.MessageContent) bool {
+		if len(messages) == 0 {
+			return false
+		}
+		for _, part := range messages[len(messages)-1].Parts {
+			switch part := part.(type) {
+			case llms.TextContent:
+				if strings.Contains(part.Text, substr) {
+					return true
+				}
+			case llms.ToolCallResponse:
+				if strings.Contains(part.Content, substr) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
+
+// LastMessageRole matches calls whose last message has the role.
+func LastMessageRole(role llms.ChatMessageType) Matcher {
+	return func(messages []llms.MessageContent) bool {
+		return len(messages) > 0 && messages[len(messages)-1].Role == role
+	}
+}
+
+// ToolResult matches calls whose last message has the result of a call of
+// the tool, or of any tool if name is empty.
+func ToolResult(name string) Matcher {
+	return func(messages []llms.MessageContent) bool {
+		if len(messages) == 0 {
+			return false
+		}
+		for _, part := range messages[len(messages)-1].Parts {
+			if part, ok := part.(llms.ToolCallResponse); ok && (name == "" || part.Name == name) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// All matches calls matched by all the matchers.
+func All(matchers ...Matcher) Matcher {
+	return func(messages []llms.MessageContent) bool {
+		for _, match := range matchers {
+			if !match(messages) {
+				return false
+			}
+		}
+		return true
+	}
+}
@@ -0,0 +1,146 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestScriptedLLMToolCalls(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	search := llms.ToolCall{
+		ID:           "call_1",
+		Type:         "function",
+		FunctionCall: &llms.FunctionCall{Name: "search", Arguments: `{"query":"weather"}`},
+	}
+	llm := NewScriptedLLM(
+		Response{
+			Match:   ToolResult("search"),
+			Content: "It's sunny.",
+			Usage:   llms.NewUsage(20, 4),
+		},
+		Response{ToolCalls: []llms.ToolCall{search}, StopReason: "tool_calls"},
+	)
+
+	messages := []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "What's the weather?")}
+	resp, err := llm.GenerateContent(ctx, messages, llms.WithTemperature(0))
+	require.NoError(t, err)
+	require.Equal(t, []llms.ToolCall{search}, resp.Choices[0].ToolCalls)
+	require.Equal(t, search.FunctionCall, resp.Choices[0].FuncCall)
+	require.Equal(t, "tool_calls", resp.Choices[0].StopReason)
+
+	messages = append(messages,
+		llms.MessageContent{Role: llms.ChatMessageTypeAI, Parts: []llms.ContentPart{search}},
+		llms.MessageContent{Role: llms.ChatMessageTypeTool, Parts: []llms.ContentPart{
+			llms.ToolCallResponse{ToolCallID: "call_1", Name: "search", Content: "sunny"},
+		}},
+	)
+	resp, err = llm.GenerateContent(ctx, messages)
+	require.NoError(t, err)
+	require.Equal(t, "It's sunny.", resp.Choices[0].Content)
+	require.Equal(t, llms.NewUsage(20, 4), resp.Usage)
+	require.Equal(t, 0, llm.Remaining())
+
+	_, err = llm.GenerateContent(ctx, messages)
+	require.ErrorIs(t, err, ErrNoResponse)
+
+	calls := llm.Calls()
+	require.Len(t, calls, 3)
+	require.Len(t, calls[0].Messages, 1)
+	require.NotNil(t, calls[0].Options.Temperature)
+	require.Len(t, calls[1].Messages, 3)
+}
+
+func TestScriptedLLMMatchers(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	llm := NewScriptedLLM(
+		Response{Match: LastMessageContains("error"), Err: errors.New("boom")},
+		Response{
+			Match:   All(LastMessageRole(llms.ChatMessageTypeHuman), LastMessageContains("ping")),
+			Content: "pong",
+			Repeat:  true,
+		},
+		Response{Content: "default", Repeat: true},
+	)
+
+	for range 2 {
+		output, err := llm.Call(ctx, "ping")
+		require.NoError(t, err)
+		require.Equal(t, "pong", output)
+	}
+	output, err := llm.Call(ctx, "hello")
+	require.NoError(t, err)
+	require.Equal(t, "default", output)
+
+	_, err = llm.Call(ctx, "an error")
+	require.EqualError(t, err, "boom")
+	// The error response was used.
+	output, err = llm.Call(ctx, "an error")
+	require.NoError(t, err)
+	require.Equal(t, "default", output)
+}
+
+func TestScriptedLLMStreaming(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	call := llms.ToolCall{ID: "call_1", Type: "function", FunctionCall: &llms.FunctionCall{Name: "f", Arguments: "{}"}}
+	response := Response{
+		Content:          "hello world",
+		ReasoningContent: "think",
+		Chunks:           []string{"hello", " world"},
+		ToolCalls:        []llms.ToolCall{call},
+	}
+	llm := NewScriptedLLM(response, response, Response{Chunks: []string{"partial"}, Err: errors.New("cut off")})
+
+	var chunks []string
+	var reasoning string
+	resp, err := llm.GenerateContent(ctx, nil,
+		llms.WithStreamingFunc(func(_ context.Context, chunk []byte) error {
+			chunks = append(chunks, string(chunk))
+			return nil
+		}),
+		llms.WithStreamingReasoningFunc(func(_ context.Context, reasoningChunk, _ []byte) error {
+			reasoning += string(reasoningChunk)
+			return nil
+		}))
+	require.NoError(t, err)
+	require.Equal(t, "hello world", resp.Choices[0].Content)
+	require.Equal(t, []string{"hello", " world"}, chunks)
+	require.Equal(t, "think", reasoning)
+
+	var events []llms.StreamEvent
+	for event := range llms.StreamContent(ctx, llm, nil) {
+		events = append(events, event)
+	}
+	require.Equal(t, []llms.StreamEventType{
+		llms.StreamEventReasoning,
+		llms.StreamEventText,
+		llms.StreamEventText,
+		llms.StreamEventToolCall,
+		llms.StreamEventStop,
+	}, eventTypes(events))
+
+	chunks = nil
+	_, err = llm.GenerateContent(ctx, nil, llms.WithStreamingFunc(func(_ context.Context, chunk []byte) error {
+		chunks = append(chunks, string(chunk))
+		return nil
+	}))
+	require.EqualError(t, err, "cut off")
+	require.Equal(t, []string{"partial"}, chunks)
+}
+
+func eventTypes(events []llms.StreamEvent) []llms.StreamEventType {
+	types := make([]llms.StreamEventType, 0, len(events))
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	return types
+}
@@ -53,6 +53,9 @@ type ContentPart interface {
 // TextContent is content with some text.
 type TextContent struct {
 	Text string
+
+	// CacheControl marks the part as a cache breakpoint, see WithCacheControl.
+	CacheControl *CacheControl
 }
 
 func (tc TextContent) String() string {
@@ -77,6 +80,9 @@ func (ImageURLContent) isPart() {}
 type BinaryContent struct {
 	MIMEType string
 	Data     []byte
+
+	// CacheControl marks the part as a cache breakpoint, see WithCacheControl.
+	CacheControl *CacheControl
 }
 
 func (bc BinaryContent) String() string {
@@ -102,6 +108,8 @@ type ToolCall struct {
 	Type string `json:"type"`
 	// FunctionCall is the function call to be executed.
 	FunctionCall *FunctionCall `json:"function,omitempty"`
+	// CacheControl marks the part as a cache breakpoint, see WithCacheControl.
+	CacheControl *CacheControl `json:"cache_control,omitempty"`
 }
 
 func (ToolCall) isPart() {}
@@ -114,14 +122,77 @@ type ToolCallResponse struct {
 	Name string `json:"name"`
 	// Content is the textual content of the response.
 	Content string `json:"content"`
+	// CacheControl marks the part as a cache breakpoint, see WithCacheControl.
+	CacheControl *CacheControl `json:"cache_control,omitempty"`
 }
 
 func (ToolCallResponse) isPart() {}
 
+// ThinkingContent is a thinking block of a model with extended thinking, e.g.
+// Anthropic models. The thinking blocks of a response are in
+// ContentChoice.Thinking; they must be sent back unchanged, before the tool
+// calls, in the AI message of a tool use conversation.
+type ThinkingContent struct {
+	// Thinking is the reasoning of the model, empty for redacted thinking.
+	Thinking string
+	// Signature lets the provider verify the thinking when it is sent back.
+	Signature string
+	// RedactedData is the encrypted reasoning of a redacted thinking block.
+	RedactedData string
+}
+
+func (tc ThinkingContent) String() string {
+	return tc.Thinking
+}
+
+func (ThinkingContent) isPart() {}
+
+// CacheControl describes how the provider caches the prompt up to and
+// including a content part.
+type CacheControl struct {
+	// Type is the type of the cache. Anthropic only supports "ephemeral".
+	Type string `json:"type"`
+	// TTL is the lifetime of the cache entry, e.g. "5m" or "1h". The default
+	// lifetime of the provider is used if empty.
+	TTL string `json:"ttl,omitempty"`
+}
+
+// WithCacheControl returns a copy of the part marked as a cache breakpoint:
+// providers with explicit prompt caching, e.g. Anthropic, cache the prompt up
+// to and including the part, so that later calls sharing this prefix are
+// cheaper and faster. Providers caching prompts automatically ignore it.
+//
+// TextContent, BinaryContent, ToolCall and ToolCallResponse parts can be
+// breakpoints; other parts are returned unchanged.
+func WithCacheControl(part ContentPart, cacheControl CacheControl) ContentPart {
+	switch p := part.(type) {
+	case TextContent:
+		p.CacheControl = &cacheControl
+		return p
+	case BinaryContent:
+		p.CacheControl = &cacheControl
+		return p
+	case ToolCall:
+		p.CacheControl = &cacheControl
+		return p
+	case ToolCallResponse:
+		p.CacheControl = &cacheControl
+		return p
+	}
+	return part
+}
+
 // ContentResponse is the response returned by a GenerateContent call.
 // It can potentially return multiple content choices.
 type ContentResponse struct {
 	Choices []*ContentChoice
+
+	// Usage is the token usage of the call, or nil if the provider doesn't
+	// report it. The local and googleai/palm adapters always return a nil
+	// Usage, as do huggingface for servers without token counts, cloudflare
+	// for the models without usage and bedrock for Cohere models when Bedrock
+	// omits the token count headers.
+	Usage *Usage
 }
 
 // ContentChoice is one of the response choices returned by GenerateContent
@@ -144,8 +215,15 @@ type ContentChoice struct {
 	// ToolCalls is a list of tool calls the model asks to invoke.
 	ToolCalls []ToolCall
 
-	// This field is only used with the deepseek-reasoner model and represents the reasoning contents of the assistant message before the final answer.
+	// ReasoningContent is the reasoning of the model before its final answer,
+	// for models exposing it, e.g. deepseek-reasoner or Anthropic models with
+	// extended thinking.
 	ReasoningContent string
+
+	// Thinking is the thinking blocks of the response, for models which need
+	// them back in the AI message, e.g. Anthropic models with extended
+	// thinking.
+	Thinking []ThinkingContent
 }
 
 // TextParts is a helper function to create a MessageContent with a role and a
@@ -179,6 +257,8 @@ func ShowMessageContents(w io.Writer, msgs []MessageContent) {
 				fmt.Fprintf(w, "ToolCall ID=%v, Type=%v, Func=%v(%v)\n", pp.ID, pp.Type, pp.FunctionCall.Name, pp.FunctionCall.Arguments)
 			case ToolCallResponse:
 				fmt.Fprintf(w, "ToolCallResponse ID=%v, Name=%v, Content=%v\n", pp.ToolCallID, pp.Name, pp.Content)
+			case ThinkingContent:
+				fmt.Fprintf(w, "ThinkingContent %q, redacted=%v\n", pp.Thinking, pp.RedactedData != "")
 			default:
 				fmt.Fprintf(w, "unknown type %T\n", pp)
 			}
@@ -34,3 +34,26 @@ func TestTextParts(t *testing.T) {
 		})
 	}
 }
+
+func TestWithCacheControl(t *testing.T) {
+	t.Parallel()
+	cc := CacheControl{Type: "ephemeral", TTL: "1h"}
+	tests := []struct {
+		name string
+		part ContentPart
+		want ContentPart
+	}{
+		{"text", TextPart("a"), TextContent{Text: "a", CacheControl: &cc}},
+		{"binary", BinaryPart("image/png", []byte{1}), BinaryContent{MIMEType: "image/png", Data: []byte{1}, CacheControl: &cc}},
+		{"tool response", ToolCallResponse{ToolCallID: "1"}, ToolCallResponse{ToolCallID: "1", CacheControl: &cc}},
+		{"unsupported", ImageURLPart("https://example.com/a.png"), ImageURLPart("https://example.com/a.png")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := WithCacheControl(tt.part, cc); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WithCacheControl() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
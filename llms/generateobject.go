@@ -0,0 +1,222 @@
+package llms
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/tmc/langchaingo/jsonschema"
+)
+
+// ErrInvalidObject is returned by GenerateObject when the model doesn't
+// respond with a JSON document matching the schema, even after being asked to
+// repair it.
+var ErrInvalidObject = errors.New("model response does not match the schema")
+
+// ResponseSchemaSupporter is implemented by models able to constrain their
+// responses to a JSON schema given with WithResponseSchema, using the
+// structured output mode of their provider.
+type ResponseSchemaSupporter interface {
+	// SupportsResponseSchema reports whether the model honors the
+	// ResponseSchema call option.
+	SupportsResponseSchema() bool
+}
+
+// defaultMaxRepairAttempts is the number of repair attempts of GenerateObject
+// unless WithMaxRepairAttempts is given.
+const defaultMaxRepairAttempts = 2
+
+// valueProperty is the property holding values that aren't JSON objects, as
+// most providers require the response to be an object.
+const valueProperty = "value"
+
+var schemaNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// GenerateObject asks the model to respond to the messages with a JSON
+// document describing a value of type T, and decodes it. The schema of the
+// document is derived from T with jsonschema.Reflect.
+//
+// Models implementing ResponseSchemaSupporter are given the schema with
+// WithResponseSchema, so that their provider's structured output mode is
+// used. Other models are instructed to respond with JSON in the prompt. In
+// both cases, responses that don't match the schema are sent back to the
+// model with the validation errors, up to MaxRepairAttempts times (2 by
+// default). The name and description of the schema can be set with
+// WithResponseSchema.
+func GenerateObject[T any](ctx context.Context, model Model, messages []MessageContent, options ...CallOption) (T, error) { //nolint:lll
+	var zero T
+
+	opts := CallOptions{MaxRepairAttempts: defaultMaxRepairAttempts}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	schema, wrapped := objectSchema(jsonschema.Reflect[T]())
+	rs := &ResponseSchema{Name: schemaName(reflect.TypeOf((*T)(nil)).Elem())}
+	if opts.ResponseSchema != nil {
+		*rs = *opts.ResponseSchema
+	}
+	rs.Schema = schema
+
+	callOptions := append(options[:len(options):len(options)], WithResponseSchema(rs))
+	messages = slices.Clone(messages)
+	if s, ok := model.(ResponseSchemaSupporter); !ok || !s.SupportsResponseSchema() {
+		instructions, err := schemaInstructions(rs)
+		if err != nil {
+			return zero, err
+		}
+		messages = appendInstructions(messages, instructions)
+		callOptions = append(callOptions, WithJSONMode())
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= max(opts.MaxRepairAttempts, 0); attempt++ {
+		resp, err := model.GenerateContent(ctx, messages, callOptions...)
+		if err != nil {
+			return zero, err
+		}
+		text, err := responseText(resp, rs.Name)
+		if err != nil {
+			return zero, err
+		}
+
+		v, err := decodeObject[T](schema, text, wrapped)
+		if err == nil {
+			return v, nil
+		}
+		lastErr = err
+		messages = append(messages,
+			TextParts(ChatMessageTypeAI, text),
+			TextParts(ChatMessageTypeHuman, fmt.Sprintf(
+				"Your response does not match the JSON schema:\n%v\n\n"+
+					"Respond again with only the corrected JSON document.", err)),
+		)
+	}
+	return zero, fmt.Errorf("%w: %w", ErrInvalidObject, lastErr)
+}
+
+// objectSchema returns the schema of the JSON object describing a value of
+// the given schema, and whether the value is wrapped in the valueProperty of
+// the object.
+func objectSchema(schema jsonschema.Definition) (jsonschema.Definition, bool) {
+	if schema.Type == jsonschema.Object && schema.Ref == "" {
+		return schema, false
+	}
+	defs := schema.Defs
+	schema.Defs = nil
+	return jsonschema.Definition{
+		Type:       jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{valueProperty: schema},
+		Required:   []string{valueProperty},
+		Defs:       defs,
+	}, true
+}
+
+// schemaName returns the name of the schema describing values of type t.
+func schemaName(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if schemaNamePattern.MatchString(t.Name()) {
+		return t.Name()
+	}
+	return "response"
+}
+
+// schemaInstructions returns the instructions given to models without native
+// structured output support.
+func schemaInstructions(rs *ResponseSchema) (string, error) {
+	b, err := json.Marshal(rs.Schema)
+	if err != nil {
+		return "", fmt.Errorf("marshal response schema: %w", err)
+	}
+	var sb strings.Builder
+	sb.WriteString("Respond only with a JSON document matching the following JSON schema, ")
+	sb.WriteString("without any other text or markdown formatting.")
+	if rs.Description != "" {
+		sb.WriteString("\nThe document describes: " + rs.Description)
+	}
+	sb.WriteString("\n\nJSON schema:\n")
+	sb.Write(b)
+	return sb.String(), nil
+}
+
+// appendInstructions adds the instructions to the last message if it was
+// sent by the user, or in a new user message otherwise. The parts of the last
+// message are copied, so that the messages of the caller are left unchanged.
+func appendInstructions(messages []MessageContent, instructions string) []MessageContent {
+	if n := len(messages); n > 0 && messages[n-1].Role == ChatMessageTypeHuman {
+		last := messages[n-1]
+		parts := make([]ContentPart, 0, len(last.Parts)+1)
+		parts = append(parts, last.Parts...)
+		last.Parts = append(parts, TextPart(instructions))
+		return append(messages[:n-1], last)
+	}
+	return append(messages, TextParts(ChatMessageTypeHuman, instructions))
+}
+
+// responseText returns the JSON document of the response: the content of the
+// first choice, or the arguments of its call to the schema tool for providers
+// implementing structured output with tools.
+func responseText(resp *ContentResponse, name string) (string, error) {
+	if resp == nil || len(resp.Choices) == 0 {
+		return "", errors.New("empty response from model")
+	}
+	choice := resp.Choices[0]
+	if strings.TrimSpace(choice.Content) == "" {
+		for _, call := range choice.ToolCalls {
+			if call.FunctionCall != nil && call.FunctionCall.Name == name {
+				return call.FunctionCall.Arguments, nil
+			}
+		}
+	}
+	return choice.Content, nil
+}
+
+// decodeObject validates the JSON document in text and decodes it.
+func decodeObject[T any](schema jsonschema.Definition, text string, wrapped bool) (T, error) {
+	var v T
+	data := []byte(extractJSON(text))
+	if err := jsonschema.Validate(schema, data); err != nil {
+		return v, err
+	}
+	if !wrapped {
+		err := json.Unmarshal(data, &v)
+		return v, err
+	}
+	var w map[string]json.RawMessage
+	if err := json.Unmarshal(data, &w); err != nil {
+		return v, err
+	}
+	err := json.Unmarshal(w[valueProperty], &v)
+	return v, err
+}
+
+// extractJSON returns the JSON document in text, removing markdown code
+// fences and text around the document.
+func extractJSON(text string) string {
+	text = strings.TrimSpace(text)
+	if json.Valid([]byte(text)) {
+		return text
+	}
+	if _, after, ok := strings.Cut(text, "```"); ok {
+		after = strings.TrimPrefix(after, "json")
+		if block, _, ok := strings.Cut(after, "```"); ok {
+			text = strings.TrimSpace(block)
+			if json.Valid([]byte(text)) {
+				return text
+			}
+		}
+	}
+	start := strings.IndexAny(text, "{[")
+	end := strings.LastIndexAny(text, "}]")
+	if start >= 0 && end > start {
+		return text[start : end+1]
+	}
+	return text
+}
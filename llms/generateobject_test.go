@@ -0,0 +1,143 @@
+package llms
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// objectModel returns canned responses and records the requests it receives.
+type objectModel struct {
+	native    bool
+	responses []*ContentResponse
+	messages  [][]MessageContent
+	options   []CallOptions
+}
+
+func (m *objectModel) GenerateContent(_ context.Context, messages []MessageContent, options ...CallOption) (*ContentResponse, error) { //nolint:lll
+	var opts CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+	m.messages = append(m.messages, messages)
+	m.options = append(m.options, opts)
+	resp := m.responses[0]
+	m.responses = m.responses[1:]
+	return resp, nil
+}
+
+func (m *objectModel) Call(context.Context, string, ...CallOption) (string, error) {
+	return "", nil
+}
+
+type nativeObjectModel struct {
+	objectModel
+}
+
+func (m *nativeObjectModel) SupportsResponseSchema() bool {
+	return true
+}
+
+func textResponse(text string) *ContentResponse {
+	return &ContentResponse{Choices: []*ContentChoice{{Content: text}}}
+}
+
+type recipe struct {
+	Title       string   `json:"title"`
+	Ingredients []string `json:"ingredients"`
+	Minutes     int      `json:"minutes" minimum:"1"`
+}
+
+func TestGenerateObjectNative(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	m := &nativeObjectModel{}
+	m.responses = []*ContentResponse{
+		{Choices: []*ContentChoice{{ToolCalls: []ToolCall{{
+			FunctionCall: &FunctionCall{Name: "recipe", Arguments: `{"title":"Soup","ingredients":["water"],"minutes":10}`},
+		}}}}},
+	}
+
+	v, err := GenerateObject[recipe](ctx, m, []MessageContent{TextParts(ChatMessageTypeHuman, "soup")},
+		WithTemperature(0.5))
+	require.NoError(t, err)
+	require.Equal(t, recipe{Title: "Soup", Ingredients: []string{"water"}, Minutes: 10}, v)
+
+	require.Len(t, m.options, 1)
+	opts := m.options[0]
+	require.InDelta(t, 0.5, opts.Temperature, 1e-9)
+	require.False(t, opts.JSONMode)
+	require.NotNil(t, opts.ResponseSchema)
+	require.Equal(t, "recipe", opts.ResponseSchema.Name)
+	require.Equal(t, []string{"title", "ingredients", "minutes"}, opts.ResponseSchema.Schema.Required)
+	require.Equal(t, []MessageContent{TextParts(ChatMessageTypeHuman, "soup")}, m.messages[0])
+}
+
+func TestGenerateObjectRepair(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	m := &objectModel{responses: []*ContentResponse{
+		textResponse(`{"title":"Soup","ingredients":["water"],"minutes":0}`),
+		textResponse("Here it is:\n```json\n{\"title\":\"Soup\",\"ingredients\":[\"water\"],\"minutes\":5}\n```"),
+	}}
+
+	messages := []MessageContent{
+		TextParts(ChatMessageTypeSystem, "You are a cook."),
+		TextParts(ChatMessageTypeHuman, "soup"),
+	}
+	v, err := GenerateObject[recipe](ctx, m, messages)
+	require.NoError(t, err)
+	require.Equal(t, 5, v.Minutes)
+
+	require.Len(t, m.messages, 2)
+	require.True(t, m.options[0].JSONMode)
+	require.Len(t, messages[1].Parts, 1, "the messages of the caller must not be modified")
+
+	first := m.messages[0]
+	require.Len(t, first, 2)
+	require.Len(t, first[1].Parts, 2)
+	require.Contains(t, first[1].Parts[1].(TextContent).Text, `"minutes"`)
+
+	repair := m.messages[1]
+	require.Len(t, repair, 4)
+	require.Equal(t, ChatMessageTypeAI, repair[2].Role)
+	require.Equal(t, ChatMessageTypeHuman, repair[3].Role)
+	require.Contains(t, repair[3].Parts[0].(TextContent).Text, "/minutes: value 0 is less than the minimum of 1")
+}
+
+func TestGenerateObjectInvalid(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	m := &objectModel{responses: []*ContentResponse{
+		textResponse("not json"),
+		textResponse(`{"title":"Soup"}`),
+	}}
+
+	_, err := GenerateObject[recipe](ctx, m, []MessageContent{TextParts(ChatMessageTypeHuman, "soup")},
+		WithMaxRepairAttempts(1))
+	require.ErrorIs(t, err, ErrInvalidObject)
+	require.ErrorContains(t, err, `missing required property "minutes"`)
+	require.Len(t, m.messages, 2)
+}
+
+func TestGenerateObjectWrapsValues(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	m := &nativeObjectModel{}
+	m.responses = []*ContentResponse{textResponse(`{"value":["a","b"]}`)}
+
+	v, err := GenerateObject[[]string](ctx, m, []MessageContent{TextParts(ChatMessageTypeHuman, "letters")},
+		WithResponseSchema(&ResponseSchema{Name: "letters", Description: "Some letters"}))
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, v)
+
+	rs := m.options[0].ResponseSchema
+	require.Equal(t, "letters", rs.Name)
+	require.Equal(t, "Some letters", rs.Description)
+	require.Equal(t, []string{"value"}, rs.Schema.Required)
+}
@@ -3,6 +3,8 @@ package googleai
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,6 +13,7 @@ import (
 
 	"github.com/google/generative-ai-go/genai"
 	"github.com/invopop/jsonschema"
+	"github.com/tmc/langchaingo/callbacks"
 	"github.com/tmc/langchaingo/internal/imageutil"
 	"github.com/tmc/langchaingo/llms"
 	"google.golang.org/api/iterator"
@@ -43,10 +46,6 @@ func (g *GoogleAI) GenerateContent(
 	messages []llms.MessageContent,
 	options ...llms.CallOption,
 ) (*llms.ContentResponse, error) {
-	if g.CallbacksHandler != nil {
-		g.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
-	}
-
 	opts := llms.CallOptions{
 		Model:          g.opts.DefaultModel,
 		CandidateCount: g.opts.DefaultCandidateCount,
@@ -59,6 +58,27 @@ func (g *GoogleAI) GenerateContent(
 		opt(&opts)
 	}
 
+	ctx = callbacks.StartRun(ctx, callbacks.RunLLM, opts.Model)
+	if g.CallbacksHandler != nil {
+		g.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
+	}
+
+	response, err := g.generateContent(ctx, messages, opts)
+	if g.CallbacksHandler != nil {
+		if err != nil {
+			g.CallbacksHandler.HandleLLMError(ctx, err)
+		} else {
+			g.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, response)
+		}
+	}
+	return response, err
+}
+
+func (g *GoogleAI) generateContent(
+	ctx context.Context,
+	messages []llms.MessageContent,
+	opts llms.CallOptions,
+) (*llms.ContentResponse, error) {
 	model := g.client.GenerativeModel(opts.Model)
 	model.SetCandidateCount(int32(opts.CandidateCount))
 	model.SetMaxOutputTokens(int32(opts.MaxTokens))
@@ -99,6 +119,14 @@ func (g *GoogleAI) GenerateContent(
 		model.ResponseMIMEType = ResponseMIMETypeJson
 	}
 
+	if opts.ResponseSchema != nil {
+		if model.ResponseMIMEType != "" && model.ResponseMIMEType != ResponseMIMETypeJson {
+			return nil, fmt.Errorf("conflicting options, can't use ResponseSchema and ResponseMIMEType %q together", model.ResponseMIMEType)
+		}
+		model.ResponseMIMEType = ResponseMIMETypeJson
+		model.ResponseSchema = convertResponseSchema(opts.ResponseSchema.Schema)
+	}
+
 	var response *llms.ContentResponse
 
 	if len(messages) == 1 {
@@ -113,21 +141,22 @@ func (g *GoogleAI) GenerateContent(
 	if err != nil {
 		return nil, err
 	}
-
-	if g.CallbacksHandler != nil {
-		g.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, response)
-	}
-
 	return response, nil
 }
 
+// SupportsResponseSchema implements llms.ResponseSchemaSupporter: response
+// schemas are converted to the response schema of the model.
+func (g *GoogleAI) SupportsResponseSchema() bool {
+	return true
+}
+
 // convertCandidates converts a sequence of genai.Candidate to a response.
 func convertCandidates(candidates []*genai.Candidate, usage *genai.UsageMetadata) (*llms.ContentResponse, error) {
 	var contentResponse llms.ContentResponse
-	var toolCalls []llms.ToolCall
 
 	for _, candidate := range candidates {
 		buf := strings.Builder{}
+		var toolCalls []llms.ToolCall
 
 		if candidate.Content != nil {
 			for _, part := range candidate.Content.Parts {
@@ -143,6 +172,8 @@ func convertCandidates(candidates []*genai.Candidate, usage *genai.UsageMetadata
 						return nil, err
 					}
 					toolCall := llms.ToolCall{
+						ID:   newToolCallID(),
+						Type: "function",
 						FunctionCall: &llms.FunctionCall{
 							Name:      v.Name,
 							Arguments: string(b),
@@ -165,13 +196,24 @@ func convertCandidates(candidates []*genai.Candidate, usage *genai.UsageMetadata
 			metadata["total_tokens"] = usage.TotalTokenCount
 		}
 
-		contentResponse.Choices = append(contentResponse.Choices,
-			&llms.ContentChoice{
-				Content:        buf.String(),
-				StopReason:     candidate.FinishReason.String(),
-				GenerationInfo: metadata,
-				ToolCalls:      toolCalls,
-			})
+		choice := &llms.ContentChoice{
+			Content:        buf.String(),
+			StopReason:     candidate.FinishReason.String(),
+			GenerationInfo: metadata,
+			ToolCalls:      toolCalls,
+		}
+		if len(toolCalls) > 0 {
+			choice.FuncCall = toolCalls[0].FunctionCall
+		}
+		contentResponse.Choices = append(contentResponse.Choices, choice)
+	}
+
+	if usage != nil {
+		contentResponse.Usage = &llms.Usage{
+			PromptTokens:     int(usage.PromptTokenCount),
+			CompletionTokens: int(usage.CandidatesTokenCount),
+			TotalTokens:      int(usage.TotalTokenCount),
+		}
 	}
 	return &contentResponse, nil
 }
@@ -261,7 +303,7 @@ func generateFromSingleMessage(
 		return nil, err
 	}
 
-	if opts.StreamingFunc == nil {
+	if opts.StreamingFunc == nil && opts.StreamingEventFunc == nil {
 		// When no streaming is requested, just call GenerateContent and return
 		// the complete response with a list of candidates.
 		resp, err := model.GenerateContent(ctx, convertedParts...)
@@ -285,7 +327,7 @@ func generateFromMessages(
 	opts *llms.CallOptions,
 ) (*llms.ContentResponse, error) {
 	history := make([]*genai.Content, 0, len(messages))
-	for _, mc := range messages {
+	for i, mc := range messages {
 		content, err := convertContent(mc)
 		if err != nil {
 			return nil, err
@@ -294,6 +336,13 @@ func generateFromMessages(
 			model.SystemInstruction = content
 			continue
 		}
+		// The responses of parallel function calls, e.g. in a tool message
+		// each, must be in a single content.
+		if i > 0 && mc.Role == llms.ChatMessageTypeTool && messages[i-1].Role == llms.ChatMessageTypeTool {
+			last := history[len(history)-1]
+			last.Parts = append(last.Parts, content.Parts...)
+			continue
+		}
 		history = append(history, content)
 	}
 
@@ -306,7 +355,7 @@ func generateFromMessages(
 	session := model.StartChat()
 	session.History = history
 
-	if opts.StreamingFunc == nil {
+	if opts.StreamingFunc == nil && opts.StreamingEventFunc == nil {
 		resp, err := session.SendMessage(ctx, reqContent.Parts...)
 		if err != nil {
 			return nil, err
@@ -334,6 +383,10 @@ func convertAndStreamFromIterator(
 	candidate := &genai.Candidate{
 		Content: &genai.Content{},
 	}
+	var toolCallIDs []string
+	// The merged response keeps the usage of the first chunk, but the usage
+	// is only complete in the last one.
+	var usage *genai.UsageMetadata
 DoStream:
 	for {
 		resp, err := iter.Next()
@@ -348,16 +401,36 @@ DoStream:
 			return nil, fmt.Errorf("expect single candidate in stream mode; got %v", len(resp.Candidates))
 		}
 		respCandidate := resp.Candidates[0]
-
-		if respCandidate.Content == nil {
-			break DoStream
+		if resp.UsageMetadata != nil {
+			usage = resp.UsageMetadata
 		}
-		candidate.Content.Parts = append(candidate.Content.Parts, respCandidate.Content.Parts...)
-		candidate.Content.Role = respCandidate.Content.Role
+
 		candidate.FinishReason = respCandidate.FinishReason
 		candidate.SafetyRatings = respCandidate.SafetyRatings
 		candidate.CitationMetadata = respCandidate.CitationMetadata
 		candidate.TokenCount += respCandidate.TokenCount
+		// The last chunk may only have the finish reason of the response.
+		if respCandidate.Content == nil {
+			continue
+		}
+		candidate.Content.Parts = append(candidate.Content.Parts, respCandidate.Content.Parts...)
+		candidate.Content.Role = respCandidate.Content.Role
+
+		if opts.StreamingEventFunc != nil {
+			events, err := streamEvents(respCandidate.Content.Parts, len(toolCallIDs))
+			if err != nil {
+				return nil, err
+			}
+			for _, event := range events {
+				if event.Type == llms.StreamEventToolCall {
+					toolCallIDs = append(toolCallIDs, event.ToolCall.ID)
+				}
+				if err := opts.StreamingEventFunc(ctx, event); err != nil {
+					return nil, fmt.Errorf("streaming event func returned an error: %w", err)
+				}
+			}
+			continue
+		}
 
 		for _, part := range respCandidate.Content.Parts {
 			if text, ok := part.(genai.Text); ok {
@@ -367,8 +440,52 @@ DoStream:
 			}
 		}
 	}
-	mresp := iter.MergedResponse()
-	return convertCandidates([]*genai.Candidate{candidate}, mresp.UsageMetadata)
+	resp, err := convertCandidates([]*genai.Candidate{candidate}, usage)
+	if err != nil {
+		return nil, err
+	}
+	// The tool calls keep the IDs of their stream events.
+	for i, id := range toolCallIDs {
+		resp.Choices[0].ToolCalls[i].ID = id
+	}
+	return resp, nil
+}
+
+// streamEvents returns the events of the parts of a streamed response, given
+// the number of tool calls of the previous parts. Function calls are not
+// split across parts, so each of them is a single delta.
+func streamEvents(parts []genai.Part, numToolCalls int) ([]llms.StreamEvent, error) {
+	events := make([]llms.StreamEvent, 0, len(parts))
+	for _, part := range parts {
+		switch v := part.(type) {
+		case genai.Text:
+			events = append(events, llms.StreamEvent{Type: llms.StreamEventText, Text: string(v)})
+		case genai.FunctionCall:
+			b, err := json.Marshal(v.Args)
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, llms.StreamEvent{
+				Type: llms.StreamEventToolCall,
+				ToolCall: &llms.ToolCallDelta{
+					Index:     numToolCalls,
+					ID:        newToolCallID(),
+					Name:      v.Name,
+					Arguments: string(b),
+				},
+			})
+			numToolCalls++
+		}
+	}
+	return events, nil
+}
+
+// newToolCallID returns an ID for a function call, as Gemini doesn't identify
+// them.
+func newToolCallID() string {
+	var b [12]byte
+	_, _ = rand.Read(b[:])
+	return "call_" + hex.EncodeToString(b[:])
 }
 
 // convertTools converts from a list of langchaingo tools to a list of genai
@@ -0,0 +1,81 @@
+package googleai
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"google.golang.org/api/option"
+)
+
+// newFixtureServer serves the recorded stream of a streamGenerateContent call.
+func newFixtureServer(t *testing.T, fixture string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.True(t, strings.HasSuffix(r.URL.Path, ":streamGenerateContent"), r.URL.Path)
+		f, err := os.Open(fixture)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer f.Close()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.Copy(w, f)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGenerateContentStreamingToolCalls(t *testing.T) {
+	t.Parallel()
+
+	server := newFixtureServer(t, "testdata/stream_tool_calls.json")
+	llm, err := New(context.Background(), WithAPIKey("test"), func(o *Options) {
+		o.ClientOptions = append(o.ClientOptions, option.WithEndpoint(server.URL))
+	})
+	require.NoError(t, err)
+
+	var events []llms.StreamEvent
+	resp, err := llm.GenerateContent(context.Background(),
+		[]llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "Weather in Paris and Rome?")},
+		llms.WithTools([]llms.Tool{{Type: "function", Function: &llms.FunctionDefinition{
+			Name:       "weather",
+			Parameters: map[string]any{"type": "object", "properties": map[string]any{"city": map[string]any{"type": "string"}}},
+		}}}),
+		llms.WithStreamingEventFunc(func(_ context.Context, event llms.StreamEvent) error {
+			events = append(events, event)
+			return nil
+		}))
+	require.NoError(t, err)
+
+	require.Len(t, resp.Choices, 1)
+	choice := resp.Choices[0]
+	// Gemini doesn't identify function calls, so their IDs are random.
+	require.Len(t, choice.ToolCalls, 2)
+	paris, rome := choice.ToolCalls[0].ID, choice.ToolCalls[1].ID
+	assert.NotEmpty(t, paris)
+	assert.NotEqual(t, paris, rome)
+
+	assert.Equal(t, []llms.StreamEvent{
+		{Type: llms.StreamEventText, Text: "Let me check "},
+		{Type: llms.StreamEventText, Text: "both cities."},
+		{Type: llms.StreamEventToolCall, ToolCall: &llms.ToolCallDelta{Index: 0, ID: paris, Name: "weather", Arguments: `{"city":"Paris"}`}},
+		{Type: llms.StreamEventToolCall, ToolCall: &llms.ToolCallDelta{Index: 1, ID: rome, Name: "weather", Arguments: `{"city":"Rome"}`}},
+	}, events)
+
+	assert.Equal(t, "Let me check both cities.", choice.Content)
+	assert.Equal(t, "FinishReasonStop", choice.StopReason)
+	assert.Equal(t, []llms.ToolCall{
+		{ID: paris, Type: "function", FunctionCall: &llms.FunctionCall{Name: "weather", Arguments: `{"city":"Paris"}`}},
+		{ID: rome, Type: "function", FunctionCall: &llms.FunctionCall{Name: "weather", Arguments: `{"city":"Rome"}`}},
+	}, choice.ToolCalls)
+	assert.Equal(t, choice.ToolCalls[0].FunctionCall, choice.FuncCall)
+	assert.Equal(t, &llms.Usage{PromptTokens: 42, CompletionTokens: 18, TotalTokens: 60}, resp.Usage)
+}
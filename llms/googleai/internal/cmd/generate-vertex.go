@@ -1,8 +1,11 @@
-// Code generator for vertex.go from googleai.go
+// Code generator for vertex.go from googleai.go, and for the other files of
+// the vertex package shared with googleai, e.g. response_schema.go with
+// -src response_schema.go.
 // nolint
 package main
 
 import (
+	"flag"
 	"fmt"
 	"go/ast"
 	"go/format"
@@ -15,7 +18,10 @@ import (
 	"golang.org/x/tools/go/ast/astutil"
 )
 
+var src = flag.String("src", "googleai.go", "name of the googleai file read from stdin")
+
 func main() {
+	flag.Parse()
 	fset := token.NewFileSet()
 	file, err := parser.ParseFile(fset, "src.go", os.Stdin, parser.ParseComments)
 	if err != nil {
@@ -40,12 +46,12 @@ func main() {
 		return true
 	})
 
-	fmt.Println(strings.TrimLeft(preamble, "\r\n"))
+	fmt.Printf(strings.TrimLeft(preamble, "\r\n")+"\n", *src)
 	format.Node(os.Stdout, fset, file)
 }
 
 const preamble = `
-// DO NOT EDIT THIS FILE -- it is automatically generated from googleai.go
+// DO NOT EDIT THIS FILE -- it is automatically generated from %s
 // See the README file in this directory for additional details
 `
 
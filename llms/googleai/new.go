@@ -17,7 +17,10 @@ type GoogleAI struct {
 	opts             Options
 }
 
-var _ llms.Model = &GoogleAI{}
+var (
+	_ llms.Model                   = &GoogleAI{}
+	_ llms.ResponseSchemaSupporter = &GoogleAI{}
+)
 
 // New creates a new GoogleAI client.
 func New(ctx context.Context, opts ...Option) (*GoogleAI, error) {
@@ -0,0 +1,79 @@
+package googleai
+
+import (
+	"strings"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/tmc/langchaingo/jsonschema"
+)
+
+// maxResponseSchemaDepth bounds the depth of the references inlined by
+// convertResponseSchema, as Gemini schemas can't be recursive.
+const maxResponseSchemaDepth = 8
+
+// convertResponseSchema converts a jsonschema.Definition to the response
+// schema of a model. References are inlined, up to maxResponseSchemaDepth, and
+// keywords not supported by Gemini are dropped.
+func convertResponseSchema(def jsonschema.Definition) *genai.Schema {
+	return convertDefinition(def, def, 0)
+}
+
+func convertDefinition(root, def jsonschema.Definition, depth int) *genai.Schema {
+	if def.Ref != "" {
+		ref, ok := resolveRef(root, def.Ref)
+		if !ok || depth >= maxResponseSchemaDepth {
+			return &genai.Schema{Type: genai.TypeObject, Nullable: true, Description: def.Description}
+		}
+		nullable := def.Nullable
+		def = ref
+		def.Nullable = def.Nullable || nullable
+		depth++
+	}
+
+	// Gemini has no combinators: the first alternative is used.
+	if def.Type == "" {
+		switch {
+		case len(def.AnyOf) > 0:
+			return convertDefinition(root, def.AnyOf[0], depth)
+		case len(def.OneOf) > 0:
+			return convertDefinition(root, def.OneOf[0], depth)
+		}
+	}
+
+	schema := &genai.Schema{
+		Type:        convertToolSchemaType(string(def.Type)),
+		Description: def.Description,
+		Nullable:    def.Nullable,
+		Enum:        def.Enum,
+		Required:    def.Required,
+	}
+	// Gemini only supports the date-time and enum formats for strings.
+	switch {
+	case len(def.Enum) > 0:
+		schema.Format = "enum"
+	case def.Format == "date-time":
+		schema.Format = def.Format
+	}
+	if len(def.Properties) > 0 {
+		schema.Properties = make(map[string]*genai.Schema, len(def.Properties))
+		for name, prop := range def.Properties {
+			schema.Properties[name] = convertDefinition(root, prop, depth)
+		}
+	}
+	if def.Items != nil {
+		schema.Items = convertDefinition(root, *def.Items, depth)
+	}
+	return schema
+}
+
+func resolveRef(root jsonschema.Definition, ref string) (jsonschema.Definition, bool) {
+	if ref == "#" {
+		return root, true
+	}
+	name, ok := strings.CutPrefix(ref, "#/$defs/")
+	if !ok {
+		return jsonschema.Definition{}, false
+	}
+	def, ok := root.Defs[name]
+	return def, ok
+}
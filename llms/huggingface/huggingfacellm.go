@@ -28,18 +28,20 @@ func (o *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOptio
 	return llms.GenerateFromSinglePrompt(ctx, o, prompt, options...)
 }
 
-// GenerateContent implements the Model interface.
+// GenerateContent implements the Model interface. The Usage of the response is
+// only set by text generation servers, which report token counts.
 func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint: lll, cyclop, whitespace
 
-	if o.CallbacksHandler != nil {
-		o.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
-	}
-
 	opts := &llms.CallOptions{Model: defaultModel}
 	for _, opt := range options {
 		opt(opts)
 	}
 
+	ctx = callbacks.StartRun(ctx, callbacks.RunLLM, o.client.Model)
+	if o.CallbacksHandler != nil {
+		o.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
+	}
+
 	// Assume we get a single text message
 	msg0 := messages[0]
 	part := msg0.Parts[0]
@@ -69,6 +71,12 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 			},
 		},
 	}
+	if result.Usage != nil {
+		resp.Usage = llms.NewUsage(result.Usage.PromptTokens, result.Usage.CompletionTokens)
+	}
+	if o.CallbacksHandler != nil {
+		o.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, resp)
+	}
 	return resp, nil
 }
 
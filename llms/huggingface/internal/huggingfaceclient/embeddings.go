@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+
+	"github.com/tmc/langchaingo/llms"
 )
 
 type embeddingPayload struct {
@@ -40,9 +42,7 @@ func (c *Client) createEmbedding(ctx context.Context, model string, task string,
 	defer r.Body.Close()
 
 	if r.StatusCode != http.StatusOK {
-		msg := fmt.Sprintf("API returned unexpected status code: %d", r.StatusCode)
-
-		return nil, fmt.Errorf("%s: %s", msg, "unable to create embeddings") // nolint:goerr113
+		return nil, llms.NewAPIError(r, "unable to create embeddings")
 	}
 
 	var response [][]float32
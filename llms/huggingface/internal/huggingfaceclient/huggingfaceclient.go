@@ -43,6 +43,15 @@ type InferenceRequest struct {
 
 type InferenceResponse struct {
 	Text string `json:"generated_text"`
+	// Usage is the number of tokens of the prompt and of the generated text,
+	// nil if the server doesn't report them.
+	Usage *InferenceUsage `json:"usage,omitempty"`
+}
+
+// InferenceUsage is the token usage of an inference.
+type InferenceUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
 }
 
 func (c *Client) RunInference(ctx context.Context, request *InferenceRequest) (*InferenceResponse, error) {
@@ -57,6 +66,9 @@ func (c *Client) RunInference(ctx context.Context, request *InferenceRequest) (*
 			MaxLength:         request.MaxLength,
 			RepetitionPenalty: request.RepetitionPenalty,
 			Seed:              request.Seed,
+			// Only text generation servers report token counts.
+			Details:             request.Task == InferenceTaskTextGeneration,
+			DecoderInputDetails: request.Task == InferenceTaskTextGeneration,
 		},
 	}
 	resp, err := c.runInference(ctx, payload)
@@ -69,9 +81,16 @@ func (c *Client) RunInference(ctx context.Context, request *InferenceRequest) (*
 	text := resp[0].Text
 	// TODO: Add response cleaning based on Model.
 	// e.g., for gpt2, text = text[len(request.Prompt)+1:]
-	return &InferenceResponse{
+	response := &InferenceResponse{
 		Text: text,
-	}, nil
+	}
+	if details := resp[0].Details; details != nil {
+		response.Usage = &InferenceUsage{
+			PromptTokens:     len(details.Prefill),
+			CompletionTokens: details.GeneratedTokens,
+		}
+	}
+	return response, nil
 }
 
 // EmbeddingRequest is a request to create an embedding.
@@ -28,6 +28,10 @@ func TestRunInference(t *testing.T) {
 		wantErr  string
 	}{
 		{"ok", &InferenceRequest{}, &InferenceResponse{Text: goodResponse}, ""},
+		{"text generation", &InferenceRequest{Task: InferenceTaskTextGeneration}, &InferenceResponse{
+			Text:  goodResponse,
+			Usage: &InferenceUsage{PromptTokens: 3, CompletionTokens: 9},
+		}, ""},
 		{"not ok", &InferenceRequest{TopK: -1}, nil, errMsg},
 	}
 
@@ -63,6 +67,11 @@ func mockServer(t *testing.T) *httptest.Server {
 		if infReq.Parameters.TopK == -1 {
 			w.WriteHeader(http.StatusBadRequest)
 			_, _ = fmt.Fprintf(w, `{"error":["%s"]}`, errMsg)
+		} else if infReq.Parameters.Details && infReq.Parameters.DecoderInputDetails {
+			w.WriteHeader(http.StatusOK)
+			_, _ = fmt.Fprintf(w, `[{"generated_text":"%s","details":{"finish_reason":"eos_token","generated_tokens":9,"prefill":[`+
+				`{"id":1,"text":"<s>","logprob":null},{"id":22557,"text":"Hello","logprob":-9.2},{"id":28808,"text":"!","logprob":-3.1}]}}]`,
+				goodResponse)
 		} else {
 			w.WriteHeader(http.StatusOK)
 			_, _ = fmt.Fprintf(w, `[{"generated_text":"%s"}]`, goodResponse)
@@ -34,12 +34,22 @@ type parameters struct {
 	MaxLength         int     `json:"max_length,omitempty"`
 	RepetitionPenalty float64 `json:"repetition_penalty,omitempty"`
 	Seed              int     `json:"seed,omitempty"`
+	// Details asks text generation servers for the generated tokens, and
+	// DecoderInputDetails for the prompt tokens.
+	Details             bool `json:"details,omitempty"`
+	DecoderInputDetails bool `json:"decoder_input_details,omitempty"`
 }
 
 type (
 	inferenceResponsePayload []inferenceResponse
 	inferenceResponse        struct {
-		Text string `json:"generated_text"`
+		Text    string            `json:"generated_text"`
+		Details *inferenceDetails `json:"details,omitempty"`
+	}
+	inferenceDetails struct {
+		GeneratedTokens int `json:"generated_tokens"`
+		// Prefill holds a token of the prompt each.
+		Prefill []json.RawMessage `json:"prefill"`
 	}
 )
 
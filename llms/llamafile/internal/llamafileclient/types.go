@@ -5,6 +5,8 @@ import (
 	"net/http"
 	"net/url"
 	"time"
+
+	"github.com/tmc/langchaingo/llms"
 )
 
 type StatusError struct {
@@ -27,6 +29,12 @@ func (e StatusError) Error() string {
 	}
 }
 
+// Retryable reports whether the request that failed with this error may
+// succeed if retried.
+func (e StatusError) Retryable() bool {
+	return llms.IsRetryableStatus(e.StatusCode)
+}
+
 type GenerateRequest struct {
 	Prompt   string `json:"prompt"`
 	System   string `json:"system"`
@@ -45,16 +45,29 @@ func (o *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOptio
 
 // GenerateContent implements the Model interface.
 // nolint: goerr113
-func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { // nolint: lll, cyclop, funlen
+func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { // nolint: lll
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	ctx = callbacks.StartRun(ctx, callbacks.RunLLM, opts.Model)
 	if o.CallbacksHandler != nil {
 		o.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
 	}
 
-	opts := llms.CallOptions{}
-	for _, opt := range options {
-		opt(&opts)
+	resp, err := o.generateContent(ctx, messages, opts)
+	if o.CallbacksHandler != nil {
+		if err != nil {
+			o.CallbacksHandler.HandleLLMError(ctx, err)
+		} else {
+			o.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, resp)
+		}
 	}
+	return resp, err
+}
 
+func (o *LLM) generateContent(ctx context.Context, messages []llms.MessageContent, opts llms.CallOptions) (*llms.ContentResponse, error) { // nolint: lll, cyclop, funlen, goerr113
 	// Our input is a sequence of MessageContent, each of which potentially has
 	// a sequence of Part that could be text, images etc.
 	// We have to convert it to a format Ollama undestands: ChatRequest, which
@@ -98,6 +111,7 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 	req = makeLlamaOptionsFromOptions(req, opts)
 
 	streamedResponse := ""
+	var usage *llms.Usage
 	fn := func(response llamafileclient.ChatResponse) error {
 		if opts.StreamingFunc != nil && response.Content != "" {
 			if err := opts.StreamingFunc(ctx, []byte(response.Content)); err != nil {
@@ -107,15 +121,17 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 		if response.Content != "" {
 			streamedResponse += response.Content
 		}
+		// token counts are only reported with the final response.
+		if response.Stop {
+			usage = llms.NewUsage(response.TokensEvaluated, response.TokensPredicted)
+			usage.CachedTokens = response.TokensCached
+		}
 
 		return nil
 	}
 
 	err := o.client.GenerateChat(ctx, req, fn)
 	if err != nil {
-		if o.CallbacksHandler != nil {
-			o.CallbacksHandler.HandleLLMError(ctx, err)
-		}
 		return nil, err
 	}
 
@@ -125,6 +141,7 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 				Content: streamedResponse,
 			},
 		},
+		Usage: usage,
 	}, nil
 }
 
@@ -57,18 +57,20 @@ func (o *LLM) appendGlobalsToArgs(opts llms.CallOptions) {
 	}
 }
 
-// GenerateContent implements the Model interface.
+// GenerateContent implements the Model interface. The binary only outputs the
+// completion, so the Usage of the response is always nil.
 func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint: lll, cyclop, whitespace
 
-	if o.CallbacksHandler != nil {
-		o.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
-	}
-
 	opts := &llms.CallOptions{}
 	for _, opt := range options {
 		opt(opts)
 	}
 
+	ctx = callbacks.StartRun(ctx, callbacks.RunLLM, opts.Model)
+	if o.CallbacksHandler != nil {
+		o.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
+	}
+
 	// If o.client.GlobalAsArgs is true
 	if o.client.GlobalAsArgs {
 		// Then add the option to the args in --key=value format
@@ -82,6 +84,9 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 		Prompt: part.(llms.TextContent).Text,
 	})
 	if err != nil {
+		if o.CallbacksHandler != nil {
+			o.CallbacksHandler.HandleLLMError(ctx, err)
+		}
 		return nil, err
 	}
 
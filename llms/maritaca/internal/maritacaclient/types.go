@@ -2,6 +2,8 @@ package maritacaclient
 
 import (
 	"fmt"
+
+	"github.com/tmc/langchaingo/llms"
 )
 
 type StatusError struct {
@@ -24,6 +26,12 @@ func (e StatusError) Error() string {
 	}
 }
 
+// Retryable reports whether the request that failed with this error may
+// succeed if retried.
+func (e StatusError) Retryable() bool {
+	return llms.IsRetryableStatus(e.StatusCode)
+}
+
 type Message struct {
 	Role    string `json:"role"` // one of ["system", "user", "assistant"]
 	Content string `json:"content"`
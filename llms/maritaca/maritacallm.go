@@ -50,11 +50,7 @@ func (o *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOptio
 
 // GenerateContent implements the Model interface.
 // nolint: goerr113
-func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { // nolint: lll, cyclop, funlen
-	if o.CallbacksHandler != nil {
-		o.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
-	}
-
+func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { // nolint: lll
 	opts := llms.CallOptions{}
 	for _, opt := range options {
 		opt(&opts)
@@ -66,6 +62,23 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 		model = opts.Model
 	}
 
+	ctx = callbacks.StartRun(ctx, callbacks.RunLLM, model)
+	if o.CallbacksHandler != nil {
+		o.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
+	}
+
+	resp, err := o.generateContent(ctx, model, messages, opts)
+	if o.CallbacksHandler != nil {
+		if err != nil {
+			o.CallbacksHandler.HandleLLMError(ctx, err)
+		} else {
+			o.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, resp)
+		}
+	}
+	return resp, err
+}
+
+func (o *LLM) generateContent(ctx context.Context, model string, messages []llms.MessageContent, opts llms.CallOptions) (*llms.ContentResponse, error) { // nolint: lll, cyclop, funlen, goerr113
 	// Our input is a sequence of MessageContent, each of which potentially has
 	// a sequence of Part that could be text, images etc.
 	// We have to convert it to a format maritaca undestands: ChatRequest, which
@@ -129,6 +142,7 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 			streamedResponse += response.Text
 		case "end":
 			resp.Answer = streamedResponse
+			resp.Metrics = response.Metrics
 		case "nostream":
 			resp = response
 		}
@@ -138,18 +152,18 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 	o.client.Token = o.options.maritacaOptions.Token
 	err := o.client.Generate(ctx, req, fn)
 	if err != nil {
-		if o.CallbacksHandler != nil {
-			o.CallbacksHandler.HandleLLMError(ctx, err)
-		}
 		return nil, err
 	}
 
 	choices := createChoice(resp)
 
-	response := &llms.ContentResponse{Choices: choices}
-
-	if o.CallbacksHandler != nil {
-		o.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, response)
+	response := &llms.ContentResponse{
+		Choices: choices,
+		Usage: &llms.Usage{
+			PromptTokens:     resp.Usage.PromptTokens,
+			CompletionTokens: resp.Usage.CompletionTokens,
+			TotalTokens:      resp.Usage.TotalTokens,
+		},
 	}
 
 	return response, nil
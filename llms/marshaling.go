@@ -9,7 +9,8 @@ import (
 func (mc MessageContent) MarshalJSON() ([]byte, error) {
 	hasSingleTextPart := false
 	if len(mc.Parts) == 1 {
-		_, hasSingleTextPart = mc.Parts[0].(TextContent)
+		tp, ok := mc.Parts[0].(TextContent)
+		hasSingleTextPart = ok && tp.CacheControl == nil
 	}
 	if hasSingleTextPart {
 		tp, _ := mc.Parts[0].(TextContent)
@@ -54,6 +55,12 @@ func (mc *MessageContent) UnmarshalJSON(data []byte) error {
 				Name       string `json:"name"`
 				Content    string `json:"content"`
 			} `json:"tool_response"`
+			Thinking struct {
+				Thinking     string `json:"thinking"`
+				Signature    string `json:"signature"`
+				RedactedData string `json:"redacted_data"`
+			} `json:"thinking"`
+			CacheControl *CacheControl `json:"cache_control"`
 		} `json:"parts"`
 	}
 	if err := json.Unmarshal(data, &m); err != nil {
@@ -64,7 +71,7 @@ func (mc *MessageContent) UnmarshalJSON(data []byte) error {
 	for _, part := range m.Parts {
 		switch part.Type {
 		case "text", "":
-			mc.Parts = append(mc.Parts, TextContent{Text: part.Text})
+			mc.Parts = append(mc.Parts, TextContent{Text: part.Text, CacheControl: part.CacheControl})
 		case "image_url":
 			mc.Parts = append(mc.Parts, ImageURLContent{
 				URL:    part.ImageURL.URL,
@@ -75,18 +82,30 @@ func (mc *MessageContent) UnmarshalJSON(data []byte) error {
 			if err != nil {
 				return fmt.Errorf("failed to decode binary data: %w", err)
 			}
-			mc.Parts = append(mc.Parts, BinaryContent{MIMEType: part.Binary.MIMEType, Data: decoded})
+			mc.Parts = append(mc.Parts, BinaryContent{
+				MIMEType:     part.Binary.MIMEType,
+				Data:         decoded,
+				CacheControl: part.CacheControl,
+			})
 		case "tool_call":
 			mc.Parts = append(mc.Parts, ToolCall{
 				ID:           part.ToolCall.ID,
 				Type:         part.ToolCall.Type,
 				FunctionCall: part.ToolCall.FunctionCall,
+				CacheControl: part.CacheControl,
 			})
 		case "tool_response":
 			mc.Parts = append(mc.Parts, ToolCallResponse{
-				ToolCallID: part.ToolResponse.ToolCallID,
-				Name:       part.ToolResponse.Name,
-				Content:    part.ToolResponse.Content,
+				ToolCallID:   part.ToolResponse.ToolCallID,
+				Name:         part.ToolResponse.Name,
+				Content:      part.ToolResponse.Content,
+				CacheControl: part.CacheControl,
+			})
+		case "thinking":
+			mc.Parts = append(mc.Parts, ThinkingContent{
+				Thinking:     part.Thinking.Thinking,
+				Signature:    part.Thinking.Signature,
+				RedactedData: part.Thinking.RedactedData,
 			})
 		default:
 			return fmt.Errorf("unknown content type: '%s'", part.Type)
@@ -100,22 +119,30 @@ func (mc *MessageContent) UnmarshalJSON(data []byte) error {
 }
 
 func (tc TextContent) MarshalJSON() ([]byte, error) {
-	m := map[string]string{
+	m := map[string]any{
 		"type": "text",
 		"text": tc.Text,
 	}
+	if tc.CacheControl != nil {
+		m["cache_control"] = tc.CacheControl
+	}
 	return json.Marshal(m)
 }
 
 func (tc *TextContent) UnmarshalJSON(data []byte) error {
-	var m map[string]string
+	var m struct {
+		Type         string        `json:"type"`
+		Text         string        `json:"text"`
+		CacheControl *CacheControl `json:"cache_control"`
+	}
 	if err := json.Unmarshal(data, &m); err != nil {
 		return err
 	}
-	if m["type"] != "text" {
-		return fmt.Errorf("invalid type for TextContent: %v", m["type"])
+	if m.Type != "text" {
+		return fmt.Errorf("invalid type for TextContent: %v", m.Type)
 	}
-	tc.Text = m["text"]
+	tc.Text = m.Text
+	tc.CacheControl = m.CacheControl
 	return nil
 }
 
@@ -162,14 +189,16 @@ func (iuc *ImageURLContent) UnmarshalJSON(data []byte) error {
 
 func (bc BinaryContent) MarshalJSON() ([]byte, error) {
 	m := struct {
-		Type   string            `json:"type"`
-		Binary map[string]string `json:"binary"`
+		Type         string            `json:"type"`
+		Binary       map[string]string `json:"binary"`
+		CacheControl *CacheControl     `json:"cache_control,omitempty"`
 	}{
 		Type: "binary",
 		Binary: map[string]string{
 			"mime_type": bc.MIMEType,
 			"data":      base64.StdEncoding.EncodeToString(bc.Data),
 		},
+		CacheControl: bc.CacheControl,
 	}
 	return json.Marshal(m)
 }
@@ -198,8 +227,13 @@ func (bc *BinaryContent) UnmarshalJSON(data []byte) error {
 	if err != nil {
 		return fmt.Errorf("error decoding base64 data: %w", err)
 	}
+	var cc cacheControlField
+	if err := json.Unmarshal(data, &cc); err != nil {
+		return err
+	}
 	bc.MIMEType = mimeType
 	bc.Data = enc
+	bc.CacheControl = cc.CacheControl
 	return nil
 }
 
@@ -209,8 +243,9 @@ func (tc ToolCall) MarshalJSON() ([]byte, error) {
 		return nil, err
 	}
 	m := struct {
-		Type     string         `json:"type"`
-		ToolCall map[string]any `json:"tool_call"`
+		Type         string         `json:"type"`
+		ToolCall     map[string]any `json:"tool_call"`
+		CacheControl *CacheControl  `json:"cache_control,omitempty"`
 	}{
 		Type: "tool_call",
 		ToolCall: map[string]any{
@@ -218,6 +253,7 @@ func (tc ToolCall) MarshalJSON() ([]byte, error) {
 			"type":     tc.Type,
 			"function": json.RawMessage(fc),
 		},
+		CacheControl: tc.CacheControl,
 	}
 	return json.Marshal(m)
 }
@@ -244,15 +280,23 @@ func (tc *ToolCall) UnmarshalJSON(data []byte) error {
 		return fmt.Errorf("invalid type field in ToolCall")
 	}
 	var fc FunctionCall
-	fcData, ok := toolCall["function"].(json.RawMessage)
-	if ok {
+	if function, ok := toolCall["function"]; ok && function != nil {
+		fcData, err := json.Marshal(function)
+		if err != nil {
+			return fmt.Errorf("error marshalling function call: %w", err)
+		}
 		if err := json.Unmarshal(fcData, &fc); err != nil {
 			return fmt.Errorf("error unmarshalling function call: %w", err)
 		}
 	}
+	var cc cacheControlField
+	if err := json.Unmarshal(data, &cc); err != nil {
+		return err
+	}
 	tc.ID = id
 	tc.Type = typ
 	tc.FunctionCall = &fc
+	tc.CacheControl = cc.CacheControl
 	return nil
 }
 
@@ -260,6 +304,7 @@ func (tc ToolCallResponse) MarshalJSON() ([]byte, error) {
 	m := struct {
 		Type         string            `json:"type"`
 		ToolResponse map[string]string `json:"tool_response"`
+		CacheControl *CacheControl     `json:"cache_control,omitempty"`
 	}{
 		Type: "tool_response",
 		ToolResponse: map[string]string{
@@ -267,6 +312,7 @@ func (tc ToolCallResponse) MarshalJSON() ([]byte, error) {
 			"name":         tc.Name,
 			"content":      tc.Content,
 		},
+		CacheControl: tc.CacheControl,
 	}
 	return json.Marshal(m)
 }
@@ -295,8 +341,59 @@ func (tc *ToolCallResponse) UnmarshalJSON(data []byte) error {
 	if !ok {
 		return fmt.Errorf("invalid content field in ToolCallResponse")
 	}
+	var cc cacheControlField
+	if err := json.Unmarshal(data, &cc); err != nil {
+		return err
+	}
 	tc.ToolCallID = toolCallID
 	tc.Name = name
 	tc.Content = content
+	tc.CacheControl = cc.CacheControl
+	return nil
+}
+
+// cacheControlField is the cache control of a marshaled part.
+type cacheControlField struct {
+	CacheControl *CacheControl `json:"cache_control"`
+}
+
+func (tc ThinkingContent) MarshalJSON() ([]byte, error) {
+	m := struct {
+		Type     string            `json:"type"`
+		Thinking map[string]string `json:"thinking"`
+	}{
+		Type: "thinking",
+		Thinking: map[string]string{
+			"thinking":  tc.Thinking,
+			"signature": tc.Signature,
+		},
+	}
+	if tc.RedactedData != "" {
+		m.Thinking["redacted_data"] = tc.RedactedData
+	}
+	return json.Marshal(m)
+}
+
+func (tc *ThinkingContent) UnmarshalJSON(data []byte) error {
+	var m struct {
+		Type     string `json:"type"`
+		Thinking *struct {
+			Thinking     string `json:"thinking"`
+			Signature    string `json:"signature"`
+			RedactedData string `json:"redacted_data"`
+		} `json:"thinking"`
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	if m.Type != "thinking" {
+		return fmt.Errorf("invalid type for ThinkingContent: %v", m.Type)
+	}
+	if m.Thinking == nil {
+		return fmt.Errorf("invalid thinking field in ThinkingContent")
+	}
+	tc.Thinking = m.Thinking.Thinking
+	tc.Signature = m.Thinking.Signature
+	tc.RedactedData = m.Thinking.RedactedData
 	return nil
 }
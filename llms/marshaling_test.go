@@ -429,6 +429,57 @@ role: user
 role: assistant
 `,
 		},
+		{
+			name: "cache breakpoints",
+			in: MessageContent{
+				Role: "user",
+				Parts: []ContentPart{
+					TextContent{Text: "Long document.", CacheControl: &CacheControl{Type: "ephemeral"}},
+					BinaryContent{
+						MIMEType:     "image/png",
+						Data:         []byte("Hello, world!"),
+						CacheControl: &CacheControl{Type: "ephemeral", TTL: "1h"},
+					},
+					ToolCallResponse{ToolCallID: "123", Name: "hammer", Content: "hit", CacheControl: &CacheControl{Type: "ephemeral"}},
+				},
+			},
+			assertedJSON: `{"role":"user","parts":[{"cache_control":{"type":"ephemeral"},"text":"Long document.","type":"text"},{"type":"binary","binary":{"data":"SGVsbG8sIHdvcmxkIQ==","mime_type":"image/png"},"cache_control":{"type":"ephemeral","ttl":"1h"}},{"type":"tool_response","tool_response":{"content":"hit","name":"hammer","tool_call_id":"123"},"cache_control":{"type":"ephemeral"}}]}`,
+		},
+		{
+			name: "single text cache breakpoint",
+			in: MessageContent{
+				Role: "system",
+				Parts: []ContentPart{
+					TextContent{Text: "You are helpful.", CacheControl: &CacheControl{Type: "ephemeral"}},
+				},
+			},
+			assertedJSON: `{"role":"system","parts":[{"cache_control":{"type":"ephemeral"},"text":"You are helpful.","type":"text"}]}`,
+		},
+		{
+			name: "tool use cache breakpoint",
+			in: MessageContent{
+				Role: "assistant",
+				Parts: []ContentPart{
+					ToolCall{
+						Type: "function", ID: "tc01",
+						FunctionCall: &FunctionCall{Name: "get_current_weather", Arguments: `{ "location": "New York" }`},
+						CacheControl: &CacheControl{Type: "ephemeral"},
+					},
+				},
+			},
+		},
+		{
+			name: "thinking before tool use",
+			in: MessageContent{
+				Role: "assistant",
+				Parts: []ContentPart{
+					ThinkingContent{Thinking: "The weather is needed.", Signature: "sig"},
+					ThinkingContent{RedactedData: "encrypted"},
+					ToolCall{Type: "function", ID: "tc01", FunctionCall: &FunctionCall{Name: "get_current_weather", Arguments: `{ "location": "New York" }`}},
+				},
+			},
+			assertedJSON: `{"role":"assistant","parts":[{"type":"thinking","thinking":{"signature":"sig","thinking":"The weather is needed."}},{"type":"thinking","thinking":{"redacted_data":"encrypted","signature":"","thinking":""}},{"type":"tool_call","tool_call":{"function":{"name":"get_current_weather","arguments":"{ \"location\": \"New York\" }"},"id":"tc01","type":"function"}}]}`,
+		},
 		{
 			name: "tool use with arguments",
 			in: MessageContent{
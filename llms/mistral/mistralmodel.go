@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"os"
+	"strings"
 
 	sdk "github.com/gage-technologies/mistral-go"
 	"github.com/tmc/langchaingo/callbacks"
@@ -69,8 +70,19 @@ func (m *Model) Call(ctx context.Context, prompt string, options ...llms.CallOpt
 func (m *Model) GenerateContent(ctx context.Context, langchainMessages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
 	callOptions := resolveDefaultOptions(sdk.DefaultChatRequestParams, m.clientOptions)
 	setCallOptions(options, callOptions)
+	ctx = callbacks.StartRun(ctx, callbacks.RunLLM, callOptions.Model)
 	m.CallbacksHandler.HandleLLMGenerateContentStart(ctx, langchainMessages)
 
+	resp, err := generateContent(ctx, m, callOptions, langchainMessages)
+	if err != nil {
+		m.CallbacksHandler.HandleLLMError(ctx, err)
+		return resp, err
+	}
+	m.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, resp)
+	return resp, nil
+}
+
+func generateContent(ctx context.Context, m *Model, callOptions *llms.CallOptions, langchainMessages []llms.MessageContent) (*llms.ContentResponse, error) {
 	chatOpts := mistralChatParamsFromCallOptions(callOptions)
 
 	messages, err := convertToMistralChatMessages(langchainMessages)
@@ -78,10 +90,10 @@ func (m *Model) GenerateContent(ctx context.Context, langchainMessages []llms.Me
 		return nil, err
 	}
 
-	if callOptions.StreamingFunc != nil {
+	if callOptions.StreamingFunc != nil || callOptions.StreamingEventFunc != nil {
 		return generateStreamingContent(ctx, m, callOptions, messages, chatOpts)
 	}
-	return generateNonStreamingContent(ctx, m, callOptions, messages, chatOpts)
+	return generateNonStreamingContent(m, callOptions, messages, chatOpts)
 }
 
 func setCallOptions(options []llms.CallOption, callOpts *llms.CallOptions) {
@@ -143,21 +155,19 @@ func mistralChatParamsFromCallOptions(callOpts *llms.CallOptions) sdk.ChatReques
 	return chatOpts
 }
 
-func generateNonStreamingContent(ctx context.Context, m *Model, callOptions *llms.CallOptions, messages []sdk.ChatMessage, chatOpts sdk.ChatRequestParams) (*llms.ContentResponse, error) {
+func generateNonStreamingContent(m *Model, callOptions *llms.CallOptions, messages []sdk.ChatMessage, chatOpts sdk.ChatRequestParams) (*llms.ContentResponse, error) {
 	res, err := m.client.Chat(callOptions.Model, messages, &chatOpts)
-	m.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, nil)
 	if err != nil {
-		m.CallbacksHandler.HandleLLMError(ctx, err)
 		return nil, err
 	}
 
 	if len(res.Choices) < 1 {
-		m.CallbacksHandler.HandleLLMError(ctx, err)
 		return nil, errors.New("unexpected response from Mistral SDK, length of the Choices slice must be greater than or equal 1")
 	}
 
 	langchainContentResponse := &llms.ContentResponse{
 		Choices: make([]*llms.ContentChoice, 0),
+		Usage:   usageFromMistral(res.Usage),
 	}
 	for idx, choice := range res.Choices {
 		langchainContentResponse.Choices = append(langchainContentResponse.Choices, &llms.ContentChoice{
@@ -184,15 +194,12 @@ func generateNonStreamingContent(ctx context.Context, m *Model, callOptions *llm
 			}
 		}
 	}
-	m.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, langchainContentResponse)
-
 	return langchainContentResponse, nil
 }
 
 func generateStreamingContent(ctx context.Context, m *Model, callOptions *llms.CallOptions, messages []sdk.ChatMessage, chatOpts sdk.ChatRequestParams) (*llms.ContentResponse, error) {
 	chatResChan, err := m.client.ChatStream(callOptions.Model, messages, &chatOpts)
 	if err != nil {
-		m.CallbacksHandler.HandleLLMError(ctx, err)
 		return nil, err
 	}
 	langchainContentResponse := &llms.ContentResponse{
@@ -208,14 +215,32 @@ func generateStreamingContent(ctx context.Context, m *Model, callOptions *llms.C
 		langchainContentResponse.Choices[0].GenerationInfo["created"] = chatResChunk.Created
 		langchainContentResponse.Choices[0].GenerationInfo["model"] = chatResChunk.Model
 		langchainContentResponse.Choices[0].GenerationInfo["usage"] = chatResChunk.Usage
+		if chatResChunk.Usage.TotalTokens > 0 {
+			langchainContentResponse.Usage = usageFromMistral(chatResChunk.Usage)
+		}
 		if chatResChunk.Error == nil {
+			var events []llms.StreamEvent
 			for _, choice := range chatResChunk.Choices {
 				chunkStr += choice.Delta.Content
+				if choice.Delta.Content != "" {
+					events = append(events, llms.StreamEvent{Type: llms.StreamEventText, Text: choice.Delta.Content})
+				}
 				langchainContentResponse.Choices[0].Content += choice.Delta.Content
 				langchainContentResponse.Choices[0].StopReason = string(choice.FinishReason)
 				if len(choice.Delta.ToolCalls) > 0 {
 					langchainContentResponse.Choices[0].FuncCall = (*llms.FunctionCall)(&choice.Delta.ToolCalls[0].Function)
 					for _, tool := range choice.Delta.ToolCalls {
+						// Tool calls are not split across chunks, so each of
+						// them is a single delta.
+						events = append(events, llms.StreamEvent{
+							Type: llms.StreamEventToolCall,
+							ToolCall: &llms.ToolCallDelta{
+								Index:     len(langchainContentResponse.Choices[0].ToolCalls),
+								ID:        tool.Id,
+								Name:      tool.Function.Name,
+								Arguments: tool.Function.Arguments,
+							},
+						})
 						langchainContentResponse.Choices[0].ToolCalls = append(langchainContentResponse.Choices[0].ToolCalls, llms.ToolCall{
 							ID:   tool.Id,
 							Type: string(tool.Type),
@@ -227,6 +252,14 @@ func generateStreamingContent(ctx context.Context, m *Model, callOptions *llms.C
 					}
 				}
 			}
+			if callOptions.StreamingEventFunc != nil {
+				for _, event := range events {
+					if err := callOptions.StreamingEventFunc(ctx, event); err != nil {
+						return langchainContentResponse, err
+					}
+				}
+				continue
+			}
 			err := callOptions.StreamingFunc(ctx, []byte(chunkStr))
 			if err != nil {
 				return langchainContentResponse, err
@@ -239,9 +272,23 @@ func generateStreamingContent(ctx context.Context, m *Model, callOptions *llms.C
 	return langchainContentResponse, nil
 }
 
+func usageFromMistral(usage sdk.UsageInfo) *llms.Usage {
+	return &llms.Usage{
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		TotalTokens:      usage.TotalTokens,
+	}
+}
+
 func convertToMistralChatMessages(langchainMessages []llms.MessageContent) ([]sdk.ChatMessage, error) {
 	messages := make([]sdk.ChatMessage, 0)
 	for _, msg := range langchainMessages {
+		// The tool calls of a message, e.g. parallel calls, are in a single
+		// assistant message, with the text of the message.
+		if chatMsg, ok := toolCallsMessage(msg); ok {
+			messages = append(messages, chatMsg)
+			continue
+		}
 		for _, part := range msg.Parts {
 			switch p := part.(type) {
 			case llms.TextContent:
@@ -266,6 +313,32 @@ func convertToMistralChatMessages(langchainMessages []llms.MessageContent) ([]sd
 	return messages, nil
 }
 
+// toolCallsMessage returns the chat message of a message with tool calls, and
+// whether the message has tool calls.
+func toolCallsMessage(msg llms.MessageContent) (sdk.ChatMessage, bool) {
+	var text strings.Builder
+	var toolCalls []sdk.ToolCall
+	for _, part := range msg.Parts {
+		switch p := part.(type) {
+		case llms.TextContent:
+			text.WriteString(p.Text)
+		case llms.ToolCall:
+			if p.FunctionCall == nil {
+				continue
+			}
+			toolCalls = append(toolCalls, sdk.ToolCall{Id: p.ID, Type: sdk.ToolTypeFunction, Function: sdk.FunctionCall{Name: p.FunctionCall.Name, Arguments: p.FunctionCall.Arguments}})
+		default:
+			return sdk.ChatMessage{}, false
+		}
+	}
+	if len(toolCalls) == 0 {
+		return sdk.ChatMessage{}, false
+	}
+	chatMsg := sdk.ChatMessage{Role: string(msg.Role), Content: text.String(), ToolCalls: toolCalls}
+	setMistralChatMessageRole(&msg, &chatMsg)
+	return chatMsg, true
+}
+
 func setMistralChatMessageRole(msg *llms.MessageContent, chatMsg *sdk.ChatMessage) {
 	switch msg.Role {
 	case llms.ChatMessageTypeAI:
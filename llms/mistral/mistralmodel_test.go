@@ -0,0 +1,59 @@
+package mistral
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestGenerateContentStreamingToolCalls(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		f, err := os.Open("testdata/stream_tool_calls.sse")
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer f.Close()
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.Copy(w, f)
+	}))
+	t.Cleanup(server.Close)
+
+	llm, err := New(WithAPIKey("test"), WithEndpoint(server.URL), WithModel("mistral-large-latest"))
+	require.NoError(t, err)
+
+	var events []llms.StreamEvent
+	resp, err := llm.GenerateContent(context.Background(),
+		[]llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "Weather in Paris and Rome?")},
+		llms.WithStreamingEventFunc(func(_ context.Context, event llms.StreamEvent) error {
+			events = append(events, event)
+			return nil
+		}))
+	require.NoError(t, err)
+
+	assert.Equal(t, []llms.StreamEvent{
+		{Type: llms.StreamEventText, Text: "Checking both."},
+		{Type: llms.StreamEventToolCall, ToolCall: &llms.ToolCallDelta{Index: 0, ID: "D681PevKs", Name: "weather", Arguments: `{"city": "Paris"}`}},
+		{Type: llms.StreamEventToolCall, ToolCall: &llms.ToolCallDelta{Index: 1, ID: "F3xkdPq2a", Name: "weather", Arguments: `{"city": "Rome"}`}},
+	}, events)
+
+	require.Len(t, resp.Choices, 1)
+	choice := resp.Choices[0]
+	assert.Equal(t, "Checking both.", choice.Content)
+	assert.Equal(t, "tool_calls", choice.StopReason)
+	assert.Equal(t, []llms.ToolCall{
+		{ID: "D681PevKs", Type: "function", FunctionCall: &llms.FunctionCall{Name: "weather", Arguments: `{"city": "Paris"}`}},
+		{ID: "F3xkdPq2a", Type: "function", FunctionCall: &llms.FunctionCall{Name: "weather", Arguments: `{"city": "Rome"}`}},
+	}, choice.ToolCalls)
+	assert.Equal(t, choice.ToolCalls[0].FunctionCall, choice.FuncCall)
+	assert.Equal(t, &llms.Usage{PromptTokens: 80, CompletionTokens: 40, TotalTokens: 120}, resp.Usage)
+}
@@ -0,0 +1,191 @@
+package llms
+
+import (
+	"strings"
+	"sync"
+)
+
+// Tokenizers of the models of the registry. The OpenAI tokenizers are the
+// names of tiktoken encodings; the others name the tokenizer of a model
+// family.
+const (
+	TokenizerO200kBase  = "o200k_base"
+	TokenizerCL100kBase = "cl100k_base"
+	TokenizerP50kBase   = "p50k_base"
+	TokenizerR50kBase   = "r50k_base"
+	TokenizerClaude     = "claude"
+	TokenizerGemini     = "gemini"
+	TokenizerMistral    = "mistral"
+	TokenizerLlama2     = "llama2"
+	TokenizerLlama3     = "llama3"
+)
+
+// ModelInfo describes the limits of a model.
+type ModelInfo struct {
+	// Name is the name of the model, e.g. "gpt-4o". Versioned names, e.g.
+	// "gpt-4o-2024-08-06", match the model with the longest matching name.
+	Name string `json:"name"`
+	// Provider is the provider of the model, e.g. "openai".
+	Provider string `json:"provider"`
+	// ContextSize is the number of tokens of the context window of the model,
+	// input and output included.
+	ContextSize int `json:"context_size"`
+	// MaxOutputTokens is the maximum number of tokens the model generates in a
+	// response, or zero if unknown.
+	MaxOutputTokens int `json:"max_output_tokens,omitempty"`
+	// Tokenizer is the tokenizer of the model, one of the Tokenizer constants.
+	Tokenizer string `json:"tokenizer,omitempty"`
+}
+
+// nolint:gochecknoglobals
+var (
+	modelRegistryMu sync.RWMutex
+	modelRegistry   = map[string]ModelInfo{}
+)
+
+func init() { //nolint:gochecknoinits
+	for _, info := range knownModels {
+		RegisterModel(info)
+	}
+}
+
+// RegisterModel adds a model to the registry, or replaces the model with the
+// same name, e.g. to add fine-tuned models or correct the limits of a model.
+func RegisterModel(info ModelInfo) {
+	modelRegistryMu.Lock()
+	defer modelRegistryMu.Unlock()
+	modelRegistry[strings.ToLower(info.Name)] = info
+}
+
+// LookupModel returns the registered model with the given name. Names are
+// matched case insensitively, and names with a version or tag suffix, e.g.
+// "claude-3-5-sonnet-20241022" or "llama3.1:8b", match the registered model
+// with the longest matching name. Provider prefixes, e.g. "models/" for
+// Gemini or "anthropic." for Bedrock, are ignored.
+func LookupModel(name string) (ModelInfo, bool) {
+	modelRegistryMu.RLock()
+	defer modelRegistryMu.RUnlock()
+
+	name = strings.ToLower(name)
+	candidates := []string{name}
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+		candidates = append(candidates, name)
+	}
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		candidates = append(candidates, name[i+1:])
+	}
+	for _, candidate := range candidates {
+		if info, ok := lookupModelLocked(candidate); ok {
+			return info, true
+		}
+	}
+	return ModelInfo{}, false
+}
+
+func lookupModelLocked(name string) (ModelInfo, bool) {
+	if info, ok := modelRegistry[name]; ok {
+		return info, true
+	}
+	// Remove the suffixes of the name until it matches a registered model.
+	for i := len(name) - 1; i > 0; i-- {
+		switch name[i] {
+		case '-', ':', '@':
+			if info, ok := modelRegistry[name[:i]]; ok {
+				return info, true
+			}
+		}
+	}
+	return ModelInfo{}, false
+}
+
+// nolint:gochecknoglobals,mnd
+var knownModels = []ModelInfo{
+	// OpenAI
+	{Name: "gpt-4.1", Provider: "openai", ContextSize: 1047576, MaxOutputTokens: 32768, Tokenizer: TokenizerO200kBase},
+	{Name: "gpt-4.1-mini", Provider: "openai", ContextSize: 1047576, MaxOutputTokens: 32768, Tokenizer: TokenizerO200kBase},
+	{Name: "gpt-4.1-nano", Provider: "openai", ContextSize: 1047576, MaxOutputTokens: 32768, Tokenizer: TokenizerO200kBase},
+	{Name: "gpt-4o", Provider: "openai", ContextSize: 128000, MaxOutputTokens: 16384, Tokenizer: TokenizerO200kBase},
+	{Name: "gpt-4o-mini", Provider: "openai", ContextSize: 128000, MaxOutputTokens: 16384, Tokenizer: TokenizerO200kBase},
+	{Name: "chatgpt-4o-latest", Provider: "openai", ContextSize: 128000, MaxOutputTokens: 16384, Tokenizer: TokenizerO200kBase},
+	{Name: "o1", Provider: "openai", ContextSize: 200000, MaxOutputTokens: 100000, Tokenizer: TokenizerO200kBase},
+	{Name: "o1-mini", Provider: "openai", ContextSize: 128000, MaxOutputTokens: 65536, Tokenizer: TokenizerO200kBase},
+	{Name: "o3", Provider: "openai", ContextSize: 200000, MaxOutputTokens: 100000, Tokenizer: TokenizerO200kBase},
+	{Name: "o3-mini", Provider: "openai", ContextSize: 200000, MaxOutputTokens: 100000, Tokenizer: TokenizerO200kBase},
+	{Name: "o4-mini", Provider: "openai", ContextSize: 200000, MaxOutputTokens: 100000, Tokenizer: TokenizerO200kBase},
+	{Name: "gpt-4-turbo", Provider: "openai", ContextSize: 128000, MaxOutputTokens: 4096, Tokenizer: TokenizerCL100kBase},
+	{Name: "gpt-4-1106-preview", Provider: "openai", ContextSize: 128000, MaxOutputTokens: 4096, Tokenizer: TokenizerCL100kBase},
+	{Name: "gpt-4-0125-preview", Provider: "openai", ContextSize: 128000, MaxOutputTokens: 4096, Tokenizer: TokenizerCL100kBase},
+	{Name: "gpt-4", Provider: "openai", ContextSize: 8192, MaxOutputTokens: 8192, Tokenizer: TokenizerCL100kBase},
+	{Name: "gpt-4-32k", Provider: "openai", ContextSize: 32768, MaxOutputTokens: 32768, Tokenizer: TokenizerCL100kBase},
+	{Name: "gpt-3.5-turbo", Provider: "openai", ContextSize: 16385, MaxOutputTokens: 4096, Tokenizer: TokenizerCL100kBase},
+	{Name: "gpt-3.5-turbo-instruct", Provider: "openai", ContextSize: 4096, MaxOutputTokens: 4096, Tokenizer: TokenizerCL100kBase},
+	{Name: "text-davinci-003", Provider: "openai", ContextSize: 4097, MaxOutputTokens: 4097, Tokenizer: TokenizerP50kBase},
+	{Name: "text-curie-001", Provider: "openai", ContextSize: 2048, MaxOutputTokens: 2048, Tokenizer: TokenizerR50kBase},
+	{Name: "text-babbage-001", Provider: "openai", ContextSize: 2048, MaxOutputTokens: 2048, Tokenizer: TokenizerR50kBase},
+	{Name: "text-ada-001", Provider: "openai", ContextSize: 2048, MaxOutputTokens: 2048, Tokenizer: TokenizerR50kBase},
+	{Name: "code-davinci-002", Provider: "openai", ContextSize: 8000, MaxOutputTokens: 8000, Tokenizer: TokenizerP50kBase},
+	{Name: "code-cushman-001", Provider: "openai", ContextSize: 2048, MaxOutputTokens: 2048, Tokenizer: TokenizerP50kBase},
+
+	// Anthropic
+	{Name: "claude-opus-4", Provider: "anthropic", ContextSize: 200000, MaxOutputTokens: 32000, Tokenizer: TokenizerClaude},
+	{Name: "claude-sonnet-4", Provider: "anthropic", ContextSize: 200000, MaxOutputTokens: 64000, Tokenizer: TokenizerClaude},
+	{Name: "claude-3-7-sonnet", Provider: "anthropic", ContextSize: 200000, MaxOutputTokens: 64000, Tokenizer: TokenizerClaude},
+	{Name: "claude-3-5-sonnet", Provider: "anthropic", ContextSize: 200000, MaxOutputTokens: 8192, Tokenizer: TokenizerClaude},
+	{Name: "claude-3-5-haiku", Provider: "anthropic", ContextSize: 200000, MaxOutputTokens: 8192, Tokenizer: TokenizerClaude},
+	{Name: "claude-3-opus", Provider: "anthropic", ContextSize: 200000, MaxOutputTokens: 4096, Tokenizer: TokenizerClaude},
+	{Name: "claude-3-sonnet", Provider: "anthropic", ContextSize: 200000, MaxOutputTokens: 4096, Tokenizer: TokenizerClaude},
+	{Name: "claude-3-haiku", Provider: "anthropic", ContextSize: 200000, MaxOutputTokens: 4096, Tokenizer: TokenizerClaude},
+	{Name: "claude-2.1", Provider: "anthropic", ContextSize: 200000, MaxOutputTokens: 4096, Tokenizer: TokenizerClaude},
+	{Name: "claude-2", Provider: "anthropic", ContextSize: 100000, MaxOutputTokens: 4096, Tokenizer: TokenizerClaude},
+	{Name: "claude-instant-1.2", Provider: "anthropic", ContextSize: 100000, MaxOutputTokens: 4096, Tokenizer: TokenizerClaude},
+	{Name: "claude-v2", Provider: "anthropic", ContextSize: 100000, MaxOutputTokens: 4096, Tokenizer: TokenizerClaude},
+	{Name: "claude-instant-v1", Provider: "anthropic", ContextSize: 100000, MaxOutputTokens: 4096, Tokenizer: TokenizerClaude},
+
+	// Google
+	{Name: "gemini-2.5-pro", Provider: "google", ContextSize: 1048576, MaxOutputTokens: 65536, Tokenizer: TokenizerGemini},
+	{Name: "gemini-2.5-flash", Provider: "google", ContextSize: 1048576, MaxOutputTokens: 65536, Tokenizer: TokenizerGemini},
+	{Name: "gemini-2.0-flash", Provider: "google", ContextSize: 1048576, MaxOutputTokens: 8192, Tokenizer: TokenizerGemini},
+	{Name: "gemini-2.0-flash-lite", Provider: "google", ContextSize: 1048576, MaxOutputTokens: 8192, Tokenizer: TokenizerGemini},
+	{Name: "gemini-1.5-pro", Provider: "google", ContextSize: 2097152, MaxOutputTokens: 8192, Tokenizer: TokenizerGemini},
+	{Name: "gemini-1.5-flash", Provider: "google", ContextSize: 1048576, MaxOutputTokens: 8192, Tokenizer: TokenizerGemini},
+	{Name: "gemini-1.5-flash-8b", Provider: "google", ContextSize: 1048576, MaxOutputTokens: 8192, Tokenizer: TokenizerGemini},
+	{Name: "gemini-1.0-pro", Provider: "google", ContextSize: 32760, MaxOutputTokens: 8192, Tokenizer: TokenizerGemini},
+	{Name: "gemini-pro", Provider: "google", ContextSize: 32760, MaxOutputTokens: 8192, Tokenizer: TokenizerGemini},
+	{Name: "text-bison", Provider: "google", ContextSize: 8192, MaxOutputTokens: 1024, Tokenizer: TokenizerGemini},
+	{Name: "chat-bison", Provider: "google", ContextSize: 8192, MaxOutputTokens: 1024, Tokenizer: TokenizerGemini},
+
+	// Mistral
+	{Name: "mistral-large", Provider: "mistral", ContextSize: 131072, Tokenizer: TokenizerMistral},
+	{Name: "mistral-medium", Provider: "mistral", ContextSize: 131072, Tokenizer: TokenizerMistral},
+	{Name: "mistral-small", Provider: "mistral", ContextSize: 131072, Tokenizer: TokenizerMistral},
+	{Name: "mistral-tiny", Provider: "mistral", ContextSize: 32768, Tokenizer: TokenizerMistral},
+	{Name: "codestral", Provider: "mistral", ContextSize: 256000, Tokenizer: TokenizerMistral},
+	{Name: "pixtral-large", Provider: "mistral", ContextSize: 131072, Tokenizer: TokenizerMistral},
+	{Name: "ministral-8b", Provider: "mistral", ContextSize: 131072, Tokenizer: TokenizerMistral},
+	{Name: "ministral-3b", Provider: "mistral", ContextSize: 131072, Tokenizer: TokenizerMistral},
+	{Name: "open-mistral-nemo", Provider: "mistral", ContextSize: 131072, Tokenizer: TokenizerMistral},
+	{Name: "open-mistral-7b", Provider: "mistral", ContextSize: 32768, Tokenizer: TokenizerMistral},
+	{Name: "open-mixtral-8x7b", Provider: "mistral", ContextSize: 32768, Tokenizer: TokenizerMistral},
+	{Name: "open-mixtral-8x22b", Provider: "mistral", ContextSize: 65536, Tokenizer: TokenizerMistral},
+	{Name: "mistral", Provider: "mistral", ContextSize: 32768, Tokenizer: TokenizerMistral},
+	{Name: "mixtral", Provider: "mistral", ContextSize: 32768, Tokenizer: TokenizerMistral},
+	{Name: "mistral-nemo", Provider: "mistral", ContextSize: 131072, Tokenizer: TokenizerMistral},
+
+	// Meta Llama, with the names of Ollama, Hugging Face and Bedrock.
+	{Name: "llama2", Provider: "meta", ContextSize: 4096, Tokenizer: TokenizerLlama2},
+	{Name: "llama-2", Provider: "meta", ContextSize: 4096, Tokenizer: TokenizerLlama2},
+	{Name: "llama3", Provider: "meta", ContextSize: 8192, Tokenizer: TokenizerLlama3},
+	{Name: "llama-3", Provider: "meta", ContextSize: 8192, Tokenizer: TokenizerLlama3},
+	{Name: "meta-llama-3", Provider: "meta", ContextSize: 8192, Tokenizer: TokenizerLlama3},
+	{Name: "llama3.1", Provider: "meta", ContextSize: 131072, Tokenizer: TokenizerLlama3},
+	{Name: "llama-3.1", Provider: "meta", ContextSize: 131072, Tokenizer: TokenizerLlama3},
+	{Name: "meta-llama-3.1", Provider: "meta", ContextSize: 131072, Tokenizer: TokenizerLlama3},
+	{Name: "llama3-1", Provider: "meta", ContextSize: 131072, Tokenizer: TokenizerLlama3},
+	{Name: "llama3.2", Provider: "meta", ContextSize: 131072, Tokenizer: TokenizerLlama3},
+	{Name: "llama-3.2", Provider: "meta", ContextSize: 131072, Tokenizer: TokenizerLlama3},
+	{Name: "llama3-2", Provider: "meta", ContextSize: 131072, Tokenizer: TokenizerLlama3},
+	{Name: "llama3.3", Provider: "meta", ContextSize: 131072, Tokenizer: TokenizerLlama3},
+	{Name: "llama-3.3", Provider: "meta", ContextSize: 131072, Tokenizer: TokenizerLlama3},
+	{Name: "llama3-3", Provider: "meta", ContextSize: 131072, Tokenizer: TokenizerLlama3},
+}
@@ -0,0 +1,54 @@
+package llms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupModel(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		want        string
+		contextSize int
+	}{
+		{"gpt-4o", "gpt-4o", 128000},
+		{"gpt-4o-mini-2024-07-18", "gpt-4o-mini", 128000},
+		{"gpt-4-0613", "gpt-4", 8192},
+		{"GPT-4-Turbo-Preview", "gpt-4-turbo", 128000},
+		{"claude-3-5-sonnet-20241022", "claude-3-5-sonnet", 200000},
+		{"claude-3-5-sonnet-v2@20241022", "claude-3-5-sonnet", 200000},
+		{"us.anthropic.claude-3-7-sonnet-20250219-v1:0", "claude-3-7-sonnet", 200000},
+		{"models/gemini-1.5-pro-002", "gemini-1.5-pro", 2097152},
+		{"mistral-large-latest", "mistral-large", 131072},
+		{"llama3.1:8b", "llama3.1", 131072},
+		{"meta-llama/Llama-3.1-8B-Instruct", "llama-3.1", 131072},
+		{"meta.llama3-70b-instruct-v1:0", "llama3", 8192},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			info, ok := LookupModel(tt.name)
+			require.True(t, ok)
+			assert.Equal(t, tt.want, info.Name)
+			assert.Equal(t, tt.contextSize, info.ContextSize)
+			assert.Equal(t, tt.contextSize, GetModelContextSize(tt.name))
+		})
+	}
+
+	_, ok := LookupModel("gpt-5o")
+	assert.False(t, ok)
+	assert.Equal(t, 2048, GetModelContextSize("unknown-model"))
+}
+
+func TestRegisterModel(t *testing.T) {
+	t.Parallel()
+	RegisterModel(ModelInfo{Name: "acme-chat", Provider: "acme", ContextSize: 65536, Tokenizer: TokenizerCL100kBase})
+
+	info, ok := LookupModel("acme-chat-v2")
+	require.True(t, ok)
+	assert.Equal(t, "acme", info.Provider)
+	assert.Equal(t, 65536, info.ContextSize)
+}
@@ -163,10 +163,6 @@ func (c *Client) stream(ctx context.Context, method, path string, data any, fn f
 			return err
 		}
 
-		if errorResponse.Error != "" {
-			return fmt.Errorf(errorResponse.Error) //nolint
-		}
-
 		if response.StatusCode >= http.StatusBadRequest {
 			return StatusError{
 				StatusCode:   response.StatusCode,
@@ -175,6 +171,10 @@ func (c *Client) stream(ctx context.Context, method, path string, data any, fn f
 			}
 		}
 
+		if errorResponse.Error != "" {
+			return fmt.Errorf(errorResponse.Error) //nolint
+		}
+
 		if err := fn(bts); err != nil {
 			return err
 		}
@@ -1,9 +1,12 @@
 package ollamaclient
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"time"
+
+	"github.com/tmc/langchaingo/llms"
 )
 
 type StatusError struct {
@@ -26,6 +29,12 @@ func (e StatusError) Error() string {
 	}
 }
 
+// Retryable reports whether the request that failed with this error may
+// succeed if retried.
+func (e StatusError) Retryable() bool {
+	return llms.IsRetryableStatus(e.StatusCode)
+}
+
 type GenerateRequest struct {
 	Model     string `json:"model"`
 	Prompt    string `json:"prompt"`
@@ -41,17 +50,43 @@ type GenerateRequest struct {
 type ImageData []byte
 
 type Message struct {
-	Role    string      `json:"role"` // one of ["system", "user", "assistant"]
-	Content string      `json:"content"`
-	Images  []ImageData `json:"images,omitempty"`
+	Role      string      `json:"role"` // one of ["system", "user", "assistant", "tool"]
+	Content   string      `json:"content"`
+	Images    []ImageData `json:"images,omitempty"`
+	ToolCalls []ToolCall  `json:"tool_calls,omitempty"`
+	// ToolName is the name of the tool whose result a "tool" message holds.
+	ToolName string `json:"tool_name,omitempty"`
+}
+
+type ToolCall struct {
+	Function ToolCallFunction `json:"function"`
+}
+
+type ToolCallFunction struct {
+	Index     int             `json:"index,omitempty"`
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+type Tool struct {
+	Type     string       `json:"type"`
+	Function ToolFunction `json:"function"`
+}
+
+type ToolFunction struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Parameters  any    `json:"parameters,omitempty"`
 }
 
 type ChatRequest struct {
-	Model     string     `json:"model"`
-	Messages  []*Message `json:"messages"`
-	Stream    bool       `json:"stream,omitempty"`
-	Format    string     `json:"format"`
-	KeepAlive string     `json:"keep_alive,omitempty"`
+	Model    string     `json:"model"`
+	Messages []*Message `json:"messages"`
+	Stream   bool       `json:"stream,omitempty"`
+	// Format is either "json" or the JSON schema of the response.
+	Format    any    `json:"format,omitempty"`
+	KeepAlive string `json:"keep_alive,omitempty"`
+	Tools     []Tool `json:"tools,omitempty"`
 
 	Options Options `json:"options"`
 }
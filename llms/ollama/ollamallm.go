@@ -2,7 +2,11 @@ package ollama
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"fmt"
 
 	"github.com/tmc/langchaingo/callbacks"
 	"github.com/tmc/langchaingo/llms"
@@ -21,7 +25,10 @@ type LLM struct {
 	options          options
 }
 
-var _ llms.Model = (*LLM)(nil)
+var (
+	_ llms.Model                   = (*LLM)(nil)
+	_ llms.ResponseSchemaSupporter = (*LLM)(nil)
+)
 
 // New creates a new ollama LLM implementation.
 func New(opts ...Option) (*LLM, error) {
@@ -43,13 +50,15 @@ func (o *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOptio
 	return llms.GenerateFromSinglePrompt(ctx, o, prompt, options...)
 }
 
+// SupportsResponseSchema implements llms.ResponseSchemaSupporter: response
+// schemas are sent as the format of the response.
+func (o *LLM) SupportsResponseSchema() bool {
+	return true
+}
+
 // GenerateContent implements the Model interface.
 // nolint: goerr113
-func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { // nolint: lll, cyclop, funlen
-	if o.CallbacksHandler != nil {
-		o.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
-	}
-
+func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { // nolint: lll
 	opts := llms.CallOptions{}
 	for _, opt := range options {
 		opt(&opts)
@@ -61,44 +70,36 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 		model = opts.Model
 	}
 
-	// Our input is a sequence of MessageContent, each of which potentially has
-	// a sequence of Part that could be text, images etc.
-	// We have to convert it to a format Ollama undestands: ChatRequest, which
-	// has a sequence of Message, each of which has a role and content - single
-	// text + potential images.
-	chatMsgs := make([]*ollamaclient.Message, 0, len(messages))
-	for _, mc := range messages {
-		msg := &ollamaclient.Message{Role: typeToRole(mc.Role)}
-
-		// Look at all the parts in mc; expect to find a single Text part and
-		// any number of binary parts.
-		var text string
-		foundText := false
-		var images []ollamaclient.ImageData
+	ctx = callbacks.StartRun(ctx, callbacks.RunLLM, model)
+	if o.CallbacksHandler != nil {
+		o.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
+	}
 
-		for _, p := range mc.Parts {
-			switch pt := p.(type) {
-			case llms.TextContent:
-				if foundText {
-					return nil, errors.New("expecting a single Text content")
-				}
-				foundText = true
-				text = pt.Text
-			case llms.BinaryContent:
-				images = append(images, ollamaclient.ImageData(pt.Data))
-			default:
-				return nil, errors.New("only support Text and BinaryContent parts right now")
-			}
+	resp, err := o.generateContent(ctx, model, messages, opts)
+	if o.CallbacksHandler != nil {
+		if err != nil {
+			o.CallbacksHandler.HandleLLMError(ctx, err)
+		} else {
+			o.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, resp)
 		}
+	}
+	return resp, err
+}
 
-		msg.Content = text
-		msg.Images = images
-		chatMsgs = append(chatMsgs, msg)
+func (o *LLM) generateContent(ctx context.Context, model string, messages []llms.MessageContent, opts llms.CallOptions) (*llms.ContentResponse, error) { // nolint: lll, cyclop, funlen, goerr113
+	chatMsgs, err := makeOllamaMessages(messages)
+	if err != nil {
+		return nil, err
 	}
 
-	format := o.options.format
-	if opts.JSONMode {
+	var format any
+	switch {
+	case opts.ResponseSchema != nil:
+		format = opts.ResponseSchema.Schema
+	case opts.JSONMode:
 		format = "json"
+	case o.options.format != "":
+		format = o.options.format
 	}
 
 	// Get our ollamaOptions from llms.CallOptions
@@ -108,7 +109,8 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 		Format:   format,
 		Messages: chatMsgs,
 		Options:  ollamaOptions,
-		Stream:   opts.StreamingFunc != nil,
+		Stream:   opts.StreamingFunc != nil || opts.StreamingEventFunc != nil,
+		Tools:    makeOllamaTools(opts),
 	}
 
 	keepAlive := o.options.keepAlive
@@ -118,32 +120,45 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 
 	var fn ollamaclient.ChatResponseFunc
 	streamedResponse := ""
+	var toolCalls []ollamaclient.ToolCall
+	var llmToolCalls []llms.ToolCall
 	var resp ollamaclient.ChatResponse
 
 	fn = func(response ollamaclient.ChatResponse) error {
-		if opts.StreamingFunc != nil && response.Message != nil {
+		var chunkToolCalls []llms.ToolCall
+		if response.Message != nil {
+			chunkToolCalls = makeLLMToolCalls(response.Message.ToolCalls)
+		}
+		if opts.StreamingEventFunc != nil && response.Message != nil {
+			err := streamEvents(ctx, opts.StreamingEventFunc, response.Message.Content, chunkToolCalls, len(llmToolCalls))
+			if err != nil {
+				return err
+			}
+		} else if opts.StreamingFunc != nil && response.Message != nil {
 			if err := opts.StreamingFunc(ctx, []byte(response.Message.Content)); err != nil {
 				return err
 			}
 		}
 		if response.Message != nil {
 			streamedResponse += response.Message.Content
+			// Tool calls are not split across chunks, but a streamed response
+			// may spread several calls over several chunks.
+			toolCalls = append(toolCalls, response.Message.ToolCalls...)
+			llmToolCalls = append(llmToolCalls, chunkToolCalls...)
 		}
 		if !req.Stream || response.Done {
 			resp = response
 			resp.Message = &ollamaclient.Message{
-				Role:    "assistant",
-				Content: streamedResponse,
+				Role:      "assistant",
+				Content:   streamedResponse,
+				ToolCalls: toolCalls,
 			}
 		}
 		return nil
 	}
 
-	err := o.client.GenerateChat(ctx, req, fn)
+	err = o.client.GenerateChat(ctx, req, fn)
 	if err != nil {
-		if o.CallbacksHandler != nil {
-			o.CallbacksHandler.HandleLLMError(ctx, err)
-		}
 		return nil, err
 	}
 
@@ -155,13 +170,16 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 				"PromptTokens":     resp.PromptEvalCount,
 				"TotalTokens":      resp.EvalCount + resp.PromptEvalCount,
 			},
+			ToolCalls: llmToolCalls,
 		},
 	}
+	if len(choices[0].ToolCalls) > 0 {
+		choices[0].FuncCall = choices[0].ToolCalls[0].FunctionCall
+	}
 
-	response := &llms.ContentResponse{Choices: choices}
-
-	if o.CallbacksHandler != nil {
-		o.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, response)
+	response := &llms.ContentResponse{
+		Choices: choices,
+		Usage:   llms.NewUsage(resp.PromptEvalCount, resp.EvalCount),
 	}
 
 	return response, nil
@@ -198,6 +216,146 @@ func (o *LLM) CreateEmbedding(ctx context.Context, inputTexts []string) ([][]flo
 	return embeddings, nil
 }
 
+// makeOllamaMessages converts a sequence of MessageContent to a format Ollama
+// understands: a sequence of Message, each of which has a role and content -
+// single text + potential images, tool calls made by the model, or the
+// result of a single tool call.
+// nolint: goerr113
+func makeOllamaMessages(messages []llms.MessageContent) ([]*ollamaclient.Message, error) {
+	chatMsgs := make([]*ollamaclient.Message, 0, len(messages))
+	// Ollama identifies tool results by the tool name rather than the call ID,
+	// so remember the name of every call to fill it in for responses that
+	// don't carry one.
+	toolNames := make(map[string]string)
+	for _, mc := range messages {
+		msg := &ollamaclient.Message{Role: typeToRole(mc.Role)}
+
+		// Look at all the parts in mc; expect to find a single Text part and
+		// any number of binary parts or tool calls. Each tool call response
+		// becomes a message of its own.
+		var text string
+		foundText := false
+		var images []ollamaclient.ImageData
+		var toolResponses []*ollamaclient.Message
+
+		for _, p := range mc.Parts {
+			switch pt := p.(type) {
+			case llms.TextContent:
+				if foundText {
+					return nil, errors.New("expecting a single Text content")
+				}
+				foundText = true
+				text = pt.Text
+			case llms.BinaryContent:
+				images = append(images, ollamaclient.ImageData(pt.Data))
+			case llms.ToolCall:
+				if pt.FunctionCall == nil {
+					return nil, errors.New("tool call without a function")
+				}
+				args := json.RawMessage(pt.FunctionCall.Arguments)
+				if len(args) == 0 {
+					args = json.RawMessage("{}")
+				}
+				if !json.Valid(args) {
+					return nil, fmt.Errorf("tool call %q has invalid JSON arguments", pt.FunctionCall.Name)
+				}
+				toolNames[pt.ID] = pt.FunctionCall.Name
+				msg.ToolCalls = append(msg.ToolCalls, ollamaclient.ToolCall{
+					Function: ollamaclient.ToolCallFunction{
+						Name:      pt.FunctionCall.Name,
+						Arguments: args,
+					},
+				})
+			case llms.ToolCallResponse:
+				name := pt.Name
+				if name == "" {
+					name = toolNames[pt.ToolCallID]
+				}
+				toolResponses = append(toolResponses, &ollamaclient.Message{
+					Role:     "tool",
+					Content:  pt.Content,
+					ToolName: name,
+				})
+			default:
+				return nil, errors.New("only support Text, BinaryContent, ToolCall and ToolCallResponse parts right now")
+			}
+		}
+
+		if len(toolResponses) > 0 {
+			if foundText || len(images) > 0 || len(msg.ToolCalls) > 0 {
+				return nil, errors.New("tool call responses can't be mixed with other parts")
+			}
+			chatMsgs = append(chatMsgs, toolResponses...)
+			continue
+		}
+
+		msg.Content = text
+		msg.Images = images
+		chatMsgs = append(chatMsgs, msg)
+	}
+	return chatMsgs, nil
+}
+
+// makeOllamaTools converts the tools and the deprecated functions of the call
+// options to Ollama tools.
+func makeOllamaTools(opts llms.CallOptions) []ollamaclient.Tool {
+	tools := make([]ollamaclient.Tool, 0, len(opts.Tools)+len(opts.Functions))
+	for _, t := range opts.Tools {
+		if t.Function == nil {
+			continue
+		}
+		tools = append(tools, makeOllamaTool(*t.Function))
+	}
+	for _, fn := range opts.Functions {
+		tools = append(tools, makeOllamaTool(fn))
+	}
+	if len(tools) == 0 {
+		return nil
+	}
+	return tools
+}
+
+func makeOllamaTool(fn llms.FunctionDefinition) ollamaclient.Tool {
+	return ollamaclient.Tool{
+		Type: "function",
+		Function: ollamaclient.ToolFunction{
+			Name:        fn.Name,
+			Description: fn.Description,
+			Parameters:  fn.Parameters,
+		},
+	}
+}
+
+// makeLLMToolCalls converts the tool calls returned by Ollama. Ollama doesn't
+// assign IDs to tool calls, so unique IDs are generated for them.
+func makeLLMToolCalls(toolCalls []ollamaclient.ToolCall) []llms.ToolCall {
+	if len(toolCalls) == 0 {
+		return nil
+	}
+	result := make([]llms.ToolCall, 0, len(toolCalls))
+	for _, tc := range toolCalls {
+		args := string(tc.Function.Arguments)
+		if args == "" || args == "null" {
+			args = "{}"
+		}
+		result = append(result, llms.ToolCall{
+			ID:   newToolCallID(),
+			Type: "function",
+			FunctionCall: &llms.FunctionCall{
+				Name:      tc.Function.Name,
+				Arguments: args,
+			},
+		})
+	}
+	return result
+}
+
+func newToolCallID() string {
+	var b [12]byte
+	_, _ = rand.Read(b[:])
+	return "call_" + hex.EncodeToString(b[:])
+}
+
 func typeToRole(typ llms.ChatMessageType) string {
 	switch typ {
 	case llms.ChatMessageTypeSystem:
@@ -230,3 +388,33 @@ func makeOllamaOptionsFromOptions(ollamaOptions ollamaclient.Options, opts llms.
 
 	return ollamaOptions
 }
+
+// streamEvents sends the events of a chunk of a streamed response, given the
+// number of tool calls of the previous chunks. Tool calls are not split
+// across chunks, so each of them is sent as a single delta.
+func streamEvents(
+	ctx context.Context,
+	fn func(ctx context.Context, event llms.StreamEvent) error,
+	content string,
+	toolCalls []llms.ToolCall,
+	numToolCalls int,
+) error {
+	events := []llms.StreamEvent{{Type: llms.StreamEventText, Text: content}}
+	for i, call := range toolCalls {
+		events = append(events, llms.StreamEvent{
+			Type: llms.StreamEventToolCall,
+			ToolCall: &llms.ToolCallDelta{
+				Index:     numToolCalls + i,
+				ID:        call.ID,
+				Name:      call.FunctionCall.Name,
+				Arguments: call.FunctionCall.Arguments,
+			},
+		})
+	}
+	for _, event := range events {
+		if err := fn(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
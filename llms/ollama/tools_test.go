@@ -0,0 +1,217 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/ollama/internal/ollamaclient"
+)
+
+var weatherTool = llms.Tool{
+	Type: "function",
+	Function: &llms.FunctionDefinition{
+		Name:        "getCurrentWeather",
+		Description: "Get the current weather in a given location",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"location": map[string]any{"type": "string"},
+			},
+			"required": []string{"location"},
+		},
+	},
+}
+
+// mockChatServer serves /api/chat with the given ndjson lines and records the
+// requests it receives.
+func mockChatServer(t *testing.T, lines []string, requests *[]ollamaclient.ChatRequest) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/chat", r.URL.Path)
+
+		var req ollamaclient.ChatRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		*requests = append(*requests, req)
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		for _, line := range lines {
+			_, _ = fmt.Fprintln(w, line)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGenerateContentWithTools(t *testing.T) {
+	t.Parallel()
+
+	var requests []ollamaclient.ChatRequest
+	server := mockChatServer(t, []string{
+		`{"model":"llama3.1","message":{"role":"assistant","content":"","tool_calls":[` +
+			`{"function":{"name":"getCurrentWeather","arguments":{"location":"Boston"}}},` +
+			`{"function":{"name":"getCurrentWeather","arguments":{"location":"Chicago"}}}]},` +
+			`"done":true,"prompt_eval_count":20,"eval_count":10}`,
+	}, &requests)
+
+	llm, err := New(WithServerURL(server.URL), WithModel("llama3.1"))
+	require.NoError(t, err)
+
+	messages := []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "What's the weather in Boston and Chicago?"),
+	}
+	resp, err := llm.GenerateContent(context.Background(), messages, llms.WithTools([]llms.Tool{weatherTool}))
+	require.NoError(t, err)
+
+	require.Len(t, requests, 1)
+	require.Len(t, requests[0].Tools, 1)
+	assert.Equal(t, "function", requests[0].Tools[0].Type)
+	assert.Equal(t, "getCurrentWeather", requests[0].Tools[0].Function.Name)
+	assert.NotNil(t, requests[0].Tools[0].Function.Parameters)
+
+	require.Len(t, resp.Choices, 1)
+	choice := resp.Choices[0]
+	require.Len(t, choice.ToolCalls, 2)
+	assert.Equal(t, "getCurrentWeather", choice.ToolCalls[0].FunctionCall.Name)
+	assert.JSONEq(t, `{"location":"Boston"}`, choice.ToolCalls[0].FunctionCall.Arguments)
+	assert.JSONEq(t, `{"location":"Chicago"}`, choice.ToolCalls[1].FunctionCall.Arguments)
+	assert.NotEmpty(t, choice.ToolCalls[0].ID)
+	assert.NotEqual(t, choice.ToolCalls[0].ID, choice.ToolCalls[1].ID)
+	assert.Equal(t, choice.ToolCalls[0].FunctionCall, choice.FuncCall)
+
+	// Send the tool results back.
+	messages = append(messages, llms.MessageContent{
+		Role:  llms.ChatMessageTypeAI,
+		Parts: []llms.ContentPart{choice.ToolCalls[0], choice.ToolCalls[1]},
+	}, llms.MessageContent{
+		Role: llms.ChatMessageTypeTool,
+		Parts: []llms.ContentPart{
+			llms.ToolCallResponse{ToolCallID: choice.ToolCalls[0].ID, Content: "72 and sunny"},
+			llms.ToolCallResponse{ToolCallID: choice.ToolCalls[1].ID, Name: "getCurrentWeather", Content: "65 and windy"},
+		},
+	})
+	_, err = llm.GenerateContent(context.Background(), messages, llms.WithTools([]llms.Tool{weatherTool}))
+	require.NoError(t, err)
+
+	require.Len(t, requests, 2)
+	msgs := requests[1].Messages
+	require.Len(t, msgs, 4)
+	assert.Equal(t, "assistant", msgs[1].Role)
+	require.Len(t, msgs[1].ToolCalls, 2)
+	assert.JSONEq(t, `{"location":"Boston"}`, string(msgs[1].ToolCalls[0].Function.Arguments))
+	assert.Equal(t, &ollamaclient.Message{Role: "tool", Content: "72 and sunny", ToolName: "getCurrentWeather"}, msgs[2])
+	assert.Equal(t, &ollamaclient.Message{Role: "tool", Content: "65 and windy", ToolName: "getCurrentWeather"}, msgs[3])
+}
+
+func TestGenerateContentWithToolsStreaming(t *testing.T) {
+	t.Parallel()
+
+	var requests []ollamaclient.ChatRequest
+	server := mockChatServer(t, []string{
+		`{"model":"llama3.1","message":{"role":"assistant","content":"Let me check."},"done":false}`,
+		`{"model":"llama3.1","message":{"role":"assistant","content":"","tool_calls":[` +
+			`{"function":{"name":"getCurrentWeather","arguments":{"location":"Boston"}}}]},"done":false}`,
+		`{"model":"llama3.1","message":{"role":"assistant","content":""},"done":true,"prompt_eval_count":20,"eval_count":10}`,
+	}, &requests)
+
+	llm, err := New(WithServerURL(server.URL), WithModel("llama3.1"))
+	require.NoError(t, err)
+
+	var sb strings.Builder
+	resp, err := llm.GenerateContent(context.Background(),
+		[]llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "What's the weather in Boston?")},
+		llms.WithFunctions([]llms.FunctionDefinition{*weatherTool.Function}),
+		llms.WithStreamingFunc(func(_ context.Context, chunk []byte) error {
+			sb.Write(chunk)
+			return nil
+		}))
+	require.NoError(t, err)
+
+	require.Len(t, requests, 1)
+	assert.True(t, requests[0].Stream)
+	require.Len(t, requests[0].Tools, 1)
+
+	assert.Equal(t, "Let me check.", sb.String())
+	require.Len(t, resp.Choices, 1)
+	assert.Equal(t, "Let me check.", resp.Choices[0].Content)
+	require.Len(t, resp.Choices[0].ToolCalls, 1)
+	assert.JSONEq(t, `{"location":"Boston"}`, resp.Choices[0].ToolCalls[0].FunctionCall.Arguments)
+	assert.Equal(t, &llms.Usage{PromptTokens: 20, CompletionTokens: 10, TotalTokens: 30}, resp.Usage)
+}
+
+func TestGenerateObject(t *testing.T) {
+	t.Parallel()
+
+	var requests []ollamaclient.ChatRequest
+	server := mockChatServer(t, []string{
+		`{"model":"llama3.1","message":{"role":"assistant","content":"{\"city\":\"Boston\",\"celsius\":21.5}"},` +
+			`"done":true}`,
+	}, &requests)
+
+	llm, err := New(WithServerURL(server.URL), WithModel("llama3.1"))
+	require.NoError(t, err)
+
+	type weather struct {
+		City    string  `json:"city"`
+		Celsius float64 `json:"celsius"`
+	}
+	messages := []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "What's the weather in Boston?"),
+	}
+	v, err := llms.GenerateObject[weather](context.Background(), llm, messages)
+	require.NoError(t, err)
+	assert.Equal(t, weather{City: "Boston", Celsius: 21.5}, v)
+
+	require.Len(t, requests, 1)
+	format, ok := requests[0].Format.(map[string]any)
+	require.True(t, ok, "format is %T", requests[0].Format)
+	assert.Equal(t, "object", format["type"])
+	assert.Equal(t, []any{"city", "celsius"}, format["required"])
+}
+
+func TestStreamContent(t *testing.T) {
+	t.Parallel()
+
+	var requests []ollamaclient.ChatRequest
+	server := mockChatServer(t, []string{
+		`{"model":"llama3.1","message":{"role":"assistant","content":"Checking"},"done":false}`,
+		`{"model":"llama3.1","message":{"role":"assistant","content":"","tool_calls":[` +
+			`{"function":{"name":"getCurrentWeather","arguments":{"location":"Boston"}}}]},"done":false}`,
+		`{"model":"llama3.1","message":{"role":"assistant","content":""},` +
+			`"done":true,"done_reason":"stop","prompt_eval_count":20,"eval_count":10}`,
+	}, &requests)
+
+	llm, err := New(WithServerURL(server.URL), WithModel("llama3.1"))
+	require.NoError(t, err)
+
+	messages := []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "What's the weather in Boston?"),
+	}
+	var events []llms.StreamEvent
+	for event := range llms.StreamContent(context.Background(), llm, messages, llms.WithTools([]llms.Tool{weatherTool})) {
+		events = append(events, event)
+	}
+
+	require.Len(t, requests, 1)
+	assert.True(t, requests[0].Stream)
+
+	require.Len(t, events, 4)
+	assert.Equal(t, llms.StreamEvent{Type: llms.StreamEventText, Text: "Checking"}, events[0])
+	assert.Equal(t, llms.StreamEventToolCall, events[1].Type)
+	assert.Equal(t, "getCurrentWeather", events[1].ToolCall.Name)
+	assert.JSONEq(t, `{"location":"Boston"}`, events[1].ToolCall.Arguments)
+	assert.Equal(t, llms.StreamEventUsage, events[2].Type)
+	assert.Equal(t, llms.StreamEventStop, events[3].Type)
+
+	toolCalls := events[3].Response.Choices[0].ToolCalls
+	require.Len(t, toolCalls, 1)
+	assert.Equal(t, events[1].ToolCall.ID, toolCalls[0].ID)
+}
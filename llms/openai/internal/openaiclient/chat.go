@@ -8,8 +8,10 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 
+	"github.com/tmc/langchaingo/jsonschema"
 	"github.com/tmc/langchaingo/llms"
 )
 
@@ -71,6 +73,11 @@ type ChatRequest struct {
 	// Return an error to stop streaming early.
 	StreamingReasoningFunc func(ctx context.Context, reasoningChunk, chunk []byte) error `json:"-"`
 
+	// StreamingEventFunc is a function to be called for each event of a streaming response.
+	// If set, it is called instead of StreamingFunc and StreamingReasoningFunc.
+	// Return an error to stop streaming early.
+	StreamingEventFunc func(ctx context.Context, event llms.StreamEvent) error `json:"-"`
+
 	// Deprecated: use Tools instead.
 	Functions []FunctionDefinition `json:"functions,omitempty"`
 	// Deprecated: use ToolChoice instead.
@@ -107,6 +114,9 @@ type ToolFunction struct {
 
 // ToolCall is a call to a tool.
 type ToolCall struct {
+	// Index is the position of the tool call in the message. It is only set
+	// in the deltas of streamed responses.
+	Index    *int         `json:"index,omitempty"`
 	ID       string       `json:"id,omitempty"`
 	Type     ToolType     `json:"type"`
 	Function ToolFunction `json:"function,omitempty"`
@@ -124,9 +134,24 @@ type ResponseFormatJSONSchemaProperty struct {
 }
 
 type ResponseFormatJSONSchema struct {
-	Name   string                            `json:"name"`
-	Strict bool                              `json:"strict"`
-	Schema *ResponseFormatJSONSchemaProperty `json:"schema"`
+	Name        string                            `json:"name"`
+	Description string                            `json:"description,omitempty"`
+	Strict      bool                              `json:"strict"`
+	Schema      *ResponseFormatJSONSchemaProperty `json:"schema"`
+	// Definition, if set, is sent as the schema instead of Schema.
+	Definition *jsonschema.Definition `json:"-"`
+}
+
+// MarshalJSON sends the Definition as the schema, if set.
+func (f ResponseFormatJSONSchema) MarshalJSON() ([]byte, error) {
+	type alias ResponseFormatJSONSchema
+	if f.Definition == nil {
+		return json.Marshal(alias(f))
+	}
+	return json.Marshal(struct {
+		alias
+		Schema *jsonschema.Definition `json:"schema"`
+	}{alias(f), f.Definition})
 }
 
 // ResponseFormat is the format of the response.
@@ -302,6 +327,9 @@ type ChatUsage struct {
 	CompletionTokensDetails struct {
 		ReasoningTokens int `json:"reasoning_tokens"`
 	} `json:"completion_tokens_details"`
+	PromptTokensDetails struct {
+		CachedTokens int `json:"cached_tokens"`
+	} `json:"prompt_tokens_details"`
 }
 
 // ChatCompletionResponse is a response to a chat request.
@@ -322,6 +350,9 @@ type Usage struct {
 	CompletionTokensDetails struct {
 		ReasoningTokens int `json:"reasoning_tokens"`
 	} `json:"completion_tokens_details"`
+	PromptTokensDetails struct {
+		CachedTokens int `json:"cached_tokens"`
+	} `json:"prompt_tokens_details"`
 }
 
 // StreamedChatResponsePayload is a chunk from the stream.
@@ -383,8 +414,14 @@ type FunctionCall struct {
 	Arguments string `json:"arguments"`
 }
 
+// streaming reports whether the response is streamed to one of the streaming
+// functions of the request.
+func (r *ChatRequest) streaming() bool {
+	return r.StreamingFunc != nil || r.StreamingReasoningFunc != nil || r.StreamingEventFunc != nil
+}
+
 func (c *Client) createChat(ctx context.Context, payload *ChatRequest) (*ChatCompletionResponse, error) {
-	if payload.StreamingFunc != nil || payload.StreamingReasoningFunc != nil {
+	if payload.streaming() {
 		payload.Stream = true
 		if payload.StreamOptions == nil {
 			payload.StreamOptions = &StreamOptions{IncludeUsage: true}
@@ -414,18 +451,14 @@ func (c *Client) createChat(ctx context.Context, payload *ChatRequest) (*ChatCom
 	defer r.Body.Close()
 
 	if r.StatusCode != http.StatusOK {
-		msg := fmt.Sprintf("API returned unexpected status code: %d", r.StatusCode)
-
 		// No need to check the error here: if it fails, we'll just return the
 		// status code.
 		var errResp errorMessage
-		if err := json.NewDecoder(r.Body).Decode(&errResp); err != nil {
-			return nil, errors.New(msg) // nolint:goerr113
-		}
+		_ = json.NewDecoder(r.Body).Decode(&errResp)
 
-		return nil, fmt.Errorf("%s: %s", msg, errResp.Error.Message) // nolint:goerr113
+		return nil, llms.NewAPIError(r, errResp.Error.Message)
 	}
-	if payload.StreamingFunc != nil || payload.StreamingReasoningFunc != nil {
+	if payload.streaming() {
 		return parseStreamingChatResponse(ctx, r, payload)
 	}
 	// Parse response
@@ -490,6 +523,7 @@ func combineStreamingChatResponse(
 			response.Usage.PromptTokens = streamResponse.Usage.PromptTokens
 			response.Usage.TotalTokens = streamResponse.Usage.TotalTokens
 			response.Usage.CompletionTokensDetails.ReasoningTokens = streamResponse.Usage.CompletionTokensDetails.ReasoningTokens
+			response.Usage.PromptTokensDetails.CachedTokens = streamResponse.Usage.PromptTokensDetails.CachedTokens
 		}
 
 		if len(streamResponse.Choices) == 0 {
@@ -506,11 +540,26 @@ func combineStreamingChatResponse(
 			chunk = updateFunctionCall(response.Choices[0].Message, choice.Delta.FunctionCall)
 		}
 
+		var toolCallEvents []llms.StreamEvent
 		if len(choice.Delta.ToolCalls) > 0 {
+			toolCallEvents = toolCallDeltaEvents(response.Choices[0].Message.ToolCalls, choice.Delta.ToolCalls)
 			chunk, response.Choices[0].Message.ToolCalls = updateToolCalls(response.Choices[0].Message.ToolCalls,
 				choice.Delta.ToolCalls)
 		}
 
+		if payload.StreamingEventFunc != nil {
+			events := append([]llms.StreamEvent{
+				{Type: llms.StreamEventReasoning, Text: choice.Delta.ReasoningContent},
+				{Type: llms.StreamEventText, Text: choice.Delta.Content},
+			}, toolCallEvents...)
+			for _, event := range events {
+				if err := payload.StreamingEventFunc(ctx, event); err != nil {
+					return nil, fmt.Errorf("streaming event func returned an error: %w", err)
+				}
+			}
+			continue
+		}
+
 		if payload.StreamingFunc != nil {
 			err := payload.StreamingFunc(ctx, chunk)
 			if err != nil {
@@ -527,6 +576,53 @@ func combineStreamingChatResponse(
 	return &response, nil
 }
 
+// toolCallDeltaEvents returns the events of the tool call deltas of a chunk,
+// given the tool calls of the previous chunks.
+func toolCallDeltaEvents(tools []ToolCall, delta []*ToolCall) []llms.StreamEvent {
+	events := make([]llms.StreamEvent, 0, len(delta))
+	for _, t := range delta {
+		var index int
+		index, tools = toolCallIndex(tools, t)
+		if index < 0 {
+			continue
+		}
+		events = append(events, llms.StreamEvent{
+			Type: llms.StreamEventToolCall,
+			ToolCall: &llms.ToolCallDelta{
+				Index:     index,
+				ID:        t.ID,
+				Name:      t.Function.Name,
+				Arguments: t.Function.Arguments,
+			},
+		})
+	}
+	return events
+}
+
+// toolCallIndex returns the position of the tool call a delta belongs to,
+// and the tool calls with a placeholder for it if it starts a new call. The
+// position is -1 if the delta doesn't belong to any call.
+//
+// Deltas have the index of their call, but some OpenAI compatible APIs omit
+// it: deltas with a type then start a new call, and deltas without a type
+// continue the last call.
+func toolCallIndex(tools []ToolCall, delta *ToolCall) (int, []ToolCall) {
+	if delta.Index != nil {
+		index := *delta.Index
+		if index < 0 {
+			return -1, tools
+		}
+		for len(tools) <= index {
+			tools = append(tools, ToolCall{})
+		}
+		return index, tools
+	}
+	if delta.Type == `` && delta.ID == `` {
+		return len(tools) - 1, tools
+	}
+	return len(tools), append(tools, ToolCall{})
+}
+
 func updateFunctionCall(message ChatMessage, functionCall *FunctionCall) []byte {
 	if message.FunctionCall == nil {
 		message.FunctionCall = functionCall
@@ -541,20 +637,24 @@ func updateToolCalls(tools []ToolCall, delta []*ToolCall) ([]byte, []ToolCall) {
 	if len(delta) == 0 {
 		return []byte{}, tools
 	}
+	tools = slices.Clone(tools)
 	for _, t := range delta {
-		// if we have arguments append to the last Tool call
-		if t.Type == `` && t.Function.Arguments != `` {
-			lindex := len(tools) - 1
-			if lindex < 0 {
-				continue
-			}
-
-			tools[lindex].Function.Arguments += t.Function.Arguments
+		var index int
+		index, tools = toolCallIndex(tools, t)
+		if index < 0 {
 			continue
 		}
-
-		// Otherwise, this is a new tool call, append that to the stack
-		tools = append(tools, *t)
+		call := &tools[index]
+		if t.ID != `` {
+			call.ID = t.ID
+		}
+		if t.Type != `` {
+			call.Type = t.Type
+		}
+		if t.Function.Name != `` {
+			call.Function.Name = t.Function.Name
+		}
+		call.Function.Arguments += t.Function.Arguments
 	}
 
 	chunk, _ := json.Marshal(delta) // nolint:errchkjson
@@ -564,26 +664,5 @@ func updateToolCalls(tools []ToolCall, delta []*ToolCall) ([]byte, []ToolCall) {
 
 // StreamingChatResponseTools is a helper function to append tool calls to the stack.
 func StreamingChatResponseTools(tools []ToolCall, delta []*ToolCall) ([]byte, []ToolCall) {
-	if len(delta) == 0 {
-		return []byte{}, tools
-	}
-	for _, t := range delta {
-		// if we have arguments append to the last Tool call
-		if t.Type == `` && t.Function.Arguments != `` {
-			lindex := len(tools) - 1
-			if lindex < 0 {
-				continue
-			}
-
-			tools[lindex].Function.Arguments += t.Function.Arguments
-			continue
-		}
-
-		// Otherwise, this is a new tool call, append that to the stack
-		tools = append(tools, *t)
-	}
-
-	chunk, _ := json.Marshal(delta) // nolint:errchkjson
-
-	return chunk, tools
+	return updateToolCalls(tools, delta)
 }
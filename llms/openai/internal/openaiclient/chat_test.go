@@ -6,10 +6,13 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/jsonschema"
+	"github.com/tmc/langchaingo/llms"
 )
 
 func TestParseStreamingChatResponse_FinishReason(t *testing.T) {
@@ -103,6 +106,25 @@ func TestChatMessage_MarshalUnmarshal(t *testing.T) {
 	require.Equal(t, msg, msg2)
 }
 
+func TestResponseFormatJSONSchema_Marshal(t *testing.T) {
+	t.Parallel()
+	format := ResponseFormatJSONSchema{
+		Name:   "answer",
+		Strict: true,
+		Schema: &ResponseFormatJSONSchemaProperty{Type: "object", Required: []string{"a"}},
+	}
+	text, err := json.Marshal(format)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"name":"answer","strict":true,
+		"schema":{"type":"object","additionalProperties":false,"required":["a"]}}`, string(text))
+
+	format.Definition = &jsonschema.Definition{Type: jsonschema.Object, Required: []string{"b"}}
+	text, err = json.Marshal(&format)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"name":"answer","strict":true,
+		"schema":{"type":"object","properties":{},"required":["b"]}}`, string(text))
+}
+
 func TestChatMessage_MarshalUnmarshal_WithReasoning(t *testing.T) {
 	t.Parallel()
 	msg := ChatMessage{
@@ -119,3 +141,103 @@ func TestChatMessage_MarshalUnmarshal_WithReasoning(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, msg, msg2)
 }
+
+func TestParseStreamingChatResponse_Usage(t *testing.T) {
+	t.Parallel()
+	mockBody := `data: {"choices":[{"index":0,"delta":{"role":"assistant","content":"hello"},"finish_reason":"stop"}]}
+data: {"choices":[],"usage":{"prompt_tokens":20,"completion_tokens":10,"total_tokens":30,"prompt_tokens_details":{"cached_tokens":8},"completion_tokens_details":{"reasoning_tokens":4}}}
+data: [DONE]`
+	r := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(mockBody)),
+	}
+
+	req := &ChatRequest{
+		StreamingFunc: func(_ context.Context, _ []byte) error {
+			return nil
+		},
+	}
+
+	resp, err := parseStreamingChatResponse(context.Background(), r, req)
+
+	require.NoError(t, err)
+	assert.Equal(t, 20, resp.Usage.PromptTokens)
+	assert.Equal(t, 10, resp.Usage.CompletionTokens)
+	assert.Equal(t, 30, resp.Usage.TotalTokens)
+	assert.Equal(t, 8, resp.Usage.PromptTokensDetails.CachedTokens)
+	assert.Equal(t, 4, resp.Usage.CompletionTokensDetails.ReasoningTokens)
+}
+
+func TestParseStreamingChatResponse_Events(t *testing.T) {
+	t.Parallel()
+	mockBody := `data: {"choices":[{"index":0,"delta":{"role":"assistant","reasoning_content":"think"}}]}
+data: {"choices":[{"index":0,"delta":{"content":"Let me check."}}]}
+data: {"choices":[{"index":0,"delta":{"tool_calls":[{"id":"call_1","type":"function","function":{"name":"weather","arguments":""}}]}}]}
+data: {"choices":[{"index":0,"delta":{"tool_calls":[{"function":{"arguments":"{\"city\":"}}]}}]}
+data: {"choices":[{"index":0,"delta":{"tool_calls":[{"function":{"arguments":"\"Paris\"}"}}]},"finish_reason":"tool_calls"}]}
+data: [DONE]`
+	r := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(mockBody)),
+	}
+
+	var events []llms.StreamEvent
+	req := &ChatRequest{
+		StreamingFunc: func(_ context.Context, _ []byte) error {
+			t.Error("StreamingFunc must not be called when StreamingEventFunc is set")
+			return nil
+		},
+		StreamingEventFunc: func(_ context.Context, event llms.StreamEvent) error {
+			if event.Text != "" || event.ToolCall != nil {
+				events = append(events, event)
+			}
+			return nil
+		},
+	}
+
+	resp, err := parseStreamingChatResponse(context.Background(), r, req)
+	require.NoError(t, err)
+	assert.Equal(t, []llms.StreamEvent{
+		{Type: llms.StreamEventReasoning, Text: "think"},
+		{Type: llms.StreamEventText, Text: "Let me check."},
+		{Type: llms.StreamEventToolCall, ToolCall: &llms.ToolCallDelta{Index: 0, ID: "call_1", Name: "weather"}},
+		{Type: llms.StreamEventToolCall, ToolCall: &llms.ToolCallDelta{Index: 0, Arguments: `{"city":`}},
+		{Type: llms.StreamEventToolCall, ToolCall: &llms.ToolCallDelta{Index: 0, Arguments: `"Paris"}`}},
+	}, events)
+	require.Len(t, resp.Choices[0].Message.ToolCalls, 1)
+	assert.Equal(t, `{"city":"Paris"}`, resp.Choices[0].Message.ToolCalls[0].Function.Arguments)
+}
+
+func TestParseStreamingChatResponse_ParallelToolCalls(t *testing.T) {
+	t.Parallel()
+	f, err := os.Open("testdata/parallel_tool_calls.sse")
+	require.NoError(t, err)
+	defer f.Close()
+	r := &http.Response{StatusCode: http.StatusOK, Body: f}
+
+	var deltas []llms.ToolCallDelta
+	req := &ChatRequest{
+		StreamingEventFunc: func(_ context.Context, event llms.StreamEvent) error {
+			if event.ToolCall != nil {
+				deltas = append(deltas, *event.ToolCall)
+			}
+			return nil
+		},
+	}
+
+	resp, err := parseStreamingChatResponse(context.Background(), r, req)
+	require.NoError(t, err)
+	assert.Equal(t, []llms.ToolCallDelta{
+		{Index: 0, ID: "call_paris", Name: "weather"},
+		{Index: 0, Arguments: `{"city"`},
+		{Index: 1, ID: "call_rome", Name: "weather"},
+		{Index: 1, Arguments: `{"city": "Rome"}`},
+		{Index: 0, Arguments: `: "Paris"}`},
+	}, deltas)
+
+	assert.Equal(t, FinishReason("tool_calls"), resp.Choices[0].FinishReason)
+	assert.Equal(t, []ToolCall{
+		{ID: "call_paris", Type: ToolTypeFunction, Function: ToolFunction{Name: "weather", Arguments: `{"city": "Paris"}`}},
+		{ID: "call_rome", Type: ToolTypeFunction, Function: ToolFunction{Name: "weather", Arguments: `{"city": "Rome"}`}},
+	}, resp.Choices[0].Message.ToolCalls)
+}
@@ -4,9 +4,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net/http"
+
+	"github.com/tmc/langchaingo/llms"
 )
 
 const (
@@ -62,16 +63,12 @@ func (c *Client) createEmbedding(ctx context.Context, payload *embeddingPayload)
 	defer r.Body.Close()
 
 	if r.StatusCode != http.StatusOK {
-		msg := fmt.Sprintf("API returned unexpected status code: %d", r.StatusCode)
-
 		// No need to check the error here: if it fails, we'll just return the
 		// status code.
 		var errResp errorMessage
-		if err := json.NewDecoder(r.Body).Decode(&errResp); err != nil {
-			return nil, errors.New(msg) // nolint:goerr113
-		}
+		_ = json.NewDecoder(r.Body).Decode(&errResp)
 
-		return nil, fmt.Errorf("%s: %s", msg, errResp.Error.Message) // nolint:goerr113
+		return nil, llms.NewAPIError(r, errResp.Error.Message)
 	}
 
 	var response embeddingResponsePayload
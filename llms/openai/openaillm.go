@@ -24,7 +24,10 @@ const (
 	RoleTool      = "tool"
 )
 
-var _ llms.Model = (*LLM)(nil)
+var (
+	_ llms.Model                   = (*LLM)(nil)
+	_ llms.ResponseSchemaSupporter = (*LLM)(nil)
+)
 
 // New returns a new OpenAI LLM.
 func New(opts ...Option) (*LLM, error) {
@@ -44,16 +47,33 @@ func (o *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOptio
 }
 
 // GenerateContent implements the Model interface.
-func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint: lll, cyclop, goerr113, funlen
+func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	model := opts.Model
+	if model == "" {
+		model = o.client.Model
+	}
+	ctx = callbacks.StartRun(ctx, callbacks.RunLLM, model)
 	if o.CallbacksHandler != nil {
 		o.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
 	}
 
-	opts := llms.CallOptions{}
-	for _, opt := range options {
-		opt(&opts)
+	response, err := o.generateContent(ctx, messages, opts)
+	if o.CallbacksHandler != nil {
+		if err != nil {
+			o.CallbacksHandler.HandleLLMError(ctx, err)
+		} else {
+			o.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, response)
+		}
 	}
+	return response, err
+}
 
+func (o *LLM) generateContent(ctx context.Context, messages []llms.MessageContent, opts llms.CallOptions) (*llms.ContentResponse, error) { //nolint: lll, cyclop, goerr113, funlen
 	chatMsgs := make([]*ChatMessage, 0, len(messages))
 	for _, mc := range messages {
 		msg := &ChatMessage{MultiContent: mc.Parts}
@@ -101,6 +121,7 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 		Messages:               chatMsgs,
 		StreamingFunc:          opts.StreamingFunc,
 		StreamingReasoningFunc: opts.StreamingReasoningFunc,
+		StreamingEventFunc:     opts.StreamingEventFunc,
 		Temperature:            opts.Temperature,
 		N:                      opts.N,
 		FrequencyPenalty:       opts.FrequencyPenalty,
@@ -142,6 +163,17 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 	if o.client.ResponseFormat != nil {
 		req.ResponseFormat = o.client.ResponseFormat
 	}
+	if rs := opts.ResponseSchema; rs != nil {
+		req.ResponseFormat = &ResponseFormat{
+			Type: "json_schema",
+			JSONSchema: &ResponseFormatJSONSchema{
+				Name:        rs.Name,
+				Description: rs.Description,
+				Strict:      rs.Strict,
+				Definition:  &rs.Schema,
+			},
+		}
+	}
 
 	result, err := o.client.CreateChat(ctx, req)
 	if err != nil {
@@ -186,13 +218,25 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 			choices[i].FuncCall = choices[i].ToolCalls[0].FunctionCall
 		}
 	}
-	response := &llms.ContentResponse{Choices: choices}
-	if o.CallbacksHandler != nil {
-		o.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, response)
+	response := &llms.ContentResponse{
+		Choices: choices,
+		Usage: &llms.Usage{
+			PromptTokens:     result.Usage.PromptTokens,
+			CompletionTokens: result.Usage.CompletionTokens,
+			TotalTokens:      result.Usage.TotalTokens,
+			CachedTokens:     result.Usage.PromptTokensDetails.CachedTokens,
+			ReasoningTokens:  result.Usage.CompletionTokensDetails.ReasoningTokens,
+		},
 	}
 	return response, nil
 }
 
+// SupportsResponseSchema implements llms.ResponseSchemaSupporter: response
+// schemas are sent as a json_schema response format.
+func (o *LLM) SupportsResponseSchema() bool {
+	return true
+}
+
 // CreateEmbedding creates embeddings for the given input texts.
 func (o *LLM) CreateEmbedding(ctx context.Context, inputTexts []string) ([][]float32, error) {
 	embeddings, err := o.client.CreateEmbedding(ctx, &openaiclient.EmbeddingRequest{
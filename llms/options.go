@@ -1,6 +1,11 @@
 package llms
 
-import "context"
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/tmc/langchaingo/jsonschema"
+)
 
 // CallOption is a function that configures a CallOptions.
 type CallOption func(*CallOptions)
@@ -24,6 +29,10 @@ type CallOptions struct {
 	// StreamingReasoningFunc is a function to be called for each chunk of a streaming response.
 	// Return an error to stop streaming early.
 	StreamingReasoningFunc func(ctx context.Context, reasoningChunk, chunk []byte) error `json:"-"`
+	// StreamingEventFunc is a function to be called for each event of a streaming
+	// response. Models supporting it call it instead of StreamingFunc and
+	// StreamingReasoningFunc. Return an error to stop streaming early.
+	StreamingEventFunc func(ctx context.Context, event StreamEvent) error `json:"-"`
 	// TopK is the number of tokens to consider for top-k sampling.
 	TopK int `json:"top_k"`
 	// TopP is the cumulative probability for top-p sampling.
@@ -69,6 +78,31 @@ type CallOptions struct {
 	// Supported MIME types are: text/plain: (default) Text output.
 	// application/json: JSON response in the response candidates.
 	ResponseMIMEType string `json:"response_mime_type,omitempty"`
+
+	// ResponseSchema is the schema of the JSON document the model must respond
+	// with. It is only honored by models implementing ResponseSchemaSupporter.
+	ResponseSchema *ResponseSchema `json:"response_schema,omitempty"`
+	// MaxRepairAttempts is the number of times GenerateObject asks the model to
+	// fix a response that doesn't match the schema.
+	MaxRepairAttempts int `json:"max_repair_attempts,omitempty"`
+
+	// ThinkingBudget is the number of tokens models with extended thinking may
+	// spend reasoning before responding. Zero disables extended thinking.
+	ThinkingBudget int `json:"thinking_budget,omitempty"`
+}
+
+// ResponseSchema describes the JSON document a model must respond with.
+type ResponseSchema struct {
+	// Name is the name of the schema, e.g. the name of the type it describes.
+	Name string `json:"name"`
+	// Description describes the expected response.
+	Description string `json:"description,omitempty"`
+	// Schema is the JSON schema of the response.
+	Schema jsonschema.Definition `json:"schema"`
+	// Strict asks the model to follow the schema exactly, for providers
+	// supporting it. Strict schemas may have to mark every property as
+	// required and forbid additional properties.
+	Strict bool `json:"strict,omitempty"`
 }
 
 // Tool is a tool that can be used by the model.
@@ -172,6 +206,48 @@ func WithStreamingReasoningFunc(streamingReasoningFunc func(ctx context.Context,
 	}
 }
 
+// TrackStreaming wraps the streaming functions of the options, and returns the
+// wrapped options with a function reporting whether any of them was called.
+// Model wrappers use it not to retry, or fall back from, a call whose output
+// was already delivered to the caller, through StreamingFunc,
+// StreamingReasoningFunc or StreamingEventFunc, and so StreamContent.
+func TrackStreaming(options []CallOption) ([]CallOption, func() bool) {
+	var opts CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	streamed := &atomic.Bool{}
+	options = options[:len(options):len(options)]
+	if streamingFunc := opts.StreamingFunc; streamingFunc != nil {
+		options = append(options, WithStreamingFunc(func(ctx context.Context, chunk []byte) error {
+			streamed.Store(true)
+			return streamingFunc(ctx, chunk)
+		}))
+	}
+	if reasoningFunc := opts.StreamingReasoningFunc; reasoningFunc != nil {
+		options = append(options, WithStreamingReasoningFunc(func(ctx context.Context, reasoningChunk, chunk []byte) error {
+			streamed.Store(true)
+			return reasoningFunc(ctx, reasoningChunk, chunk)
+		}))
+	}
+	if eventFunc := opts.StreamingEventFunc; eventFunc != nil {
+		options = append(options, WithStreamingEventFunc(func(ctx context.Context, event StreamEvent) error {
+			streamed.Store(true)
+			return eventFunc(ctx, event)
+		}))
+	}
+	return options, streamed.Load
+}
+
+// WithStreamingEventFunc specifies the function called for each event of a
+// streaming response. See StreamContent.
+func WithStreamingEventFunc(streamingEventFunc func(ctx context.Context, event StreamEvent) error) CallOption {
+	return func(o *CallOptions) {
+		o.StreamingEventFunc = streamingEventFunc
+	}
+}
+
 // WithTopK will add an option to use top-k sampling.
 func WithTopK(topK int) CallOption {
 	return func(o *CallOptions) {
@@ -290,3 +366,30 @@ func WithResponseMIMEType(responseMIMEType string) CallOption {
 		o.ResponseMIMEType = responseMIMEType
 	}
 }
+
+// WithResponseSchema will add an option to constrain the response of the model
+// to a JSON document matching the schema. See GenerateObject.
+func WithResponseSchema(schema *ResponseSchema) CallOption {
+	return func(o *CallOptions) {
+		o.ResponseSchema = schema
+	}
+}
+
+// WithMaxRepairAttempts will add an option to set how many times GenerateObject
+// asks the model to fix an invalid response.
+func WithMaxRepairAttempts(n int) CallOption {
+	return func(o *CallOptions) {
+		o.MaxRepairAttempts = n
+	}
+}
+
+// WithThinkingBudget enables the extended thinking of models supporting it,
+// letting them spend up to the given number of tokens reasoning before
+// responding. The reasoning is returned in ContentChoice.ReasoningContent.
+// Providers may reject options incompatible with extended thinking, e.g.
+// Anthropic rejects a temperature other than 1.
+func WithThinkingBudget(tokens int) CallOption {
+	return func(o *CallOptions) {
+		o.ThinkingBudget = tokens
+	}
+}
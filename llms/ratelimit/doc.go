@@ -0,0 +1,4 @@
+// Package ratelimit provides a token-bucket limiter and a wrapper that
+// throttles the calls made to a `llms.Model` to a number of requests and
+// tokens per minute, matching the limits providers enforce.
+package ratelimit
@@ -0,0 +1,129 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter is a token-bucket rate limiter for requests per minute and tokens
+// per minute. Both buckets start full and refill continuously. The zero value
+// of a limit means unlimited.
+type Limiter struct {
+	mu       sync.Mutex
+	requests bucket
+	tokens   bucket
+	now      func() time.Time
+}
+
+// NewLimiter creates a Limiter allowing requestsPerMinute requests and
+// tokensPerMinute tokens per minute. A limit of zero disables that bucket.
+func NewLimiter(requestsPerMinute, tokensPerMinute int) *Limiter {
+	l := &Limiter{now: time.Now}
+	now := l.now()
+	l.requests = newBucket(requestsPerMinute, now)
+	l.tokens = newBucket(tokensPerMinute, now)
+	return l
+}
+
+// Wait blocks until a request consuming the given number of tokens is
+// allowed, or the context is done. Requests estimated to use more tokens than
+// the per-minute limit wait for a full bucket rather than forever.
+func (l *Limiter) Wait(ctx context.Context, tokens int) error {
+	for {
+		delay := l.reserve(tokens)
+		if delay == 0 {
+			return nil
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Adjust corrects the number of tokens consumed once the actual usage of a
+// request is known. A positive delta consumes more tokens, possibly putting
+// the bucket into debt that later requests wait for; a negative delta returns
+// tokens to the bucket.
+func (l *Limiter) Adjust(delta int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens.refill(l.now())
+	l.tokens.take(float64(delta))
+}
+
+// reserve takes a request and the tokens from the buckets if both allow it
+// and returns zero, or returns how long to wait before trying again.
+func (l *Limiter) reserve(tokens int) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.requests.refill(now)
+	l.tokens.refill(now)
+
+	want := float64(tokens)
+	if l.tokens.limited() && want > l.tokens.capacity {
+		want = l.tokens.capacity
+	}
+	delay := max(l.requests.wait(1), l.tokens.wait(want))
+	if delay > 0 {
+		return delay
+	}
+	l.requests.take(1)
+	l.tokens.take(float64(tokens))
+	return 0
+}
+
+type bucket struct {
+	capacity  float64
+	available float64
+	perSecond float64
+	last      time.Time
+}
+
+func newBucket(perMinute int, now time.Time) bucket {
+	if perMinute <= 0 {
+		return bucket{}
+	}
+	return bucket{
+		capacity:  float64(perMinute),
+		available: float64(perMinute),
+		perSecond: float64(perMinute) / 60,
+		last:      now,
+	}
+}
+
+func (b *bucket) limited() bool {
+	return b.capacity > 0
+}
+
+func (b *bucket) refill(now time.Time) {
+	if !b.limited() {
+		return
+	}
+	elapsed := now.Sub(b.last).Seconds()
+	b.last = now
+	if elapsed > 0 {
+		b.available = min(b.capacity, b.available+elapsed*b.perSecond)
+	}
+}
+
+func (b *bucket) take(n float64) {
+	if b.limited() {
+		b.available = min(b.capacity, b.available-n)
+	}
+}
+
+// wait returns how long until n units are available.
+func (b *bucket) wait(n float64) time.Duration {
+	if !b.limited() || b.available >= n {
+		return 0
+	}
+	seconds := (n - b.available) / b.perSecond
+	return max(time.Duration(seconds*float64(time.Second)), time.Millisecond)
+}
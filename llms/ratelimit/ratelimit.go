@@ -0,0 +1,91 @@
+package ratelimit
+
+import (
+	"context"
+	"unicode/utf8"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// charsPerToken is the rough number of characters per token used to estimate
+// the size of a request before it is sent.
+const charsPerToken = 4
+
+// Model is an LLM wrapper that waits for the limiter before each call to the
+// LLM.
+type Model struct {
+	llm     llms.Model
+	limiter *Limiter
+}
+
+// assert that `Model` implements the `llms.Model` interface.
+var _ llms.Model = (*Model)(nil)
+
+// New wraps a Model and throttles its calls with the given limiter. A limiter
+// may be shared between several models that draw from the same quota.
+func New(llm llms.Model, limiter *Limiter) *Model {
+	return &Model{
+		llm:     llm,
+		limiter: limiter,
+	}
+}
+
+// Call is a simplified interface for a text-only Model, generating a single
+// string response from a single string prompt.
+//
+// Deprecated: this method is retained for backwards compatibility. Use the
+// more general [GenerateContent] instead. You can also use
+// the [GenerateFromSinglePrompt] function which provides a similar capability
+// to Call and is built on top of the new interface.
+func (m *Model) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+// GenerateContent asks the model to generate content from a sequence of
+// messages. It's the most general interface for multi-modal LLMs that support
+// chat-like interactions.
+//
+// The number of tokens a call consumes is estimated up front from the size of
+// the messages and the MaxTokens option, and corrected with the usage
+// reported in the response when the provider reports it.
+func (m *Model) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	var opts llms.CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	estimate := estimateTokens(messages, opts)
+	if err := m.limiter.Wait(ctx, estimate); err != nil {
+		return nil, err
+	}
+
+	response, err := m.llm.GenerateContent(ctx, messages, options...)
+	if err != nil {
+		return nil, err
+	}
+	if response.Usage != nil && response.Usage.TotalTokens > 0 {
+		m.limiter.Adjust(response.Usage.TotalTokens - estimate)
+	}
+	return response, nil
+}
+
+func estimateTokens(messages []llms.MessageContent, opts llms.CallOptions) int {
+	chars := 0
+	for _, msg := range messages {
+		for _, part := range msg.Parts {
+			switch p := part.(type) {
+			case llms.TextContent:
+				chars += utf8.RuneCountInString(p.Text)
+			case llms.ToolCall:
+				if p.FunctionCall != nil {
+					chars += utf8.RuneCountInString(p.FunctionCall.Arguments)
+				}
+			case llms.ToolCallResponse:
+				chars += utf8.RuneCountInString(p.Content)
+			case llms.ThinkingContent:
+				chars += utf8.RuneCountInString(p.Thinking) + utf8.RuneCountInString(p.RedactedData)
+			}
+		}
+	}
+	return chars/charsPerToken + opts.MaxTokens
+}
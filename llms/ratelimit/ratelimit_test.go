@@ -0,0 +1,91 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+type mockLLM struct {
+	usage *llms.Usage
+}
+
+func (m *mockLLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func (m *mockLLM) GenerateContent(_ context.Context, _ []llms.MessageContent, _ ...llms.CallOption) (*llms.ContentResponse, error) {
+	return &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{Content: "ok"}},
+		Usage:   m.usage,
+	}, nil
+}
+
+func newTestLimiter(rpm, tpm int) (*Limiter, *time.Time) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewLimiter(rpm, tpm)
+	l.now = func() time.Time { return now }
+	l.requests.last = now
+	l.tokens.last = now
+	return l, &now
+}
+
+func TestLimiter_Requests(t *testing.T) {
+	t.Parallel()
+
+	l, now := newTestLimiter(60, 0)
+	for range 60 {
+		require.Zero(t, l.reserve(1000))
+	}
+	require.Equal(t, time.Second, l.reserve(1000))
+
+	*now = now.Add(time.Second)
+	require.Zero(t, l.reserve(1000))
+}
+
+func TestLimiter_Tokens(t *testing.T) {
+	t.Parallel()
+
+	l, now := newTestLimiter(0, 600)
+	require.Zero(t, l.reserve(500))
+	require.Equal(t, 40*time.Second, l.reserve(500))
+
+	// Requests larger than the limit wait for a full bucket.
+	*now = now.Add(40 * time.Second)
+	require.Equal(t, 10*time.Second, l.reserve(1000))
+	*now = now.Add(10 * time.Second)
+	require.Zero(t, l.reserve(1000))
+
+	// The bucket is in debt and refills from below zero.
+	require.Equal(t, 100*time.Second, l.reserve(600))
+
+	l.Adjust(-400)
+	require.Equal(t, 60*time.Second, l.reserve(600))
+}
+
+func TestLimiter_Wait(t *testing.T) {
+	t.Parallel()
+
+	l := NewLimiter(1, 0)
+	require.NoError(t, l.Wait(context.Background(), 0))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, l.Wait(ctx, 0), context.DeadlineExceeded)
+}
+
+func TestModel(t *testing.T) {
+	t.Parallel()
+
+	l, _ := newTestLimiter(0, 1000)
+	llm := New(&mockLLM{usage: llms.NewUsage(100, 50)}, l)
+
+	_, err := llm.Call(context.Background(), "hello world!", llms.WithMaxTokens(200))
+	require.NoError(t, err)
+	// 3 tokens estimated for the prompt plus 200 for the completion are
+	// replaced by the 150 tokens reported.
+	require.InDelta(t, 850, l.tokens.available, 0.001)
+}
@@ -0,0 +1,92 @@
+package recorder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+// Embedder is an `embeddings.Embedder` recording the calls of an embedder,
+// or replaying them.
+type Embedder struct {
+	embedder embeddings.Embedder
+	file     *goldenFile
+}
+
+var _ embeddings.Embedder = (*Embedder)(nil)
+
+// embedRequest is the request of an EmbedDocuments or EmbedQuery call.
+type embedRequest struct {
+	Documents []string `json:"documents,omitempty"`
+	Query     *string  `json:"query,omitempty"`
+}
+
+// NewEmbedder returns an `embeddings.Embedder` recording the calls of
+// embedder to the golden file at path, or replaying them from it, depending
+// on the mode. embedder may be nil in ModeReplay.
+func NewEmbedder(path string, embedder embeddings.Embedder, opts ...Option) (*Embedder, error) {
+	options := applyOptions(opts...)
+	if options.Mode == ModeRecord && embedder == nil {
+		return nil, errors.New("recorder needs an embedder to record")
+	}
+	file, err := openGoldenFile(path, options.Mode)
+	if err != nil {
+		return nil, err
+	}
+	return &Embedder{embedder: embedder, file: file}, nil
+}
+
+// EmbedDocuments records the call of the embedder, or replays it.
+func (e *Embedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
+	var vectors [][]float32
+	err := e.do(embedRequest{Documents: texts}, &vectors, func() (any, error) {
+		return e.embedder.EmbedDocuments(ctx, texts)
+	})
+	return vectors, err
+}
+
+// EmbedQuery records the call of the embedder, or replays it.
+func (e *Embedder) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
+	var vector []float32
+	err := e.do(embedRequest{Query: &text}, &vector, func() (any, error) {
+		return e.embedder.EmbedQuery(ctx, text)
+	})
+	return vector, err
+}
+
+// do records the call of the embedder, or replays it, decoding its response
+// into result.
+func (e *Embedder) do(request embedRequest, result any, call func() (any, error)) error {
+	key, data, err := requestKey(request)
+	if err != nil {
+		return err
+	}
+
+	interaction := Interaction{Key: key, Request: data}
+	if e.file.mode == ModeReplay {
+		if interaction, err = e.file.replay(key); err != nil {
+			return err
+		}
+	} else {
+		response, err := call()
+		if err != nil {
+			interaction.Error = err.Error()
+		} else if interaction.Response, err = json.Marshal(response); err != nil {
+			return fmt.Errorf("encode response: %w", err)
+		}
+		if err := e.file.record(interaction); err != nil {
+			return err
+		}
+	}
+
+	if interaction.Error != "" {
+		return errors.New(interaction.Error)
+	}
+	if err := json.Unmarshal(interaction.Response, result); err != nil {
+		return fmt.Errorf("decode recorded response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package recorder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+const (
+	funcStreaming = "streaming"
+	funcReasoning = "reasoning"
+	funcEvent     = "event"
+)
+
+// LLM is an `llms.Model` recording the calls of a model, or replaying them.
+type LLM struct {
+	llm  llms.Model
+	file *goldenFile
+}
+
+var _ llms.Model = (*LLM)(nil)
+
+// generateRequest is the request of a GenerateContent call.
+type generateRequest struct {
+	Messages []llms.MessageContent `json:"messages"`
+	Options  llms.CallOptions      `json:"options"`
+	// Streaming are the streaming functions of the call, as models stream
+	// differently depending on them.
+	Streaming []string `json:"streaming,omitempty"`
+}
+
+// NewLLM returns an `llms.Model` recording the calls of llm to the golden
+// file at path, or replaying them from it, depending on the mode. llm may be
+// nil in ModeReplay.
+func NewLLM(path string, llm llms.Model, opts ...Option) (*LLM, error) {
+	options := applyOptions(opts...)
+	if options.Mode == ModeRecord && llm == nil {
+		return nil, errors.New("recorder needs a model to record")
+	}
+	file, err := openGoldenFile(path, options.Mode)
+	if err != nil {
+		return nil, err
+	}
+	return &LLM{llm: llm, file: file}, nil
+}
+
+// Call implements the deprecated `llms.Model.Call` method.
+func (l *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, l, prompt, options...)
+}
+
+// GenerateContent records the call of the model, or replays it.
+func (l *LLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint:lll
+	var opts llms.CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+	request := generateRequest{Messages: messages, Options: opts}
+	if opts.StreamingFunc != nil {
+		request.Streaming = append(request.Streaming, funcStreaming)
+	}
+	if opts.StreamingReasoningFunc != nil {
+		request.Streaming = append(request.Streaming, funcReasoning)
+	}
+	if opts.StreamingEventFunc != nil {
+		request.Streaming = append(request.Streaming, funcEvent)
+	}
+	key, data, err := requestKey(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if l.file.mode == ModeReplay {
+		interaction, err := l.file.replay(key)
+		if err != nil {
+			return nil, err
+		}
+		return replayGenerate(ctx, opts, interaction)
+	}
+
+	interaction := Interaction{Key: key, Request: data}
+	response, err := l.llm.GenerateContent(ctx, messages, append(options, recordChunks(opts, &interaction)...)...)
+	if err != nil {
+		interaction.Error = err.Error()
+	} else if interaction.Response, err = json.Marshal(response); err != nil {
+		return nil, fmt.Errorf("encode response: %w", err)
+	}
+	if recordErr := l.file.record(interaction); recordErr != nil {
+		return nil, recordErr
+	}
+	return response, err
+}
+
+// recordChunks returns the call options wrapping the streaming functions of
+// opts, adding their calls to the chunks of the interaction.
+func recordChunks(opts llms.CallOptions, interaction *Interaction) []llms.CallOption {
+	var options []llms.CallOption
+	if f := opts.StreamingFunc; f != nil {
+		options = append(options, llms.WithStreamingFunc(func(ctx context.Context, chunk []byte) error {
+			interaction.Chunks = append(interaction.Chunks, Chunk{Func: funcStreaming, Chunk: string(chunk)})
+			return f(ctx, chunk)
+		}))
+	}
+	if f := opts.StreamingReasoningFunc; f != nil {
+		options = append(options, llms.WithStreamingReasoningFunc(func(ctx context.Context, reasoningChunk, chunk []byte) error { //nolint:lll
+			interaction.Chunks = append(interaction.Chunks, Chunk{
+				Func:           funcReasoning,
+				Chunk:          string(chunk),
+				ReasoningChunk: string(reasoningChunk),
+			})
+			return f(ctx, reasoningChunk, chunk)
+		}))
+	}
+	if f := opts.StreamingEventFunc; f != nil {
+		options = append(options, llms.WithStreamingEventFunc(func(ctx context.Context, event llms.StreamEvent) error {
+			interaction.Chunks = append(interaction.Chunks, Chunk{Func: funcEvent, Event: &event})
+			return f(ctx, event)
+		}))
+	}
+	return options
+}
+
+// replayGenerate streams the chunks of a recorded call and returns its
+// response or its error.
+func replayGenerate(ctx context.Context, opts llms.CallOptions, interaction Interaction) (*llms.ContentResponse, error) {
+	for _, chunk := range interaction.Chunks {
+		var err error
+		switch chunk.Func {
+		case funcStreaming:
+			err = opts.StreamingFunc(ctx, []byte(chunk.Chunk))
+		case funcReasoning:
+			err = opts.StreamingReasoningFunc(ctx, []byte(chunk.ReasoningChunk), []byte(chunk.Chunk))
+		case funcEvent:
+			if chunk.Event != nil {
+				err = opts.StreamingEventFunc(ctx, *chunk.Event)
+			}
+		default:
+			err = fmt.Errorf("unknown streaming function %q in recording", chunk.Func)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if interaction.Error != "" {
+		return nil, errors.New(interaction.Error)
+	}
+	var response llms.ContentResponse
+	if err := json.Unmarshal(interaction.Response, &response); err != nil {
+		return nil, fmt.Errorf("decode recorded response: %w", err)
+	}
+	return &response, nil
+}
@@ -0,0 +1,25 @@
+package recorder
+
+// Option is a functional argument that configures the Options.
+type Option func(*Options)
+
+// Options is a set of options for the recorders.
+type Options struct {
+	// Mode is whether calls are recorded or replayed, ModeReplay by default.
+	Mode Mode
+}
+
+// WithMode specifies whether calls are recorded or replayed.
+func WithMode(mode Mode) Option {
+	return func(o *Options) {
+		o.Mode = mode
+	}
+}
+
+func applyOptions(opts ...Option) Options {
+	var o Options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
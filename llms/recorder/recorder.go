@@ -0,0 +1,197 @@
+// Package recorder provides test doubles recording the calls of an
+// `llms.Model` or an `embeddings.Embedder` to a golden file, and replaying
+// them offline.
+//
+// In ModeRecord, calls are forwarded to the wrapped model, and their
+// requests and responses, streamed chunks and tool calls included, are
+// written to the file. In ModeReplay, the default, calls are served from the
+// file without the wrapped model, which may be nil, and calls that weren't
+// recorded fail with ErrNoRecording. This makes tests of chains and agents
+// deterministic:
+//
+//	mode := recorder.ModeReplay
+//	if *update {
+//		mode = recorder.ModeRecord
+//	}
+//	llm, err := recorder.NewLLM("testdata/agent.json", openaiLLM, recorder.WithMode(mode))
+package recorder
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// ErrNoRecording is returned in ModeReplay by calls whose request isn't in
+// the golden file.
+var ErrNoRecording = errors.New("no recording of request")
+
+// Mode is whether calls are recorded or replayed.
+type Mode int
+
+const (
+	// ModeReplay serves calls from the golden file.
+	ModeReplay Mode = iota
+	// ModeRecord forwards calls to the wrapped model and writes them to the
+	// golden file, replacing its previous recordings.
+	ModeRecord
+)
+
+// String returns the name of the mode.
+func (m Mode) String() string {
+	switch m {
+	case ModeReplay:
+		return "replay"
+	case ModeRecord:
+		return "record"
+	default:
+		return fmt.Sprintf("Mode(%d)", int(m))
+	}
+}
+
+// Interaction is a recorded call.
+type Interaction struct {
+	// Key is the hash of the request.
+	Key string `json:"key"`
+	// Request is the request of the call, kept to make the file readable
+	// and its diffs reviewable.
+	Request json.RawMessage `json:"request"`
+	// Response is the response of the call, if it succeeded.
+	Response json.RawMessage `json:"response,omitempty"`
+	// Chunks are the streamed chunks of the response.
+	Chunks []Chunk `json:"chunks,omitempty"`
+	// Error is the message of the error of the call, if it failed.
+	Error string `json:"error,omitempty"`
+}
+
+// Chunk is a streamed chunk of a recorded response: a call of one of the
+// streaming functions of the call options, replayed as recorded.
+type Chunk struct {
+	// Func is the called function: "streaming" for StreamingFunc,
+	// "reasoning" for StreamingReasoningFunc and "event" for
+	// StreamingEventFunc.
+	Func string `json:"func"`
+	// Chunk is the chunk argument of StreamingFunc and
+	// StreamingReasoningFunc.
+	Chunk string `json:"chunk,omitempty"`
+	// ReasoningChunk is the reasoning chunk argument of
+	// StreamingReasoningFunc.
+	ReasoningChunk string `json:"reasoning_chunk,omitempty"`
+	// Event is the event argument of StreamingEventFunc.
+	Event *llms.StreamEvent `json:"event,omitempty"`
+}
+
+// goldenFile is the file of the recorded calls of a model.
+type goldenFile struct {
+	path string
+	mode Mode
+
+	mu           sync.Mutex
+	interactions []Interaction
+	// replayed is the number of replayed interactions of each key.
+	replayed map[string]int
+}
+
+type goldenFileJSON struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+func openGoldenFile(path string, mode Mode) (*goldenFile, error) {
+	f := &goldenFile{path: path, mode: mode, replayed: map[string]int{}}
+	switch mode {
+	case ModeReplay:
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read recording: %w", err)
+		}
+		var file goldenFileJSON
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("decode recording %s: %w", path, err)
+		}
+		f.interactions = file.Interactions
+	case ModeRecord:
+		// The recordings are replaced by the calls of this run.
+		f.interactions = []Interaction{}
+		if err := f.save(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown recorder mode %v", mode)
+	}
+	return f, nil
+}
+
+// requestKey returns the key of a request: the hash of its JSON encoding.
+func requestKey(request any) (string, json.RawMessage, error) {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return "", nil, fmt.Errorf("encode request: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), data, nil
+}
+
+// replay returns the recorded interaction of a request. Identical requests
+// are served their recordings in order, the last one being repeated.
+func (f *goldenFile) replay(key string) (Interaction, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var matches []Interaction
+	for _, interaction := range f.interactions {
+		if interaction.Key == key {
+			matches = append(matches, interaction)
+		}
+	}
+	if len(matches) == 0 {
+		return Interaction{}, fmt.Errorf("%w %s in %s, record it again", ErrNoRecording, key, f.path)
+	}
+	i := min(f.replayed[key], len(matches)-1)
+	f.replayed[key]++
+	return matches[i], nil
+}
+
+// record adds an interaction to the file.
+func (f *goldenFile) record(interaction Interaction) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.interactions = append(f.interactions, interaction)
+	return f.save()
+}
+
+// save writes the file. The file is replaced atomically, so that it's never
+// left half written.
+func (f *goldenFile) save() error {
+	data, err := json.MarshalIndent(goldenFileJSON{Interactions: f.interactions}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode recording: %w", err)
+	}
+	dir := filepath.Dir(f.path)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("create recording directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".*.tmp")
+	if err != nil {
+		return fmt.Errorf("write recording: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write recording: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write recording: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), f.path); err != nil {
+		return fmt.Errorf("write recording: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package recorder
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// streamingModel streams its responses, numbered by call, and calls a tool.
+type streamingModel struct {
+	calls int
+}
+
+func (m *streamingModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func (m *streamingModel) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint:lll
+	var opts llms.CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+	m.calls++
+	if len(messages) == 0 {
+		return nil, errors.New("no messages")
+	}
+
+	content := []string{"hello ", "world"}
+	if m.calls > 1 {
+		content = []string{"hello ", "again"}
+	}
+	for _, chunk := range content {
+		if opts.StreamingEventFunc != nil {
+			if err := opts.StreamingEventFunc(ctx, llms.StreamEvent{Type: llms.StreamEventText, Text: chunk}); err != nil {
+				return nil, err
+			}
+		} else if opts.StreamingFunc != nil {
+			if err := opts.StreamingFunc(ctx, []byte(chunk)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	call := llms.ToolCall{
+		ID:           "call_1",
+		Type:         "function",
+		FunctionCall: &llms.FunctionCall{Name: "search", Arguments: `{"query":"go"}`},
+	}
+	if opts.StreamingEventFunc != nil {
+		event := llms.StreamEvent{Type: llms.StreamEventToolCall, ToolCall: &llms.ToolCallDelta{
+			ID: call.ID, Name: call.FunctionCall.Name, Arguments: call.FunctionCall.Arguments,
+		}}
+		if err := opts.StreamingEventFunc(ctx, event); err != nil {
+			return nil, err
+		}
+	}
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{
+		Content:    content[0] + content[1],
+		StopReason: "tool_calls",
+		ToolCalls:  []llms.ToolCall{call},
+	}}}, nil
+}
+
+func TestLLMRecordReplay(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "llm.json")
+	messages := []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "hi")}
+	tools := llms.WithTools([]llms.Tool{{Type: "function", Function: &llms.FunctionDefinition{Name: "search"}}})
+
+	type result struct {
+		response *llms.ContentResponse
+		chunks   []string
+		events   []llms.StreamEvent
+	}
+	run := func(llm llms.Model) []result {
+		var results []result
+		for range 3 {
+			var r result
+			resp, err := llm.GenerateContent(ctx, messages, tools,
+				llms.WithStreamingFunc(func(_ context.Context, chunk []byte) error {
+					r.chunks = append(r.chunks, string(chunk))
+					return nil
+				}))
+			require.NoError(t, err)
+			r.response = resp
+			results = append(results, r)
+		}
+		var r result
+		resp, err := llm.GenerateContent(ctx, messages, tools,
+			llms.WithStreamingEventFunc(func(_ context.Context, event llms.StreamEvent) error {
+				r.events = append(r.events, event)
+				return nil
+			}))
+		require.NoError(t, err)
+		r.response = resp
+		return append(results, r)
+	}
+
+	recorder, err := NewLLM(path, &streamingModel{}, WithMode(ModeRecord))
+	require.NoError(t, err)
+	recorded := run(recorder)
+	require.Equal(t, []string{"hello ", "world"}, recorded[0].chunks)
+	require.Equal(t, []string{"hello ", "again"}, recorded[1].chunks)
+	require.Len(t, recorded[3].events, 3)
+
+	replayer, err := NewLLM(path, nil)
+	require.NoError(t, err)
+	require.Equal(t, recorded, run(replayer))
+
+	_, err = replayer.GenerateContent(ctx, messages)
+	require.ErrorIs(t, err, ErrNoRecording)
+}
+
+func TestLLMReplayError(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "llm.json")
+
+	recorder, err := NewLLM(path, &streamingModel{}, WithMode(ModeRecord))
+	require.NoError(t, err)
+	_, err = recorder.GenerateContent(ctx, nil)
+	require.EqualError(t, err, "no messages")
+
+	replayer, err := NewLLM(path, nil)
+	require.NoError(t, err)
+	_, err = replayer.GenerateContent(ctx, nil)
+	require.EqualError(t, err, "no messages")
+}
+
+func TestLLMReplayCacheControl(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "llm.json")
+	messages := []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "hi")}
+
+	recorder, err := NewLLM(path, &streamingModel{}, WithMode(ModeRecord))
+	require.NoError(t, err)
+	_, err = recorder.GenerateContent(ctx, messages)
+	require.NoError(t, err)
+
+	// A cache breakpoint changes the request sent to the provider.
+	replayer, err := NewLLM(path, nil)
+	require.NoError(t, err)
+	_, err = replayer.GenerateContent(ctx, []llms.MessageContent{{Role: llms.ChatMessageTypeHuman, Parts: []llms.ContentPart{
+		llms.WithCacheControl(llms.TextPart("hi"), llms.CacheControl{Type: "ephemeral"}),
+	}}})
+	require.ErrorIs(t, err, ErrNoRecording)
+	_, err = replayer.GenerateContent(ctx, messages)
+	require.NoError(t, err)
+}
+
+func TestNewLLMMissingRecording(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewLLM(filepath.Join(t.TempDir(), "missing.json"), nil)
+	require.Error(t, err)
+	_, err = NewLLM(filepath.Join(t.TempDir(), "llm.json"), nil, WithMode(ModeRecord))
+	require.Error(t, err)
+}
+
+type countingEmbedder struct {
+	calls int
+}
+
+func (e *countingEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(texts))
+	for _, text := range texts {
+		vector, err := e.EmbedQuery(ctx, text)
+		if err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, vector)
+	}
+	return vectors, nil
+}
+
+func (e *countingEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	e.calls++
+	return []float32{float32(len(text)), 0.1, float32(e.calls)}, nil
+}
+
+func TestEmbedderRecordReplay(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "embedder.json")
+
+	embedder := &countingEmbedder{}
+	recorder, err := NewEmbedder(path, embedder, WithMode(ModeRecord))
+	require.NoError(t, err)
+	documents, err := recorder.EmbedDocuments(ctx, []string{"a", "bb"})
+	require.NoError(t, err)
+	query, err := recorder.EmbedQuery(ctx, "ccc")
+	require.NoError(t, err)
+	require.Equal(t, [][]float32{{1, 0.1, 1}, {2, 0.1, 2}}, documents)
+	require.Equal(t, []float32{3, 0.1, 3}, query)
+
+	replayer, err := NewEmbedder(path, nil)
+	require.NoError(t, err)
+	replayedDocuments, err := replayer.EmbedDocuments(ctx, []string{"a", "bb"})
+	require.NoError(t, err)
+	replayedQuery, err := replayer.EmbedQuery(ctx, "ccc")
+	require.NoError(t, err)
+	require.Equal(t, documents, replayedDocuments)
+	require.Equal(t, query, replayedQuery)
+	require.Equal(t, 3, embedder.calls)
+
+	// A query isn't a document.
+	_, err = replayer.EmbedDocuments(ctx, []string{"ccc"})
+	require.ErrorIs(t, err, ErrNoRecording)
+}
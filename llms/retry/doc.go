@@ -0,0 +1,5 @@
+// Package retry provides a wrapper that retries failed calls to a `llms.Model`
+// with exponential backoff and jitter. Errors are classified with
+// `llms.IsRetryable`, and delays requested by the provider through the
+// Retry-After header are honoured.
+package retry
@@ -0,0 +1,181 @@
+package retry
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+const (
+	defaultMaxRetries     = 3
+	defaultInitialBackoff = 500 * time.Millisecond
+	defaultMaxBackoff     = 30 * time.Second
+	defaultMultiplier     = 2.0
+)
+
+// Retrier is an LLM wrapper that retries calls to the LLM that fail with a
+// transient error.
+type Retrier struct {
+	llm            llms.Model
+	maxRetries     int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	multiplier     float64
+	jitter         bool
+	shouldRetry    func(error) bool
+	onRetry        func(attempt int, delay time.Duration, err error)
+	sleep          func(ctx context.Context, d time.Duration) error
+}
+
+// assert that `Retrier` implements the `llms.Model` interface.
+var _ llms.Model = (*Retrier)(nil)
+
+// Option is a functional argument that configures the Retrier.
+type Option func(*Retrier)
+
+// WithMaxRetries sets the maximum number of retries after the first attempt.
+// The default is 3; zero disables retries.
+func WithMaxRetries(n int) Option {
+	return func(r *Retrier) {
+		r.maxRetries = n
+	}
+}
+
+// WithBackoff sets the delay before the first retry and the maximum delay
+// between retries, which also caps the delays requested by the provider, e.g.
+// with a Retry-After header. The defaults are 500ms and 30s.
+func WithBackoff(initial, maxBackoff time.Duration) Option {
+	return func(r *Retrier) {
+		r.initialBackoff = initial
+		r.maxBackoff = maxBackoff
+	}
+}
+
+// WithMultiplier sets the factor the delay grows by after each retry. The
+// default is 2.
+func WithMultiplier(multiplier float64) Option {
+	return func(r *Retrier) {
+		r.multiplier = multiplier
+	}
+}
+
+// WithJitter enables or disables full jitter, which picks each delay at
+// random between zero and the computed backoff. Jitter is enabled by default.
+func WithJitter(jitter bool) Option {
+	return func(r *Retrier) {
+		r.jitter = jitter
+	}
+}
+
+// WithRetryIf sets the function that decides whether a failed call should be
+// retried. The default is `llms.IsRetryable`.
+func WithRetryIf(shouldRetry func(error) bool) Option {
+	return func(r *Retrier) {
+		r.shouldRetry = shouldRetry
+	}
+}
+
+// WithOnRetry sets a function that is called before each retry with the
+// attempt number (starting at 1), the delay before the retry and the error
+// that caused it.
+func WithOnRetry(onRetry func(attempt int, delay time.Duration, err error)) Option {
+	return func(r *Retrier) {
+		r.onRetry = onRetry
+	}
+}
+
+// New wraps a Model and retries calls that fail with a transient error.
+func New(llm llms.Model, opts ...Option) *Retrier {
+	r := &Retrier{
+		llm:            llm,
+		maxRetries:     defaultMaxRetries,
+		initialBackoff: defaultInitialBackoff,
+		maxBackoff:     defaultMaxBackoff,
+		multiplier:     defaultMultiplier,
+		jitter:         true,
+		shouldRetry:    llms.IsRetryable,
+		sleep:          sleep,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Call is a simplified interface for a text-only Model, generating a single
+// string response from a single string prompt.
+//
+// Deprecated: this method is retained for backwards compatibility. Use the
+// more general [GenerateContent] instead. You can also use
+// the [GenerateFromSinglePrompt] function which provides a similar capability
+// to Call and is built on top of the new interface.
+func (r *Retrier) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, r, prompt, options...)
+}
+
+// GenerateContent asks the model to generate content from a sequence of
+// messages. It's the most general interface for multi-modal LLMs that support
+// chat-like interactions.
+//
+// Failed calls are retried while the error is retryable, the retry budget is
+// not exhausted and the context is not done. A streaming call is not retried
+// once output has been delivered to one of its streaming functions, e.g. by
+// StreamContent, since the caller would otherwise see the output twice.
+func (r *Retrier) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	options, streamed := llms.TrackStreaming(options)
+
+	backoff := r.initialBackoff
+	for attempt := 0; ; attempt++ {
+		response, err := r.llm.GenerateContent(ctx, messages, options...)
+		if err == nil {
+			return response, nil
+		}
+		if attempt >= r.maxRetries || streamed() || ctx.Err() != nil || !r.shouldRetry(err) {
+			return nil, err
+		}
+
+		delay := r.delay(backoff, err)
+		if r.onRetry != nil {
+			r.onRetry(attempt+1, delay, err)
+		}
+		if err := r.sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+		backoff = time.Duration(float64(backoff) * r.multiplier)
+		if backoff > r.maxBackoff {
+			backoff = r.maxBackoff
+		}
+	}
+}
+
+// delay returns how long to wait before the next attempt. A delay requested
+// by the provider takes precedence over the computed backoff, up to the
+// maximum backoff.
+func (r *Retrier) delay(backoff time.Duration, err error) time.Duration {
+	if d, ok := llms.RetryAfter(err); ok {
+		return min(d, r.maxBackoff)
+	}
+	if backoff > r.maxBackoff {
+		backoff = r.maxBackoff
+	}
+	if r.jitter && backoff > 0 {
+		return time.Duration(rand.Int63n(int64(backoff) + 1)) //nolint:gosec
+	}
+	return backoff
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,200 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// not synchronized, don't use concurrently!
+type mockLLM struct {
+	errs      []error
+	chunks    []string
+	reasoning []string
+	calls     int
+}
+
+func (m *mockLLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func (m *mockLLM) GenerateContent(ctx context.Context, _ []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	var opts llms.CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	m.calls++
+	for _, chunk := range m.chunks {
+		var err error
+		switch {
+		case opts.StreamingEventFunc != nil:
+			err = opts.StreamingEventFunc(ctx, llms.StreamEvent{Type: llms.StreamEventText, Text: chunk})
+		case opts.StreamingFunc != nil:
+			err = opts.StreamingFunc(ctx, []byte(chunk))
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if opts.StreamingReasoningFunc != nil {
+		for _, chunk := range m.reasoning {
+			if err := opts.StreamingReasoningFunc(ctx, []byte(chunk), nil); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(m.errs) > 0 {
+		err := m.errs[0]
+		m.errs = m.errs[1:]
+		return nil, err
+	}
+	return &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{Content: "ok"}},
+	}, nil
+}
+
+func newTestRetrier(llm llms.Model, opts ...Option) (*Retrier, *[]time.Duration) {
+	var delays []time.Duration
+	r := New(llm, opts...)
+	r.sleep = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return ctx.Err()
+	}
+	return r, &delays
+}
+
+func TestRetrier(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	rq := require.New(t)
+
+	llm := &mockLLM{errs: []error{
+		&llms.APIError{StatusCode: http.StatusTooManyRequests},
+		&llms.APIError{StatusCode: http.StatusServiceUnavailable, RetryAfter: 5 * time.Second},
+		&llms.APIError{StatusCode: http.StatusInternalServerError},
+		&llms.APIError{StatusCode: http.StatusInternalServerError},
+		&llms.APIError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Hour},
+	}}
+	r, delays := newTestRetrier(llm, WithJitter(false), WithBackoff(time.Second, 6*time.Second), WithMaxRetries(5))
+
+	out, err := r.Call(ctx, "hi")
+	rq.NoError(err)
+	rq.Equal("ok", out)
+	rq.Equal(6, llm.calls)
+	// The second delay comes from Retry-After; the backoff keeps growing
+	// underneath it and is capped at the maximum, as is Retry-After.
+	rq.Equal([]time.Duration{time.Second, 5 * time.Second, 4 * time.Second, 6 * time.Second, 6 * time.Second}, *delays)
+}
+
+func TestRetrier_Errors(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("not retryable", func(t *testing.T) {
+		t.Parallel()
+		llm := &mockLLM{errs: []error{&llms.APIError{StatusCode: http.StatusBadRequest}}}
+		r, delays := newTestRetrier(llm)
+
+		_, err := r.Call(ctx, "hi")
+		require.Error(t, err)
+		require.Equal(t, 1, llm.calls)
+		require.Empty(t, *delays)
+	})
+
+	t.Run("retries exhausted", func(t *testing.T) {
+		t.Parallel()
+		apiErr := &llms.APIError{StatusCode: http.StatusTooManyRequests}
+		llm := &mockLLM{errs: []error{apiErr, apiErr, apiErr}}
+		r, _ := newTestRetrier(llm, WithMaxRetries(2))
+
+		_, err := r.Call(ctx, "hi")
+		require.ErrorIs(t, err, apiErr)
+		require.Equal(t, 3, llm.calls)
+	})
+
+	t.Run("custom predicate", func(t *testing.T) {
+		t.Parallel()
+		errFlaky := errors.New("flaky")
+		llm := &mockLLM{errs: []error{errFlaky}}
+		var attempts []int
+		r, _ := newTestRetrier(llm,
+			WithRetryIf(func(err error) bool { return errors.Is(err, errFlaky) }),
+			WithOnRetry(func(attempt int, _ time.Duration, _ error) { attempts = append(attempts, attempt) }),
+		)
+
+		_, err := r.Call(ctx, "hi")
+		require.NoError(t, err)
+		require.Equal(t, []int{1}, attempts)
+	})
+
+	t.Run("streamed", func(t *testing.T) {
+		t.Parallel()
+		llm := &mockLLM{
+			errs:   []error{&llms.APIError{StatusCode: http.StatusBadGateway}},
+			chunks: []string{"partial"},
+		}
+		r, _ := newTestRetrier(llm)
+
+		var got string
+		_, err := r.Call(ctx, "hi", llms.WithStreamingFunc(func(_ context.Context, chunk []byte) error {
+			got += string(chunk)
+			return nil
+		}))
+		require.Error(t, err)
+		require.Equal(t, 1, llm.calls)
+		require.Equal(t, "partial", got)
+	})
+
+	t.Run("streamed reasoning", func(t *testing.T) {
+		t.Parallel()
+		llm := &mockLLM{
+			errs:      []error{&llms.APIError{StatusCode: http.StatusBadGateway}},
+			reasoning: []string{"thinking"},
+		}
+		r, _ := newTestRetrier(llm)
+
+		_, err := r.Call(ctx, "hi",
+			llms.WithStreamingFunc(func(context.Context, []byte) error { return nil }),
+			llms.WithStreamingReasoningFunc(func(context.Context, []byte, []byte) error { return nil }))
+		require.Error(t, err)
+		require.Equal(t, 1, llm.calls)
+	})
+
+	t.Run("streamed events", func(t *testing.T) {
+		t.Parallel()
+		llm := &mockLLM{
+			errs:   []error{&llms.APIError{StatusCode: http.StatusBadGateway}},
+			chunks: []string{"partial"},
+		}
+		r, _ := newTestRetrier(llm)
+
+		var events []llms.StreamEvent
+		for event := range llms.StreamContent(ctx, r, []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "hi")}) {
+			events = append(events, event)
+		}
+		require.Len(t, events, 2)
+		require.Equal(t, "partial", events[0].Text)
+		require.Equal(t, llms.StreamEventError, events[1].Type)
+		require.Equal(t, 1, llm.calls)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		llm := &mockLLM{errs: []error{&llms.APIError{StatusCode: http.StatusTooManyRequests}}}
+		r := New(llm, WithBackoff(time.Hour, time.Hour))
+
+		_, err := r.Call(ctx, "hi")
+		require.Error(t, err)
+		require.Equal(t, 1, llm.calls)
+	})
+}
@@ -0,0 +1,6 @@
+// Package router provides a `llms.Model` that spreads calls over several
+// backend models and fails over to the next backend when one returns an
+// error or times out. Backends are tried in order or picked at random by
+// weight, and each backend can rewrite the call options, for example to map
+// model names between providers.
+package router
@@ -0,0 +1,220 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/llms"
+)
+
+var (
+	// ErrNoBackends is returned by New when no backends are given.
+	ErrNoBackends = errors.New("router: no backends")
+	// ErrAllBackendsFailed is returned when every backend failed to serve a
+	// call. It wraps the errors returned by the backends.
+	ErrAllBackendsFailed = errors.New("router: all backends failed")
+)
+
+// Strategy decides the order in which backends are tried.
+type Strategy int
+
+const (
+	// Ordered tries the backends in the order they were given. The first
+	// backend serves every call while it is healthy.
+	Ordered Strategy = iota
+	// Weighted picks the first backend at random with a probability
+	// proportional to its weight, then falls back to the remaining backends
+	// picked the same way. Backends with a zero weight are only used as
+	// fallbacks, in the order they were given.
+	Weighted
+)
+
+// Backend is a model the router can send calls to.
+type Backend struct {
+	// Name identifies the backend in callbacks and errors.
+	Name string
+	// Model is the model serving the calls.
+	Model llms.Model
+	// Weight is the relative share of calls sent to the backend with the
+	// Weighted strategy.
+	Weight int
+	// Options are applied after the caller's options, so they can override
+	// or rewrite them for this backend. See MapModel.
+	Options []llms.CallOption
+	// Timeout limits how long a call to the backend may take before the
+	// router falls back to the next one. Zero means no limit.
+	Timeout time.Duration
+}
+
+// MapModel returns a call option that renames the model requested by the
+// caller according to mapping. Models missing from mapping are left as they
+// are. It is meant to be used in Backend.Options, e.g. to route a request for
+// "gpt-4o" to "claude-3-5-sonnet-latest" on an Anthropic backend.
+func MapModel(mapping map[string]string) llms.CallOption {
+	return func(o *llms.CallOptions) {
+		if model, ok := mapping[o.Model]; ok {
+			o.Model = model
+		}
+	}
+}
+
+// Router is an LLM that routes calls to one of several backend models and
+// falls back to the others on failure.
+type Router struct {
+	CallbacksHandler callbacks.Handler
+
+	backends   []Backend
+	strategy   Strategy
+	fallbackIf func(error) bool
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// assert that `Router` implements the `llms.Model` interface.
+var _ llms.Model = (*Router)(nil)
+
+// Option is a functional argument that configures the Router.
+type Option func(*Router)
+
+// WithStrategy sets the strategy used to order the backends. The default is
+// Ordered.
+func WithStrategy(strategy Strategy) Option {
+	return func(r *Router) {
+		r.strategy = strategy
+	}
+}
+
+// WithFallbackIf sets the function that decides whether a failed call should
+// be sent to the next backend. By default every error falls back, except the
+// cancellation or expiry of the caller's context.
+func WithFallbackIf(fallbackIf func(error) bool) Option {
+	return func(r *Router) {
+		r.fallbackIf = fallbackIf
+	}
+}
+
+// WithCallback sets the callbacks handler of the router. If it implements
+// callbacks.RouteHandler, it is told which backend served each call, and which
+// backends failed before it.
+func WithCallback(callbacksHandler callbacks.Handler) Option {
+	return func(r *Router) {
+		r.CallbacksHandler = callbacksHandler
+	}
+}
+
+// New creates a Router that sends calls to the given backends.
+func New(backends []Backend, opts ...Option) (*Router, error) {
+	if len(backends) == 0 {
+		return nil, ErrNoBackends
+	}
+	for i, b := range backends {
+		if b.Model == nil {
+			return nil, fmt.Errorf("router: backend %d (%q) has no model", i, b.Name)
+		}
+		if b.Weight < 0 {
+			return nil, fmt.Errorf("router: backend %d (%q) has a negative weight", i, b.Name)
+		}
+	}
+
+	r := &Router{
+		backends:   backends,
+		fallbackIf: func(error) bool { return true },
+		rand:       rand.New(rand.NewSource(time.Now().UnixNano())), //nolint:gosec
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r, nil
+}
+
+// Call is a simplified interface for a text-only Model, generating a single
+// string response from a single string prompt.
+//
+// Deprecated: this method is retained for backwards compatibility. Use the
+// more general [GenerateContent] instead. You can also use
+// the [GenerateFromSinglePrompt] function which provides a similar capability
+// to Call and is built on top of the new interface.
+func (r *Router) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, r, prompt, options...)
+}
+
+// GenerateContent asks the model to generate content from a sequence of
+// messages. It's the most general interface for multi-modal LLMs that support
+// chat-like interactions.
+//
+// The call is sent to the backends in the order given by the strategy until
+// one succeeds. A streaming call does not fall back once a backend has
+// delivered output to one of its streaming functions, e.g. by StreamContent,
+// since the caller would otherwise see output from two backends.
+func (r *Router) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	options, streamed := llms.TrackStreaming(options)
+	routeHandler, _ := r.CallbacksHandler.(callbacks.RouteHandler)
+
+	var errs []error
+	for _, b := range r.order() {
+		response, err := r.generate(ctx, b, messages, options)
+		if routeHandler != nil {
+			routeHandler.HandleLLMRoute(ctx, b.Name, err)
+		}
+		if err == nil {
+			return response, nil
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", b.Name, err))
+		if streamed() || ctx.Err() != nil || !r.fallbackIf(err) {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("%w: %w", ErrAllBackendsFailed, errors.Join(errs...))
+}
+
+func (r *Router) generate(ctx context.Context, b Backend, messages []llms.MessageContent, options []llms.CallOption) (*llms.ContentResponse, error) {
+	if b.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.Timeout)
+		defer cancel()
+	}
+	if len(b.Options) > 0 {
+		options = append(options[:len(options):len(options)], b.Options...)
+	}
+	return b.Model.GenerateContent(ctx, messages, options...)
+}
+
+// order returns the backends in the order they should be tried for a call.
+func (r *Router) order() []Backend {
+	if r.strategy != Weighted {
+		return r.backends
+	}
+
+	var weighted, fallbacks []Backend
+	total := 0
+	for _, b := range r.backends {
+		if b.Weight == 0 {
+			fallbacks = append(fallbacks, b)
+			continue
+		}
+		weighted = append(weighted, b)
+		total += b.Weight
+	}
+
+	ordered := make([]Backend, 0, len(r.backends))
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for len(weighted) > 0 {
+		n := r.rand.Intn(total)
+		i := 0
+		for ; n >= weighted[i].Weight; i++ {
+			n -= weighted[i].Weight
+		}
+		ordered = append(ordered, weighted[i])
+		total -= weighted[i].Weight
+		weighted = append(weighted[:i], weighted[i+1:]...)
+	}
+	return append(ordered, fallbacks...)
+}
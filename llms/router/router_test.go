@@ -0,0 +1,203 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// not synchronized, don't use concurrently!
+type mockLLM struct {
+	name   string
+	err    error
+	delay  time.Duration
+	models []string
+	// reasoning is streamed as reasoning, or as an event to the streaming
+	// event function.
+	reasoning string
+}
+
+func (m *mockLLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func (m *mockLLM) GenerateContent(ctx context.Context, _ []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	var opts llms.CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+	m.models = append(m.models, opts.Model)
+
+	if m.delay > 0 {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(m.delay):
+		}
+	}
+	if m.reasoning != "" {
+		var err error
+		switch {
+		case opts.StreamingEventFunc != nil:
+			err = opts.StreamingEventFunc(ctx, llms.StreamEvent{Type: llms.StreamEventReasoning, Text: m.reasoning})
+		case opts.StreamingReasoningFunc != nil:
+			err = opts.StreamingReasoningFunc(ctx, []byte(m.reasoning), nil)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{Content: m.name}},
+	}, nil
+}
+
+type routeHandler struct {
+	callbacks.SimpleHandler
+	routes []string
+}
+
+func (h *routeHandler) HandleLLMRoute(_ context.Context, backend string, err error) {
+	if err != nil {
+		backend += " failed"
+	}
+	h.routes = append(h.routes, backend)
+}
+
+func TestRouter_Fallback(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	rq := require.New(t)
+
+	primary := &mockLLM{name: "openai", err: &llms.APIError{StatusCode: http.StatusServiceUnavailable}}
+	slow := &mockLLM{name: "anthropic", delay: time.Second}
+	local := &mockLLM{name: "ollama"}
+	handler := &routeHandler{}
+
+	r, err := New([]Backend{
+		{Name: "openai", Model: primary},
+		{Name: "anthropic", Model: slow, Timeout: 10 * time.Millisecond},
+		{Name: "ollama", Model: local, Options: []llms.CallOption{
+			MapModel(map[string]string{"gpt-4o": "llama3"}),
+		}},
+	}, WithCallback(handler))
+	rq.NoError(err)
+
+	out, err := r.Call(ctx, "hi", llms.WithModel("gpt-4o"))
+	rq.NoError(err)
+	rq.Equal("ollama", out)
+	rq.Equal([]string{"openai failed", "anthropic failed", "ollama"}, handler.routes)
+	rq.Equal([]string{"gpt-4o"}, primary.models)
+	rq.Equal([]string{"llama3"}, local.models)
+}
+
+func TestRouter_Errors(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	_, err := New(nil)
+	require.ErrorIs(t, err, ErrNoBackends)
+
+	errA := errors.New("a")
+	errB := errors.New("b")
+	r, err := New([]Backend{
+		{Name: "a", Model: &mockLLM{err: errA}},
+		{Name: "b", Model: &mockLLM{err: errB}},
+	})
+	require.NoError(t, err)
+	_, err = r.Call(ctx, "hi")
+	require.ErrorIs(t, err, ErrAllBackendsFailed)
+	require.ErrorIs(t, err, errA)
+	require.ErrorIs(t, err, errB)
+
+	b := &mockLLM{}
+	r, err = New([]Backend{
+		{Name: "a", Model: &mockLLM{err: errA}},
+		{Name: "b", Model: b},
+	}, WithFallbackIf(func(err error) bool { return !errors.Is(err, errA) }))
+	require.NoError(t, err)
+	_, err = r.Call(ctx, "hi")
+	require.ErrorIs(t, err, errA)
+	require.Empty(t, b.models)
+}
+
+func TestRouter_Streamed(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	errA := errors.New("a")
+	b := &mockLLM{name: "b"}
+	r, err := New([]Backend{
+		{Name: "a", Model: &mockLLM{err: errA, reasoning: "thinking"}},
+		{Name: "b", Model: b},
+	})
+	require.NoError(t, err)
+
+	// No fallback once reasoning was streamed.
+	var reasoning string
+	_, err = r.Call(ctx, "hi", llms.WithStreamingReasoningFunc(func(_ context.Context, chunk, _ []byte) error {
+		reasoning += string(chunk)
+		return nil
+	}))
+	require.ErrorIs(t, err, errA)
+	require.Equal(t, "thinking", reasoning)
+	require.Empty(t, b.models)
+
+	var events []llms.StreamEvent
+	for event := range llms.StreamContent(ctx, r, []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "hi")}) {
+		events = append(events, event)
+	}
+	require.Len(t, events, 2)
+	require.Equal(t, llms.StreamEventReasoning, events[0].Type)
+	require.ErrorIs(t, events[1].Err, errA)
+	require.Empty(t, b.models)
+
+	// Without streamed output, the call falls back.
+	out, err := r.Call(ctx, "hi")
+	require.NoError(t, err)
+	require.Equal(t, "b", out)
+}
+
+func TestRouter_Weighted(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	rq := require.New(t)
+
+	a := &mockLLM{name: "a"}
+	b := &mockLLM{name: "b"}
+	fallback := &mockLLM{name: "fallback"}
+	r, err := New([]Backend{
+		{Name: "a", Model: a, Weight: 3},
+		{Name: "b", Model: b, Weight: 1},
+		{Name: "fallback", Model: fallback},
+	}, WithStrategy(Weighted))
+	rq.NoError(err)
+	r.rand = rand.New(rand.NewSource(1)) //nolint:gosec
+
+	for range 400 {
+		_, err := r.Call(ctx, "hi")
+		rq.NoError(err)
+	}
+	rq.InDelta(300, len(a.models), 40)
+	rq.InDelta(100, len(b.models), 40)
+	rq.Empty(fallback.models)
+
+	for range 20 {
+		order := r.order()
+		rq.Len(order, 3)
+		rq.Equal("fallback", order[2].Name)
+	}
+}
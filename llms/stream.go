@@ -0,0 +1,229 @@
+package llms
+
+import (
+	"context"
+)
+
+// StreamEventType is the type of a StreamEvent.
+type StreamEventType string
+
+const (
+	// StreamEventText is a delta of the text of the response.
+	StreamEventText StreamEventType = "text"
+	// StreamEventReasoning is a delta of the reasoning of the model before its
+	// response.
+	StreamEventReasoning StreamEventType = "reasoning"
+	// StreamEventToolCall is a delta of a tool call of the response.
+	StreamEventToolCall StreamEventType = "tool_call"
+	// StreamEventUsage reports the token usage of the response.
+	StreamEventUsage StreamEventType = "usage"
+	// StreamEventStop ends a successful response.
+	StreamEventStop StreamEventType = "stop"
+	// StreamEventError ends a failed response.
+	StreamEventError StreamEventType = "error"
+)
+
+// StreamEvent is an event of a streamed response.
+type StreamEvent struct {
+	// Type is the type of the event.
+	Type StreamEventType `json:"type"`
+	// Text is the delta of text or reasoning of StreamEventText and
+	// StreamEventReasoning events.
+	Text string `json:"text,omitempty"`
+	// ToolCall is the delta of StreamEventToolCall events.
+	ToolCall *ToolCallDelta `json:"tool_call,omitempty"`
+	// Usage is the token usage of StreamEventUsage events.
+	Usage *Usage `json:"usage,omitempty"`
+	// StopReason is the reason the model stopped generating output, for
+	// StreamEventStop events.
+	StopReason string `json:"stop_reason,omitempty"`
+	// Response is the complete response, for StreamEventStop events.
+	Response *ContentResponse `json:"-"`
+	// Err is the error of StreamEventError events.
+	Err error `json:"-"`
+}
+
+// ToolCallDelta is a part of a tool call of a streamed response. The first
+// delta of a tool call has its ID and name, and the arguments of the call are
+// the concatenation of the arguments of its deltas.
+type ToolCallDelta struct {
+	// Index is the position of the tool call in the tool calls of the
+	// response.
+	Index int `json:"index"`
+	// ID is the ID of the tool call.
+	ID string `json:"id,omitempty"`
+	// Name is the name of the called function.
+	Name string `json:"name,omitempty"`
+	// Arguments is the part of the JSON encoded arguments of the call.
+	Arguments string `json:"arguments,omitempty"`
+}
+
+// StreamContent asks the model to generate content from the messages and
+// streams the response. The returned channel receives the events of the
+// response, and is closed after a StreamEventStop or StreamEventError event.
+//
+// Models honoring the StreamingEventFunc call option stream text, reasoning
+// and tool call deltas. Other models stream text and reasoning through
+// StreamingFunc and StreamingReasoningFunc, and their tool calls are sent
+// when the response is complete. Parts of the response that were not
+// streamed, e.g. by models without streaming support, are sent as a single
+// delta once the response is complete.
+//
+// The caller must read the channel until it is closed, or cancel ctx to stop
+// the generation.
+func StreamContent(ctx context.Context, model Model, messages []MessageContent, options ...CallOption) <-chan StreamEvent { //nolint:lll
+	events := make(chan StreamEvent)
+	go func() {
+		defer close(events)
+
+		s := &eventStream{ctx: ctx, events: events}
+		options = append(options[:len(options):len(options)],
+			WithStreamingFunc(func(ctx context.Context, chunk []byte) error {
+				return s.send(StreamEvent{Type: StreamEventText, Text: string(chunk)})
+			}),
+			WithStreamingReasoningFunc(func(ctx context.Context, reasoningChunk, _ []byte) error {
+				return s.send(StreamEvent{Type: StreamEventReasoning, Text: string(reasoningChunk)})
+			}),
+			WithStreamingEventFunc(func(_ context.Context, event StreamEvent) error {
+				return s.send(event)
+			}),
+		)
+
+		resp, err := model.GenerateContent(ctx, messages, options...)
+		if err == nil {
+			err = s.finish(resp)
+		}
+		if err != nil {
+			// The error isn't sent if the caller gave up on the stream.
+			_ = s.send(StreamEvent{Type: StreamEventError, Err: err})
+		}
+	}()
+	return events
+}
+
+// eventStream sends the events of StreamContent, recording what was streamed.
+type eventStream struct {
+	ctx    context.Context //nolint:containedctx
+	events chan<- StreamEvent
+
+	streamed map[StreamEventType]bool
+}
+
+func (s *eventStream) send(event StreamEvent) error {
+	switch event.Type { //nolint:exhaustive
+	case StreamEventText, StreamEventReasoning:
+		if event.Text == "" {
+			return nil
+		}
+	case StreamEventToolCall:
+		if event.ToolCall == nil {
+			return nil
+		}
+	}
+	if s.streamed == nil {
+		s.streamed = map[StreamEventType]bool{}
+	}
+	s.streamed[event.Type] = true
+
+	select {
+	case s.events <- event:
+		return nil
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	}
+}
+
+// finish sends the parts of the response that were not streamed, its usage
+// and the stop event.
+func (s *eventStream) finish(resp *ContentResponse) error {
+	var choice ContentChoice
+	if resp != nil && len(resp.Choices) > 0 && resp.Choices[0] != nil {
+		choice = *resp.Choices[0]
+	}
+
+	var pending []StreamEvent
+	if !s.streamed[StreamEventReasoning] {
+		pending = append(pending, StreamEvent{Type: StreamEventReasoning, Text: choice.ReasoningContent})
+	}
+	if !s.streamed[StreamEventText] {
+		pending = append(pending, StreamEvent{Type: StreamEventText, Text: choice.Content})
+	}
+	if !s.streamed[StreamEventToolCall] {
+		pending = append(pending, toolCallEvents(choice.ToolCalls)...)
+	}
+	if resp != nil && resp.Usage != nil {
+		pending = append(pending, StreamEvent{Type: StreamEventUsage, Usage: resp.Usage})
+	}
+	pending = append(pending, StreamEvent{Type: StreamEventStop, StopReason: choice.StopReason, Response: resp})
+
+	for _, event := range pending {
+		if err := s.send(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StreamResponse sends a complete response to the streaming functions of the
+// options, as a model streaming it would have, e.g. for responses replayed
+// from a cache or scripted in tests. Only the first choice is streamed: its
+// reasoning, its text as the chunks, or as a single chunk without chunks, and
+// with StreamingEventFunc its tool calls. Empty deltas aren't sent.
+func StreamResponse(ctx context.Context, opts CallOptions, resp *ContentResponse, chunks ...string) error {
+	var choice ContentChoice
+	if resp != nil && len(resp.Choices) > 0 && resp.Choices[0] != nil {
+		choice = *resp.Choices[0]
+	}
+	if len(chunks) == 0 {
+		chunks = []string{choice.Content}
+	}
+
+	if opts.StreamingEventFunc != nil {
+		events := []StreamEvent{{Type: StreamEventReasoning, Text: choice.ReasoningContent}}
+		for _, chunk := range chunks {
+			events = append(events, StreamEvent{Type: StreamEventText, Text: chunk})
+		}
+		events = append(events, toolCallEvents(choice.ToolCalls)...)
+		for _, event := range events {
+			if event.Type != StreamEventToolCall && event.Text == "" {
+				continue
+			}
+			if err := opts.StreamingEventFunc(ctx, event); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if opts.StreamingReasoningFunc != nil && choice.ReasoningContent != "" {
+		if err := opts.StreamingReasoningFunc(ctx, []byte(choice.ReasoningContent), nil); err != nil {
+			return err
+		}
+	}
+	if opts.StreamingFunc != nil {
+		for _, chunk := range chunks {
+			if chunk == "" {
+				continue
+			}
+			if err := opts.StreamingFunc(ctx, []byte(chunk)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// toolCallEvents returns the tool calls as StreamEventToolCall events, a
+// single delta per call.
+func toolCallEvents(calls []ToolCall) []StreamEvent {
+	events := make([]StreamEvent, 0, len(calls))
+	for i, call := range calls {
+		delta := &ToolCallDelta{Index: i, ID: call.ID}
+		if call.FunctionCall != nil {
+			delta.Name = call.FunctionCall.Name
+			delta.Arguments = call.FunctionCall.Arguments
+		}
+		events = append(events, StreamEvent{Type: StreamEventToolCall, ToolCall: delta})
+	}
+	return events
+}
@@ -0,0 +1,153 @@
+package llms
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// streamModel streams its response through the streaming functions of the
+// call options.
+type streamModel struct {
+	events   bool
+	response *ContentResponse
+	err      error
+}
+
+func (m *streamModel) GenerateContent(ctx context.Context, _ []MessageContent, options ...CallOption) (*ContentResponse, error) { //nolint:lll
+	var opts CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+	choice := m.response.Choices[0]
+	if m.events {
+		for _, event := range []StreamEvent{
+			{Type: StreamEventText, Text: choice.Content[:2]},
+			{Type: StreamEventText, Text: choice.Content[2:]},
+			{Type: StreamEventToolCall, ToolCall: &ToolCallDelta{ID: "1", Name: "search"}},
+			{Type: StreamEventToolCall, ToolCall: &ToolCallDelta{Arguments: "{}"}},
+		} {
+			if err := opts.StreamingEventFunc(ctx, event); err != nil {
+				return nil, err
+			}
+		}
+	} else if err := opts.StreamingFunc(ctx, []byte(choice.Content)); err != nil {
+		return nil, err
+	}
+	return m.response, m.err
+}
+
+func (m *streamModel) Call(context.Context, string, ...CallOption) (string, error) {
+	return "", nil
+}
+
+func collectEvents(ch <-chan StreamEvent) []StreamEvent {
+	var events []StreamEvent
+	for event := range ch {
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestStreamContent(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	resp := &ContentResponse{
+		Choices: []*ContentChoice{{
+			Content:          "hello",
+			StopReason:       "tool_calls",
+			ReasoningContent: "thinking",
+			ToolCalls:        []ToolCall{{ID: "1", FunctionCall: &FunctionCall{Name: "search", Arguments: "{}"}}},
+		}},
+		Usage: NewUsage(3, 4),
+	}
+
+	events := collectEvents(StreamContent(ctx, &streamModel{events: true, response: resp}, nil))
+	require.Equal(t, []StreamEvent{
+		{Type: StreamEventText, Text: "he"},
+		{Type: StreamEventText, Text: "llo"},
+		{Type: StreamEventToolCall, ToolCall: &ToolCallDelta{ID: "1", Name: "search"}},
+		{Type: StreamEventToolCall, ToolCall: &ToolCallDelta{Arguments: "{}"}},
+		{Type: StreamEventReasoning, Text: "thinking"},
+		{Type: StreamEventUsage, Usage: resp.Usage},
+		{Type: StreamEventStop, StopReason: "tool_calls", Response: resp},
+	}, events)
+
+	// Tool calls of models streaming only text are sent at the end.
+	events = collectEvents(StreamContent(ctx, &streamModel{response: resp}, nil))
+	require.Equal(t, []StreamEvent{
+		{Type: StreamEventText, Text: "hello"},
+		{Type: StreamEventReasoning, Text: "thinking"},
+		{Type: StreamEventToolCall, ToolCall: &ToolCallDelta{ID: "1", Name: "search", Arguments: "{}"}},
+		{Type: StreamEventUsage, Usage: resp.Usage},
+		{Type: StreamEventStop, StopReason: "tool_calls", Response: resp},
+	}, events)
+}
+
+func TestStreamContentError(t *testing.T) {
+	t.Parallel()
+
+	errFailed := errors.New("failed")
+	resp := &ContentResponse{Choices: []*ContentChoice{{Content: "hello"}}}
+	events := collectEvents(StreamContent(context.Background(), &streamModel{response: resp, err: errFailed}, nil))
+	require.Len(t, events, 2)
+	require.Equal(t, StreamEventError, events[1].Type)
+	require.ErrorIs(t, events[1].Err, errFailed)
+}
+
+func TestStreamContentCancel(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	resp := &ContentResponse{Choices: []*ContentChoice{{Content: "hello"}}}
+	ch := StreamContent(ctx, &streamModel{events: true, response: resp}, nil)
+	<-ch
+	cancel()
+	// The stream ends without blocking once the context is canceled.
+	for range ch { //nolint:revive
+	}
+}
+
+func TestStreamResponse(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	resp := &ContentResponse{Choices: []*ContentChoice{{
+		Content:          "hello",
+		ReasoningContent: "think",
+		ToolCalls:        []ToolCall{{ID: "1", FunctionCall: &FunctionCall{Name: "search", Arguments: "{}"}}},
+	}}}
+
+	var events []StreamEvent
+	require.NoError(t, StreamResponse(ctx, CallOptions{
+		StreamingEventFunc: func(_ context.Context, event StreamEvent) error {
+			events = append(events, event)
+			return nil
+		},
+	}, resp, "he", "", "llo"))
+	require.Equal(t, []StreamEvent{
+		{Type: StreamEventReasoning, Text: "think"},
+		{Type: StreamEventText, Text: "he"},
+		{Type: StreamEventText, Text: "llo"},
+		{Type: StreamEventToolCall, ToolCall: &ToolCallDelta{Index: 0, ID: "1", Name: "search", Arguments: "{}"}},
+	}, events)
+
+	var text, reasoning string
+	require.NoError(t, StreamResponse(ctx, CallOptions{
+		StreamingFunc: func(_ context.Context, chunk []byte) error {
+			text += string(chunk)
+			return nil
+		},
+		StreamingReasoningFunc: func(_ context.Context, reasoningChunk, _ []byte) error {
+			reasoning += string(reasoningChunk)
+			return nil
+		},
+	}, resp))
+	require.Equal(t, "hello", text)
+	require.Equal(t, "think", reasoning)
+
+	require.NoError(t, StreamResponse(ctx, CallOptions{}, nil))
+}
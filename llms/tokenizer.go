@@ -0,0 +1,121 @@
+package llms
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/pkoukk/tiktoken-go"
+)
+
+// ErrNoTokenizer is returned by TokenizerForModel when no tokenizer is known
+// for a model.
+var ErrNoTokenizer = errors.New("no tokenizer for model")
+
+// Tokenizer converts texts to the tokens of a model and back.
+type Tokenizer interface {
+	// Encode returns the tokens of the text.
+	Encode(text string) []int
+	// Decode returns the text of the tokens.
+	Decode(tokens []int) string
+	// Count returns the number of tokens of the text.
+	Count(text string) int
+}
+
+// nolint:gochecknoglobals
+var (
+	tokenizerRegistryMu sync.RWMutex
+	tokenizerRegistry   = map[string]Tokenizer{}
+)
+
+// RegisterTokenizer registers the tokenizer of a model, e.g. "llama3.1:8b",
+// or of all the models of the registry using a tokenizer, e.g.
+// TokenizerLlama3, see ModelInfo. Tokenizers whose vocabulary isn't public,
+// such as those of Llama or Gemini, must be registered to be used by
+// TokenizerForModel, e.g. with a tokenizer loaded from a local file by the
+// tokenizer package.
+func RegisterTokenizer(name string, tokenizer Tokenizer) {
+	tokenizerRegistryMu.Lock()
+	defer tokenizerRegistryMu.Unlock()
+	tokenizerRegistry[strings.ToLower(name)] = tokenizer
+}
+
+// TokenizerForModel returns the tokenizer of a model. It is, in order:
+//   - the tokenizer registered for the model name,
+//   - the tokenizer registered for the name of the model in the model
+//     registry, or for its tokenizer, see LookupModel,
+//   - the tiktoken encoding of the model.
+//
+// ErrNoTokenizer is returned if none is found.
+func TokenizerForModel(model string) (Tokenizer, error) {
+	names := []string{model}
+	info, ok := LookupModel(model)
+	if ok {
+		names = append(names, info.Name)
+		if info.Tokenizer != "" {
+			names = append(names, info.Tokenizer)
+		}
+	}
+
+	tokenizerRegistryMu.RLock()
+	for _, name := range names {
+		if tokenizer, ok := tokenizerRegistry[strings.ToLower(name)]; ok {
+			tokenizerRegistryMu.RUnlock()
+			return tokenizer, nil
+		}
+	}
+	tokenizerRegistryMu.RUnlock()
+
+	if ok {
+		if tokenizer, err := NewTiktokenTokenizer(info.Tokenizer); err == nil {
+			return tokenizer, nil
+		}
+	}
+	encoding, err := tiktoken.EncodingForModel(model)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q", ErrNoTokenizer, model)
+	}
+	return &TiktokenTokenizer{encoding: encoding}, nil
+}
+
+// TiktokenTokenizer is a Tokenizer using a tiktoken encoding, the tokenizer
+// of the OpenAI models.
+type TiktokenTokenizer struct {
+	encoding *tiktoken.Tiktoken
+
+	// AllowedSpecial are the special tokens encoded as such, or "all". Other
+	// special tokens are encoded as text.
+	AllowedSpecial []string
+	// DisallowedSpecial are the special tokens that make Encode panic when
+	// found in the text, or "all".
+	DisallowedSpecial []string
+}
+
+var _ Tokenizer = (*TiktokenTokenizer)(nil)
+
+// NewTiktokenTokenizer returns the tokenizer of a tiktoken encoding, e.g.
+// TokenizerCL100kBase. The encoding is downloaded on first use, unless
+// found in the directory set by the TIKTOKEN_CACHE_DIR environment variable.
+func NewTiktokenTokenizer(encoding string) (*TiktokenTokenizer, error) {
+	e, err := tiktoken.GetEncoding(encoding)
+	if err != nil {
+		return nil, fmt.Errorf("tiktoken encoding %q: %w", encoding, err)
+	}
+	return &TiktokenTokenizer{encoding: e}, nil
+}
+
+// Encode returns the tokens of the text.
+func (t *TiktokenTokenizer) Encode(text string) []int {
+	return t.encoding.Encode(text, t.AllowedSpecial, t.DisallowedSpecial)
+}
+
+// Decode returns the text of the tokens.
+func (t *TiktokenTokenizer) Decode(tokens []int) string {
+	return t.encoding.Decode(tokens)
+}
+
+// Count returns the number of tokens of the text.
+func (t *TiktokenTokenizer) Count(text string) int {
+	return len(t.Encode(text))
+}
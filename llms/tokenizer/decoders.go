@@ -0,0 +1,202 @@
+package tokenizer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// decoder converts the strings of tokens back to text. Decoders of a
+// sequence transform the strings in turn, and the results are joined.
+type decoder func(tokens []string) []string
+
+// wordPieceCleanup are the replacements of the cleanup of WordPiece decoders.
+//
+// nolint:gochecknoglobals
+var wordPieceCleanup = strings.NewReplacer(
+	" .", ".", " ?", "?", " !", "!", " ,", ",", " ' ", "'",
+	" n't", "n't", " 'm", "'m", " 's", "'s", " 've", "'ve", " 're", "'re",
+)
+
+type decoderJSON struct {
+	component
+	Decoders       []json.RawMessage `json:"decoders"`
+	Pattern        pattern           `json:"pattern"`
+	Content        string            `json:"content"`
+	Start          int               `json:"start"`
+	Stop           int               `json:"stop"`
+	Replacement    string            `json:"replacement"`
+	PrependScheme  string            `json:"prepend_scheme"`
+	AddPrefixSpace *bool             `json:"add_prefix_space"`
+	Prefix         string            `json:"prefix"`
+	Suffix         string            `json:"suffix"`
+	Cleanup        bool              `json:"cleanup"`
+}
+
+func newDecoder(data json.RawMessage) (decoder, error) { //nolint:cyclop,funlen
+	if isNull(data) {
+		// Without decoder, the tokens are separated by spaces.
+		return func(tokens []string) []string { return []string{strings.Join(tokens, " ")} }, nil
+	}
+	var d decoderJSON
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+
+	switch d.Type {
+	case "Sequence":
+		decoders := make([]decoder, 0, len(d.Decoders))
+		for _, data := range d.Decoders {
+			decoder, err := newDecoder(data)
+			if err != nil {
+				return nil, err
+			}
+			decoders = append(decoders, decoder)
+		}
+		return func(tokens []string) []string {
+			for _, decode := range decoders {
+				tokens = decode(tokens)
+			}
+			return tokens
+		}, nil
+	case "ByteLevel":
+		return func(tokens []string) []string {
+			var bytes []byte
+			for _, token := range tokens {
+				for _, r := range token {
+					if b, ok := runeToByte[r]; ok {
+						bytes = append(bytes, b)
+					} else {
+						bytes = utf8.AppendRune(bytes, r)
+					}
+				}
+			}
+			return []string{strings.ToValidUTF8(string(bytes), "�")}
+		}, nil
+	case "Metaspace":
+		replacement := d.Replacement
+		if replacement == "" {
+			replacement = "▁"
+		}
+		// The prepended space of the first token is removed.
+		prepended := d.PrependScheme != "never"
+		if d.PrependScheme == "" && d.AddPrefixSpace != nil {
+			prepended = *d.AddPrefixSpace
+		}
+		return func(tokens []string) []string {
+			decoded := make([]string, len(tokens))
+			for i, token := range tokens {
+				token = strings.ReplaceAll(token, replacement, " ")
+				if i == 0 && prepended {
+					token = strings.TrimPrefix(token, " ")
+				}
+				decoded[i] = token
+			}
+			return decoded
+		}, nil
+	case "Replace":
+		match, err := d.Pattern.compile()
+		if err != nil {
+			return nil, err
+		}
+		return func(tokens []string) []string {
+			decoded := make([]string, len(tokens))
+			for i, token := range tokens {
+				decoded[i] = replaceMatches(token, match, d.Content)
+			}
+			return decoded
+		}, nil
+	case "ByteFallback":
+		return decodeByteFallback, nil
+	case "Fuse":
+		return func(tokens []string) []string { return []string{strings.Join(tokens, "")} }, nil
+	case "Strip":
+		return func(tokens []string) []string {
+			decoded := make([]string, len(tokens))
+			for i, token := range tokens {
+				for range d.Start {
+					token = strings.TrimPrefix(token, d.Content)
+				}
+				for range d.Stop {
+					token = strings.TrimSuffix(token, d.Content)
+				}
+				decoded[i] = token
+			}
+			return decoded
+		}, nil
+	case "WordPiece":
+		prefix := d.Prefix
+		if prefix == "" {
+			prefix = "##"
+		}
+		return func(tokens []string) []string {
+			decoded := make([]string, len(tokens))
+			for i, token := range tokens {
+				if i > 0 {
+					if strings.HasPrefix(token, prefix) {
+						token = strings.TrimPrefix(token, prefix)
+					} else {
+						token = " " + token
+					}
+				}
+				if d.Cleanup {
+					token = wordPieceCleanup.Replace(token)
+				}
+				decoded[i] = token
+			}
+			return decoded
+		}, nil
+	case "BPEDecoder":
+		suffix := d.Suffix
+		if suffix == "" {
+			suffix = "</w>"
+		}
+		return func(tokens []string) []string {
+			decoded := make([]string, len(tokens))
+			for i, token := range tokens {
+				replacement := " "
+				if i == len(tokens)-1 {
+					replacement = ""
+				}
+				decoded[i] = strings.ReplaceAll(token, suffix, replacement)
+			}
+			return decoded
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported decoder %q", d.Type)
+	}
+}
+
+// decodeByteFallback converts the byte tokens, e.g. "<0x0A>", to the text of
+// their bytes. Bytes that aren't valid UTF-8 are replaced by U+FFFD.
+func decodeByteFallback(tokens []string) []string {
+	decoded := make([]string, 0, len(tokens))
+	var bytes []byte
+	flush := func() {
+		if len(bytes) == 0 {
+			return
+		}
+		if utf8.Valid(bytes) {
+			decoded = append(decoded, string(bytes))
+		} else {
+			for range bytes {
+				decoded = append(decoded, "�")
+			}
+		}
+		bytes = bytes[:0]
+	}
+	for _, token := range tokens {
+		if len(token) == 6 && strings.HasPrefix(token, "<0x") && strings.HasSuffix(token, ">") {
+			if b, err := strconv.ParseUint(token[3:5], 16, 8); err == nil {
+				bytes = append(bytes, byte(b))
+				continue
+			}
+		}
+		flush()
+		decoded = append(decoded, token)
+	}
+	flush()
+	return decoded
+}
@@ -0,0 +1,14 @@
+// Package tokenizer provides llms.Tokenizer implementations loaded from local
+// files, for models whose tokenizer isn't a tiktoken encoding.
+//
+// HuggingFace tokenizers are loaded from the tokenizer.json files distributed
+// with the models, e.g. Llama, Mistral or Gemma. They cover the byte-level BPE
+// tokenizers, the SentencePiece BPE and Unigram tokenizers converted by the
+// transformers library, and WordPiece tokenizers:
+//
+//	tk, err := tokenizer.LoadHuggingFace("Meta-Llama-3-8B-Instruct/tokenizer.json")
+//	if err != nil {
+//		return err
+//	}
+//	llms.RegisterTokenizer(llms.TokenizerLlama3, tk)
+package tokenizer
@@ -0,0 +1,155 @@
+package tokenizer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// HuggingFace is a tokenizer defined by a HuggingFace tokenizer.json file.
+//
+// Texts are encoded without the special tokens added by the post-processor
+// of the tokenizer, e.g. the BOS token, so that the tokens of texts can be
+// added up. Added tokens found in texts, e.g. "<|eot_id|>", are encoded as
+// such.
+type HuggingFace struct {
+	normalize   normalizer
+	preTokenize preTokenizer
+	model       model
+	decode      decoder
+
+	addedTokens   map[string]int
+	addedIDs      map[int]string
+	addedTokensRE *regexp.Regexp
+}
+
+var _ llms.Tokenizer = (*HuggingFace)(nil)
+
+type tokenizerJSON struct {
+	AddedTokens []struct {
+		ID      int    `json:"id"`
+		Content string `json:"content"`
+	} `json:"added_tokens"`
+	Normalizer   json.RawMessage `json:"normalizer"`
+	PreTokenizer json.RawMessage `json:"pre_tokenizer"`
+	Model        json.RawMessage `json:"model"`
+	Decoder      json.RawMessage `json:"decoder"`
+}
+
+// LoadHuggingFace loads a HuggingFace tokenizer from a tokenizer.json file.
+func LoadHuggingFace(path string) (*HuggingFace, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("load tokenizer: %w", err)
+	}
+	defer f.Close()
+	return NewHuggingFace(f)
+}
+
+// NewHuggingFace reads a HuggingFace tokenizer in the tokenizer.json format.
+// An error is returned if the tokenizer uses components that aren't
+// supported.
+func NewHuggingFace(r io.Reader) (*HuggingFace, error) {
+	var file tokenizerJSON
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("decode tokenizer: %w", err)
+	}
+
+	h := &HuggingFace{
+		addedTokens: make(map[string]int, len(file.AddedTokens)),
+		addedIDs:    make(map[int]string, len(file.AddedTokens)),
+	}
+	var err error
+	if h.normalize, err = newNormalizer(file.Normalizer); err != nil {
+		return nil, fmt.Errorf("tokenizer normalizer: %w", err)
+	}
+	if h.preTokenize, err = newPreTokenizer(file.PreTokenizer); err != nil {
+		return nil, fmt.Errorf("tokenizer pre-tokenizer: %w", err)
+	}
+	if h.model, err = newModel(file.Model); err != nil {
+		return nil, fmt.Errorf("tokenizer model: %w", err)
+	}
+	if h.decode, err = newDecoder(file.Decoder); err != nil {
+		return nil, fmt.Errorf("tokenizer decoder: %w", err)
+	}
+
+	contents := make([]string, 0, len(file.AddedTokens))
+	for _, token := range file.AddedTokens {
+		h.addedTokens[token.Content] = token.ID
+		h.addedIDs[token.ID] = token.Content
+		contents = append(contents, regexp.QuoteMeta(token.Content))
+	}
+	if len(contents) > 0 {
+		// The longest tokens are matched first, e.g. "<|end|>" before "<".
+		slices.SortFunc(contents, func(a, b string) int { return len(b) - len(a) })
+		h.addedTokensRE = regexp.MustCompile(strings.Join(contents, "|"))
+	}
+	return h, nil
+}
+
+// Encode returns the tokens of the text.
+func (h *HuggingFace) Encode(text string) []int {
+	var tokens []int
+	start := 0
+	if h.addedTokensRE != nil {
+		for _, loc := range h.addedTokensRE.FindAllStringIndex(text, -1) {
+			tokens = append(tokens, h.encodeSegment(text[start:loc[0]], start == 0)...)
+			tokens = append(tokens, h.addedTokens[text[loc[0]:loc[1]]])
+			start = loc[1]
+		}
+	}
+	return append(tokens, h.encodeSegment(text[start:], start == 0)...)
+}
+
+// encodeSegment encodes a text without added tokens. first is whether the
+// segment starts the text.
+func (h *HuggingFace) encodeSegment(text string, first bool) []int {
+	if text == "" {
+		return nil
+	}
+	pieces := []string{h.normalize(text)}
+	if h.preTokenize != nil {
+		pieces = h.preTokenize(pieces, first)
+	}
+	var tokens []int
+	for _, piece := range pieces {
+		if piece != "" {
+			tokens = append(tokens, h.model.tokenize(piece)...)
+		}
+	}
+	return tokens
+}
+
+// Decode returns the text of the tokens. Unknown tokens are ignored.
+func (h *HuggingFace) Decode(tokens []int) string {
+	pieces := make([]string, 0, len(tokens))
+	for _, id := range tokens {
+		if content, ok := h.addedIDs[id]; ok {
+			pieces = append(pieces, content)
+		} else if piece, ok := h.model.token(id); ok {
+			pieces = append(pieces, piece)
+		}
+	}
+	return strings.Join(h.decode(pieces), "")
+}
+
+// Count returns the number of tokens of the text.
+func (h *HuggingFace) Count(text string) int {
+	return len(h.Encode(text))
+}
+
+// component is the common part of the components of tokenizer.json files.
+type component struct {
+	Type string `json:"type"`
+}
+
+// isNull reports whether an optional component is missing.
+func isNull(data json.RawMessage) bool {
+	return len(data) == 0 || string(data) == "null"
+}
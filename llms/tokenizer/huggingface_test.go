@@ -0,0 +1,71 @@
+package tokenizer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHuggingFace(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		file   string
+		text   string
+		tokens []int
+		// decoded is the decoded text if it differs from text.
+		decoded string
+	}{
+		{"byte_level_bpe.json", "hello world!", []int{13, 18, 0}, ""},
+		{"byte_level_bpe.json", "hello\n", []int{13, 9}, ""},
+		{"byte_level_bpe.json", "123", []int{22, 21}, ""},
+		{"byte_level_bpe.json", "hello<|eot_id|>world", []int{13, 100, 7, 15, 4, 1}, ""},
+		{"sentencepiece_bpe.json", "hi there", []int{11, 19}, ""},
+		{"sentencepiece_bpe.json", "hi\n€", []int{11, 3, 4, 5, 6}, ""},
+		{"sentencepiece_bpe.json", "hi ☃☃", []int{11, 7, 0}, "hi <unk>"},
+		{"sentencepiece_bpe.json", "<s>hi", []int{1, 11}, "<s> hi"},
+		{"unigram.json", "new york", []int{2, 3}, ""},
+		{"unigram.json", "newyork", []int{4}, ""},
+		{"unigram.json", "new!?", []int{2, 0}, "new<unk>"},
+		{"wordpiece.json", "Hello, unaffable!", []int{3, 7, 4, 5, 6, 8}, "hello, unaffable!"},
+		{"wordpiece.json", "hello xyz", []int{3, 0}, "hello [UNK]"},
+	}
+	for _, tt := range tests {
+		t.Run(strings.TrimSuffix(tt.file, ".json")+"/"+tt.text, func(t *testing.T) {
+			t.Parallel()
+			tk, err := LoadHuggingFace("testdata/" + tt.file)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.tokens, tk.Encode(tt.text))
+			assert.Equal(t, len(tt.tokens), tk.Count(tt.text))
+			decoded := tt.decoded
+			if decoded == "" {
+				decoded = tt.text
+			}
+			assert.Equal(t, decoded, tk.Decode(tt.tokens))
+		})
+	}
+}
+
+func TestNewHuggingFaceErrors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		json string
+		err  string
+	}{
+		{"invalid", `{`, "decode tokenizer"},
+		{"missing model", `{}`, "missing model"},
+		{"unsupported model", `{"model": {"type": "Unknown"}}`, `unsupported model "Unknown"`},
+		{"unsupported normalizer", `{"normalizer": {"type": "Precompiled"}}`, `unsupported normalizer "Precompiled"`},
+		{"invalid regex", `{"pre_tokenizer": {"type": "Split", "pattern": {"Regex": "("}}}`, "pattern"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := NewHuggingFace(strings.NewReader(tt.json))
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,396 @@
+package tokenizer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// _unknownPenalty is the score penalty of unknown runes of Unigram models,
+// below the score of the least likely piece.
+const _unknownPenalty = 10
+
+// model converts the words returned by the pre-tokenizer to tokens.
+type model interface {
+	// tokenize returns the tokens of a word.
+	tokenize(word string) []int
+	// token returns the string of a token.
+	token(id int) (string, bool)
+}
+
+type modelJSON struct {
+	component
+	Vocab                   json.RawMessage   `json:"vocab"`
+	Merges                  []json.RawMessage `json:"merges"`
+	UnkToken                *string           `json:"unk_token"`
+	UnkID                   *int              `json:"unk_id"`
+	ContinuingSubwordPrefix *string           `json:"continuing_subword_prefix"`
+	EndOfWordSuffix         *string           `json:"end_of_word_suffix"`
+	FuseUnk                 bool              `json:"fuse_unk"`
+	ByteFallback            bool              `json:"byte_fallback"`
+	IgnoreMerges            bool              `json:"ignore_merges"`
+	MaxInputCharsPerWord    int               `json:"max_input_chars_per_word"`
+}
+
+func newModel(data json.RawMessage) (model, error) {
+	if isNull(data) {
+		return nil, errors.New("missing model")
+	}
+	var m modelJSON
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	if m.Type == "" && m.Merges != nil {
+		// Older files don't have the type of BPE models.
+		m.Type = "BPE"
+	}
+
+	switch m.Type {
+	case "BPE":
+		return newBPE(m)
+	case "Unigram":
+		return newUnigram(m)
+	case "WordPiece":
+		return newWordPiece(m)
+	default:
+		return nil, fmt.Errorf("unsupported model %q", m.Type)
+	}
+}
+
+// vocabulary maps the strings of tokens to their IDs and back.
+type vocabulary struct {
+	ids    map[string]int
+	tokens map[int]string
+}
+
+func newVocabulary(ids map[string]int) vocabulary {
+	tokens := make(map[int]string, len(ids))
+	for token, id := range ids {
+		tokens[id] = token
+	}
+	return vocabulary{ids: ids, tokens: tokens}
+}
+
+func (v vocabulary) token(id int) (string, bool) {
+	token, ok := v.tokens[id]
+	return token, ok
+}
+
+// byteFallback returns the tokens of the bytes of text, e.g. "<0x0A>", or
+// false if a byte has no token.
+func (v vocabulary) byteFallback(text string) ([]int, bool) {
+	ids := make([]int, 0, len(text))
+	for i := range len(text) {
+		id, ok := v.ids[fmt.Sprintf("<0x%02X>", text[i])]
+		if !ok {
+			return nil, false
+		}
+		ids = append(ids, id)
+	}
+	return ids, true
+}
+
+// bpe is a byte-pair encoding model, which merges the runes of words by
+// pairs in the order of its merges.
+type bpe struct {
+	vocabulary
+	ranks        map[[2]string]int
+	unk          *int
+	prefix       string
+	suffix       string
+	fuseUnk      bool
+	byteFallback bool
+	ignoreMerges bool
+	cache        sync.Map
+}
+
+func newBPE(m modelJSON) (*bpe, error) {
+	var ids map[string]int
+	if err := json.Unmarshal(m.Vocab, &ids); err != nil {
+		return nil, fmt.Errorf("bpe vocab: %w", err)
+	}
+	b := &bpe{
+		vocabulary:   newVocabulary(ids),
+		ranks:        make(map[[2]string]int, len(m.Merges)),
+		fuseUnk:      m.FuseUnk,
+		byteFallback: m.ByteFallback,
+		ignoreMerges: m.IgnoreMerges,
+	}
+	if m.ContinuingSubwordPrefix != nil {
+		b.prefix = *m.ContinuingSubwordPrefix
+	}
+	if m.EndOfWordSuffix != nil {
+		b.suffix = *m.EndOfWordSuffix
+	}
+	if m.UnkToken != nil {
+		if id, ok := ids[*m.UnkToken]; ok {
+			b.unk = &id
+		}
+	}
+
+	for rank, data := range m.Merges {
+		// Merges are "a b" strings, or ["a", "b"] pairs in newer files.
+		var pair [2]string
+		var merge string
+		if err := json.Unmarshal(data, &merge); err == nil {
+			left, right, ok := strings.Cut(merge, " ")
+			if !ok {
+				return nil, fmt.Errorf("bpe merge %q: missing space", merge)
+			}
+			pair = [2]string{left, right}
+		} else if err := json.Unmarshal(data, &pair); err != nil {
+			return nil, fmt.Errorf("bpe merge %s: %w", data, err)
+		}
+		if _, ok := b.ranks[pair]; !ok {
+			b.ranks[pair] = rank
+		}
+	}
+	return b, nil
+}
+
+func (b *bpe) tokenize(word string) []int {
+	if ids, ok := b.cache.Load(word); ok {
+		return ids.([]int) //nolint:forcetypeassert
+	}
+	ids := b.encode(word)
+	b.cache.Store(word, ids)
+	return ids
+}
+
+func (b *bpe) encode(word string) []int {
+	if id, ok := b.ids[word]; ok && b.ignoreMerges {
+		return []int{id}
+	}
+
+	symbols := make([]string, 0, len(word))
+	for i, r := range word {
+		symbol := string(r)
+		if i > 0 {
+			symbol = b.prefix + symbol
+		}
+		symbols = append(symbols, symbol)
+	}
+	if b.suffix != "" && len(symbols) > 0 {
+		symbols[len(symbols)-1] += b.suffix
+	}
+
+	for len(symbols) > 1 {
+		best, bestRank := -1, math.MaxInt
+		for i := 0; i+1 < len(symbols); i++ {
+			if rank, ok := b.ranks[[2]string{symbols[i], symbols[i+1]}]; ok && rank < bestRank {
+				best, bestRank = i, rank
+			}
+		}
+		if best < 0 {
+			break
+		}
+		merged := symbols[best] + strings.TrimPrefix(symbols[best+1], b.prefix)
+		symbols = slices.Replace(symbols, best, best+2, merged)
+	}
+
+	ids := make([]int, 0, len(symbols))
+	unknown := false
+	for _, symbol := range symbols {
+		if id, ok := b.ids[symbol]; ok {
+			ids = append(ids, id)
+			unknown = false
+			continue
+		}
+		if b.byteFallback {
+			if bytes, ok := b.vocabulary.byteFallback(symbol); ok {
+				ids = append(ids, bytes...)
+				unknown = false
+				continue
+			}
+		}
+		if b.unk != nil && !(unknown && b.fuseUnk) {
+			ids = append(ids, *b.unk)
+		}
+		unknown = true
+	}
+	return ids
+}
+
+// unigram is a unigram language model, which splits words into the pieces
+// of maximal likelihood.
+type unigram struct {
+	vocabulary
+	scores       map[int]float64
+	unk          *int
+	unkScore     float64
+	maxLen       int
+	byteFallback bool
+}
+
+func newUnigram(m modelJSON) (*unigram, error) {
+	var vocab [][2]json.RawMessage
+	if err := json.Unmarshal(m.Vocab, &vocab); err != nil {
+		return nil, fmt.Errorf("unigram vocab: %w", err)
+	}
+	u := &unigram{
+		scores:       make(map[int]float64, len(vocab)),
+		unk:          m.UnkID,
+		byteFallback: m.ByteFallback,
+	}
+	ids := make(map[string]int, len(vocab))
+	minScore := math.Inf(1)
+	for id, entry := range vocab {
+		var piece string
+		var score float64
+		if err := json.Unmarshal(entry[0], &piece); err != nil {
+			return nil, fmt.Errorf("unigram piece %d: %w", id, err)
+		}
+		if err := json.Unmarshal(entry[1], &score); err != nil {
+			return nil, fmt.Errorf("unigram score %d: %w", id, err)
+		}
+		ids[piece] = id
+		u.scores[id] = score
+		minScore = min(minScore, score)
+		u.maxLen = max(u.maxLen, len(piece))
+	}
+	u.vocabulary = newVocabulary(ids)
+	u.unkScore = minScore - _unknownPenalty
+	return u, nil
+}
+
+func (u *unigram) tokenize(word string) []int {
+	// best[i] is the most likely segmentation of word[:i], found by the
+	// Viterbi algorithm: the score of the segmentation, and the start and the
+	// token of its last piece, -1 if unknown.
+	type node struct {
+		score float64
+		start int
+		id    int
+		ok    bool
+	}
+	best := make([]node, len(word)+1)
+	best[0].ok = true
+	for start := range word {
+		if !best[start].ok {
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(word[start:])
+		single := false
+		for end := start + size; end <= len(word) && end-start <= u.maxLen; {
+			if id, ok := u.ids[word[start:end]]; ok {
+				score := best[start].score + u.scores[id]
+				if !best[end].ok || score > best[end].score {
+					best[end] = node{score: score, start: start, id: id, ok: true}
+				}
+				single = single || end == start+size
+			}
+			if end == len(word) {
+				break
+			}
+			_, next := utf8.DecodeRuneInString(word[end:])
+			end += next
+		}
+		if end := start + size; !single {
+			score := best[start].score + u.unkScore
+			if !best[end].ok || score > best[end].score {
+				best[end] = node{score: score, start: start, id: -1, ok: true}
+			}
+		}
+	}
+
+	type piece struct{ start, end, id int }
+	var pieces []piece
+	for end := len(word); end > 0; end = best[end].start {
+		pieces = append(pieces, piece{start: best[end].start, end: end, id: best[end].id})
+	}
+	slices.Reverse(pieces)
+
+	ids := make([]int, 0, len(pieces))
+	unknown := false
+	for _, p := range pieces {
+		if p.id >= 0 {
+			ids = append(ids, p.id)
+			unknown = false
+			continue
+		}
+		if u.byteFallback {
+			if bytes, ok := u.vocabulary.byteFallback(word[p.start:p.end]); ok {
+				ids = append(ids, bytes...)
+				unknown = false
+				continue
+			}
+		}
+		// Unknown runes are fused as by SentencePiece.
+		if u.unk != nil && !unknown {
+			ids = append(ids, *u.unk)
+		}
+		unknown = true
+	}
+	return ids
+}
+
+// wordPiece is a WordPiece model, which splits words into the longest pieces
+// of its vocabulary from the start.
+type wordPiece struct {
+	vocabulary
+	unk      int
+	prefix   string
+	maxChars int
+}
+
+func newWordPiece(m modelJSON) (*wordPiece, error) {
+	var ids map[string]int
+	if err := json.Unmarshal(m.Vocab, &ids); err != nil {
+		return nil, fmt.Errorf("wordpiece vocab: %w", err)
+	}
+	w := &wordPiece{
+		vocabulary: newVocabulary(ids),
+		prefix:     "##",
+		maxChars:   m.MaxInputCharsPerWord,
+	}
+	if m.ContinuingSubwordPrefix != nil {
+		w.prefix = *m.ContinuingSubwordPrefix
+	}
+	if w.maxChars == 0 {
+		w.maxChars = 100
+	}
+	unk := "[UNK]"
+	if m.UnkToken != nil {
+		unk = *m.UnkToken
+	}
+	id, ok := ids[unk]
+	if !ok {
+		return nil, fmt.Errorf("wordpiece unknown token %q not in vocab", unk)
+	}
+	w.unk = id
+	return w, nil
+}
+
+func (w *wordPiece) tokenize(word string) []int {
+	if utf8.RuneCountInString(word) > w.maxChars {
+		return []int{w.unk}
+	}
+	var ids []int
+	for start := 0; start < len(word); {
+		end := len(word)
+		id := -1
+		for end > start {
+			piece := word[start:end]
+			if start > 0 {
+				piece = w.prefix + piece
+			}
+			if pieceID, ok := w.ids[piece]; ok {
+				id = pieceID
+				break
+			}
+			_, size := utf8.DecodeLastRuneInString(word[start:end])
+			end -= size
+		}
+		if id < 0 {
+			return []int{w.unk}
+		}
+		ids = append(ids, id)
+		start = end
+	}
+	return ids
+}
@@ -0,0 +1,232 @@
+package tokenizer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/dlclark/regexp2"
+	"golang.org/x/text/unicode/norm"
+)
+
+// normalizer transforms texts before they are split by the pre-tokenizer.
+type normalizer func(text string) string
+
+// pattern is a string or a regular expression matched by normalizers,
+// pre-tokenizers and decoders.
+type pattern struct {
+	String *string `json:"String"`
+	Regex  *string `json:"Regex"`
+}
+
+// matcher returns the byte offsets of the non-empty matches of a pattern.
+type matcher func(text string) [][2]int
+
+func (p pattern) compile() (matcher, error) {
+	switch {
+	case p.String != nil:
+		s := *p.String
+		return func(text string) [][2]int {
+			if s == "" {
+				return nil
+			}
+			var matches [][2]int
+			for offset := 0; ; {
+				i := strings.Index(text[offset:], s)
+				if i < 0 {
+					return matches
+				}
+				matches = append(matches, [2]int{offset + i, offset + i + len(s)})
+				offset += i + len(s)
+			}
+		}, nil
+	case p.Regex != nil:
+		re, err := regexp2.Compile(*p.Regex, regexp2.None)
+		if err != nil {
+			return nil, fmt.Errorf("pattern %q: %w", *p.Regex, err)
+		}
+		return regexMatcher(re), nil
+	default:
+		return nil, errors.New("pattern has neither String nor Regex")
+	}
+}
+
+// regexMatcher returns the matcher of a regular expression. regexp2 is used
+// as the expressions of tokenizers use lookarounds.
+func regexMatcher(re *regexp2.Regexp) matcher {
+	return func(text string) [][2]int {
+		// regexp2 matches runes: offsets maps rune offsets to byte offsets.
+		runes := []rune(text)
+		offsets := make([]int, 0, len(runes)+1)
+		for i := range text {
+			offsets = append(offsets, i)
+		}
+		offsets = append(offsets, len(text))
+
+		var matches [][2]int
+		m, err := re.FindRunesMatch(runes)
+		for m != nil && err == nil {
+			if m.Length > 0 {
+				matches = append(matches, [2]int{offsets[m.Index], offsets[m.Index+m.Length]})
+			}
+			m, err = re.FindNextMatch(m)
+		}
+		return matches
+	}
+}
+
+// replaceMatches replaces the matches of a matcher by content.
+func replaceMatches(text string, match matcher, content string) string {
+	matches := match(text)
+	if len(matches) == 0 {
+		return text
+	}
+	var sb strings.Builder
+	last := 0
+	for _, m := range matches {
+		sb.WriteString(text[last:m[0]])
+		sb.WriteString(content)
+		last = m[1]
+	}
+	sb.WriteString(text[last:])
+	return sb.String()
+}
+
+type normalizerJSON struct {
+	component
+	Normalizers        []json.RawMessage `json:"normalizers"`
+	Prepend            string            `json:"prepend"`
+	Pattern            pattern           `json:"pattern"`
+	Content            string            `json:"content"`
+	Left               bool              `json:"strip_left"`
+	Right              bool              `json:"strip_right"`
+	CleanText          bool              `json:"clean_text"`
+	HandleChineseChars bool              `json:"handle_chinese_chars"`
+	StripAccents       *bool             `json:"strip_accents"`
+	Lowercase          bool              `json:"lowercase"`
+}
+
+func newNormalizer(data json.RawMessage) (normalizer, error) {
+	if isNull(data) {
+		return func(text string) string { return text }, nil
+	}
+	var n normalizerJSON
+	if err := json.Unmarshal(data, &n); err != nil {
+		return nil, err
+	}
+
+	switch n.Type {
+	case "Sequence":
+		normalizers := make([]normalizer, 0, len(n.Normalizers))
+		for _, data := range n.Normalizers {
+			normalizer, err := newNormalizer(data)
+			if err != nil {
+				return nil, err
+			}
+			normalizers = append(normalizers, normalizer)
+		}
+		return func(text string) string {
+			for _, normalize := range normalizers {
+				text = normalize(text)
+			}
+			return text
+		}, nil
+	case "Prepend":
+		return func(text string) string { return n.Prepend + text }, nil
+	case "Replace":
+		match, err := n.Pattern.compile()
+		if err != nil {
+			return nil, err
+		}
+		return func(text string) string { return replaceMatches(text, match, n.Content) }, nil
+	case "NFC":
+		return norm.NFC.String, nil
+	case "NFD":
+		return norm.NFD.String, nil
+	case "NFKC":
+		return norm.NFKC.String, nil
+	case "NFKD":
+		return norm.NFKD.String, nil
+	case "Lowercase":
+		return strings.ToLower, nil
+	case "Strip":
+		return func(text string) string {
+			if n.Left {
+				text = strings.TrimLeftFunc(text, unicode.IsSpace)
+			}
+			if n.Right {
+				text = strings.TrimRightFunc(text, unicode.IsSpace)
+			}
+			return text
+		}, nil
+	case "StripAccents":
+		return stripAccents, nil
+	case "BertNormalizer":
+		// Accents are stripped when lowercasing unless set otherwise.
+		strip := n.Lowercase
+		if n.StripAccents != nil {
+			strip = *n.StripAccents
+		}
+		return func(text string) string {
+			if n.CleanText {
+				text = cleanText(text)
+			}
+			if n.HandleChineseChars {
+				text = padChineseChars(text)
+			}
+			if strip {
+				text = stripAccents(text)
+			}
+			if n.Lowercase {
+				text = strings.ToLower(text)
+			}
+			return text
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported normalizer %q", n.Type)
+	}
+}
+
+// stripAccents removes the combining marks of the decomposed text.
+func stripAccents(text string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Mn, r) {
+			return -1
+		}
+		return r
+	}, norm.NFD.String(text))
+}
+
+// cleanText removes the control characters of the text and replaces its
+// whitespace by spaces.
+func cleanText(text string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == 0 || r == unicode.ReplacementChar:
+			return -1
+		case unicode.IsSpace(r):
+			return ' '
+		case unicode.IsControl(r) || unicode.In(r, unicode.Cc, unicode.Cf):
+			return -1
+		default:
+			return r
+		}
+	}, text)
+}
+
+// padChineseChars surrounds the CJK ideographs of the text by spaces.
+func padChineseChars(text string) string {
+	var sb strings.Builder
+	for _, r := range text {
+		if unicode.Is(unicode.Han, r) {
+			sb.WriteRune(' ')
+			sb.WriteRune(r)
+			sb.WriteRune(' ')
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
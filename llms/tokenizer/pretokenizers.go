@@ -0,0 +1,258 @@
+package tokenizer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/dlclark/regexp2"
+)
+
+// preTokenizer splits the pieces of a normalized text into the words
+// tokenized by the model. first is whether the first piece starts the text.
+type preTokenizer func(pieces []string, first bool) []string
+
+// nolint:gochecknoglobals
+var (
+	// gpt2Split is the regular expression splitting texts into words of the
+	// ByteLevel pre-tokenizer.
+	gpt2Split = regexMatcher(regexp2.MustCompile(
+		`'s|'t|'re|'ve|'m|'ll|'d| ?\p{L}+| ?\p{N}+| ?[^\s\p{L}\p{N}]+|\s+(?!\S)|\s+`, regexp2.None))
+	whitespaceSplit = regexMatcher(regexp2.MustCompile(`\w+|[^\w\s]+`, regexp2.None))
+	digitSplit      = regexMatcher(regexp2.MustCompile(`\p{N}+`, regexp2.None))
+	digitsSplit     = regexMatcher(regexp2.MustCompile(`\p{N}`, regexp2.None))
+
+	byteToRune, runeToByte = byteLevelAlphabet()
+)
+
+// byteLevelAlphabet returns the mapping of bytes to the printable runes used
+// by byte-level tokenizers, and its inverse.
+func byteLevelAlphabet() ([256]rune, map[rune]byte) {
+	var byteToRune [256]rune
+	runeToByte := make(map[rune]byte, 256)
+	n := 0
+	for b := range 256 {
+		r := rune(b)
+		if !('!' <= b && b <= '~' || '¡' <= b && b <= '¬' || '®' <= b && b <= 'ÿ') {
+			r = rune(256 + n)
+			n++
+		}
+		byteToRune[b] = r
+		runeToByte[r] = byte(b)
+	}
+	return byteToRune, runeToByte
+}
+
+type preTokenizerJSON struct {
+	component
+	PreTokenizers    []json.RawMessage `json:"pretokenizers"`
+	Pattern          pattern           `json:"pattern"`
+	Behavior         string            `json:"behavior"`
+	Invert           bool              `json:"invert"`
+	AddPrefixSpace   bool              `json:"add_prefix_space"`
+	UseRegex         *bool             `json:"use_regex"`
+	Replacement      string            `json:"replacement"`
+	PrependScheme    string            `json:"prepend_scheme"`
+	Split            *bool             `json:"split"`
+	IndividualDigits bool              `json:"individual_digits"`
+}
+
+func newPreTokenizer(data json.RawMessage) (preTokenizer, error) { //nolint:cyclop,funlen
+	if isNull(data) {
+		return nil, nil //nolint:nilnil
+	}
+	var p preTokenizerJSON
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, err
+	}
+
+	switch p.Type {
+	case "Sequence":
+		preTokenizers := make([]preTokenizer, 0, len(p.PreTokenizers))
+		for _, data := range p.PreTokenizers {
+			preTokenizer, err := newPreTokenizer(data)
+			if err != nil {
+				return nil, err
+			}
+			if preTokenizer != nil {
+				preTokenizers = append(preTokenizers, preTokenizer)
+			}
+		}
+		return func(pieces []string, first bool) []string {
+			for _, preTokenize := range preTokenizers {
+				pieces = preTokenize(pieces, first)
+			}
+			return pieces
+		}, nil
+	case "Split":
+		match, err := p.Pattern.compile()
+		if err != nil {
+			return nil, err
+		}
+		return splitPieces(match, p.Behavior, p.Invert), nil
+	case "ByteLevel":
+		useRegex := p.UseRegex == nil || *p.UseRegex
+		return func(pieces []string, _ bool) []string {
+			var words []string
+			for _, piece := range pieces {
+				if p.AddPrefixSpace && !strings.HasPrefix(piece, " ") {
+					piece = " " + piece
+				}
+				if useRegex {
+					words = append(words, split(piece, gpt2Split(piece), "Isolated", false)...)
+				} else {
+					words = append(words, piece)
+				}
+			}
+			for i, word := range words {
+				words[i] = toByteLevel(word)
+			}
+			return words
+		}, nil
+	case "Metaspace":
+		return newMetaspace(p), nil
+	case "Whitespace":
+		return splitPieces(whitespaceSplit, "Removed", true), nil
+	case "WhitespaceSplit":
+		return func(pieces []string, _ bool) []string {
+			var words []string
+			for _, piece := range pieces {
+				words = append(words, strings.Fields(piece)...)
+			}
+			return words
+		}, nil
+	case "BertPreTokenizer":
+		return func(pieces []string, _ bool) []string {
+			var words []string
+			for _, piece := range pieces {
+				for _, field := range strings.Fields(piece) {
+					words = append(words, split(field, punctuationMatches(field), "Isolated", false)...)
+				}
+			}
+			return words
+		}, nil
+	case "Punctuation":
+		behavior := p.Behavior
+		if behavior == "" {
+			behavior = "Isolated"
+		}
+		return splitPieces(punctuationMatches, behavior, false), nil
+	case "Digits":
+		if p.IndividualDigits {
+			return splitPieces(digitsSplit, "Isolated", false), nil
+		}
+		return splitPieces(digitSplit, "Isolated", false), nil
+	default:
+		return nil, fmt.Errorf("unsupported pre-tokenizer %q", p.Type)
+	}
+}
+
+// newMetaspace returns the pre-tokenizer replacing spaces by a replacement
+// rune, prepended to the words of SentencePiece tokenizers.
+func newMetaspace(p preTokenizerJSON) preTokenizer {
+	replacement := p.Replacement
+	if replacement == "" {
+		replacement = "▁"
+	}
+	scheme := p.PrependScheme
+	if scheme == "" {
+		// Older files only have add_prefix_space.
+		scheme = "never"
+		if p.AddPrefixSpace {
+			scheme = "always"
+		}
+	}
+	splitWords := p.Split == nil || *p.Split
+	match, _ := pattern{String: &replacement}.compile()
+
+	return func(pieces []string, first bool) []string {
+		var words []string
+		for i, piece := range pieces {
+			piece = strings.ReplaceAll(piece, " ", replacement)
+			prepend := scheme == "always" || scheme == "first" && first && i == 0
+			if prepend && !strings.HasPrefix(piece, replacement) {
+				piece = replacement + piece
+			}
+			if splitWords {
+				words = append(words, split(piece, match(piece), "MergedWithNext", false)...)
+			} else {
+				words = append(words, piece)
+			}
+		}
+		return words
+	}
+}
+
+// splitPieces returns the pre-tokenizer splitting pieces at the matches of a
+// matcher, see split.
+func splitPieces(match matcher, behavior string, invert bool) preTokenizer {
+	return func(pieces []string, _ bool) []string {
+		var words []string
+		for _, piece := range pieces {
+			words = append(words, split(piece, match(piece), behavior, invert)...)
+		}
+		return words
+	}
+}
+
+// split splits a text at the matches of a pattern. behavior is what happens
+// to the matches: "Removed", "Isolated", "MergedWithPrevious",
+// "MergedWithNext" or "Contiguous". When invert is true, the text between the
+// matches is split instead.
+func split(text string, matches [][2]int, behavior string, invert bool) []string {
+	type segment struct {
+		text    string
+		isMatch bool
+	}
+	var segments []segment
+	last := 0
+	for _, m := range matches {
+		if m[0] > last {
+			segments = append(segments, segment{text[last:m[0]], invert})
+		}
+		segments = append(segments, segment{text[m[0]:m[1]], !invert})
+		last = m[1]
+	}
+	if last < len(text) {
+		segments = append(segments, segment{text[last:], invert})
+	}
+
+	var words []string
+	prevMatch := false
+	for _, s := range segments {
+		switch {
+		case s.isMatch && behavior == "Removed":
+		case s.isMatch && behavior == "MergedWithPrevious" && len(words) > 0 && !prevMatch,
+			s.isMatch && behavior == "Contiguous" && prevMatch,
+			!s.isMatch && behavior == "MergedWithNext" && prevMatch:
+			words[len(words)-1] += s.text
+		default:
+			words = append(words, s.text)
+		}
+		prevMatch = s.isMatch
+	}
+	return words
+}
+
+// punctuationMatches returns the offsets of the punctuation runes of the
+// text, ASCII symbols included.
+func punctuationMatches(text string) [][2]int {
+	var matches [][2]int
+	for i, r := range text {
+		if unicode.IsPunct(r) || r < unicode.MaxASCII && unicode.IsSymbol(r) {
+			matches = append(matches, [2]int{i, i + len(string(r))})
+		}
+	}
+	return matches
+}
+
+// toByteLevel maps the bytes of a text to the runes of the byte-level
+// alphabet.
+func toByteLevel(text string) string {
+	var sb strings.Builder
+	for i := range len(text) {
+		sb.WriteRune(byteToRune[text[i]])
+	}
+	return sb.String()
+}
@@ -0,0 +1,45 @@
+package llms
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// wordTokenizer counts a token per word.
+type wordTokenizer struct{}
+
+func (wordTokenizer) Encode(text string) []int   { return make([]int, len(strings.Fields(text))) }
+func (wordTokenizer) Decode(tokens []int) string { return strings.Repeat("word ", len(tokens)) }
+func (w wordTokenizer) Count(text string) int    { return len(w.Encode(text)) }
+
+func TestTokenizerForModel(t *testing.T) {
+	t.Parallel()
+	RegisterModel(ModelInfo{Name: "tokenizer-test", ContextSize: 1024, Tokenizer: "tokenizer-test-words"})
+	RegisterTokenizer("tokenizer-test-words", wordTokenizer{})
+
+	tokenizer, err := TokenizerForModel("tokenizer-test-v2:latest")
+	require.NoError(t, err)
+	assert.Equal(t, wordTokenizer{}, tokenizer)
+	assert.Equal(t, 3, CountTokens("tokenizer-test", "one two three"))
+
+	// The tokenizers of the models of the registry aren't public.
+	_, err = TokenizerForModel("claude-3-5-sonnet-20241022")
+	require.ErrorIs(t, err, ErrNoTokenizer)
+	_, err = TokenizerForModel("unknown-model")
+	require.ErrorIs(t, err, ErrNoTokenizer)
+}
+
+func TestTrimMessagesWithTokenizer(t *testing.T) {
+	t.Parallel()
+	messages := []MessageContent{
+		TextParts(ChatMessageTypeHuman, "one two three"),
+		TextParts(ChatMessageTypeHuman, "four"),
+	}
+	trimmed, err := TrimMessages(context.Background(), messages, 5, WithTrimTokenizer(wordTokenizer{}))
+	require.NoError(t, err)
+	assert.Equal(t, messages[1:], trimmed)
+}
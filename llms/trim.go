@@ -0,0 +1,305 @@
+package llms
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"slices"
+	"strings"
+)
+
+const (
+	// _messageTokens is the number of tokens added to each message by the
+	// chat format of the providers, e.g. for the role.
+	_messageTokens = 4
+	// _toolCallTokens is the number of tokens added to each tool call.
+	_toolCallTokens = 3
+	// _defaultImageTokens is the number of tokens of images whose size is
+	// unknown, the cost of a 1024x1024 image with OpenAI.
+	_defaultImageTokens = 765
+	// _imagePixelsPerToken and _maxImageTokens estimate the tokens of images
+	// of known size, as Anthropic does.
+	_imagePixelsPerToken = 750
+	_maxImageTokens      = 1600
+	// _binaryBytesPerToken estimates the tokens of binary parts which aren't
+	// images, e.g. documents or audio, from their size.
+	_binaryBytesPerToken = 4
+	// _defaultSummaryTokens is the maximum size of the summaries of
+	// TrimSummarize.
+	_defaultSummaryTokens = 512
+)
+
+// ErrMessagesExceedBudget is returned by TrimMessages when the messages can't
+// be trimmed to the budget, e.g. because the system messages and the last
+// message alone exceed it.
+var ErrMessagesExceedBudget = errors.New("messages exceed the token budget")
+
+// TrimStrategy is how TrimMessages reduces messages exceeding the budget.
+type TrimStrategy string
+
+const (
+	// TrimDropOldest drops the oldest messages until the messages fit.
+	TrimDropOldest TrimStrategy = "drop_oldest"
+	// TrimKeepLast keeps the last messages, as many as set by
+	// WithTrimKeepLast, then drops the oldest of them until they fit.
+	TrimKeepLast TrimStrategy = "keep_last"
+	// TrimSummarize replaces the oldest messages by a summary written by the
+	// model set by WithTrimSummarizer, keeping as many of the last messages as
+	// fit.
+	TrimSummarize TrimStrategy = "summarize"
+)
+
+// TrimOption is an option of TrimMessages.
+type TrimOption func(*trimOptions)
+
+type trimOptions struct {
+	strategy      TrimStrategy
+	keepLast      int
+	model         string
+	countTokens   func(text string) int
+	summarizer    Model
+	summaryTokens int
+}
+
+// WithTrimStrategy sets the strategy of TrimMessages, TrimDropOldest by
+// default.
+func WithTrimStrategy(strategy TrimStrategy) TrimOption {
+	return func(o *trimOptions) {
+		o.strategy = strategy
+	}
+}
+
+// WithTrimKeepLast sets the number of last messages kept by TrimKeepLast.
+func WithTrimKeepLast(n int) TrimOption {
+	return func(o *trimOptions) {
+		o.keepLast = n
+	}
+}
+
+// WithTrimModel sets the model whose tokenizer counts the tokens of the
+// messages, see CountTokens.
+func WithTrimModel(model string) TrimOption {
+	return func(o *trimOptions) {
+		o.model = model
+	}
+}
+
+// WithTrimTokenCounter sets the function counting the tokens of texts,
+// instead of the tokenizer of the model.
+func WithTrimTokenCounter(countTokens func(text string) int) TrimOption {
+	return func(o *trimOptions) {
+		o.countTokens = countTokens
+	}
+}
+
+// WithTrimTokenizer sets the tokenizer counting the tokens of the messages,
+// instead of the tokenizer of the model.
+func WithTrimTokenizer(tokenizer Tokenizer) TrimOption {
+	return func(o *trimOptions) {
+		o.countTokens = tokenizer.Count
+	}
+}
+
+// WithTrimSummarizer sets the model writing the summaries of TrimSummarize,
+// and the maximum number of tokens of the summaries (512 if zero).
+func WithTrimSummarizer(model Model, maxTokens int) TrimOption {
+	return func(o *trimOptions) {
+		o.summarizer = model
+		o.summaryTokens = maxTokens
+	}
+}
+
+// TrimMessages returns the messages trimmed to fit in maxTokens tokens, e.g.
+// the context size of a model given by LookupModel minus the tokens reserved
+// for the response. Messages fitting in the budget are returned unchanged.
+//
+// The leading system messages are always kept, and messages are removed from
+// the oldest so that the last message is kept. Tool messages whose tool call
+// was removed are removed as well, as providers reject them.
+//
+// Tokens are counted with the tokenizer of the model set by WithTrimModel,
+// including the tool calls, tool responses and images of the messages, see
+// CountMessageTokens.
+func TrimMessages(ctx context.Context, messages []MessageContent, maxTokens int, options ...TrimOption) ([]MessageContent, error) { //nolint:lll
+	opts := trimOptions{
+		strategy:      TrimDropOldest,
+		summaryTokens: _defaultSummaryTokens,
+	}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	if opts.countTokens == nil {
+		model := opts.model
+		opts.countTokens = func(text string) int { return CountTokens(model, text) }
+	}
+	if opts.summaryTokens <= 0 {
+		opts.summaryTokens = _defaultSummaryTokens
+	}
+
+	count := func(messages []MessageContent) int {
+		return countMessageTokens(opts.countTokens, messages)
+	}
+	if count(messages) <= maxTokens {
+		return slices.Clone(messages), nil
+	}
+
+	n := 0
+	for n < len(messages) && messages[n].Role == ChatMessageTypeSystem {
+		n++
+	}
+	head, body := messages[:n:n], messages[n:]
+
+	switch opts.strategy {
+	case TrimDropOldest:
+	case TrimKeepLast:
+		if opts.keepLast > 0 && len(body) > opts.keepLast {
+			body = dropOrphanToolMessages(body[len(body)-opts.keepLast:])
+		}
+	case TrimSummarize:
+		if opts.summarizer == nil {
+			return nil, errors.New("trim messages: no summarizer model")
+		}
+		budget := maxTokens - count(head) - opts.summaryTokens - _messageTokens
+		cut := len(body) - 1
+		for cut > 0 && count(body[cut-1:]) <= budget {
+			cut--
+		}
+		// The kept messages can't start with the responses to removed calls.
+		for cut < len(body)-1 && body[cut].Role == ChatMessageTypeTool {
+			cut++
+		}
+		if cut > 0 {
+			summary, err := summarizeMessages(ctx, opts.summarizer, body[:cut], opts.summaryTokens)
+			if err != nil {
+				return nil, fmt.Errorf("trim messages: %w", err)
+			}
+			head = append(head, summary)
+			body = body[cut:]
+		}
+	default:
+		return nil, fmt.Errorf("trim messages: unknown strategy %q", opts.strategy)
+	}
+
+	for len(body) > 1 && count(head)+count(body) > maxTokens {
+		body = dropOrphanToolMessages(body[1:])
+	}
+	trimmed := append(head, body...)
+	if count(trimmed) > maxTokens {
+		return nil, fmt.Errorf("%w: %d tokens remain after trimming, %d allowed",
+			ErrMessagesExceedBudget, count(trimmed), maxTokens)
+	}
+	return trimmed, nil
+}
+
+// dropOrphanToolMessages removes the leading tool messages of messages,
+// keeping the last message.
+func dropOrphanToolMessages(messages []MessageContent) []MessageContent {
+	for len(messages) > 1 && messages[0].Role == ChatMessageTypeTool {
+		messages = messages[1:]
+	}
+	return messages
+}
+
+// summarizeMessages asks the model for a summary of the messages, and
+// returns it as a system message.
+func summarizeMessages(ctx context.Context, model Model, messages []MessageContent, maxTokens int) (MessageContent, error) { //nolint:lll
+	prompt := []MessageContent{
+		TextParts(ChatMessageTypeSystem,
+			"Summarize the following conversation concisely, keeping the facts, decisions and "+
+				"open questions needed to continue it. Respond only with the summary."),
+		TextParts(ChatMessageTypeHuman, messagesTranscript(messages)),
+	}
+	resp, err := model.GenerateContent(ctx, prompt, WithMaxTokens(maxTokens))
+	if err != nil {
+		return MessageContent{}, fmt.Errorf("summarize messages: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return MessageContent{}, errors.New("summarize messages: empty response")
+	}
+	return TextParts(ChatMessageTypeSystem,
+		"Summary of the earlier conversation:\n"+strings.TrimSpace(resp.Choices[0].Content)), nil
+}
+
+// messagesTranscript returns the messages as text, one line per part.
+func messagesTranscript(messages []MessageContent) string {
+	var sb strings.Builder
+	for _, m := range messages {
+		for _, part := range m.Parts {
+			sb.WriteString(string(m.Role))
+			sb.WriteString(": ")
+			switch p := part.(type) {
+			case TextContent:
+				sb.WriteString(p.Text)
+			case ToolCall:
+				if p.FunctionCall != nil {
+					fmt.Fprintf(&sb, "[call to %s with %s]", p.FunctionCall.Name, p.FunctionCall.Arguments)
+				}
+			case ToolCallResponse:
+				fmt.Fprintf(&sb, "[%s returned %s]", p.Name, p.Content)
+			default:
+				sb.WriteString("[image]")
+			}
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// CountMessageTokens returns an estimate of the number of tokens of the
+// messages for the model, see CountTokens. Tool calls, tool responses and
+// images are included, as well as the tokens added by the chat format.
+//
+// Images are measured with the decoders registered in the image package, e.g.
+// by importing image/png; images which can't be decoded are counted as a
+// 1024x1024 image.
+func CountMessageTokens(model string, messages ...MessageContent) int {
+	return countMessageTokens(func(text string) int { return CountTokens(model, text) }, messages)
+}
+
+func countMessageTokens(countTokens func(string) int, messages []MessageContent) int {
+	n := 0
+	for _, m := range messages {
+		n += _messageTokens
+		for _, part := range m.Parts {
+			n += countPartTokens(countTokens, part)
+		}
+	}
+	return n
+}
+
+func countPartTokens(countTokens func(string) int, part ContentPart) int {
+	switch p := part.(type) {
+	case TextContent:
+		return countTokens(p.Text)
+	case ToolCall:
+		n := _toolCallTokens
+		if p.FunctionCall != nil {
+			n += countTokens(p.FunctionCall.Name) + countTokens(p.FunctionCall.Arguments)
+		}
+		return n
+	case ToolCallResponse:
+		return countTokens(p.Name) + countTokens(p.Content)
+	case ThinkingContent:
+		return countTokens(p.Thinking) + countTokens(p.RedactedData)
+	case BinaryContent:
+		if !strings.HasPrefix(p.MIMEType, "image/") {
+			return (len(p.Data) + _binaryBytesPerToken - 1) / _binaryBytesPerToken
+		}
+		return imageTokens(p.Data)
+	default:
+		return _defaultImageTokens
+	}
+}
+
+// imageTokens estimates the number of tokens of an image from its size, or
+// returns _defaultImageTokens when no registered decoder reads it.
+func imageTokens(data []byte) int {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return _defaultImageTokens
+	}
+	tokens := (config.Width*config.Height + _imagePixelsPerToken - 1) / _imagePixelsPerToken
+	return min(tokens, _maxImageTokens)
+}
@@ -0,0 +1,107 @@
+package llms
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countWords counts a token per word, so that budgets are easy to follow.
+func countWords(text string) int {
+	return len(strings.Fields(text))
+}
+
+func conversation() []MessageContent {
+	return []MessageContent{
+		TextParts(ChatMessageTypeSystem, "you are helpful"),
+		TextParts(ChatMessageTypeHuman, "one two three four"),
+		TextParts(ChatMessageTypeAI, "five six seven eight"),
+		{Role: ChatMessageTypeAI, Parts: []ContentPart{
+			ToolCall{ID: "1", FunctionCall: &FunctionCall{Name: "search", Arguments: "nine ten"}},
+		}},
+		{Role: ChatMessageTypeTool, Parts: []ContentPart{
+			ToolCallResponse{ToolCallID: "1", Name: "search", Content: "eleven twelve thirteen"},
+		}},
+		TextParts(ChatMessageTypeHuman, "fourteen fifteen"),
+	}
+}
+
+func TestCountMessageTokens(t *testing.T) {
+	t.Parallel()
+	messages := conversation()
+	// 6 messages of 4 tokens, 3 + 4 + 4 + 2 text tokens, a tool call of
+	// 3 + 1 + 2 tokens and a response of 1 + 3 tokens.
+	assert.Equal(t, 24+13+6+4, countMessageTokens(countWords, messages))
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, 75, 100))))
+	images := []MessageContent{{Role: ChatMessageTypeHuman, Parts: []ContentPart{
+		BinaryPart("image/png", buf.Bytes()),
+		BinaryPart("image/png", []byte("not an image")),
+		BinaryPart("image/webp", []byte("no decoder")),
+		ImageURLPart("https://example.com/a.png"),
+		BinaryPart("application/pdf", bytes.Repeat([]byte("%"), 400)),
+	}}}
+	assert.Equal(t, 4+10+765+765+765+100, countMessageTokens(countWords, images))
+}
+
+func TestTrimMessagesDropOldest(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	messages := conversation()
+
+	trimmed, err := TrimMessages(ctx, messages, 100, WithTrimTokenCounter(countWords))
+	require.NoError(t, err)
+	assert.Equal(t, messages, trimmed)
+
+	// Dropping the AI message with the tool call drops its response too.
+	trimmed, err = TrimMessages(ctx, messages, 20, WithTrimTokenCounter(countWords))
+	require.NoError(t, err)
+	assert.Equal(t, []MessageContent{messages[0], messages[5]}, trimmed)
+
+	trimmed, err = TrimMessages(ctx, messages, 31, WithTrimTokenCounter(countWords))
+	require.NoError(t, err)
+	assert.Equal(t, []MessageContent{messages[0], messages[3], messages[4], messages[5]}, trimmed)
+
+	_, err = TrimMessages(ctx, messages, 10, WithTrimTokenCounter(countWords))
+	require.ErrorIs(t, err, ErrMessagesExceedBudget)
+}
+
+func TestTrimMessagesKeepLast(t *testing.T) {
+	t.Parallel()
+	messages := conversation()
+
+	trimmed, err := TrimMessages(context.Background(), messages, 40,
+		WithTrimTokenCounter(countWords), WithTrimStrategy(TrimKeepLast), WithTrimKeepLast(2))
+	require.NoError(t, err)
+	// The last two messages start with a tool response, which is dropped.
+	assert.Equal(t, []MessageContent{messages[0], messages[5]}, trimmed)
+}
+
+func TestTrimMessagesSummarize(t *testing.T) {
+	t.Parallel()
+	messages := conversation()
+	summarizer := &objectModel{responses: []*ContentResponse{textResponse(" they counted to eight ")}}
+
+	trimmed, err := TrimMessages(context.Background(), messages, 45,
+		WithTrimTokenCounter(countWords),
+		WithTrimStrategy(TrimSummarize),
+		WithTrimSummarizer(summarizer, 10))
+	require.NoError(t, err)
+	require.Len(t, trimmed, 5)
+	assert.Equal(t, messages[0], trimmed[0])
+	assert.Equal(t, TextParts(ChatMessageTypeSystem,
+		"Summary of the earlier conversation:\nthey counted to eight"), trimmed[1])
+	assert.Equal(t, messages[3:], trimmed[2:])
+
+	require.Len(t, summarizer.messages, 1)
+	transcript := summarizer.messages[0][1].Parts[0].(TextContent).Text
+	assert.Equal(t, "human: one two three four\nai: five six seven eight\n", transcript)
+	assert.Equal(t, 10, summarizer.options[0].MaxTokens)
+}
@@ -0,0 +1,47 @@
+package llms
+
+// Usage is the token usage reported by a model for a single GenerateContent
+// call. Providers report usage in different shapes; adapters normalize it to
+// this structure so that callers don't need provider-specific handling.
+type Usage struct {
+	// PromptTokens is the number of tokens in the input. It includes tokens
+	// that were read from or written to the provider's prompt cache.
+	PromptTokens int `json:"prompt_tokens"`
+	// CompletionTokens is the number of tokens generated by the model. It
+	// includes reasoning tokens.
+	CompletionTokens int `json:"completion_tokens"`
+	// TotalTokens is the total number of tokens used by the call.
+	TotalTokens int `json:"total_tokens"`
+	// CachedTokens is the portion of PromptTokens served from the provider's
+	// prompt cache.
+	CachedTokens int `json:"cached_tokens,omitempty"`
+	// CacheWriteTokens is the portion of PromptTokens written to the provider's
+	// prompt cache, for providers billing cache writes separately.
+	CacheWriteTokens int `json:"cache_write_tokens,omitempty"`
+	// ReasoningTokens is the portion of CompletionTokens spent on reasoning
+	// before the final answer.
+	ReasoningTokens int `json:"reasoning_tokens,omitempty"`
+}
+
+// NewUsage creates a Usage from prompt and completion token counts, computing
+// the total.
+func NewUsage(promptTokens, completionTokens int) *Usage {
+	return &Usage{
+		PromptTokens:     promptTokens,
+		CompletionTokens: completionTokens,
+		TotalTokens:      promptTokens + completionTokens,
+	}
+}
+
+// Add accumulates the token counts of other into u. A nil other is a no-op.
+func (u *Usage) Add(other *Usage) {
+	if other == nil {
+		return
+	}
+	u.PromptTokens += other.PromptTokens
+	u.CompletionTokens += other.CompletionTokens
+	u.TotalTokens += other.TotalTokens
+	u.CachedTokens += other.CachedTokens
+	u.CacheWriteTokens += other.CacheWriteTokens
+	u.ReasoningTokens += other.ReasoningTokens
+}
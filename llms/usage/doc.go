@@ -0,0 +1,4 @@
+// Package usage provides a wrapper that aggregates token usage and estimated
+// cost of the calls made to a `llms.Model`. Usage is grouped by model name and
+// priced with a pluggable price table.
+package usage
@@ -0,0 +1,63 @@
+package usage
+
+import (
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// PriceTable is the interface that needs to be implemented by price tables.
+type PriceTable interface {
+	// Price returns the price of the given model, and whether the model is
+	// known to the table.
+	Price(model string) (Price, bool)
+}
+
+// Price is the price of a model, in currency units per million tokens.
+type Price struct {
+	// Prompt is the price of uncached input tokens.
+	Prompt float64 `json:"prompt"`
+	// Completion is the price of generated tokens, including reasoning tokens.
+	Completion float64 `json:"completion"`
+	// Cached is the price of input tokens served from the prompt cache. If
+	// zero, cached tokens are charged at the Prompt price.
+	Cached float64 `json:"cached,omitempty"`
+}
+
+// Cost estimates the cost of the given usage.
+func (p Price) Cost(u llms.Usage) float64 {
+	cachedPrice := p.Cached
+	if cachedPrice == 0 {
+		cachedPrice = p.Prompt
+	}
+	uncached := u.PromptTokens - u.CachedTokens
+	cost := float64(uncached)*p.Prompt +
+		float64(u.CachedTokens)*cachedPrice +
+		float64(u.CompletionTokens)*p.Completion
+	return cost / 1_000_000
+}
+
+// Prices is a static PriceTable keyed by model name. A model that has no exact
+// entry matches the longest key that is a prefix of its name, so an entry for
+// "gpt-4o" also prices "gpt-4o-2024-08-06".
+type Prices map[string]Price
+
+var _ PriceTable = Prices(nil)
+
+// Price implements the PriceTable interface.
+func (p Prices) Price(model string) (Price, bool) {
+	if price, ok := p[model]; ok {
+		return price, true
+	}
+	var (
+		best  Price
+		found bool
+		n     int
+	)
+	for name, price := range p {
+		if len(name) > n && strings.HasPrefix(model, name) {
+			best, found, n = price, true, len(name)
+		}
+	}
+	return best, found
+}
@@ -0,0 +1,156 @@
+package usage
+
+import (
+	"context"
+	"sync"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// UnknownModel is the name usage is recorded under when neither the call
+// options nor the tracker specify a model.
+const UnknownModel = "unknown"
+
+// Stats is the aggregated usage of a model.
+type Stats struct {
+	// Calls is the number of successful calls made.
+	Calls int `json:"calls"`
+	// Usage is the sum of the token usage reported for the calls.
+	Usage llms.Usage `json:"usage"`
+	// Cost is the estimated cost of the calls. It is zero for models the price
+	// table doesn't know.
+	Cost float64 `json:"cost"`
+}
+
+func (s *Stats) add(other Stats) {
+	s.Calls += other.Calls
+	s.Usage.Add(&other.Usage)
+	s.Cost += other.Cost
+}
+
+// Tracker is an LLM wrapper that records the token usage and estimated cost
+// of the responses from the LLM.
+type Tracker struct {
+	llm    llms.Model
+	prices PriceTable
+	model  string
+
+	mu    sync.Mutex
+	stats map[string]*Stats
+}
+
+// assert that `Tracker` implements the `llms.Model` interface.
+var _ llms.Model = (*Tracker)(nil)
+
+// Option is a functional argument that configures the Tracker.
+type Option func(*Tracker)
+
+// WithPriceTable sets the price table used to estimate costs.
+func WithPriceTable(prices PriceTable) Option {
+	return func(t *Tracker) {
+		t.prices = prices
+	}
+}
+
+// WithModel sets the model name usage is recorded under for calls that don't
+// set llms.WithModel. It should match the default model of the wrapped LLM.
+func WithModel(model string) Option {
+	return func(t *Tracker) {
+		t.model = model
+	}
+}
+
+// New wraps a Model and records the usage of every call.
+func New(llm llms.Model, opts ...Option) *Tracker {
+	t := &Tracker{
+		llm:   llm,
+		model: UnknownModel,
+		stats: make(map[string]*Stats),
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Call is a simplified interface for a text-only Model, generating a single
+// string response from a single string prompt.
+//
+// Deprecated: this method is retained for backwards compatibility. Use the
+// more general [GenerateContent] instead. You can also use
+// the [GenerateFromSinglePrompt] function which provides a similar capability
+// to Call and is built on top of the new interface.
+func (t *Tracker) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, t, prompt, options...)
+}
+
+// GenerateContent asks the model to generate content from a sequence of
+// messages and records the usage reported in the response.
+func (t *Tracker) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	response, err := t.llm.GenerateContent(ctx, messages, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	var opts llms.CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+	model := opts.Model
+	if model == "" {
+		model = t.model
+	}
+	t.record(model, response.Usage)
+
+	return response, nil
+}
+
+func (t *Tracker) record(model string, usage *llms.Usage) {
+	call := Stats{Calls: 1}
+	if usage != nil {
+		call.Usage = *usage
+		if t.prices != nil {
+			if price, ok := t.prices.Price(model); ok {
+				call.Cost = price.Cost(*usage)
+			}
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s, ok := t.stats[model]
+	if !ok {
+		s = &Stats{}
+		t.stats[model] = s
+	}
+	s.add(call)
+}
+
+// Stats returns a snapshot of the usage recorded so far, keyed by model name.
+func (t *Tracker) Stats() map[string]Stats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stats := make(map[string]Stats, len(t.stats))
+	for model, s := range t.stats {
+		stats[model] = *s
+	}
+	return stats
+}
+
+// Total returns the usage recorded so far across all models.
+func (t *Tracker) Total() Stats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var total Stats
+	for _, s := range t.stats {
+		total.add(*s)
+	}
+	return total
+}
+
+// Reset discards the usage recorded so far.
+func (t *Tracker) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stats = make(map[string]*Stats)
+}
@@ -0,0 +1,94 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// not synchronized, don't use concurrently!
+type mockLLM struct {
+	response *llms.ContentResponse
+	err      error
+}
+
+func (m *mockLLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func (m *mockLLM) GenerateContent(_ context.Context, _ []llms.MessageContent, _ ...llms.CallOption) (*llms.ContentResponse, error) {
+	return m.response, m.err
+}
+
+func TestTracker(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	rq := require.New(t)
+
+	llm := &mockLLM{
+		response: &llms.ContentResponse{
+			Choices: []*llms.ContentChoice{{Content: "hello"}},
+			Usage: &llms.Usage{
+				PromptTokens:     1000,
+				CompletionTokens: 500,
+				TotalTokens:      1500,
+				CachedTokens:     400,
+			},
+		},
+	}
+	tracker := New(llm,
+		WithModel("gpt-4o"),
+		WithPriceTable(Prices{
+			"gpt-4o": {Prompt: 2.5, Completion: 10, Cached: 1.25},
+		}),
+	)
+
+	_, err := tracker.Call(ctx, "hi")
+	rq.NoError(err)
+	_, err = tracker.Call(ctx, "hi", llms.WithModel("gpt-4o-2024-08-06"))
+	rq.NoError(err)
+	_, err = tracker.Call(ctx, "hi", llms.WithModel("llama3"))
+	rq.NoError(err)
+
+	stats := tracker.Stats()
+	rq.Len(stats, 3)
+
+	// 600 uncached prompt tokens, 400 cached prompt tokens, 500 completion tokens.
+	expectedCost := (600*2.5 + 400*1.25 + 500*10) / 1_000_000
+	rq.Equal(1, stats["gpt-4o"].Calls)
+	rq.InDelta(expectedCost, stats["gpt-4o"].Cost, 1e-12)
+	rq.InDelta(expectedCost, stats["gpt-4o-2024-08-06"].Cost, 1e-12)
+	rq.Zero(stats["llama3"].Cost, "unknown models should not be priced")
+	rq.Equal(1500, stats["llama3"].Usage.TotalTokens)
+
+	total := tracker.Total()
+	rq.Equal(3, total.Calls)
+	rq.Equal(3000, total.Usage.PromptTokens)
+	rq.Equal(1200, total.Usage.CachedTokens)
+	rq.InDelta(2*expectedCost, total.Cost, 1e-12)
+
+	tracker.Reset()
+	rq.Empty(tracker.Stats())
+}
+
+func TestTracker_Errors(t *testing.T) {
+	t.Parallel()
+
+	rq := require.New(t)
+
+	tracker := New(&mockLLM{err: errors.New("boom")})
+	_, err := tracker.Call(context.Background(), "hi")
+	rq.Error(err)
+	rq.Empty(tracker.Stats(), "failed calls should not be recorded")
+
+	tracker = New(&mockLLM{response: &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{Content: "hello"}},
+	}})
+	_, err = tracker.Call(context.Background(), "hi")
+	rq.NoError(err)
+	rq.Equal(Stats{Calls: 1}, tracker.Stats()[UnknownModel])
+}
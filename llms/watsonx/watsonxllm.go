@@ -31,12 +31,16 @@ func (wx *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOpti
 // GenerateContent implements the Model interface.
 func (wx *LLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint: lll, cyclop, whitespace
 
+	ctx = callbacks.StartRun(ctx, callbacks.RunLLM, wx.modelID)
 	if wx.CallbacksHandler != nil {
 		wx.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
 	}
 
 	prompt, err := getPrompt(messages)
 	if err != nil {
+		if wx.CallbacksHandler != nil {
+			wx.CallbacksHandler.HandleLLMError(ctx, err)
+		}
 		return nil, err
 	}
 
@@ -53,6 +57,9 @@ func (wx *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConte
 	}
 
 	if result.Text == "" {
+		if wx.CallbacksHandler != nil {
+			wx.CallbacksHandler.HandleLLMError(ctx, ErrEmptyResponse)
+		}
 		return nil, ErrEmptyResponse
 	}
 
@@ -62,6 +69,10 @@ func (wx *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConte
 				Content: result.Text,
 			},
 		},
+		Usage: llms.NewUsage(result.InputTokenCount, result.GeneratedTokenCount),
+	}
+	if wx.CallbacksHandler != nil {
+		wx.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, resp)
 	}
 	return resp, nil
 }
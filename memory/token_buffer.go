@@ -12,6 +12,9 @@ type ConversationTokenBuffer struct {
 	ConversationBuffer
 	LLM           llms.Model
 	MaxTokenLimit int
+	// Tokenizer counts the tokens of the buffer. If nil, llms.CountTokens
+	// is used.
+	Tokenizer llms.Tokenizer
 }
 
 // Statically assert that ConversationTokenBuffer implement the memory interface.
@@ -105,5 +108,8 @@ func (tb *ConversationTokenBuffer) getNumTokensFromMessages(ctx context.Context)
 		return 0, err
 	}
 
+	if tb.Tokenizer != nil {
+		return tb.Tokenizer.Count(bufferString), nil
+	}
 	return llms.CountTokens("", bufferString), nil
 }
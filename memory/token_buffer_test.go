@@ -3,6 +3,7 @@ package memory
 import (
 	"context"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -94,3 +95,32 @@ func TestTokenBufferMemoryWithPreLoadedHistory(t *testing.T) {
 	expected := map[string]any{"history": "Human: bar\nAI: foo"}
 	assert.Equal(t, expected, result)
 }
+
+// wordTokenizer counts a token per word.
+type wordTokenizer struct{}
+
+func (wordTokenizer) Encode(text string) []int   { return make([]int, len(strings.Fields(text))) }
+func (wordTokenizer) Decode(tokens []int) string { return strings.Repeat("word ", len(tokens)) }
+func (w wordTokenizer) Count(text string) int    { return len(w.Encode(text)) }
+
+func TestTokenBufferMemoryWithTokenizer(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	m := NewConversationTokenBuffer(nil, 6)
+	m.Tokenizer = wordTokenizer{}
+
+	err := m.SaveContext(ctx, map[string]any{"foo": "one two"}, map[string]any{"bar": "three"})
+	require.NoError(t, err)
+	result, err := m.LoadMemoryVariables(ctx, map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": "Human: one two\nAI: three"}, result)
+
+	// "Human: one two\nAI: three\nHuman: four\nAI: five" has 9 words: the
+	// oldest messages are removed until 6 words remain.
+	err = m.SaveContext(ctx, map[string]any{"foo": "four"}, map[string]any{"bar": "five"})
+	require.NoError(t, err)
+	result, err = m.LoadMemoryVariables(ctx, map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": "AI: three\nHuman: four\nAI: five"}, result)
+}
@@ -5,4 +5,8 @@ type Document struct {
 	PageContent string
 	Metadata    map[string]any
 	Score       float32
+	// ID is the identifier of the document in a vector store. It is set on
+	// documents returned by stores that manage documents by ID, and used to
+	// replace existing documents when upserting.
+	ID string
 }
@@ -1,6 +1,10 @@
 package textsplitter
 
-import "unicode/utf8"
+import (
+	"unicode/utf8"
+
+	"github.com/tmc/langchaingo/llms"
+)
 
 // Options is a struct that contains options for a text splitter.
 type Options struct {
@@ -13,6 +17,7 @@ type Options struct {
 	EncodingName         string
 	AllowedSpecial       []string
 	DisallowedSpecial    []string
+	Tokenizer            llms.Tokenizer
 	SecondSplitter       TextSplitter
 	CodeBlocks           bool
 	ReferenceLinks       bool
@@ -83,6 +88,17 @@ func WithEncodingName(encodingName string) Option {
 	}
 }
 
+// WithTokenizer sets the tokenizer of a token splitter, instead of the
+// tiktoken encoding set by WithEncodingName or WithModelName. The length of
+// texts is also set to their number of tokens, so that other splitters split
+// texts in chunks of ChunkSize tokens.
+func WithTokenizer(tokenizer llms.Tokenizer) Option {
+	return func(o *Options) {
+		o.Tokenizer = tokenizer
+		o.LenFunc = tokenizer.Count
+	}
+}
+
 // WithAllowedSpecial sets the allowed special tokens for a text splitter.
 func WithAllowedSpecial(allowedSpecial []string) Option {
 	return func(o *Options) {
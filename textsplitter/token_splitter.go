@@ -3,7 +3,7 @@ package textsplitter
 import (
 	"fmt"
 
-	"github.com/pkoukk/tiktoken-go"
+	"github.com/tmc/langchaingo/llms"
 )
 
 const (
@@ -14,7 +14,10 @@ const (
 	_defaultTokenChunkOverlap = 100
 )
 
-// TokenSplitter is a text splitter that will split texts by tokens.
+// TokenSplitter is a text splitter that will split texts by tokens. Tokens
+// are those of Tokenizer if set, or else of the tiktoken encoding named by
+// EncodingName, or else of the tokenizer of ModelName, see
+// llms.TokenizerForModel.
 type TokenSplitter struct {
 	ChunkSize         int
 	ChunkOverlap      int
@@ -22,6 +25,7 @@ type TokenSplitter struct {
 	EncodingName      string
 	AllowedSpecial    []string
 	DisallowedSpecial []string
+	Tokenizer         llms.Tokenizer
 }
 
 func NewTokenSplitter(opts ...Option) TokenSplitter {
@@ -37,6 +41,7 @@ func NewTokenSplitter(opts ...Option) TokenSplitter {
 		EncodingName:      options.EncodingName,
 		AllowedSpecial:    options.AllowedSpecial,
 		DisallowedSpecial: options.DisallowedSpecial,
+		Tokenizer:         options.Tokenizer,
 	}
 
 	return s
@@ -44,25 +49,44 @@ func NewTokenSplitter(opts ...Option) TokenSplitter {
 
 // SplitText splits a text into multiple text.
 func (s TokenSplitter) SplitText(text string) ([]string, error) {
-	// Get the tokenizer
-	var tk *tiktoken.Tiktoken
+	tk, err := s.tokenizer()
+	if err != nil {
+		return nil, err
+	}
+	texts := s.splitText(text, tk)
+
+	return texts, nil
+}
+
+func (s TokenSplitter) tokenizer() (llms.Tokenizer, error) {
+	if s.Tokenizer != nil {
+		return s.Tokenizer, nil
+	}
+
+	var tk llms.Tokenizer
 	var err error
 	if s.EncodingName != "" {
-		tk, err = tiktoken.GetEncoding(s.EncodingName)
+		tk, err = llms.NewTiktokenTokenizer(s.EncodingName)
 	} else {
-		tk, err = tiktoken.EncodingForModel(s.ModelName)
+		tk, err = llms.TokenizerForModel(s.ModelName)
 	}
 	if err != nil {
-		return nil, fmt.Errorf("tiktoken.GetEncoding: %w", err)
+		return nil, fmt.Errorf("get tokenizer: %w", err)
 	}
-	texts := s.splitText(text, tk)
-
-	return texts, nil
+	// The special tokens only apply to tiktoken encodings, which are copied
+	// not to change the tokenizers of the registry.
+	if tiktoken, ok := tk.(*llms.TiktokenTokenizer); ok {
+		encoding := *tiktoken
+		encoding.AllowedSpecial = s.AllowedSpecial
+		encoding.DisallowedSpecial = s.DisallowedSpecial
+		tk = &encoding
+	}
+	return tk, nil
 }
 
-func (s TokenSplitter) splitText(text string, tk *tiktoken.Tiktoken) []string {
+func (s TokenSplitter) splitText(text string, tk llms.Tokenizer) []string {
 	splits := make([]string, 0)
-	inputIDs := tk.Encode(text, s.AllowedSpecial, s.DisallowedSpecial)
+	inputIDs := tk.Encode(text)
 
 	startIdx := 0
 	curIdx := len(inputIDs)
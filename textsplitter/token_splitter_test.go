@@ -1,6 +1,8 @@
 package textsplitter
 
 import (
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -86,3 +88,41 @@ Bye!
 		assert.Equal(t, tc.expectedDocs, docs)
 	}
 }
+
+// wordTokenizer is a tokenizer with a token per word of its vocabulary.
+type wordTokenizer []string
+
+func (w wordTokenizer) Encode(text string) []int {
+	var tokens []int
+	for _, word := range strings.Fields(text) {
+		tokens = append(tokens, slices.Index(w, word))
+	}
+	return tokens
+}
+
+func (w wordTokenizer) Decode(tokens []int) string {
+	words := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		words = append(words, w[token])
+	}
+	return strings.Join(words, " ")
+}
+
+func (w wordTokenizer) Count(text string) int {
+	return len(strings.Fields(text))
+}
+
+func TestTokenSplitterWithTokenizer(t *testing.T) {
+	t.Parallel()
+	tokenizer := wordTokenizer{"one", "two", "three", "four", "five"}
+
+	splitter := NewTokenSplitter(WithTokenizer(tokenizer), WithChunkSize(3), WithChunkOverlap(1))
+	texts, err := splitter.SplitText("one two three four five")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"one two three", "three four five", "five"}, texts)
+
+	splitter2 := NewRecursiveCharacter(WithTokenizer(tokenizer), WithChunkSize(2), WithChunkOverlap(0))
+	texts, err = splitter2.SplitText("one two three four five")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"one two", "three four", "five"}, texts)
+}
@@ -0,0 +1,318 @@
+package sqldatabase
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrQueryNotAllowed is returned for queries rejected by a QueryPolicy.
+var ErrQueryNotAllowed = errors.New("query not allowed")
+
+// DefaultAllowedStatements are the statements allowed by a QueryPolicy
+// without AllowedStatements: queries.
+//
+//nolint:gochecknoglobals
+var DefaultAllowedStatements = []string{"SELECT", "WITH"}
+
+// DefaultDeniedFunctions are the functions denied by a QueryPolicy without
+// DeniedFunctions: the functions of PostgreSQL, MySQL and SQLite that have
+// side effects, e.g. set_config, access the files or the network of the
+// server, e.g. lo_import, or block it, e.g. pg_sleep. Names ending with "*"
+// are prefixes.
+//
+//nolint:gochecknoglobals
+var DefaultDeniedFunctions = []string{
+	// PostgreSQL.
+	"set_config", "nextval", "setval", "lo_*", "loread", "lowrite", "dblink*", "query_to_xml*",
+	"pg_sleep*", "pg_read_*", "pg_ls_*", "pg_stat_file", "pg_file_*", "pg_terminate_backend",
+	"pg_cancel_backend", "pg_reload_conf", "pg_rotate_logfile", "pg_promote", "pg_switch_wal",
+	"pg_create_*", "pg_drop_replication_slot", "pg_replication_*", "pg_logical_*", "pg_advisory_*",
+	"pg_try_advisory_*", "pg_backup_*", "pg_start_backup", "pg_stop_backup", "pg_notify",
+	"pg_import_system_collations", "pg_log_backend_memory_contexts",
+	// MySQL.
+	"sleep", "benchmark", "load_file", "get_lock", "release_lock", "release_all_locks",
+	"master_pos_wait", "source_pos_wait", "sys_exec", "sys_eval",
+	// SQLite.
+	"load_extension", "readfile", "writefile", "edit", "fts3_tokenizer",
+}
+
+// QueryPolicy restricts the queries run on a database, e.g. the queries
+// written by a model. The zero value only allows queries, without the
+// functions known to have side effects, see DefaultAllowedStatements and
+// DefaultDeniedFunctions. Use a database user with read-only privileges to
+// guarantee that the database isn't modified.
+type QueryPolicy struct {
+	// AllowedStatements are the allowed statements, by keyword, e.g.
+	// "SELECT". A statement is rejected if it contains a statement that isn't
+	// allowed, e.g. a DELETE in a common table expression.
+	AllowedStatements []string
+	// DeniedFunctions are the functions that can't be called, by name without
+	// schema. Names ending with "*" are prefixes. Nil means
+	// DefaultDeniedFunctions, and an empty slice allows all the functions.
+	DeniedFunctions []string
+	// AllowedTables are the tables that can be used. Empty means all tables.
+	// Names without schema match the tables of all the schemas. Queries
+	// whose tables can't be determined, e.g. with a parenthesized join, are
+	// rejected.
+	AllowedTables []string
+	// AllowedColumns are the columns that can be used, by table. The columns
+	// of the tables that aren't in the map are not restricted. A column
+	// without qualifier can be a column of any table of its query or of the
+	// enclosing queries, so it must be allowed for all of them.
+	AllowedColumns map[string][]string
+	// MaxRows is the maximum number of rows returned by queries: a LIMIT is
+	// added to queries without one, and larger limits are lowered. 0 means no
+	// maximum.
+	MaxRows int
+}
+
+// Check checks that the query is allowed by the policy, and returns it with
+// its number of rows limited to MaxRows. The errors of rejected queries wrap
+// ErrQueryNotAllowed, and explain the rejection.
+//
+// The MySQL "#" comments are removed from the returned query, so that they
+// can't hide the LIMIT of MaxRows: "#" can't be used as an operator.
+func (p QueryPolicy) Check(query string) (string, error) {
+	query, err := stripHashComments(query)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrQueryNotAllowed, err)
+	}
+	stmt, err := ParseStatement(query)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrQueryNotAllowed, err)
+	}
+	if err := p.checkStatements(stmt); err != nil {
+		return "", err
+	}
+	if err := p.checkFunctions(stmt); err != nil {
+		return "", err
+	}
+	if err := p.checkTables(stmt); err != nil {
+		return "", err
+	}
+	if err := p.checkColumns(stmt); err != nil {
+		return "", err
+	}
+	if p.MaxRows <= 0 || stmt.Type != "SELECT" && stmt.Type != "WITH" {
+		return stmt.query[:stmt.tokens[len(stmt.tokens)-1].end], nil
+	}
+	return limitRows(stmt, p.MaxRows)
+}
+
+func (p QueryPolicy) checkStatements(stmt *Statement) error {
+	allowed := p.AllowedStatements
+	if len(allowed) == 0 {
+		allowed = DefaultAllowedStatements
+	}
+	if !containsFold(allowed, stmt.Type) {
+		return fmt.Errorf("%w: %s statements are not allowed", ErrQueryNotAllowed, stmt.Type)
+	}
+	for _, keyword := range stmt.keywords {
+		if keyword == "INTO" {
+			// SELECT INTO creates a table, INSERT INTO is an INSERT.
+			if stmt.Type != "INSERT" && stmt.Type != "REPLACE" && stmt.Type != "MERGE" {
+				return fmt.Errorf("%w: INTO is not allowed in %s statements", ErrQueryNotAllowed, stmt.Type)
+			}
+			continue
+		}
+		if !containsFold(allowed, keyword) {
+			return fmt.Errorf("%w: %s statements are not allowed", ErrQueryNotAllowed, keyword)
+		}
+	}
+	return nil
+}
+
+func (p QueryPolicy) checkFunctions(stmt *Statement) error {
+	denied := p.DeniedFunctions
+	if denied == nil {
+		denied = DefaultDeniedFunctions
+	}
+	for _, function := range stmt.Functions {
+		name := strings.ToLower(lastPart(function))
+		for _, pattern := range denied {
+			pattern = strings.ToLower(pattern)
+			prefix, isPrefix := strings.CutSuffix(pattern, "*")
+			if name == pattern || isPrefix && strings.HasPrefix(name, prefix) {
+				return fmt.Errorf("%w: function %s is not allowed", ErrQueryNotAllowed, function)
+			}
+		}
+	}
+	return nil
+}
+
+func (p QueryPolicy) checkTables(stmt *Statement) error {
+	if len(p.AllowedTables) == 0 {
+		return nil
+	}
+	if stmt.unresolved {
+		return fmt.Errorf("%w: the tables of the query can't be determined", ErrQueryNotAllowed)
+	}
+	for _, table := range stmt.Tables {
+		if !matchesTable(p.AllowedTables, table) {
+			return fmt.Errorf("%w: table %s is not allowed", ErrQueryNotAllowed, table)
+		}
+	}
+	return nil
+}
+
+func (p QueryPolicy) checkColumns(stmt *Statement) error { //nolint:cyclop
+	if len(p.AllowedColumns) == 0 {
+		return nil
+	}
+	restricted := map[string][]string{}
+	for _, table := range stmt.Tables {
+		if columns, ok := p.allowedColumns(table); ok {
+			restricted[table] = columns
+		}
+	}
+	if stmt.unresolved {
+		return fmt.Errorf("%w: the tables of the query can't be determined", ErrQueryNotAllowed)
+	}
+	if len(restricted) == 0 {
+		return nil
+	}
+	if stmt.Star {
+		return fmt.Errorf("%w: * is not allowed, select the allowed columns explicitly", ErrQueryNotAllowed)
+	}
+
+	for i, column := range stmt.Columns {
+		if column.Table != "" {
+			if strings.HasPrefix(column.Table, "?") {
+				// A column of a subquery, checked in the subquery.
+				continue
+			}
+			columns, ok := restricted[column.Table]
+			if ok && !containsFold(columns, column.Column) {
+				return fmt.Errorf("%w: column %s of table %s is not allowed",
+					ErrQueryNotAllowed, column.Column, column.Table)
+			}
+			continue
+		}
+
+		// The column is a column of one of the tables of its query, or of
+		// the enclosing queries. Without tables, it's a column of a subquery,
+		// checked in the subquery.
+		tables := stmt.scopeTables(stmt.columnScopes[i])
+		allowed, restrictedTables := false, 0
+		for _, table := range tables {
+			if columns, ok := restricted[table]; ok {
+				allowed = allowed || containsFold(columns, column.Column)
+				restrictedTables++
+			}
+		}
+		switch {
+		case allowed, restrictedTables == 0:
+		case restrictedTables == len(tables):
+			return fmt.Errorf("%w: column %s is not allowed", ErrQueryNotAllowed, column.Column)
+		default:
+			return fmt.Errorf("%w: column %s is ambiguous, qualify it with its table",
+				ErrQueryNotAllowed, column.Column)
+		}
+	}
+	return nil
+}
+
+// allowedColumns returns the allowed columns of a table, if restricted.
+func (p QueryPolicy) allowedColumns(table string) ([]string, bool) {
+	for name, columns := range p.AllowedColumns {
+		if matchesTable([]string{name}, table) {
+			return columns, true
+		}
+	}
+	return nil, false
+}
+
+// stripHashComments removes the MySQL "#" comments of a query, which lex
+// returns as tokens. The query is lexed again after each comment, since a
+// comment can contain quotes, e.g. "# don't".
+func stripHashComments(query string) (string, error) {
+	for {
+		tokens, err := lex(query)
+		i := slices.IndexFunc(tokens, func(t token) bool { return t.kind == tokenPunct && t.text == "#" })
+		if i < 0 {
+			return query, err
+		}
+		start := tokens[i].start
+		end := strings.IndexByte(query[start:], '\n')
+		if end < 0 {
+			query = query[:start]
+			continue
+		}
+		query = query[:start] + query[start+end:]
+	}
+}
+
+// limitRows limits the number of rows returned by a query: it lowers the
+// LIMIT or FETCH FIRST clause of the query, or adds a LIMIT clause.
+func limitRows(stmt *Statement, maxRows int) (string, error) {
+	tokens := stmt.tokens
+	query := stmt.query[:tokens[len(tokens)-1].end]
+
+	// The clause at the top level of the query, which applies to the whole
+	// query, rather than in a subquery.
+	clause := -1
+	depth := 0
+	for i, t := range tokens {
+		switch {
+		case t.text == "(":
+			depth++
+		case t.text == ")":
+			depth--
+		case depth == 0 && t.is("LIMIT", "FETCH"):
+			clause = i
+		}
+	}
+	if clause < 0 {
+		return query + " LIMIT " + strconv.Itoa(maxRows), nil
+	}
+
+	count := clause + 1
+	if tokens[clause].is("FETCH") {
+		count++ // FETCH FIRST n ROWS ONLY.
+		if count < len(tokens) && tokens[count].is("ROW", "ROWS") {
+			return query, nil // FETCH FIRST ROW ONLY.
+		}
+	} else if count+2 < len(tokens) && tokens[count+1].text == "," {
+		count += 2 // LIMIT offset, count.
+	}
+	if count >= len(tokens) {
+		return "", fmt.Errorf("%w: invalid %s clause", ErrQueryNotAllowed, strings.ToUpper(tokens[clause].text))
+	}
+
+	t := tokens[count]
+	if !t.is("ALL") {
+		n, err := strconv.Atoi(t.text)
+		if err != nil || t.kind != tokenNumber {
+			return "", fmt.Errorf("%w: the number of rows of %s must be a number",
+				ErrQueryNotAllowed, strings.ToUpper(tokens[clause].text))
+		}
+		if n <= maxRows {
+			return query, nil
+		}
+	}
+	return query[:t.start] + strconv.Itoa(maxRows) + query[t.end:], nil
+}
+
+// matchesTable reports whether a table is one of the tables. Tables without
+// schema match the tables of all the schemas.
+func matchesTable(tables []string, table string) bool {
+	for _, name := range tables {
+		if strings.EqualFold(name, table) ||
+			!strings.Contains(name, ".") && strings.EqualFold(name, lastPart(table)) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
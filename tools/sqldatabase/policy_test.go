@@ -0,0 +1,215 @@
+package sqldatabase
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStatement(t *testing.T) {
+	t.Parallel()
+
+	stmt, err := ParseStatement(`
+		WITH recent AS (SELECT user_id, total FROM orders WHERE created_at > DATE '2024-01-01')
+		SELECT u.name, COUNT(*) AS n, SUM(r.total) -- the revenue; per user
+		FROM "public"."users" AS u JOIN recent r ON r.user_id = u.id
+		WHERE EXTRACT(YEAR FROM u.created_at) = 2024 AND u.name LIKE 'a;b'
+		GROUP BY u.name ORDER BY n DESC;`)
+	require.NoError(t, err)
+	require.Equal(t, "WITH", stmt.Type)
+	require.Equal(t, []string{"orders", "public.users"}, stmt.Tables)
+	require.False(t, stmt.Star)
+	require.Equal(t, []ColumnRef{
+		{Column: "user_id"},
+		{Column: "total"},
+		{Column: "created_at"},
+		{Table: "public.users", Column: "name"},
+		{Table: "?recent", Column: "total"},
+		{Table: "?recent", Column: "user_id"},
+		{Table: "public.users", Column: "id"},
+		{Table: "public.users", Column: "created_at"},
+		{Table: "public.users", Column: "name"},
+		{Table: "public.users", Column: "name"},
+	}, stmt.Columns)
+
+	_, err = ParseStatement("SELECT 1; DROP TABLE users")
+	require.ErrorIs(t, err, ErrMultipleStatements)
+
+	for _, query := range []string{
+		"",
+		"-- nothing",
+		"SELECT 'unterminated",
+		"SELECT 'it\\'s'",
+		"SELECT $$x$$",
+		"SELECT /*! DROP TABLE users */ 1",
+	} {
+		_, err := ParseStatement(query)
+		require.ErrorIs(t, err, ErrInvalidQuery, query)
+	}
+}
+
+func TestQueryPolicyStatements(t *testing.T) {
+	t.Parallel()
+
+	policy := QueryPolicy{}
+	for _, query := range []string{
+		"SELECT * FROM users",
+		"select name from users where id in (select user_id from orders)",
+		"WITH t AS (SELECT 1) SELECT * FROM t",
+		"SELECT REPLACE(name, 'a', 'b') FROM users",
+		"SELECT 1 --1",
+		"SELECT 1 # ; DELETE FROM users",
+	} {
+		_, err := policy.Check(query)
+		require.NoError(t, err, query)
+	}
+
+	for _, query := range []string{
+		"DROP TABLE users",
+		"delete from users",
+		"UPDATE users SET name = 'x'",
+		"INSERT INTO users VALUES (1)",
+		"PRAGMA writable_schema = 1",
+		"WITH d AS (DELETE FROM users RETURNING *) SELECT * FROM d",
+		"SELECT * INTO backup FROM users",
+		"SELECT * FROM users FOR UPDATE",
+		"SELECT 1; DELETE FROM users",
+		"SELECT 1 # comment\n; DELETE FROM users",
+		"SELECT 1 --1\n; DELETE FROM users",
+		"SELECT 'a\\'; DELETE FROM users; --'",
+	} {
+		_, err := policy.Check(query)
+		require.ErrorIs(t, err, ErrQueryNotAllowed, query)
+	}
+
+	policy.AllowedStatements = []string{"SELECT", "INSERT"}
+	_, err := policy.Check("INSERT INTO users (id, name) SELECT id, name FROM staff")
+	require.NoError(t, err)
+	_, err = policy.Check("WITH t AS (SELECT 1) SELECT * FROM t")
+	require.ErrorIs(t, err, ErrQueryNotAllowed)
+}
+
+func TestQueryPolicyFunctions(t *testing.T) {
+	t.Parallel()
+
+	stmt, err := ParseStatement(`SELECT lower(name), pg_catalog.set_config('a', 'b', false), "pg_sleep"(1)
+		FROM users u, generate_series(1, 2) WHERE u.id IN (SELECT max(id) FROM orders)`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"lower", "pg_catalog.set_config", "pg_sleep", "generate_series", "max"}, stmt.Functions)
+	require.Equal(t, []string{"users", "orders"}, stmt.Tables)
+	require.Equal(t, []ColumnRef{{Column: "name"}, {Table: "users", Column: "id"}, {Column: "id"}}, stmt.Columns)
+
+	policy := QueryPolicy{}
+	for _, query := range []string{
+		"SELECT lower(name), count(*) FROM users GROUP BY 1",
+		"SELECT current_setting('timezone')",
+		"SELECT * FROM generate_series(1, 10)",
+	} {
+		_, err := policy.Check(query)
+		require.NoError(t, err, query)
+	}
+
+	for _, query := range []string{
+		"SELECT lo_import('/etc/passwd')",
+		"SELECT set_config('a','b',false)",
+		"SELECT pg_sleep(100)",
+		"SELECT PG_CATALOG.PG_SLEEP(100)",
+		`SELECT "pg_sleep"(100)`,
+		"SELECT pg_read_file('/etc/passwd')",
+		"SELECT * FROM dblink('host=evil', 'SELECT 1') AS t(a int)",
+		"SELECT name FROM users WHERE id = (SELECT nextval('users_id_seq'))",
+		"SELECT SLEEP(10)",
+		"SELECT load_file('/etc/passwd')",
+		"SELECT load_extension('evil')",
+	} {
+		_, err := policy.Check(query)
+		require.ErrorIs(t, err, ErrQueryNotAllowed, query)
+	}
+
+	policy.DeniedFunctions = []string{"lower"}
+	_, err = policy.Check("SELECT pg_sleep(1)")
+	require.NoError(t, err)
+	_, err = policy.Check("SELECT LOWER(name) FROM users")
+	require.ErrorIs(t, err, ErrQueryNotAllowed)
+}
+
+func TestQueryPolicyTablesAndColumns(t *testing.T) {
+	t.Parallel()
+
+	policy := QueryPolicy{
+		AllowedTables: []string{"users", "orders", "sales.regions"},
+		AllowedColumns: map[string][]string{
+			"users": {"id", "name"},
+		},
+	}
+	for _, query := range []string{
+		"SELECT name FROM users",
+		"SELECT u.name, o.total FROM public.users u JOIN orders o ON o.user_id = u.id",
+		"SELECT COUNT(*) FROM Users WHERE NAME = 'x'",
+		"SELECT n FROM (SELECT name AS n FROM users) s WHERE s.n <> ''",
+		"SELECT * FROM orders",
+		"SELECT name FROM sales.regions",
+		"SELECT x FROM generate_series(1, 3) AS x",
+		"SELECT name FROM ONLY users",
+		"SELECT s.x, name FROM (SELECT 1 AS x) s, users u",
+		"SELECT u.name, g.x FROM generate_series(1, 3) AS g(x) CROSS JOIN users u",
+		"SELECT COUNT(*) AS n FROM users GROUP BY name ORDER BY n DESC",
+		"SELECT name FROM users WHERE id IS DISTINCT FROM 1",
+		"SELECT name FROM users WHERE id IN (SELECT o.user_id FROM orders o)",
+	} {
+		_, err := policy.Check(query)
+		require.NoError(t, err, query)
+	}
+
+	for _, query := range []string{
+		"SELECT name FROM secrets",
+		"SELECT name FROM other.regions",
+		"SELECT o.total FROM orders o, secrets",
+		"SELECT password FROM users",
+		"SELECT u.password FROM users u",
+		"SELECT * FROM users",
+		"SELECT u.* FROM users u",
+		"SELECT name FROM users WHERE password IS NULL",
+		"SELECT total FROM orders JOIN users ON users.id = orders.user_id",
+		"SELECT * FROM ONLY secrets",
+		"SELECT s.x FROM (SELECT 1) s, secrets",
+		"SELECT name FROM generate_series(1, 1) g, secrets",
+		"SELECT name FROM users u JOIN orders o ON o.user_id = u.id, secrets",
+		"SELECT name FROM (users JOIN secrets ON true)",
+		"SELECT b FROM users AS u(a, b)",
+		"SELECT name AS password FROM users WHERE password = 'x'",
+		"SELECT name AS password FROM users ORDER BY users.password",
+		"SELECT name FROM users WHERE EXISTS (SELECT 1 FROM orders WHERE password = 'x')",
+	} {
+		_, err := policy.Check(query)
+		require.ErrorIs(t, err, ErrQueryNotAllowed, query)
+	}
+}
+
+func TestQueryPolicyMaxRows(t *testing.T) {
+	t.Parallel()
+
+	policy := QueryPolicy{MaxRows: 10}
+	for query, want := range map[string]string{
+		"SELECT * FROM users":                                "SELECT * FROM users LIMIT 10",
+		"SELECT * FROM users; -- all users":                  "SELECT * FROM users LIMIT 10",
+		"SELECT * FROM users LIMIT 5":                        "SELECT * FROM users LIMIT 5",
+		"SELECT * FROM users LIMIT 500 OFFSET 20":            "SELECT * FROM users LIMIT 10 OFFSET 20",
+		"SELECT * FROM users LIMIT 20, 500":                  "SELECT * FROM users LIMIT 20, 10",
+		"SELECT * FROM users LIMIT ALL":                      "SELECT * FROM users LIMIT 10",
+		"SELECT * FROM users FETCH FIRST 50 ROWS ONLY":       "SELECT * FROM users FETCH FIRST 10 ROWS ONLY",
+		"SELECT * FROM (SELECT * FROM users LIMIT 50) u":     "SELECT * FROM (SELECT * FROM users LIMIT 50) u LIMIT 10",
+		"SELECT id FROM users UNION SELECT id FROM orders":   "SELECT id FROM users UNION SELECT id FROM orders LIMIT 10",
+		"WITH t AS (SELECT * FROM users) SELECT * FROM t":    "WITH t AS (SELECT * FROM users) SELECT * FROM t LIMIT 10",
+		"SELECT id FROM users ORDER BY id DESC LIMIT 100 ; ": "SELECT id FROM users ORDER BY id DESC LIMIT 10",
+		"SELECT * FROM users # all users":                    "SELECT * FROM users LIMIT 10",
+		"SELECT * FROM users # it's\nWHERE id > 1":           "SELECT * FROM users \nWHERE id > 1 LIMIT 10",
+	} {
+		got, err := policy.Check(query)
+		require.NoError(t, err, query)
+		require.Equal(t, want, got, query)
+	}
+
+	_, err := policy.Check("SELECT * FROM users LIMIT ?")
+	require.ErrorIs(t, err, ErrQueryNotAllowed)
+}
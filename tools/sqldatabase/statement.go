@@ -0,0 +1,735 @@
+package sqldatabase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	// ErrInvalidQuery is returned for queries that can't be analyzed, e.g.
+	// with an unterminated string.
+	ErrInvalidQuery = errors.New("invalid query")
+	// ErrMultipleStatements is returned for queries of several statements.
+	ErrMultipleStatements = errors.New("only a single statement is allowed")
+)
+
+type tokenKind int
+
+const (
+	// tokenWord is a keyword or an unquoted identifier.
+	tokenWord tokenKind = iota
+	// tokenQuoted is a quoted identifier, e.g. "name" or `name`.
+	tokenQuoted
+	tokenString
+	tokenNumber
+	tokenPunct
+)
+
+// token is a token of a query, at query[start:end].
+type token struct {
+	kind       tokenKind
+	text       string
+	start, end int
+}
+
+// is reports whether the token is one of the keywords, case-insensitively.
+func (t token) is(keywords ...string) bool {
+	if t.kind != tokenWord {
+		return false
+	}
+	for _, keyword := range keywords {
+		if strings.EqualFold(t.text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// name returns the identifier of a word or a quoted identifier.
+func (t token) name() string {
+	if t.kind == tokenQuoted {
+		return t.text[1 : len(t.text)-1]
+	}
+	return t.text
+}
+
+// lex splits a query into tokens, skipping whitespace and comments. On error,
+// it returns the tokens preceding the error.
+//
+// The lexer doesn't know the dialect of the query: when dialects disagree,
+// e.g. on MySQL "#" comments, the text is lexed as tokens so that it's
+// analyzed rather than hidden, and constructs that can't be lexed safely for
+// all dialects are rejected.
+func lex(query string) ([]token, error) { //nolint:cyclop,funlen
+	var tokens []token
+	for i := 0; i < len(query); {
+		r, size := utf8.DecodeRuneInString(query[i:])
+		start := i
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+			continue
+		case strings.HasPrefix(query[i:], "--") && (i+2 == len(query) || isSpace(query[i+2])):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				return tokens, nil
+			}
+			i += end + 1
+			continue
+		case strings.HasPrefix(query[i:], "/*"):
+			if strings.HasPrefix(query[i:], "/*!") {
+				return tokens, fmt.Errorf("%w: executable comments are not allowed", ErrInvalidQuery)
+			}
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				return tokens, fmt.Errorf("%w: unterminated comment", ErrInvalidQuery)
+			}
+			i += end + 4
+			continue
+		case r == '\'':
+			end, err := closingQuote(query, i, '\'')
+			if err != nil {
+				return tokens, err
+			}
+			if strings.ContainsRune(query[i:end], '\\') {
+				// Backslashes escape quotes in MySQL but not in standard SQL.
+				return tokens, fmt.Errorf("%w: backslashes in string literals are not allowed", ErrInvalidQuery)
+			}
+			i = end
+			tokens = append(tokens, token{kind: tokenString, text: query[start:i], start: start, end: i})
+			continue
+		case r == '"' || r == '`':
+			end, err := closingQuote(query, i, byte(r))
+			if err != nil {
+				return tokens, err
+			}
+			i = end
+			tokens = append(tokens, token{kind: tokenQuoted, text: query[start:i], start: start, end: i})
+			continue
+		case r == '$' && i+1 < len(query) && !isDigit(query[i+1]):
+			return tokens, fmt.Errorf("%w: dollar-quoted strings are not allowed", ErrInvalidQuery)
+		case unicode.IsLetter(r) || r == '_':
+			i += size
+			for i < len(query) {
+				r, size := utf8.DecodeRuneInString(query[i:])
+				if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '$' {
+					break
+				}
+				i += size
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: query[start:i], start: start, end: i})
+			continue
+		case isDigit(query[i]) || query[i] == '.' && i+1 < len(query) && isDigit(query[i+1]):
+			i++
+			for i < len(query) && (isDigit(query[i]) || query[i] == '.' || query[i] == 'e' || query[i] == 'E') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: query[start:i], start: start, end: i})
+			continue
+		case strings.HasPrefix(query[i:], "::"):
+			i += 2
+		default:
+			i += size
+		}
+		tokens = append(tokens, token{kind: tokenPunct, text: query[start:i], start: start, end: i})
+	}
+	return tokens, nil
+}
+
+// closingQuote returns the offset after the quote closing the quoted text
+// starting at query[start]. Doubled quotes are escaped quotes.
+func closingQuote(query string, start int, quote byte) (int, error) {
+	for i := start + 1; i < len(query); i++ {
+		if query[i] != quote {
+			continue
+		}
+		if i+1 < len(query) && query[i+1] == quote {
+			i++
+			continue
+		}
+		return i + 1, nil
+	}
+	return 0, fmt.Errorf("%w: unterminated quote %c", ErrInvalidQuery, quote)
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r' || b == '\f'
+}
+
+func isDigit(b byte) bool {
+	return '0' <= b && b <= '9'
+}
+
+// Statement is a SQL statement analyzed by ParseStatement.
+type Statement struct {
+	// Type is the first keyword of the statement, in upper case, e.g.
+	// "SELECT" or "WITH".
+	Type string
+	// Tables are the tables the statement reads or writes, as written, e.g.
+	// "public.users". The names of common table expressions aren't tables.
+	Tables []string
+	// Columns are the columns referenced by the statement. Their Table is the
+	// table of their qualifier, aliases resolved, if any.
+	Columns []ColumnRef
+	// Star is whether the statement selects all the columns of a table, with
+	// "*" or "table.*".
+	Star bool
+	// Functions are the functions called by the statement, as written, e.g.
+	// "pg_catalog.set_config".
+	Functions []string
+
+	query  string
+	tokens []token
+	// keywords are the statement keywords of the statement, e.g. "DELETE" in
+	// a data-modifying common table expression.
+	keywords []string
+	// aliases maps the aliases of tables to the tables.
+	aliases map[string]string
+	// scopes are the scopes of the tokens, and parents the enclosing scope of
+	// each scope: the statement is scope 0, and each parenthesis opens a
+	// scope.
+	scopes, parents []int
+	// tableScopes and columnScopes are the scopes of the Tables and Columns.
+	tableScopes, columnScopes []int
+	// unresolved is whether a table source of the statement can't be
+	// analyzed, e.g. a parenthesized join, so that Tables may be incomplete.
+	unresolved bool
+}
+
+// ColumnRef is a reference to a column.
+type ColumnRef struct {
+	// Table is the table of the qualifier of the column, empty for columns
+	// without qualifier. For qualifiers that aren't tables, e.g. subqueries,
+	// Table is the qualifier prefixed by "?".
+	Table  string
+	Column string
+}
+
+// ParseStatement analyzes a single SQL statement. A trailing semicolon is
+// allowed, and ErrMultipleStatements is returned for several statements.
+//
+// The analysis is lexical and doesn't depend on the dialect: it finds the
+// tables following FROM, JOIN, INTO, UPDATE and TABLE, the functions, which
+// are the identifiers followed by a parenthesis, and considers the other
+// identifiers that aren't keywords or aliases to be columns.
+func ParseStatement(query string) (*Statement, error) {
+	tokens, err := lex(query)
+	if err != nil {
+		return nil, err
+	}
+	for len(tokens) > 0 && tokens[len(tokens)-1].text == ";" {
+		tokens = tokens[:len(tokens)-1]
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("%w: empty query", ErrInvalidQuery)
+	}
+	for _, t := range tokens {
+		if t.kind == tokenPunct && t.text == ";" {
+			return nil, ErrMultipleStatements
+		}
+	}
+	if tokens[0].kind != tokenWord {
+		return nil, fmt.Errorf("%w: query doesn't start with a keyword", ErrInvalidQuery)
+	}
+
+	s := &Statement{
+		Type:    strings.ToUpper(tokens[0].text),
+		query:   query,
+		tokens:  tokens,
+		aliases: map[string]string{},
+	}
+	s.analyze()
+	return s, nil
+}
+
+// statementKeywords are the keywords of statements other than queries.
+//
+//nolint:gochecknoglobals
+var statementKeywords = map[string]bool{
+	"INSERT": true, "UPDATE": true, "DELETE": true, "MERGE": true, "UPSERT": true, "REPLACE": true,
+	"DROP": true, "CREATE": true, "ALTER": true, "TRUNCATE": true, "RENAME": true,
+	"GRANT": true, "REVOKE": true, "ATTACH": true, "DETACH": true, "PRAGMA": true,
+	"COPY": true, "VACUUM": true, "INTO": true,
+}
+
+// keywords are the keywords that aren't identifiers.
+//
+//nolint:gochecknoglobals
+var keywords = map[string]bool{
+	"SELECT": true, "FROM": true, "WHERE": true, "AND": true, "OR": true, "NOT": true, "IN": true,
+	"IS": true, "NULL": true, "LIKE": true, "ILIKE": true, "GLOB": true, "REGEXP": true, "BETWEEN": true,
+	"EXISTS": true, "CASE": true, "WHEN": true, "THEN": true, "ELSE": true, "END": true, "AS": true,
+	"ON": true, "JOIN": true, "INNER": true, "LEFT": true, "RIGHT": true, "FULL": true, "OUTER": true,
+	"CROSS": true, "NATURAL": true, "USING": true, "GROUP": true, "BY": true, "ORDER": true,
+	"HAVING": true, "LIMIT": true, "OFFSET": true, "ASC": true, "DESC": true, "DISTINCT": true,
+	"ALL": true, "ANY": true, "SOME": true, "UNION": true, "INTERSECT": true, "EXCEPT": true,
+	"WITH": true, "RECURSIVE": true, "TRUE": true, "FALSE": true, "CAST": true, "INTERVAL": true,
+	"FETCH": true, "FIRST": true, "NEXT": true, "ROWS": true, "ROW": true, "ONLY": true,
+	"NULLS": true, "LAST": true, "OVER": true, "PARTITION": true, "WINDOW": true, "FILTER": true,
+	"COLLATE": true, "ESCAPE": true, "MATERIALIZED": true, "LATERAL": true, "VALUES": true,
+	"RETURNING": true, "SET": true, "TABLE": true, "FOR": true, "SHARE": true, "OF": true,
+	"EXTRACT": true, "SUBSTRING": true, "TRIM": true, "POSITION": true, "OVERLAY": true,
+	"LEADING": true, "TRAILING": true, "BOTH": true, "UNBOUNDED": true, "PRECEDING": true,
+	"FOLLOWING": true, "CURRENT": true, "RANGE": true, "GROUPS": true, "CURRENT_DATE": true,
+	"CURRENT_TIME": true, "CURRENT_TIMESTAMP": true, "EXPLAIN": true, "WITHIN": true,
+}
+
+// tableKeywords are the keywords followed by tables.
+//
+//nolint:gochecknoglobals
+var tableKeywords = map[string]bool{
+	"FROM": true, "JOIN": true, "INTO": true, "UPDATE": true, "TABLE": true,
+}
+
+// sourceEnds are the keywords that can follow a table, subquery or table
+// function of a FROM clause, and its alias.
+//
+//nolint:gochecknoglobals
+var sourceEnds = map[string]bool{
+	"JOIN": true, "INNER": true, "LEFT": true, "RIGHT": true, "FULL": true, "OUTER": true,
+	"CROSS": true, "NATURAL": true, "STRAIGHT_JOIN": true, "ON": true, "USING": true,
+}
+
+// clauseKeywords are the keywords of the clauses that end a FROM clause.
+//
+//nolint:gochecknoglobals
+var clauseKeywords = map[string]bool{
+	"WHERE": true, "GROUP": true, "HAVING": true, "WINDOW": true, "ORDER": true, "LIMIT": true,
+	"OFFSET": true, "FETCH": true, "FOR": true, "UNION": true, "INTERSECT": true, "EXCEPT": true,
+	"RETURNING": true, "INTO": true,
+}
+
+// analyze finds the tables, columns and statement keywords of the statement.
+func (s *Statement) analyze() { //nolint:cyclop,funlen,gocognit
+	tokens := s.tokens
+	ctes := s.commonTableExpressions()
+	orderBy, selectAliases := s.analyzeScopes()
+
+	// skip marks the tokens that aren't columns: tables, aliases and CTEs.
+	skip := make([]bool, len(tokens))
+	// joins marks the joins analyzed with their FROM clause.
+	joins := make([]bool, len(tokens))
+	// functionParens are the depths of the parentheses of function calls, in
+	// which FROM doesn't introduce tables, e.g. EXTRACT(YEAR FROM date).
+	var parens []bool
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		upper := strings.ToUpper(t.text)
+		switch {
+		case t.kind == tokenPunct && t.text == "(":
+			parens = append(parens, i > 0 && isFunction(tokens[i-1]))
+			continue
+		case t.kind == tokenPunct && t.text == ")":
+			if len(parens) > 0 {
+				parens = parens[:len(parens)-1]
+			}
+			continue
+		case t.kind != tokenWord:
+			continue
+		}
+
+		if statementKeywords[upper] && !s.isFunctionCall(i) && !s.isQualified(i) {
+			s.keywords = append(s.keywords, upper)
+		}
+		if !tableKeywords[upper] || len(parens) > 0 && parens[len(parens)-1] {
+			continue
+		}
+		switch {
+		case t.is("FROM"):
+			if i > 1 && tokens[i-1].is("DISTINCT") && tokens[i-2].is("IS", "NOT") {
+				continue // IS [NOT] DISTINCT FROM.
+			}
+			s.fromClause(i, skip, joins, ctes)
+		case t.is("JOIN"):
+			if joins[i] {
+				continue
+			}
+			if _, ok := s.source(i+1, true, skip, ctes); !ok {
+				s.unresolved = true
+			}
+		case t.is("UPDATE") && i > 0 && tokens[i-1].is("DO", "FOR", "KEY"):
+			// ON CONFLICT DO UPDATE, FOR [NO KEY] UPDATE or ON DUPLICATE KEY
+			// UPDATE.
+		default:
+			if _, ok := s.source(i+1, false, skip, ctes); !ok {
+				s.unresolved = true
+			}
+		}
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		if skip[i] {
+			continue
+		}
+		if t.kind == tokenPunct && t.text == "*" && i > 0 && isStarContext(tokens[i-1]) {
+			s.Star = true
+			continue
+		}
+		if t.kind != tokenWord && t.kind != tokenQuoted {
+			continue
+		}
+		upper := strings.ToUpper(t.text)
+		switch {
+		case t.kind == tokenWord && (keywords[upper] || statementKeywords[upper]):
+			continue
+		case s.isFunctionCall(i):
+			if !ctes[strings.ToLower(t.name())] {
+				s.Functions = append(s.Functions, t.name())
+			}
+			continue
+		case i > 0 && (tokens[i-1].is("AS") || tokens[i-1].text == "::"):
+			// An alias or a type.
+			continue
+		case i+1 < len(tokens) && tokens[i+1].kind == tokenString && t.kind == tokenWord:
+			// A typed literal, e.g. DATE '2024-01-01'.
+			continue
+		case i > 1 && tokens[i-1].text == "(" && tokens[i-2].is("EXTRACT"),
+			i > 1 && tokens[i-1].kind == tokenString && tokens[i-2].is("INTERVAL"):
+			// A field, e.g. EXTRACT(YEAR FROM ...) or INTERVAL '1' DAY.
+			continue
+		case ctes[strings.ToLower(t.name())] && !s.isQualifier(i):
+			continue
+		case s.isQualified(i):
+			// Handled with its qualifier.
+			continue
+		}
+
+		if s.isQualifier(i) {
+			name, next, _ := s.qualifiedName(i)
+			if s.isFunctionCall(next - 1) {
+				s.Functions = append(s.Functions, name)
+				i = next - 1
+				continue
+			}
+			column := tokens[i+2]
+			table := s.resolve(t.name(), ctes)
+			if column.kind == tokenPunct && column.text == "*" {
+				s.Star = true
+			} else {
+				s.Columns = append(s.Columns, ColumnRef{Table: table, Column: column.name()})
+				s.columnScopes = append(s.columnScopes, s.scopes[i])
+			}
+			i += 2
+			continue
+		}
+		if s.isAliasDefinition(i) {
+			continue
+		}
+		if orderBy[i] && selectAliases[s.scopes[i]][strings.ToLower(t.name())] {
+			// An alias of the select list, e.g. "n" in
+			// "SELECT COUNT(*) AS n ... ORDER BY n".
+			continue
+		}
+		s.Columns = append(s.Columns, ColumnRef{Column: t.name()})
+		s.columnScopes = append(s.columnScopes, s.scopes[i])
+	}
+}
+
+// analyzeScopes finds the scope of each token: the parentheses it's in, a
+// subquery or not. It returns the tokens in ORDER BY clauses, and the
+// aliases defined with AS in the select lists, by scope.
+func (s *Statement) analyzeScopes() ([]bool, map[int]map[string]bool) {
+	tokens := s.tokens
+	s.scopes = make([]int, len(tokens))
+	s.parents = []int{-1}
+	orderBy := make([]bool, len(tokens))
+	selectAliases := map[int]map[string]bool{}
+
+	// clauses are the clauses of the enclosing scopes, by depth.
+	clauses := []string{""}
+	scopes := []int{0}
+	for i, t := range tokens {
+		scope := scopes[len(scopes)-1]
+		switch {
+		case t.kind == tokenPunct && t.text == "(":
+			s.parents = append(s.parents, scope)
+			scopes = append(scopes, len(s.parents)-1)
+			clauses = append(clauses, "")
+		case t.kind == tokenPunct && t.text == ")":
+			if len(scopes) > 1 {
+				scopes = scopes[:len(scopes)-1]
+				clauses = clauses[:len(clauses)-1]
+			}
+			scope = scopes[len(scopes)-1]
+		case t.is("SELECT", "FROM", "WHERE", "GROUP", "HAVING", "WINDOW", "ORDER", "LIMIT", "OFFSET",
+			"FETCH", "UNION", "INTERSECT", "EXCEPT"):
+			clauses[len(clauses)-1] = strings.ToUpper(t.text)
+		case clauses[len(clauses)-1] == "SELECT" && i > 0 && tokens[i-1].is("AS") &&
+			(t.kind == tokenWord || t.kind == tokenQuoted):
+			if selectAliases[scope] == nil {
+				selectAliases[scope] = map[string]bool{}
+			}
+			selectAliases[scope][strings.ToLower(t.name())] = true
+		}
+		s.scopes[i] = scope
+		orderBy[i] = clauses[len(clauses)-1] == "ORDER"
+	}
+	return orderBy, selectAliases
+}
+
+// fromClause analyzes the FROM clause at i: its tables, subqueries and table
+// functions, separated by commas or joins. The statement is unresolved if
+// one of them can't be analyzed.
+func (s *Statement) fromClause(i int, skip, joins []bool, ctes map[string]bool) {
+	tokens := s.tokens
+	expectSource := true
+	depth := 0
+	for j := i + 1; j < len(tokens); {
+		t := tokens[j]
+		if depth == 0 && (t.text == ")" || t.kind == tokenWord && clauseKeywords[strings.ToUpper(t.text)]) {
+			return
+		}
+		if expectSource {
+			next, ok := s.source(j, true, skip, ctes)
+			if !ok || next < len(tokens) && !endsSource(tokens[next]) {
+				s.unresolved = true
+				return
+			}
+			j, expectSource = next, false
+			continue
+		}
+		switch {
+		case t.text == "(":
+			depth++
+		case t.text == ")":
+			depth--
+		case depth == 0 && t.text == ",":
+			expectSource = true
+		case depth == 0 && t.is("JOIN", "STRAIGHT_JOIN"):
+			joins[j] = true
+			expectSource = true
+		}
+		j++
+	}
+}
+
+// source analyzes the table at i, and its alias, and returns the index after
+// them. In FROM clauses, the source can also be a subquery or a table
+// function. ok is false if the source can't be analyzed, e.g. a
+// parenthesized join.
+func (s *Statement) source(i int, from bool, skip []bool, ctes map[string]bool) (int, bool) {
+	tokens := s.tokens
+	for i < len(tokens) && tokens[i].is("ONLY", "LATERAL") {
+		i++
+	}
+	table := ""
+	switch {
+	case i >= len(tokens):
+		return i, false
+	case tokens[i].text == "(":
+		if !from || i+1 >= len(tokens) || !tokens[i+1].is("SELECT", "WITH", "VALUES") {
+			return i, false
+		}
+		i = s.closingParen(i) + 1
+	default:
+		name, next, ok := s.qualifiedName(i)
+		if !ok || tokens[i].kind == tokenWord && keywords[strings.ToUpper(tokens[i].text)] {
+			return i, false
+		}
+		if from && s.isFunctionCall(next-1) {
+			// A table-valued function, e.g. FROM generate_series(1, 10).
+			i = s.closingParen(next) + 1
+			break
+		}
+		for k := i; k < next; k++ {
+			skip[k] = true
+		}
+		if !ctes[strings.ToLower(name)] {
+			s.Tables = append(s.Tables, name)
+			s.tableScopes = append(s.tableScopes, s.scopes[i])
+		}
+		table, i = name, next
+		if i < len(tokens) && tokens[i].text == "*" {
+			i++ // The descendant tables, e.g. FROM users * in PostgreSQL.
+		}
+	}
+
+	// The alias of the source, if any.
+	if i < len(tokens) && tokens[i].is("AS") {
+		i++
+	}
+	if i >= len(tokens) || tokens[i].kind != tokenQuoted && (tokens[i].kind != tokenWord ||
+		keywords[strings.ToUpper(tokens[i].text)] || statementKeywords[strings.ToUpper(tokens[i].text)] ||
+		sourceEnds[strings.ToUpper(tokens[i].text)]) {
+		return i, true
+	}
+	alias := tokens[i].name()
+	skip[i] = true
+	i++
+	if table != "" {
+		s.aliases[strings.ToLower(alias)] = table
+		// The columns of tables can't be renamed, e.g. FROM users AS u(a, b),
+		// but INSERT INTO users AS u (a, b) lists the inserted columns.
+		return i, !from || i >= len(tokens) || tokens[i].text != "("
+	}
+	s.aliases[strings.ToLower(alias)] = "?" + alias
+	if i < len(tokens) && tokens[i].text == "(" {
+		// The columns of the subquery or function, e.g. AS t(a int).
+		end := s.closingParen(i)
+		for k := i; k <= end && k < len(tokens); k++ {
+			skip[k] = true
+		}
+		i = end + 1
+	}
+	return i, true
+}
+
+// endsSource reports whether the token can follow a source of a FROM clause.
+func endsSource(t token) bool {
+	return t.kind == tokenPunct && (t.text == "," || t.text == ")") ||
+		t.kind == tokenWord && (sourceEnds[strings.ToUpper(t.text)] || clauseKeywords[strings.ToUpper(t.text)])
+}
+
+// commonTableExpressions returns the lower-cased names of the common table
+// expressions of the statement.
+func (s *Statement) commonTableExpressions() map[string]bool {
+	ctes := map[string]bool{}
+	tokens := s.tokens
+	for i, t := range tokens {
+		// A CTE is "name [(columns)] AS [NOT] [MATERIALIZED] (", after WITH
+		// or a comma.
+		if i == 0 || !(tokens[i-1].is("WITH", "RECURSIVE") || tokens[i-1].text == ",") {
+			continue
+		}
+		if t.kind != tokenWord && t.kind != tokenQuoted {
+			continue
+		}
+		j := i + 1
+		if j < len(tokens) && tokens[j].text == "(" {
+			j = s.closingParen(j) + 1
+		}
+		if j < len(tokens) && tokens[j].is("AS") {
+			ctes[strings.ToLower(t.name())] = true
+		}
+	}
+	return ctes
+}
+
+// closingParen returns the index of the parenthesis closing the one at i.
+func (s *Statement) closingParen(i int) int {
+	depth := 0
+	for j := i; j < len(s.tokens); j++ {
+		switch s.tokens[j].text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 {
+				return j
+			}
+		}
+	}
+	return len(s.tokens)
+}
+
+// qualifiedName returns the possibly qualified name starting at i, e.g.
+// "schema.table", and the index after it.
+func (s *Statement) qualifiedName(i int) (string, int, bool) {
+	tokens := s.tokens
+	if i >= len(tokens) || tokens[i].kind != tokenWord && tokens[i].kind != tokenQuoted {
+		return "", i, false
+	}
+	name := tokens[i].name()
+	j := i + 1
+	for j+1 < len(tokens) && tokens[j].text == "." &&
+		(tokens[j+1].kind == tokenWord || tokens[j+1].kind == tokenQuoted) {
+		name += "." + tokens[j+1].name()
+		j += 2
+	}
+	return name, j, true
+}
+
+// resolve returns the table of a qualifier.
+func (s *Statement) resolve(qualifier string, ctes map[string]bool) string {
+	if table, ok := s.aliases[strings.ToLower(qualifier)]; ok {
+		if ctes[strings.ToLower(table)] {
+			return "?" + table
+		}
+		return table
+	}
+	for _, table := range s.Tables {
+		if strings.EqualFold(table, qualifier) || strings.EqualFold(lastPart(table), qualifier) {
+			return table
+		}
+	}
+	return "?" + qualifier
+}
+
+// scopeTables returns the tables of a scope and of the enclosing scopes.
+func (s *Statement) scopeTables(scope int) []string {
+	var tables []string
+	for ; scope >= 0; scope = s.parents[scope] {
+		for i, table := range s.Tables {
+			if s.tableScopes[i] == scope {
+				tables = append(tables, table)
+			}
+		}
+	}
+	return tables
+}
+
+// isFunctionCall reports whether the token at i is the name of a called
+// function.
+func (s *Statement) isFunctionCall(i int) bool {
+	return i+1 < len(s.tokens) && s.tokens[i+1].text == "(" && isFunction(s.tokens[i])
+}
+
+// isQualifier reports whether the token at i qualifies the next one, e.g.
+// "t" in "t.name".
+func (s *Statement) isQualifier(i int) bool {
+	return i+2 < len(s.tokens) && s.tokens[i+1].text == "." &&
+		(s.tokens[i+2].kind == tokenWord || s.tokens[i+2].kind == tokenQuoted || s.tokens[i+2].text == "*")
+}
+
+// isQualified reports whether the token at i is qualified, e.g. "name" in
+// "t.name".
+func (s *Statement) isQualified(i int) bool {
+	return i > 1 && s.tokens[i-1].text == "." &&
+		(s.tokens[i-2].kind == tokenWord || s.tokens[i-2].kind == tokenQuoted)
+}
+
+// isAliasDefinition reports whether the identifier at i defines an alias of
+// a subquery without AS, e.g. "x" in "SELECT (SELECT ...) x".
+func (s *Statement) isAliasDefinition(i int) bool {
+	if i > 0 && s.tokens[i-1].text == ")" {
+		s.aliases[strings.ToLower(s.tokens[i].name())] = "?" + s.tokens[i].name()
+		return true
+	}
+	return false
+}
+
+// isFunction reports whether a token followed by a parenthesis is a function
+// name rather than a keyword like IN or EXISTS.
+func isFunction(t token) bool {
+	if t.kind == tokenQuoted {
+		// A quoted name, e.g. "pg_sleep"(1) in PostgreSQL.
+		return true
+	}
+	if t.kind != tokenWord {
+		return false
+	}
+	upper := strings.ToUpper(t.text)
+	switch upper {
+	case "EXTRACT", "SUBSTRING", "TRIM", "POSITION", "OVERLAY", "CAST", "REPLACE":
+		return true
+	}
+	return !keywords[upper] && !statementKeywords[upper]
+}
+
+// isStarContext reports whether a "*" following the token selects all the
+// columns, rather than being a multiplication or COUNT(*).
+func isStarContext(t token) bool {
+	return t.is("SELECT", "DISTINCT", "ALL") || t.kind == tokenPunct && t.text == ","
+}
+
+// lastPart returns the last part of a qualified name.
+func lastPart(name string) string {
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
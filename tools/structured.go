@@ -0,0 +1,98 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/tmc/langchaingo/jsonschema"
+)
+
+// ErrInvalidArguments is returned when the arguments given to a structured
+// tool don't match its schema.
+var ErrInvalidArguments = errors.New("invalid tool arguments")
+
+// StructuredTool is a tool whose input is a JSON object described by a JSON
+// schema, rather than a free-form string. Agents that support function
+// calling give the schema to the model, so it can produce the arguments
+// directly.
+//
+// The Call method of a structured tool accepts the JSON encoded arguments as
+// its input, so structured tools can be used wherever a Tool is expected.
+type StructuredTool interface {
+	Tool
+	// Schema returns the JSON schema of the arguments of the tool.
+	Schema() jsonschema.Definition
+	// CallStructured calls the tool with JSON encoded arguments.
+	CallStructured(ctx context.Context, args json.RawMessage) (string, error)
+}
+
+// ValidateArguments checks that args match the schema of the tool. The
+// returned error wraps ErrInvalidArguments and the jsonschema validation
+// errors.
+func ValidateArguments(tool StructuredTool, args json.RawMessage) error {
+	if err := jsonschema.Validate(tool.Schema(), args); err != nil {
+		return fmt.Errorf("%w for %s: %w", ErrInvalidArguments, tool.Name(), err)
+	}
+	return nil
+}
+
+// Structured is a StructuredTool calling a Go function with arguments of
+// type T, usually a struct. The schema of the arguments is derived from T
+// with jsonschema.Reflect.
+type Structured[T any] struct {
+	name        string
+	description string
+	schema      jsonschema.Definition
+	fn          func(ctx context.Context, args T) (string, error)
+}
+
+var _ StructuredTool = &Structured[struct{}]{}
+
+// NewStructured creates a structured tool calling fn with arguments decoded
+// from JSON.
+func NewStructured[T any](
+	name, description string,
+	fn func(ctx context.Context, args T) (string, error),
+) *Structured[T] {
+	return &Structured[T]{
+		name:        name,
+		description: description,
+		schema:      jsonschema.Reflect[T](),
+		fn:          fn,
+	}
+}
+
+// Name returns the name of the tool.
+func (s *Structured[T]) Name() string {
+	return s.name
+}
+
+// Description returns the description of the tool.
+func (s *Structured[T]) Description() string {
+	return s.description
+}
+
+// Schema returns the JSON schema of the arguments of the tool.
+func (s *Structured[T]) Schema() jsonschema.Definition {
+	return s.schema
+}
+
+// Call calls the tool with JSON encoded arguments.
+func (s *Structured[T]) Call(ctx context.Context, input string) (string, error) {
+	return s.CallStructured(ctx, json.RawMessage(input))
+}
+
+// CallStructured validates the arguments against the schema of the tool,
+// decodes them and calls the tool function.
+func (s *Structured[T]) CallStructured(ctx context.Context, args json.RawMessage) (string, error) {
+	if err := ValidateArguments(s, args); err != nil {
+		return "", err
+	}
+	var v T
+	if err := json.Unmarshal(args, &v); err != nil {
+		return "", fmt.Errorf("%w for %s: %w", ErrInvalidArguments, s.name, err)
+	}
+	return s.fn(ctx, v)
+}
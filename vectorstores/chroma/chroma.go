@@ -22,6 +22,7 @@ var (
 	ErrAddDocument              = errors.New("error adding document")
 	ErrRemoveCollection         = errors.New("error resetting collection")
 	ErrUnsupportedOptions       = errors.New("unsupported options")
+	ErrDeleteDocuments          = errors.New("error deleting documents")
 )
 
 // Store is a wrapper around the chromaGo API and client.
@@ -39,7 +40,7 @@ type Store struct {
 	includes     []chromatypes.QueryEnum
 }
 
-var _ vectorstores.VectorStore = Store{}
+var _ vectorstores.ExtendedVectorStore = Store{}
 
 // New creates an active client connection to the (specified, or default) collection in the Chroma server
 // and returns the `Store` object needed by the other accessors.
@@ -92,21 +93,134 @@ func (s Store) AddDocuments(ctx context.Context,
 	docs []schema.Document,
 	options ...vectorstores.Option,
 ) ([]string, error) {
+	opts := s.getOptions(options...)
+	ids := make([]string, len(docs))
+	for docIdx := range docs {
+		ids[docIdx] = uuid.New().String() // TODO (noodnik2): find & use something more meaningful
+	}
+
+	texts, metadatas, err := s.prepareDocuments(opts, docs)
+	if err != nil {
+		return nil, err
+	}
+
+	col := s.collection
+	if _, addErr := col.Add(ctx, nil, metadatas, texts, ids); addErr != nil {
+		return nil, fmt.Errorf("%w: %w", ErrAddDocument, addErr)
+	}
+	return ids, nil
+}
+
+// UpsertDocuments adds the text and metadata from the documents to the Chroma collection associated with 'Store',
+// replacing the documents that have the same ID, and returns the ids of the documents.
+func (s Store) UpsertDocuments(ctx context.Context,
+	docs []schema.Document,
+	options ...vectorstores.Option,
+) ([]string, error) {
+	opts := s.getOptions(options...)
+	ids := make([]string, len(docs))
+	for docIdx, doc := range docs {
+		ids[docIdx] = doc.ID
+		if ids[docIdx] == "" {
+			ids[docIdx] = uuid.New().String()
+		}
+	}
+
+	texts, metadatas, err := s.prepareDocuments(opts, docs)
+	if err != nil {
+		return nil, err
+	}
+
+	col := s.collection
+	if _, upsertErr := col.Upsert(ctx, nil, metadatas, texts, ids); upsertErr != nil {
+		return nil, fmt.Errorf("%w: %w", ErrAddDocument, upsertErr)
+	}
+	return ids, nil
+}
+
+// DeleteDocuments deletes the documents with the given ids from the Chroma collection associated with 'Store'.
+func (s Store) DeleteDocuments(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	opts := s.getOptions(options...)
+	if opts.Embedder != nil || opts.ScoreThreshold != 0 || opts.Filters != nil {
+		return ErrUnsupportedOptions
+	}
+
+	if _, err := s.collection.Delete(ctx, ids, s.getNamespacedFilter(opts), nil); err != nil {
+		return fmt.Errorf("%w: %w", ErrDeleteDocuments, err)
+	}
+	return nil
+}
+
+// DeleteByFilter deletes the documents matching a Chroma "where" filter from the Chroma collection
+// associated with 'Store'.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, options ...vectorstores.Option) error {
+	if _, ok := filter.(map[string]any); !ok {
+		return fmt.Errorf("%w: filter must be a map[string]any", ErrUnsupportedOptions)
+	}
+	opts := s.getOptions(options...)
+	if opts.Embedder != nil || opts.ScoreThreshold != 0 {
+		return ErrUnsupportedOptions
+	}
+	opts.Filters = filter
+
+	if _, err := s.collection.Delete(ctx, nil, s.getNamespacedFilter(opts), nil); err != nil {
+		return fmt.Errorf("%w: %w", ErrDeleteDocuments, err)
+	}
+	return nil
+}
+
+// GetDocuments returns the documents with the given ids from the Chroma collection associated with 'Store'.
+func (s Store) GetDocuments(ctx context.Context, ids []string, options ...vectorstores.Option) ([]schema.Document, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
 	opts := s.getOptions(options...)
 	if opts.Embedder != nil || opts.ScoreThreshold != 0 || opts.Filters != nil {
 		return nil, ErrUnsupportedOptions
 	}
 
+	col, err := s.collection.Get(ctx, s.getNamespacedFilter(opts), nil, ids,
+		[]chromatypes.QueryEnum{chromatypes.IDocuments, chromatypes.IMetadatas})
+	if err != nil {
+		return nil, err
+	}
+	data := col.CollectionData
+	if data == nil {
+		return nil, nil
+	}
+	if len(data.Documents) != len(data.Ids) || len(data.Metadatas) != len(data.Ids) {
+		return nil, fmt.Errorf("%w: Ids[%d], Documents[%d], Metadatas[%d]",
+			ErrUnexpectedResponseLength, len(data.Ids), len(data.Documents), len(data.Metadatas))
+	}
+
+	docs := make([]schema.Document, len(data.Ids))
+	for i, id := range data.Ids {
+		docs[i] = schema.Document{
+			ID:          id,
+			PageContent: data.Documents[i],
+			Metadata:    data.Metadatas[i],
+		}
+	}
+	return docs, nil
+}
+
+// prepareDocuments returns the texts and the metadata, including the name space, of the documents.
+func (s Store) prepareDocuments(opts vectorstores.Options, docs []schema.Document) ([]string, []map[string]any, error) {
+	if opts.Embedder != nil || opts.ScoreThreshold != 0 || opts.Filters != nil {
+		return nil, nil, ErrUnsupportedOptions
+	}
+
 	nameSpace := s.getNameSpace(opts)
 	if nameSpace != "" && s.nameSpaceKey == "" {
-		return nil, fmt.Errorf("%w: nameSpace without nameSpaceKey", ErrUnsupportedOptions)
+		return nil, nil, fmt.Errorf("%w: nameSpace without nameSpaceKey", ErrUnsupportedOptions)
 	}
 
-	ids := make([]string, len(docs))
 	texts := make([]string, len(docs))
 	metadatas := make([]map[string]any, len(docs))
 	for docIdx, doc := range docs {
-		ids[docIdx] = uuid.New().String() // TODO (noodnik2): find & use something more meaningful
 		texts[docIdx] = doc.PageContent
 		mc := make(map[string]any, 0)
 		maps.Copy(mc, doc.Metadata)
@@ -115,12 +229,7 @@ func (s Store) AddDocuments(ctx context.Context,
 			metadatas[docIdx][s.nameSpaceKey] = nameSpace
 		}
 	}
-
-	col := s.collection
-	if _, addErr := col.Add(ctx, nil, metadatas, texts, ids); addErr != nil {
-		return nil, fmt.Errorf("%w: %w", ErrAddDocument, addErr)
-	}
-	return ids, nil
+	return texts, metadatas, nil
 }
 
 func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments int,
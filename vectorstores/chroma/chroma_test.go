@@ -66,6 +66,57 @@ func TestChromaGoStoreRest(t *testing.T) {
 	require.Equal(t, "japan", country)
 }
 
+func TestChromaStoreDocuments(t *testing.T) {
+	t.Parallel()
+
+	testChromaURL, openaiAPIKey := getValues(t)
+	llm, err := openai.New()
+	require.NoError(t, err)
+	e, err := embeddings.NewEmbedder(llm)
+	require.NoError(t, err)
+
+	s, err := chroma.New(
+		chroma.WithOpenAIAPIKey(openaiAPIKey),
+		chroma.WithChromaURL(testChromaURL),
+		chroma.WithDistanceFunction(chromatypes.COSINE),
+		chroma.WithNameSpace(getTestNameSpace()),
+		chroma.WithEmbedder(e),
+	)
+	require.NoError(t, err)
+
+	defer cleanupTestArtifacts(t, s)
+
+	require.Equal(t, vectorstores.Capabilities{
+		Delete: true, DeleteByFilter: true, Upsert: true, Get: true,
+	}, vectorstores.CapabilitiesOf(s))
+
+	ctx := context.Background()
+	ids, err := s.UpsertDocuments(ctx, []schema.Document{
+		{ID: "tokyo", PageContent: "tokyo", Metadata: map[string]any{"country": "japan"}},
+		{ID: "potato", PageContent: "potato", Metadata: map[string]any{"country": "peru"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"tokyo", "potato"}, ids)
+
+	_, err = s.UpsertDocuments(ctx, []schema.Document{
+		{ID: "tokyo", PageContent: "kyoto", Metadata: map[string]any{"country": "japan"}},
+	})
+	require.NoError(t, err)
+
+	docs, err := s.GetDocuments(ctx, []string{"tokyo"})
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, "tokyo", docs[0].ID)
+	require.Equal(t, "kyoto", docs[0].PageContent)
+
+	require.NoError(t, s.DeleteDocuments(ctx, []string{"tokyo"}))
+	require.NoError(t, s.DeleteByFilter(ctx, map[string]any{"country": "peru"}))
+
+	docs, err = s.GetDocuments(ctx, ids)
+	require.NoError(t, err)
+	require.Empty(t, docs)
+}
+
 func TestChromaStoreRestWithScoreThreshold(t *testing.T) {
 	t.Parallel()
 
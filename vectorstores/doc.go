@@ -5,6 +5,7 @@ and querying documents as vector embeddings.
 The main components of this package are:
 
 - VectorStore interface: a common interface for saving and querying vector embeddings of documents.
+- DocumentDeleter, FilterDeleter, DocumentUpserter and DocumentGetter interfaces: optional document management.
 - Options: a set of options for similarity search and document addition.
 - Retriever: a retriever for vector stores that implements the schema.Retriever interface.
 
@@ -0,0 +1,118 @@
+package vectorstores
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ErrNotSupported is returned when a vector store does not support an
+// operation.
+var ErrNotSupported = errors.New("operation not supported by the vector store")
+
+// DocumentDeleter is implemented by vector stores that can delete documents
+// by ID.
+type DocumentDeleter interface {
+	// DeleteDocuments deletes the documents with the given IDs. IDs that don't
+	// exist are ignored.
+	DeleteDocuments(ctx context.Context, ids []string, options ...Option) error
+}
+
+// FilterDeleter is implemented by vector stores that can delete the documents
+// matching a metadata filter.
+type FilterDeleter interface {
+	// DeleteByFilter deletes the documents matching filter. The format of the
+	// filter is the one the store accepts in WithFilters.
+	DeleteByFilter(ctx context.Context, filter any, options ...Option) error
+}
+
+// DocumentUpserter is implemented by vector stores that can insert or replace
+// documents by ID.
+type DocumentUpserter interface {
+	// UpsertDocuments adds the documents, replacing the stored documents that
+	// have the same ID. Documents without an ID are assigned a new one. It
+	// returns the IDs of the documents in the order they were given.
+	UpsertDocuments(ctx context.Context, docs []schema.Document, options ...Option) ([]string, error)
+}
+
+// DocumentGetter is implemented by vector stores that can fetch documents by
+// ID.
+type DocumentGetter interface {
+	// GetDocuments returns the documents with the given IDs, with their ID
+	// field set. IDs that don't exist are skipped, so fewer documents than
+	// IDs may be returned.
+	GetDocuments(ctx context.Context, ids []string, options ...Option) ([]schema.Document, error)
+}
+
+// ExtendedVectorStore is a VectorStore that supports managing documents by
+// ID and deleting by filter.
+type ExtendedVectorStore interface {
+	VectorStore
+	DocumentDeleter
+	FilterDeleter
+	DocumentUpserter
+	DocumentGetter
+}
+
+// Capabilities describes the optional operations a vector store supports.
+type Capabilities struct {
+	Delete         bool
+	DeleteByFilter bool
+	Upsert         bool
+	Get            bool
+}
+
+// CapabilitiesOf reports the optional operations supported by a vector store.
+func CapabilitiesOf(store VectorStore) Capabilities {
+	_, deleter := store.(DocumentDeleter)
+	_, filterDeleter := store.(FilterDeleter)
+	_, upserter := store.(DocumentUpserter)
+	_, getter := store.(DocumentGetter)
+	return Capabilities{
+		Delete:         deleter,
+		DeleteByFilter: filterDeleter,
+		Upsert:         upserter,
+		Get:            getter,
+	}
+}
+
+// DeleteDocuments deletes documents by ID from the store, or returns
+// ErrNotSupported if the store can't delete documents.
+func DeleteDocuments(ctx context.Context, store VectorStore, ids []string, options ...Option) error {
+	deleter, ok := store.(DocumentDeleter)
+	if !ok {
+		return ErrNotSupported
+	}
+	return deleter.DeleteDocuments(ctx, ids, options...)
+}
+
+// DeleteByFilter deletes the documents matching filter from the store, or
+// returns ErrNotSupported if the store can't delete by filter.
+func DeleteByFilter(ctx context.Context, store VectorStore, filter any, options ...Option) error {
+	deleter, ok := store.(FilterDeleter)
+	if !ok {
+		return ErrNotSupported
+	}
+	return deleter.DeleteByFilter(ctx, filter, options...)
+}
+
+// UpsertDocuments inserts or replaces documents by ID in the store, or
+// returns ErrNotSupported if the store can't upsert documents.
+func UpsertDocuments(ctx context.Context, store VectorStore, docs []schema.Document, options ...Option) ([]string, error) {
+	upserter, ok := store.(DocumentUpserter)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+	return upserter.UpsertDocuments(ctx, docs, options...)
+}
+
+// GetDocuments fetches documents by ID from the store, or returns
+// ErrNotSupported if the store can't get documents by ID.
+func GetDocuments(ctx context.Context, store VectorStore, ids []string, options ...Option) ([]schema.Document, error) {
+	getter, ok := store.(DocumentGetter)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+	return getter.GetDocuments(ctx, ids, options...)
+}
@@ -0,0 +1,8 @@
+// Package inmemory contains an implementation of the VectorStore interface
+// that keeps the documents and their embeddings in memory, with optional
+// persistence to a local JSON file.
+//
+// It needs no external server, which makes it convenient for tests, small
+// command line tools and prototypes. Searches are exact: every stored vector
+// is compared with the query, so it is not meant for large collections.
+package inmemory
@@ -0,0 +1,233 @@
+package inmemory
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ErrInvalidFilter is returned when a metadata filter can't be understood.
+var ErrInvalidFilter = errors.New("invalid filter")
+
+type matcher func(doc schema.Document) bool
+
+// newMatcher compiles a filter given with vectorstores.WithFilters.
+func newMatcher(filter any) (matcher, error) {
+	switch f := filter.(type) {
+	case func(schema.Document) bool:
+		return f, nil
+	case map[string]any:
+		return newMapMatcher(f)
+	default:
+		return nil, fmt.Errorf("%w: unsupported type %T", ErrInvalidFilter, filter)
+	}
+}
+
+func newMapMatcher(filter map[string]any) (matcher, error) {
+	matchers := make([]matcher, 0, len(filter))
+	for key, value := range filter {
+		var (
+			m   matcher
+			err error
+		)
+		switch key {
+		case "$and", "$or":
+			m, err = newListMatcher(key, value)
+		default:
+			m, err = newFieldMatcher(key, value)
+		}
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, m)
+	}
+	return all(matchers), nil
+}
+
+func newListMatcher(op string, value any) (matcher, error) {
+	list, ok := value.([]any)
+	if !ok {
+		if maps, isMaps := value.([]map[string]any); isMaps {
+			for _, m := range maps {
+				list = append(list, m)
+			}
+			ok = true
+		}
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %s takes a list of filters", ErrInvalidFilter, op)
+	}
+
+	matchers := make([]matcher, 0, len(list))
+	for _, item := range list {
+		m, err := newMatcher(item)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, m)
+	}
+	if op == "$and" {
+		return all(matchers), nil
+	}
+	return func(doc schema.Document) bool {
+		for _, m := range matchers {
+			if m(doc) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+func newFieldMatcher(key string, value any) (matcher, error) {
+	ops, ok := value.(map[string]any)
+	if !ok || !isOperators(ops) {
+		return func(doc schema.Document) bool {
+			v, ok := doc.Metadata[key]
+			return ok && equal(v, value)
+		}, nil
+	}
+
+	conds := make([]func(v any, ok bool) bool, 0, len(ops))
+	for op, arg := range ops {
+		cond, err := newCondition(op, arg)
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, cond)
+	}
+	return func(doc schema.Document) bool {
+		v, ok := doc.Metadata[key]
+		for _, cond := range conds {
+			if !cond(v, ok) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+func newCondition(op string, arg any) (func(v any, ok bool) bool, error) {
+	switch op {
+	case "$eq":
+		return func(v any, ok bool) bool { return ok && equal(v, arg) }, nil
+	case "$ne":
+		return func(v any, ok bool) bool { return !ok || !equal(v, arg) }, nil
+	case "$gt", "$gte", "$lt", "$lte":
+		return func(v any, ok bool) bool {
+			if !ok {
+				return false
+			}
+			c, comparable := compare(v, arg)
+			if !comparable {
+				return false
+			}
+			switch op {
+			case "$gt":
+				return c > 0
+			case "$gte":
+				return c >= 0
+			case "$lt":
+				return c < 0
+			default:
+				return c <= 0
+			}
+		}, nil
+	case "$in", "$nin":
+		rv := reflect.ValueOf(arg)
+		if rv.Kind() != reflect.Slice {
+			return nil, fmt.Errorf("%w: %s takes a list of values", ErrInvalidFilter, op)
+		}
+		values := make([]any, rv.Len())
+		for i := range values {
+			values[i] = rv.Index(i).Interface()
+		}
+		in := func(v any) bool {
+			for _, value := range values {
+				if equal(v, value) {
+					return true
+				}
+			}
+			return false
+		}
+		if op == "$in" {
+			return func(v any, ok bool) bool { return ok && in(v) }, nil
+		}
+		return func(v any, ok bool) bool { return !ok || !in(v) }, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown operator %q", ErrInvalidFilter, op)
+	}
+}
+
+// isOperators reports whether a filter value is a map of operators rather
+// than a value to compare with.
+func isOperators(m map[string]any) bool {
+	for k := range m {
+		if !strings.HasPrefix(k, "$") {
+			return false
+		}
+	}
+	return len(m) > 0
+}
+
+func all(matchers []matcher) matcher {
+	return func(doc schema.Document) bool {
+		for _, m := range matchers {
+			if !m(doc) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func equal(a, b any) bool {
+	if c, ok := compare(a, b); ok {
+		return c == 0
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// compare compares two numbers or two strings. Numbers of different types
+// are compared by value, since metadata loaded from JSON holds float64s.
+func compare(a, b any) (int, bool) {
+	if fa, ok := toFloat(a); ok {
+		fb, ok := toFloat(b)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case fa < fb:
+			return -1, true
+		case fa > fb:
+			return 1, true
+		}
+		return 0, true
+	}
+	sa, ok := a.(string)
+	if !ok {
+		return 0, false
+	}
+	sb, ok := b.(string)
+	if !ok {
+		return 0, false
+	}
+	return strings.Compare(sa, sb), true
+}
+
+func toFloat(v any) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() { //nolint:exhaustive
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,403 @@
+package inmemory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+var (
+	// ErrEmbedderWrongNumberVectors is returned when the embedder returns a
+	// number of vectors that differs from the number of documents.
+	ErrEmbedderWrongNumberVectors = errors.New(
+		"number of vectors from embedder does not match number of documents",
+	)
+	// ErrDimensionMismatch is returned when a vector does not have the same
+	// dimension as the vectors already stored in the name space.
+	ErrDimensionMismatch = errors.New("vector dimension does not match the stored vectors")
+	// ErrMissingFilter is returned by DeleteByFilter if no filter is given.
+	ErrMissingFilter = errors.New("missing filter")
+)
+
+// Store is a vector store that keeps documents in memory. It is safe for
+// concurrent use.
+type Store struct {
+	embedder  embeddings.Embedder
+	metric    Metric
+	nameSpace string
+	path      string
+
+	mu         sync.RWMutex
+	nameSpaces map[string]*collection
+}
+
+// record is a stored document and its embedding.
+type record struct {
+	ID       string         `json:"id"`
+	Content  string         `json:"content"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+	Vector   []float32      `json:"vector"`
+}
+
+// collection holds the records of a name space in insertion order.
+type collection struct {
+	records []*record
+	index   map[string]int
+}
+
+var _ vectorstores.ExtendedVectorStore = (*Store)(nil)
+
+// New creates a new in-memory Store with options. If persistence is enabled
+// and the file exists, the stored documents are loaded from it.
+func New(opts ...Option) (*Store, error) {
+	s, err := applyClientOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if s.path != "" {
+		if err := s.load(); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// AddDocuments creates vector embeddings from the documents using the embedder
+// and stores them. Documents keep their ID if they have one, otherwise a new
+// one is assigned. It returns the ids of the added documents.
+func (s *Store) AddDocuments(ctx context.Context,
+	docs []schema.Document,
+	options ...vectorstores.Option,
+) ([]string, error) {
+	opts := s.getOptions(options...)
+	if opts.Deduplicater != nil {
+		docs = slices.DeleteFunc(slices.Clone(docs), func(doc schema.Document) bool {
+			return opts.Deduplicater(ctx, doc)
+		})
+	}
+	return s.upsert(ctx, opts, docs)
+}
+
+// UpsertDocuments creates vector embeddings from the documents using the
+// embedder and stores them, replacing the documents that have the same ID.
+// Documents without an ID are assigned a new one.
+func (s *Store) UpsertDocuments(ctx context.Context,
+	docs []schema.Document,
+	options ...vectorstores.Option,
+) ([]string, error) {
+	return s.upsert(ctx, s.getOptions(options...), docs)
+}
+
+// DeleteDocuments deletes the documents with the given ids.
+func (s *Store) DeleteDocuments(_ context.Context, ids []string, options ...vectorstores.Option) error {
+	opts := s.getOptions(options...)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.nameSpaces[s.getNameSpace(opts)]
+	if !ok {
+		return nil
+	}
+	deleted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if _, ok := c.index[id]; ok {
+			deleted[id] = true
+		}
+	}
+	if len(deleted) == 0 {
+		return nil
+	}
+	c.remove(func(r *record) bool { return deleted[r.ID] })
+	return s.save()
+}
+
+// DeleteByFilter deletes the documents matching a metadata filter. See
+// SimilaritySearch for the supported filters.
+func (s *Store) DeleteByFilter(_ context.Context, filter any, options ...vectorstores.Option) error {
+	if filter == nil {
+		return ErrMissingFilter
+	}
+	opts := s.getOptions(options...)
+	match, err := newMatcher(filter)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.nameSpaces[s.getNameSpace(opts)]
+	if !ok {
+		return nil
+	}
+	if c.remove(func(r *record) bool { return match(r.document()) }) == 0 {
+		return nil
+	}
+	return s.save()
+}
+
+// GetDocuments returns the documents with the given ids. Ids that don't exist
+// are skipped.
+func (s *Store) GetDocuments(_ context.Context, ids []string, options ...vectorstores.Option) ([]schema.Document, error) {
+	opts := s.getOptions(options...)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	c, ok := s.nameSpaces[s.getNameSpace(opts)]
+	if !ok {
+		return nil, nil
+	}
+	docs := make([]schema.Document, 0, len(ids))
+	for _, id := range ids {
+		if i, ok := c.index[id]; ok {
+			docs = append(docs, c.records[i].document())
+		}
+	}
+	return docs, nil
+}
+
+// SimilaritySearch creates a vector embedding from the query using the
+// embedder and returns the numDocuments most similar documents, most similar
+// first. Documents scoring below the threshold given with
+// vectorstores.WithScoreThreshold are left out.
+//
+// Filters given with vectorstores.WithFilters can be a
+// func(schema.Document) bool, or a map[string]any matching metadata keys. A
+// map value is either compared for equality, or is a map of operators:
+// "$eq", "$ne", "$gt", "$gte", "$lt", "$lte", "$in" and "$nin". The "$and"
+// and "$or" keys take a list of filters.
+func (s *Store) SimilaritySearch(ctx context.Context,
+	query string,
+	numDocuments int,
+	options ...vectorstores.Option,
+) ([]schema.Document, error) {
+	opts := s.getOptions(options...)
+
+	match := func(schema.Document) bool { return true }
+	if opts.Filters != nil {
+		var err error
+		if match, err = newMatcher(opts.Filters); err != nil {
+			return nil, err
+		}
+	}
+
+	vector, err := s.getEmbedder(opts).EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	c, ok := s.nameSpaces[s.getNameSpace(opts)]
+	if !ok {
+		return []schema.Document{}, nil
+	}
+
+	docs := []schema.Document{}
+	for _, r := range c.records {
+		if len(r.Vector) != len(vector) {
+			return nil, fmt.Errorf("%w: got %d, want %d", ErrDimensionMismatch, len(vector), len(r.Vector))
+		}
+		score := s.score(vector, r.Vector)
+		if opts.ScoreThreshold != 0 && score < opts.ScoreThreshold {
+			continue
+		}
+		doc := r.document()
+		if !match(doc) {
+			continue
+		}
+		doc.Score = score
+		docs = append(docs, doc)
+	}
+
+	sort.SliceStable(docs, func(i, j int) bool {
+		return docs[i].Score > docs[j].Score
+	})
+	if numDocuments >= 0 && len(docs) > numDocuments {
+		docs = docs[:numDocuments]
+	}
+	return docs, nil
+}
+
+// Len returns the number of documents stored in a name space.
+func (s *Store) Len(nameSpace string) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if c, ok := s.nameSpaces[nameSpace]; ok {
+		return len(c.records)
+	}
+	return 0
+}
+
+// DropNameSpace deletes all the documents stored in a name space.
+func (s *Store) DropNameSpace(nameSpace string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.nameSpaces[nameSpace]; !ok {
+		return nil
+	}
+	delete(s.nameSpaces, nameSpace)
+	return s.save()
+}
+
+func (s *Store) upsert(ctx context.Context, opts vectorstores.Options, docs []schema.Document) ([]string, error) {
+	if len(docs) == 0 {
+		return []string{}, nil
+	}
+
+	texts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		texts = append(texts, doc.PageContent)
+	}
+
+	vectors, err := s.getEmbedder(opts).EmbedDocuments(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(vectors) != len(docs) {
+		return nil, ErrEmbedderWrongNumberVectors
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	nameSpace := s.getNameSpace(opts)
+	c, ok := s.nameSpaces[nameSpace]
+	if !ok {
+		c = &collection{index: map[string]int{}}
+	}
+
+	dim := len(vectors[0])
+	if len(c.records) > 0 {
+		dim = len(c.records[0].Vector)
+	}
+	for _, v := range vectors {
+		if len(v) != dim {
+			return nil, fmt.Errorf("%w: got %d, want %d", ErrDimensionMismatch, len(v), dim)
+		}
+	}
+
+	ids := make([]string, 0, len(docs))
+	for i, doc := range docs {
+		id := doc.ID
+		if id == "" {
+			id = uuid.New().String()
+		}
+		c.put(&record{
+			ID:       id,
+			Content:  doc.PageContent,
+			Metadata: copyMetadata(doc.Metadata),
+			Vector:   slices.Clone(vectors[i]),
+		})
+		ids = append(ids, id)
+	}
+	s.nameSpaces[nameSpace] = c
+
+	return ids, s.save()
+}
+
+func (s *Store) score(a, b []float32) float32 {
+	switch s.metric {
+	case DotProduct:
+		return dot(a, b)
+	case Euclidean:
+		var sum float64
+		for i := range a {
+			d := float64(a[i] - b[i])
+			sum += d * d
+		}
+		return float32(1 / (1 + math.Sqrt(sum)))
+	default:
+		na, nb := math.Sqrt(float64(dot(a, a))), math.Sqrt(float64(dot(b, b)))
+		if na == 0 || nb == 0 {
+			return 0
+		}
+		return float32(float64(dot(a, b)) / (na * nb))
+	}
+}
+
+func dot(a, b []float32) float32 {
+	var sum float32
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
+
+func (s *Store) getOptions(options ...vectorstores.Option) vectorstores.Options {
+	opts := vectorstores.Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	return opts
+}
+
+func (s *Store) getNameSpace(opts vectorstores.Options) string {
+	if opts.NameSpace != "" {
+		return opts.NameSpace
+	}
+	return s.nameSpace
+}
+
+func (s *Store) getEmbedder(opts vectorstores.Options) embeddings.Embedder {
+	if opts.Embedder != nil {
+		return opts.Embedder
+	}
+	return s.embedder
+}
+
+// put adds a record, replacing the record with the same ID in place.
+func (c *collection) put(r *record) {
+	if i, ok := c.index[r.ID]; ok {
+		c.records[i] = r
+		return
+	}
+	c.index[r.ID] = len(c.records)
+	c.records = append(c.records, r)
+}
+
+// remove deletes the records for which del returns true and returns how many
+// were deleted.
+func (c *collection) remove(del func(*record) bool) int {
+	n := len(c.records)
+	c.records = slices.DeleteFunc(c.records, del)
+	clear(c.index)
+	for i, r := range c.records {
+		c.index[r.ID] = i
+	}
+	return n - len(c.records)
+}
+
+func (r *record) document() schema.Document {
+	return schema.Document{
+		ID:          r.ID,
+		PageContent: r.Content,
+		Metadata:    copyMetadata(r.Metadata),
+	}
+}
+
+func copyMetadata(metadata map[string]any) map[string]any {
+	if metadata == nil {
+		return nil
+	}
+	m := make(map[string]any, len(metadata))
+	for k, v := range metadata {
+		m[k] = v
+	}
+	return m
+}
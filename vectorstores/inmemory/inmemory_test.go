@@ -0,0 +1,185 @@
+package inmemory
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// testEmbedder embeds texts with a fixed lookup table.
+type testEmbedder map[string][]float32
+
+func (e testEmbedder) EmbedDocuments(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = e[text]
+	}
+	return vectors, nil
+}
+
+func (e testEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	return e[text], nil
+}
+
+var embedder = testEmbedder{
+	"tokyo":  {1, 0, 0},
+	"kyoto":  {0.9, 0.1, 0},
+	"paris":  {0, 1, 0},
+	"potato": {0, 0, 1},
+	"japan":  {1, 0.05, 0},
+	"france": {0.1, 1, 0},
+}
+
+var cities = []schema.Document{
+	{ID: "tokyo", PageContent: "tokyo", Metadata: map[string]any{"country": "japan", "population": 14}},
+	{ID: "kyoto", PageContent: "kyoto", Metadata: map[string]any{"country": "japan", "population": 1.5}},
+	{ID: "paris", PageContent: "paris", Metadata: map[string]any{"country": "france", "population": 2.1}},
+	{PageContent: "potato"},
+}
+
+func newTestStore(t *testing.T, opts ...Option) *Store {
+	t.Helper()
+
+	s, err := New(append([]Option{WithEmbedder(embedder)}, opts...)...)
+	require.NoError(t, err)
+	ids, err := s.AddDocuments(context.Background(), cities)
+	require.NoError(t, err)
+	require.Len(t, ids, len(cities))
+	return s
+}
+
+func contents(docs []schema.Document) []string {
+	out := make([]string, len(docs))
+	for i, doc := range docs {
+		out[i] = doc.PageContent
+	}
+	return out
+}
+
+func TestSimilaritySearch(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	for _, metric := range []Metric{Cosine, DotProduct, Euclidean} {
+		s := newTestStore(t, WithMetric(metric))
+
+		docs, err := s.SimilaritySearch(ctx, "japan", 2)
+		require.NoError(t, err)
+		require.Equal(t, []string{"tokyo", "kyoto"}, contents(docs), "metric %d", metric)
+		require.Equal(t, "tokyo", docs[0].ID)
+		require.Greater(t, docs[0].Score, docs[1].Score)
+
+		docs, err = s.SimilaritySearch(ctx, "france", 1)
+		require.NoError(t, err)
+		require.Equal(t, []string{"paris"}, contents(docs), "metric %d", metric)
+	}
+
+	s := newTestStore(t)
+	docs, err := s.SimilaritySearch(ctx, "japan", 10, vectorstores.WithScoreThreshold(0.9))
+	require.NoError(t, err)
+	require.Equal(t, []string{"tokyo", "kyoto"}, contents(docs))
+
+	docs, err = s.SimilaritySearch(ctx, "japan", 10, vectorstores.WithNameSpace("other"))
+	require.NoError(t, err)
+	require.Empty(t, docs)
+}
+
+func TestSimilaritySearchWithFilters(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := newTestStore(t)
+
+	tests := []struct {
+		name   string
+		filter any
+		want   []string
+	}{
+		{"equal", map[string]any{"country": "japan"}, []string{"tokyo", "kyoto"}},
+		{"numbers", map[string]any{"population": 14.0}, []string{"tokyo"}},
+		{"gt", map[string]any{"population": map[string]any{"$gt": 2}}, []string{"tokyo", "paris"}},
+		{"range", map[string]any{"population": map[string]any{"$gte": 1.5, "$lt": 10}}, []string{"kyoto", "paris"}},
+		{"in", map[string]any{"country": map[string]any{"$in": []string{"france", "italy"}}}, []string{"paris"}},
+		{"nin", map[string]any{"country": map[string]any{"$nin": []any{"japan"}}}, []string{"paris", "potato"}},
+		{"ne", map[string]any{"country": map[string]any{"$ne": "japan"}}, []string{"paris", "potato"}},
+		{"or", map[string]any{"$or": []any{
+			map[string]any{"country": "france"},
+			map[string]any{"population": map[string]any{"$gt": 10}},
+		}}, []string{"tokyo", "paris"}},
+		{"func", func(doc schema.Document) bool { return doc.ID == "kyoto" }, []string{"kyoto"}},
+	}
+	for _, tc := range tests {
+		docs, err := s.SimilaritySearch(ctx, "japan", 10, vectorstores.WithFilters(tc.filter))
+		require.NoError(t, err, tc.name)
+		require.ElementsMatch(t, tc.want, contents(docs), tc.name)
+	}
+
+	_, err := s.SimilaritySearch(ctx, "japan", 10, vectorstores.WithFilters(map[string]any{
+		"population": map[string]any{"$near": 1},
+	}))
+	require.ErrorIs(t, err, ErrInvalidFilter)
+}
+
+func TestDocuments(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := newTestStore(t)
+	require.Equal(t, vectorstores.Capabilities{Delete: true, DeleteByFilter: true, Upsert: true, Get: true},
+		vectorstores.CapabilitiesOf(s))
+
+	_, err := s.UpsertDocuments(ctx, []schema.Document{
+		{ID: "tokyo", PageContent: "kyoto", Metadata: map[string]any{"country": "japan"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 4, s.Len(defaultNameSpace))
+
+	docs, err := s.GetDocuments(ctx, []string{"tokyo", "missing", "paris"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"kyoto", "paris"}, contents(docs))
+	require.Equal(t, "tokyo", docs[0].ID)
+
+	require.NoError(t, s.DeleteDocuments(ctx, []string{"paris", "missing"}))
+	require.NoError(t, s.DeleteByFilter(ctx, map[string]any{"country": "japan"}))
+	require.Equal(t, 1, s.Len(defaultNameSpace))
+
+	docs, err = s.SimilaritySearch(ctx, "japan", 10)
+	require.NoError(t, err)
+	require.Equal(t, []string{"potato"}, contents(docs))
+
+	_, err = s.AddDocuments(ctx, []schema.Document{{PageContent: "unknown"}})
+	require.ErrorIs(t, err, ErrDimensionMismatch)
+}
+
+func TestPersistence(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "store.json")
+	s := newTestStore(t, WithPersistence(path))
+	_, err := s.AddDocuments(ctx, []schema.Document{{ID: "paris", PageContent: "paris"}},
+		vectorstores.WithNameSpace("europe"))
+	require.NoError(t, err)
+	require.NoError(t, s.DeleteDocuments(ctx, []string{"kyoto"}))
+
+	loaded, err := New(WithEmbedder(embedder), WithPersistence(path))
+	require.NoError(t, err)
+	require.Equal(t, 3, loaded.Len(defaultNameSpace))
+	require.Equal(t, 1, loaded.Len("europe"))
+
+	docs, err := loaded.SimilaritySearch(ctx, "japan", 1,
+		vectorstores.WithFilters(map[string]any{"population": map[string]any{"$gt": 10}}))
+	require.NoError(t, err)
+	require.Equal(t, []string{"tokyo"}, contents(docs))
+	require.Equal(t, "japan", docs[0].Metadata["country"])
+
+	copyPath := filepath.Join(t.TempDir(), "copy.json")
+	require.NoError(t, loaded.Save(copyPath))
+	copied, err := New(WithEmbedder(embedder), WithPersistence(copyPath))
+	require.NoError(t, err)
+	require.Equal(t, 1, copied.Len("europe"))
+}
@@ -0,0 +1,85 @@
+package inmemory
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+const defaultNameSpace = "default"
+
+// ErrInvalidOptions is returned when the options given are invalid.
+var ErrInvalidOptions = errors.New("invalid options")
+
+// Metric is the function used to score how similar two vectors are. Higher
+// scores mean more similar vectors.
+type Metric int
+
+const (
+	// Cosine scores vectors by their cosine similarity, from -1 to 1.
+	Cosine Metric = iota
+	// DotProduct scores vectors by their dot product.
+	DotProduct
+	// Euclidean scores vectors by 1 / (1 + d), where d is their euclidean
+	// distance, so that scores range from 0 to 1.
+	Euclidean
+)
+
+// Option is a function that configures a Store.
+type Option func(s *Store)
+
+// WithEmbedder returns an Option for setting the embedder to be used when
+// adding documents or doing similarity search. Required.
+func WithEmbedder(embedder embeddings.Embedder) Option {
+	return func(s *Store) {
+		s.embedder = embedder
+	}
+}
+
+// WithMetric returns an Option for setting the similarity metric. Optional.
+// Defaults to Cosine.
+func WithMetric(metric Metric) Option {
+	return func(s *Store) {
+		s.metric = metric
+	}
+}
+
+// WithNameSpace returns an Option for setting the name space used when none is
+// given with vectorstores.WithNameSpace. Optional. Defaults to "default".
+func WithNameSpace(nameSpace string) Option {
+	return func(s *Store) {
+		s.nameSpace = nameSpace
+	}
+}
+
+// WithPersistence returns an Option for persisting the store to a JSON file.
+// The file is loaded by New if it exists, and rewritten after every change.
+// Optional.
+func WithPersistence(path string) Option {
+	return func(s *Store) {
+		s.path = path
+	}
+}
+
+func applyClientOptions(opts ...Option) (*Store, error) {
+	s := &Store{
+		metric:     Cosine,
+		nameSpace:  defaultNameSpace,
+		nameSpaces: map[string]*collection{},
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.embedder == nil {
+		return nil, fmt.Errorf("%w: missing embedder", ErrInvalidOptions)
+	}
+
+	if s.metric < Cosine || s.metric > Euclidean {
+		return nil, fmt.Errorf("%w: unknown metric %d", ErrInvalidOptions, s.metric)
+	}
+
+	return s, nil
+}
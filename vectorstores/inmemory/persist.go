@@ -0,0 +1,97 @@
+package inmemory
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// fileVersion is the version of the persisted file format.
+const fileVersion = 1
+
+type file struct {
+	Version    int                  `json:"version"`
+	NameSpaces map[string][]*record `json:"namespaces"`
+}
+
+// Save writes the store to a JSON file, which can be loaded with
+// WithPersistence. The file is replaced atomically.
+func (s *Store) Save(path string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.saveTo(path)
+}
+
+// save persists the store if persistence is enabled. The caller must hold the
+// lock.
+func (s *Store) save() error {
+	if s.path == "" {
+		return nil
+	}
+	return s.saveTo(s.path)
+}
+
+func (s *Store) saveTo(path string) error {
+	f := file{
+		Version:    fileVersion,
+		NameSpaces: make(map[string][]*record, len(s.nameSpaces)),
+	}
+	for name, c := range s.nameSpaces {
+		f.NameSpaces[name] = c.records
+	}
+
+	data, err := json.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("encode store: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("save store: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("save store: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("save store: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("save store: %w", err)
+	}
+	return nil
+}
+
+// load reads the store from the persistence file, if it exists.
+func (s *Store) load() error {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("load store: %w", err)
+	}
+
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return fmt.Errorf("load store: %w", err)
+	}
+	if f.Version != fileVersion {
+		return fmt.Errorf("load store: unsupported file version %d", f.Version)
+	}
+
+	for name, records := range f.NameSpaces {
+		c := &collection{index: make(map[string]int, len(records))}
+		for _, r := range records {
+			c.put(r)
+		}
+		s.nameSpaces[name] = c
+	}
+	return nil
+}
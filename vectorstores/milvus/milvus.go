@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/milvus-io/milvus-sdk-go/v2/client"
 	"github.com/milvus-io/milvus-sdk-go/v2/entity"
@@ -47,6 +48,18 @@ var (
 	)
 	ErrColumnNotFound = errors.New("invalid field")
 	ErrInvalidFilters = errors.New("invalid filters")
+	ErrInvalidID      = errors.New("invalid document id")
+)
+
+// The store doesn't implement vectorstores.DocumentUpserter: the primary keys
+// of its collections are generated by Milvus, and Milvus can't upsert the
+// entities of collections with generated keys. vectorstores.UpsertDocuments
+// returns vectorstores.ErrNotSupported; delete the documents and add them
+// again instead.
+var (
+	_ vectorstores.DocumentDeleter = Store{}
+	_ vectorstores.FilterDeleter   = Store{}
+	_ vectorstores.DocumentGetter  = Store{}
 )
 
 // New creates an active client connection to the (specified, or default) collection in the Milvus server
@@ -223,7 +236,7 @@ func (s Store) AddDocuments(ctx context.Context, docs []schema.Document,
 		colsData = append(colsData, docMap)
 	}
 
-	_, err = s.client.InsertRows(ctx, s.collectionName, s.partitionName, colsData)
+	idCol, err := s.client.InsertRows(ctx, s.collectionName, s.partitionName, colsData)
 	if err != nil {
 		return nil, err
 	}
@@ -232,7 +245,134 @@ func (s Store) AddDocuments(ctx context.Context, docs []schema.Document,
 			return nil, err
 		}
 	}
-	return nil, nil
+
+	var ids []string
+	if col, ok := idCol.(*entity.ColumnInt64); ok {
+		ids = make([]string, 0, col.Len())
+		for _, id := range col.Data() {
+			ids = append(ids, strconv.FormatInt(id, 10))
+		}
+	}
+	return ids, nil
+}
+
+// DeleteDocuments deletes the documents with the given primary keys, as returned
+// by AddDocuments, from the Milvus collection.
+func (s Store) DeleteDocuments(ctx context.Context, ids []string, _ ...vectorstores.Option) error {
+	if len(ids) == 0 || !s.collectionExists {
+		return nil
+	}
+	expr, err := s.idsExpr(ids)
+	if err != nil {
+		return err
+	}
+	return s.delete(ctx, expr)
+}
+
+// DeleteByFilter deletes the documents matching a Milvus boolean expression,
+// such as "meta['area'] > 500", from the Milvus collection.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, _ ...vectorstores.Option) error {
+	expr, ok := filter.(string)
+	if !ok || expr == "" {
+		return ErrInvalidFilters
+	}
+	if !s.collectionExists {
+		return nil
+	}
+	return s.delete(ctx, expr)
+}
+
+// GetDocuments returns the documents with the given primary keys from the Milvus collection.
+// Documents that do not exist are skipped.
+func (s Store) GetDocuments(ctx context.Context, ids []string, _ ...vectorstores.Option) ([]schema.Document, error) {
+	if len(ids) == 0 || !s.collectionExists {
+		return nil, nil
+	}
+	expr, err := s.idsExpr(ids)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.init(ctx, 0); err != nil {
+		return nil, err
+	}
+
+	partitions := []string{}
+	if s.partitionName != "" {
+		partitions = append(partitions, s.partitionName)
+	}
+	resultSet, err := s.client.Query(ctx,
+		s.collectionName,
+		partitions,
+		expr,
+		[]string{s.primaryField, s.textField, s.metaField},
+		client.WithSearchQueryConsistencyLevel(s.consistencyLevel),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	idcol, ok := resultSet.GetColumn(s.primaryField).(*entity.ColumnInt64)
+	if !ok {
+		return nil, fmt.Errorf("%w: primary column missing", ErrColumnNotFound)
+	}
+	textcol, ok := resultSet.GetColumn(s.textField).(*entity.ColumnVarChar)
+	if !ok {
+		return nil, fmt.Errorf("%w: text column missing", ErrColumnNotFound)
+	}
+	metacol, ok := resultSet.GetColumn(s.metaField).(*entity.ColumnJSONBytes)
+	if !ok {
+		return nil, fmt.Errorf("%w: metadata column missing", ErrColumnNotFound)
+	}
+
+	byID := make(map[string]schema.Document, idcol.Len())
+	for i := 0; i < idcol.Len(); i++ {
+		id, err := idcol.ValueByIdx(i)
+		if err != nil {
+			return nil, err
+		}
+		doc := schema.Document{ID: strconv.FormatInt(id, 10)}
+		doc.PageContent, err = textcol.ValueByIdx(i)
+		if err != nil {
+			return nil, err
+		}
+		metaStr, err := metacol.ValueByIdx(i)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(metaStr, &doc.Metadata); err != nil {
+			return nil, err
+		}
+		byID[doc.ID] = doc
+	}
+
+	// keep the order of the given ids.
+	docs := make([]schema.Document, 0, len(byID))
+	for _, id := range ids {
+		if doc, ok := byID[id]; ok {
+			docs = append(docs, doc)
+		}
+	}
+	return docs, nil
+}
+
+func (s Store) delete(ctx context.Context, expr string) error {
+	if err := s.client.Delete(ctx, s.collectionName, s.partitionName, expr); err != nil {
+		return err
+	}
+	if !s.skipFlushOnWrite {
+		return s.client.Flush(ctx, s.collectionName, false)
+	}
+	return nil
+}
+
+// idsExpr returns a boolean expression matching the given primary keys.
+func (s Store) idsExpr(ids []string) (string, error) {
+	for _, id := range ids {
+		if _, err := strconv.ParseInt(id, 10, 64); err != nil {
+			return "", fmt.Errorf("%w: %q", ErrInvalidID, id)
+		}
+	}
+	return fmt.Sprintf("%s in [%s]", s.primaryField, strings.Join(ids, ",")), nil
 }
 
 func (s *Store) getSearchFields() []string {
@@ -117,3 +117,35 @@ func TestMilvusConnection(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, japanRes, 1)
 }
+
+func TestMilvusDocuments(t *testing.T) {
+	t.Parallel()
+	storer, err := getNewStore(t, WithDropOld(), WithCollectionName("test_documents"))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	ids, err := storer.AddDocuments(ctx, []schema.Document{
+		{PageContent: "Tokyo", Metadata: map[string]any{"area": 622}},
+		{PageContent: "Kyoto", Metadata: map[string]any{"area": 828}},
+		{PageContent: "Paris", Metadata: map[string]any{"area": 105}},
+	})
+	require.NoError(t, err)
+	require.Len(t, ids, 3)
+
+	docs, err := storer.GetDocuments(ctx, []string{ids[1], ids[0]})
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	require.Equal(t, ids[1], docs[0].ID)
+	require.Equal(t, "Kyoto", docs[0].PageContent)
+	require.Equal(t, "Tokyo", docs[1].PageContent)
+
+	require.NoError(t, storer.DeleteDocuments(ctx, ids[:1]))
+	require.NoError(t, storer.DeleteByFilter(ctx, "meta['area'] < 200"))
+
+	docs, err = storer.GetDocuments(ctx, ids)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, "Kyoto", docs[0].PageContent)
+
+	require.ErrorIs(t, storer.DeleteDocuments(ctx, []string{"not-a-number"}), ErrInvalidID)
+}
@@ -0,0 +1,174 @@
+package opensearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/opensearch-project/opensearch-go/opensearchapi"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// ErrMissingFilter is returned by DeleteByFilter if no filter is given.
+var ErrMissingFilter = errors.New("missing filter")
+
+var _ vectorstores.ExtendedVectorStore = Store{}
+
+// UpsertDocuments adds the documents to the index, replacing the documents that have the same ID.
+// Documents without an ID are assigned a new one.
+func (s Store) UpsertDocuments(
+	ctx context.Context,
+	docs []schema.Document,
+	options ...vectorstores.Option,
+) ([]string, error) {
+	opts := s.getOptions(options...)
+
+	texts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		texts = append(texts, doc.PageContent)
+	}
+
+	vectors, err := s.embedder.EmbedDocuments(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(vectors) != len(docs) {
+		return nil, ErrNumberOfVectorDoesNotMatch
+	}
+
+	ids := make([]string, 0, len(docs))
+	for i, doc := range docs {
+		id := doc.ID
+		if id == "" {
+			id = uuid.NewString()
+		}
+		res, err := s.documentIndexing(ctx, id, opts.NameSpace, doc.PageContent, vectors[i], doc.Metadata)
+		if err != nil {
+			return ids, err
+		}
+		if err := checkResponse(res, "index document"); err != nil {
+			return ids, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// DeleteDocuments deletes the documents with the given ids from the index.
+// Documents that do not exist are ignored.
+func (s Store) DeleteDocuments(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	opts := s.getOptions(options...)
+
+	for _, id := range ids {
+		req := opensearchapi.DeleteRequest{
+			Index:      opts.NameSpace,
+			DocumentID: id,
+		}
+		res, err := req.Do(ctx, s.client)
+		if err != nil {
+			return err
+		}
+		if res.StatusCode == http.StatusNotFound {
+			res.Body.Close()
+			continue
+		}
+		if err := checkResponse(res, "delete document"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteByFilter deletes the documents matching an OpenSearch query, such as
+// map[string]any{"term": map[string]any{"metadata.source": "a.txt"}}, from the index.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, options ...vectorstores.Option) error {
+	if filter == nil {
+		return ErrMissingFilter
+	}
+	opts := s.getOptions(options...)
+
+	buf := new(bytes.Buffer)
+	if err := json.NewEncoder(buf).Encode(map[string]any{"query": filter}); err != nil {
+		return fmt.Errorf("error encoding query to json buffer %w", err)
+	}
+
+	req := opensearchapi.DeleteByQueryRequest{
+		Index: []string{opts.NameSpace},
+		Body:  buf,
+	}
+	res, err := req.Do(ctx, s.client)
+	if err != nil {
+		return err
+	}
+	return checkResponse(res, "delete by query")
+}
+
+// GetDocuments returns the documents with the given ids from the index.
+// Documents that do not exist are skipped.
+func (s Store) GetDocuments(
+	ctx context.Context,
+	ids []string,
+	options ...vectorstores.Option,
+) ([]schema.Document, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	opts := s.getOptions(options...)
+
+	buf := new(bytes.Buffer)
+	if err := json.NewEncoder(buf).Encode(map[string]any{"ids": ids}); err != nil {
+		return nil, fmt.Errorf("error encoding ids to json buffer %w", err)
+	}
+
+	req := opensearchapi.MgetRequest{
+		Index: opts.NameSpace,
+		Body:  buf,
+	}
+	res, err := req.Do(ctx, s.client)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return nil, fmt.Errorf("mget failed: %s", res.String())
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading mget response body: %w", err)
+	}
+	results := mgetResults{}
+	if err := json.Unmarshal(body, &results); err != nil {
+		return nil, fmt.Errorf("error unmarshalling mget response body: %w %s", err, body)
+	}
+
+	docs := make([]schema.Document, 0, len(results.Docs))
+	for _, doc := range results.Docs {
+		if !doc.Found {
+			continue
+		}
+		docs = append(docs, schema.Document{
+			ID:          doc.ID,
+			PageContent: doc.Source.FieldsContent,
+			Metadata:    doc.Source.FieldsMetadata,
+		})
+	}
+	return docs, nil
+}
+
+// checkResponse closes the response body and returns an error if the request failed.
+func checkResponse(res *opensearchapi.Response, action string) error {
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("%s failed: %s", action, res.String())
+	}
+	return nil
+}
@@ -143,6 +143,7 @@ func (s Store) SimilaritySearch(
 		}
 
 		output = append(output, schema.Document{
+			ID:          hit.ID,
 			PageContent: hit.Source.FieldsContent,
 			Metadata:    hit.Source.FieldsMetadata,
 			Score:       hit.Score,
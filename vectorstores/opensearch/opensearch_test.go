@@ -285,3 +285,54 @@ func TestOpensearchAsRetrieverWithScoreThreshold(t *testing.T) {
 	require.Contains(t, result, "black", "expected black in result")
 	require.Contains(t, result, "beige", "expected beige in result")
 }
+
+func TestOpensearchStoreDocuments(t *testing.T) {
+	t.Parallel()
+	opensearchEndpoint, opensearchUser, opensearchPassword := getEnvVariables(t)
+	indexName := uuid.New().String()
+	llm := setLLM(t)
+	e, err := embeddings.NewEmbedder(llm)
+	require.NoError(t, err)
+
+	storer, err := opensearch.New(
+		setOpensearchClient(t, opensearchEndpoint, opensearchUser, opensearchPassword),
+		opensearch.WithEmbedder(e),
+	)
+	require.NoError(t, err)
+
+	setIndex(t, storer, indexName)
+	defer removeIndex(t, storer, indexName)
+
+	ctx := context.Background()
+	ids, err := storer.UpsertDocuments(ctx, []schema.Document{
+		{ID: "tokyo", PageContent: "tokyo", Metadata: map[string]any{"country": "japan"}},
+		{PageContent: "potato", Metadata: map[string]any{"country": "peru"}},
+		{ID: "paris", PageContent: "paris", Metadata: map[string]any{"country": "france"}},
+	}, vectorstores.WithNameSpace(indexName))
+	require.NoError(t, err)
+	require.Len(t, ids, 3)
+	require.Equal(t, "tokyo", ids[0])
+
+	_, err = storer.UpsertDocuments(ctx, []schema.Document{
+		{ID: "tokyo", PageContent: "kyoto", Metadata: map[string]any{"country": "japan"}},
+	}, vectorstores.WithNameSpace(indexName))
+	require.NoError(t, err)
+
+	docs, err := storer.GetDocuments(ctx, []string{"tokyo", ids[1], "missing"}, vectorstores.WithNameSpace(indexName))
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	require.Equal(t, "tokyo", docs[0].ID)
+	require.Equal(t, "kyoto", docs[0].PageContent)
+	require.Equal(t, "potato", docs[1].PageContent)
+
+	require.NoError(t, storer.DeleteDocuments(ctx, []string{ids[1], "missing"}, vectorstores.WithNameSpace(indexName)))
+	time.Sleep(time.Second)
+	require.NoError(t, storer.DeleteByFilter(ctx, map[string]any{
+		"match": map[string]any{"metadata.country": "france"},
+	}, vectorstores.WithNameSpace(indexName)))
+
+	docs, err = storer.GetDocuments(ctx, ids, vectorstores.WithNameSpace(indexName))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, "tokyo", docs[0].ID)
+}
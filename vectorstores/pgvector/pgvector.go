@@ -34,6 +34,8 @@ var (
 	ErrInvalidScoreThreshold      = errors.New("score threshold must be between 0 and 1")
 	ErrInvalidFilters             = errors.New("invalid filters")
 	ErrUnsupportedOptions         = errors.New("unsupported options")
+	ErrInvalidID                  = errors.New("invalid document id")
+	ErrOtherCollection            = errors.New("document belongs to another collection")
 )
 
 // PGXConn represents both a pgx.Conn and pgxpool.Pool conn.
@@ -71,7 +73,7 @@ type HNSWIndex struct {
 	distanceFunction string
 }
 
-var _ vectorstores.VectorStore = Store{}
+var _ vectorstores.ExtendedVectorStore = Store{}
 
 // New creates a new Store with options.
 func New(ctx context.Context, opts ...Option) (Store, error) {
@@ -268,6 +270,151 @@ func (s Store) AddDocuments(
 	return ids, s.conn.SendBatch(ctx, b).Close()
 }
 
+// UpsertDocuments adds documents to the Postgres collection associated with
+// 'Store', or of the namespace of the options, replacing the documents that
+// have the same ID, and returns the ids of the documents. IDs must be UUIDs;
+// documents without an ID are assigned a new one. ErrOtherCollection is
+// returned, and no document is upserted, if an ID is the ID of a document of
+// another collection.
+func (s Store) UpsertDocuments(
+	ctx context.Context,
+	docs []schema.Document,
+	options ...vectorstores.Option,
+) ([]string, error) {
+	opts := s.getOptions(options...)
+	if opts.ScoreThreshold != 0 || opts.Filters != nil {
+		return nil, ErrUnsupportedOptions
+	}
+
+	docs = s.deduplicate(ctx, opts, docs)
+
+	ids := make([]string, len(docs))
+	texts := make([]string, 0, len(docs))
+	for docIdx, doc := range docs {
+		ids[docIdx] = doc.ID
+		if doc.ID == "" {
+			ids[docIdx] = uuid.New().String()
+		} else if _, err := uuid.Parse(doc.ID); err != nil {
+			return nil, fmt.Errorf("%w: %q is not a UUID", ErrInvalidID, doc.ID)
+		}
+		texts = append(texts, doc.PageContent)
+	}
+
+	embedder := s.embedder
+	if opts.Embedder != nil {
+		embedder = opts.Embedder
+	}
+	vectors, err := embedder.EmbedDocuments(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(vectors) != len(docs) {
+		return nil, ErrEmbedderWrongNumberVectors
+	}
+
+	collectionUUID, err := s.getCollectionUUID(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// The documents of other collections aren't updated, and return no row.
+	b := &pgx.Batch{}
+	sql := fmt.Sprintf(`INSERT INTO %[1]s (uuid, document, embedding, cmetadata, collection_id)
+		VALUES($1, $2, $3, $4, $5) ON CONFLICT (uuid) DO
+		UPDATE SET document = $2, embedding = $3, cmetadata = $4
+		WHERE %[1]s.collection_id = $5 RETURNING uuid`, s.embeddingTableName)
+
+	for docIdx, doc := range docs {
+		b.Queue(sql, ids[docIdx], doc.PageContent, pgvector.NewVector(vectors[docIdx]), doc.Metadata, collectionUUID)
+	}
+
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+	results := tx.SendBatch(ctx, b)
+	for _, id := range ids {
+		var upserted string
+		err := results.QueryRow().Scan(&upserted)
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = fmt.Errorf("%w: %s", ErrOtherCollection, id)
+		}
+		if err != nil {
+			results.Close()
+			return nil, err
+		}
+	}
+	if err := results.Close(); err != nil {
+		return nil, err
+	}
+	return ids, tx.Commit(ctx)
+}
+
+// DeleteDocuments deletes the documents with the given ids from the
+// collection.
+func (s Store) DeleteDocuments(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	opts := s.getOptions(options...)
+	sql := fmt.Sprintf(`DELETE FROM %s
+WHERE collection_id = (SELECT uuid FROM %s WHERE name = $1)
+	AND uuid = ANY(CAST($2::text[] AS uuid[]))`, s.embeddingTableName, s.collectionTableName)
+	_, err := s.conn.Exec(ctx, sql, s.getNameSpace(opts), ids)
+	return err
+}
+
+// DeleteByFilter deletes the documents of the collection whose metadata
+// matches filter, which must be a non-empty map[key]value like the filters of
+// SimilaritySearch.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, options ...vectorstores.Option) error {
+	filters, ok := filter.(map[string]any)
+	if !ok || len(filters) == 0 {
+		return ErrInvalidFilters
+	}
+	opts := s.getOptions(options...)
+
+	args := []any{s.getNameSpace(opts)}
+	whereQuerys := make([]string, 0, len(filters))
+	for k, v := range filters {
+		whereQuerys = append(whereQuerys, fmt.Sprintf("(cmetadata ->> $%d) = $%d", len(args)+1, len(args)+2))
+		args = append(args, k, fmt.Sprint(v))
+	}
+	sql := fmt.Sprintf(`DELETE FROM %s
+WHERE collection_id = (SELECT uuid FROM %s WHERE name = $1)
+	AND %s`, s.embeddingTableName, s.collectionTableName, strings.Join(whereQuerys, " AND "))
+	_, err := s.conn.Exec(ctx, sql, args...)
+	return err
+}
+
+// GetDocuments returns the documents of the collection with the given ids.
+func (s Store) GetDocuments(ctx context.Context, ids []string, options ...vectorstores.Option) ([]schema.Document, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	opts := s.getOptions(options...)
+	sql := fmt.Sprintf(`SELECT uuid, document, cmetadata FROM %s
+WHERE collection_id = (SELECT uuid FROM %s WHERE name = $1)
+	AND uuid = ANY(CAST($2::text[] AS uuid[]))`, s.embeddingTableName, s.collectionTableName)
+	rows, err := s.conn.Query(ctx, sql, s.getNameSpace(opts), ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	docs := make([]schema.Document, 0, len(ids))
+	for rows.Next() {
+		doc := schema.Document{}
+		if err := rows.Scan(&doc.ID, &doc.PageContent, &doc.Metadata); err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+	return docs, rows.Err()
+}
+
 //nolint:cyclop
 func (s Store) SimilaritySearch(
 	ctx context.Context,
@@ -438,6 +585,24 @@ func (s Store) getNameSpace(opts vectorstores.Options) string {
 	return s.collectionName
 }
 
+// getCollectionUUID returns the uuid of the collection of the namespace of the
+// options, creating the collection if it does not exist.
+func (s Store) getCollectionUUID(ctx context.Context, opts vectorstores.Options) (string, error) {
+	name := s.getNameSpace(opts)
+	if name == s.collectionName {
+		return s.collectionUUID, nil
+	}
+	sql := fmt.Sprintf(`INSERT INTO %s (uuid, name, cmetadata)
+		VALUES($1, $2, $3) ON CONFLICT (name) DO NOTHING`, s.collectionTableName)
+	if _, err := s.conn.Exec(ctx, sql, uuid.New().String(), name, map[string]any{}); err != nil {
+		return "", err
+	}
+	var collectionUUID string
+	sql = fmt.Sprintf(`SELECT uuid FROM %s WHERE name = $1`, s.collectionTableName)
+	err := s.conn.QueryRow(ctx, sql, name).Scan(&collectionUUID)
+	return collectionUUID, err
+}
+
 func (s Store) getScoreThreshold(opts vectorstores.Options) (float32, error) {
 	if opts.ScoreThreshold < 0 || opts.ScoreThreshold > 1 {
 		return 0, ErrInvalidScoreThreshold
@@ -119,6 +119,92 @@ func TestPgvectorStoreRest(t *testing.T) {
 	require.Equal(t, "japan", docs[0].Metadata["country"])
 }
 
+func TestPgvectorStoreDocuments(t *testing.T) {
+	t.Parallel()
+	pgvectorURL := preCheckEnvSetting(t)
+	ctx := context.Background()
+
+	llm, err := openai.New(
+		openai.WithEmbeddingModel("text-embedding-ada-002"),
+	)
+	require.NoError(t, err)
+	e, err := embeddings.NewEmbedder(llm)
+	require.NoError(t, err)
+
+	conn, err := pgx.Connect(ctx, pgvectorURL)
+	require.NoError(t, err)
+
+	store, err := pgvector.New(
+		ctx,
+		pgvector.WithConn(conn),
+		pgvector.WithEmbedder(e),
+		pgvector.WithPreDeleteCollection(true),
+		pgvector.WithCollectionName(makeNewCollectionName()),
+	)
+	require.NoError(t, err)
+
+	defer cleanupTestArtifacts(ctx, t, store, pgvectorURL)
+
+	require.Equal(t, vectorstores.Capabilities{
+		Delete: true, DeleteByFilter: true, Upsert: true, Get: true,
+	}, vectorstores.CapabilitiesOf(store))
+
+	tokyoID := uuid.New().String()
+	ids, err := store.UpsertDocuments(ctx, []schema.Document{
+		{ID: tokyoID, PageContent: "tokyo", Metadata: map[string]any{"country": "japan"}},
+		{PageContent: "potato", Metadata: map[string]any{"country": "peru"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, ids, 2)
+	require.Equal(t, tokyoID, ids[0])
+
+	_, err = store.UpsertDocuments(ctx, []schema.Document{
+		{ID: tokyoID, PageContent: "kyoto", Metadata: map[string]any{"country": "japan"}},
+	})
+	require.NoError(t, err)
+
+	docs, err := store.GetDocuments(ctx, []string{tokyoID})
+	require.NoError(t, err)
+	require.Equal(t, []schema.Document{
+		{ID: tokyoID, PageContent: "kyoto", Metadata: map[string]any{"country": "japan"}},
+	}, docs)
+
+	_, err = store.UpsertDocuments(ctx, []schema.Document{{ID: "not-a-uuid", PageContent: "x"}})
+	require.ErrorIs(t, err, pgvector.ErrInvalidID)
+
+	// The namespace selects the collection, as for Get and Delete.
+	nameSpace := makeNewCollectionName()
+	osakaID := uuid.New().String()
+	_, err = store.UpsertDocuments(ctx, []schema.Document{{ID: osakaID, PageContent: "osaka"}},
+		vectorstores.WithNameSpace(nameSpace))
+	require.NoError(t, err)
+	docs, err = store.GetDocuments(ctx, []string{osakaID})
+	require.NoError(t, err)
+	require.Empty(t, docs)
+	docs, err = store.GetDocuments(ctx, []string{osakaID}, vectorstores.WithNameSpace(nameSpace))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.NoError(t, store.DeleteDocuments(ctx, []string{osakaID}, vectorstores.WithNameSpace(nameSpace)))
+
+	// The documents can't be moved to another collection.
+	_, err = store.UpsertDocuments(ctx, []schema.Document{{ID: tokyoID, PageContent: "tokyo"}},
+		vectorstores.WithNameSpace(nameSpace))
+	require.ErrorIs(t, err, pgvector.ErrOtherCollection)
+	docs, err = store.GetDocuments(ctx, []string{tokyoID})
+	require.NoError(t, err)
+	require.Equal(t, "kyoto", docs[0].PageContent)
+
+	_, err = store.AddDocuments(ctx, []schema.Document{{PageContent: "nara"}}, vectorstores.WithNameSpace(nameSpace))
+	require.ErrorIs(t, err, pgvector.ErrUnsupportedOptions)
+
+	require.NoError(t, store.DeleteDocuments(ctx, []string{tokyoID}))
+	require.NoError(t, store.DeleteByFilter(ctx, map[string]any{"country": "peru"}))
+
+	docs, err = store.GetDocuments(ctx, ids)
+	require.NoError(t, err)
+	require.Empty(t, docs)
+}
+
 func TestPgvectorStoreRestWithScoreThreshold(t *testing.T) {
 	t.Parallel()
 	pgvectorURL := preCheckEnvSetting(t)
@@ -27,6 +27,8 @@ var (
 	ErrEmptyResponse         = errors.New("empty response")
 	ErrInvalidScoreThreshold = errors.New(
 		"score threshold must be between 0 and 1")
+	// ErrMissingFilter is returned by DeleteByFilter if no filter is given.
+	ErrMissingFilter = errors.New("missing filter")
 )
 
 // Store is a wrapper around the pinecone rest API and grpc client.
@@ -40,6 +42,8 @@ type Store struct {
 	nameSpace string
 }
 
+var _ vectorstores.ExtendedVectorStore = Store{}
+
 // New creates a new Store with options. Options for WithAPIKey, WithHost and WithEmbedder must be set.
 func New(opts ...Option) (Store, error) {
 	s, err := applyClientOptions(opts...)
@@ -60,6 +64,114 @@ func New(opts ...Option) (Store, error) {
 func (s Store) AddDocuments(ctx context.Context,
 	docs []schema.Document,
 	options ...vectorstores.Option,
+) ([]string, error) {
+	ids := make([]string, len(docs))
+	for i := range docs {
+		ids[i] = uuid.New().String()
+	}
+	return s.upsertDocuments(ctx, ids, docs, options...)
+}
+
+// UpsertDocuments creates vector embeddings from the documents using the embedder
+// and upserts the vectors to the pinecone index, replacing the vectors that have the same ID.
+// Documents without an ID are assigned a new one.
+func (s Store) UpsertDocuments(ctx context.Context,
+	docs []schema.Document,
+	options ...vectorstores.Option,
+) ([]string, error) {
+	ids := make([]string, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.ID
+		if ids[i] == "" {
+			ids[i] = uuid.New().String()
+		}
+	}
+	return s.upsertDocuments(ctx, ids, docs, options...)
+}
+
+// DeleteDocuments deletes the vectors with the given ids from the pinecone index.
+func (s Store) DeleteDocuments(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	opts := s.getOptions(options...)
+
+	indexConn, err := s.client.IndexWithNamespace(s.host, s.getNameSpace(opts))
+	if err != nil {
+		return err
+	}
+	defer indexConn.Close()
+
+	return indexConn.DeleteVectorsById(&ctx, ids)
+}
+
+// DeleteByFilter deletes the vectors matching a pinecone metadata filter from the pinecone index.
+// Deleting by filter is not supported by serverless indexes.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, options ...vectorstores.Option) error {
+	if filter == nil {
+		return ErrMissingFilter
+	}
+	opts := s.getOptions(options...)
+
+	protoFilterStruct, err := s.createProtoStructFilter(filter)
+	if err != nil {
+		return err
+	}
+
+	indexConn, err := s.client.IndexWithNamespace(s.host, s.getNameSpace(opts))
+	if err != nil {
+		return err
+	}
+	defer indexConn.Close()
+
+	return indexConn.DeleteVectorsByFilter(&ctx, protoFilterStruct)
+}
+
+// GetDocuments fetches the vectors with the given ids from the pinecone index.
+func (s Store) GetDocuments(ctx context.Context, ids []string, options ...vectorstores.Option) ([]schema.Document, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	opts := s.getOptions(options...)
+
+	indexConn, err := s.client.IndexWithNamespace(s.host, s.getNameSpace(opts))
+	if err != nil {
+		return nil, err
+	}
+	defer indexConn.Close()
+
+	res, err := indexConn.FetchVectors(&ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make([]schema.Document, 0, len(res.Vectors))
+	// keep the order of the given ids.
+	for _, id := range ids {
+		vector, ok := res.Vectors[id]
+		if !ok {
+			continue
+		}
+		metadata := vector.Metadata.AsMap()
+		pageContent, ok := metadata[s.textKey].(string)
+		if !ok {
+			return nil, ErrMissingTextKey
+		}
+		delete(metadata, s.textKey)
+
+		docs = append(docs, schema.Document{
+			ID:          id,
+			PageContent: pageContent,
+			Metadata:    metadata,
+		})
+	}
+	return docs, nil
+}
+
+func (s Store) upsertDocuments(ctx context.Context,
+	ids []string,
+	docs []schema.Document,
+	options ...vectorstores.Option,
 ) ([]string, error) {
 	opts := s.getOptions(options...)
 
@@ -98,19 +210,16 @@ func (s Store) AddDocuments(ctx context.Context,
 
 	pineconeVectors := make([]*pinecone.Vector, 0, len(vectors))
 
-	ids := make([]string, len(vectors))
 	for i := 0; i < len(vectors); i++ {
 		metadataStruct, err := structpb.NewStruct(metadatas[i])
 		if err != nil {
 			return nil, err
 		}
 
-		id := uuid.New().String()
-		ids[i] = id
 		pineconeVectors = append(
 			pineconeVectors,
 			&pinecone.Vector{
-				Id:       id,
+				Id:       ids[i],
 				Values:   vectors[i],
 				Metadata: metadataStruct,
 			},
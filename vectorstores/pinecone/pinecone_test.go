@@ -551,3 +551,52 @@ func TestPineconeAsRetrieverWithMetadataFilters(t *testing.T) {
 
 	require.Contains(t, result, "purple", "expected black in purple")
 }
+
+func TestPineconeStoreDocuments(t *testing.T) {
+	t.Parallel()
+
+	apiKey, host := getValues(t)
+
+	llm, err := openai.New(openai.WithEmbeddingModel("text-embedding-ada-002"))
+	require.NoError(t, err)
+	e, err := embeddings.NewEmbedder(llm)
+	require.NoError(t, err)
+
+	storer, err := pinecone.New(
+		pinecone.WithAPIKey(apiKey),
+		pinecone.WithHost(host),
+		pinecone.WithEmbedder(e),
+		pinecone.WithNameSpace(uuid.New().String()),
+	)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	ids, err := storer.UpsertDocuments(ctx, []schema.Document{
+		{ID: "tokyo", PageContent: "tokyo", Metadata: map[string]any{"country": "japan"}},
+		{PageContent: "potato"},
+	})
+	require.NoError(t, err)
+	require.Len(t, ids, 2)
+	require.Equal(t, "tokyo", ids[0])
+	require.NotEmpty(t, ids[1])
+
+	_, err = storer.UpsertDocuments(ctx, []schema.Document{
+		{ID: "tokyo", PageContent: "kyoto", Metadata: map[string]any{"country": "japan"}},
+	})
+	require.NoError(t, err)
+
+	docs, err := storer.GetDocuments(ctx, []string{"tokyo", ids[1], "missing"})
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	require.Equal(t, "tokyo", docs[0].ID)
+	require.Equal(t, "kyoto", docs[0].PageContent)
+	require.Equal(t, "japan", docs[0].Metadata["country"])
+	require.Equal(t, "potato", docs[1].PageContent)
+
+	require.NoError(t, storer.DeleteDocuments(ctx, []string{ids[1]}))
+
+	docs, err = storer.GetDocuments(ctx, ids)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, "tokyo", docs[0].ID)
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/url"
 
+	"github.com/google/uuid"
 	"github.com/tmc/langchaingo/embeddings"
 	"github.com/tmc/langchaingo/schema"
 	"github.com/tmc/langchaingo/vectorstores"
@@ -18,7 +19,7 @@ type Store struct {
 	contentKey     string
 }
 
-var _ vectorstores.VectorStore = Store{}
+var _ vectorstores.ExtendedVectorStore = Store{}
 
 func New(opts ...Option) (Store, error) {
 	s, err := applyClientOptions(opts...)
@@ -32,6 +33,56 @@ func (s Store) AddDocuments(ctx context.Context,
 	docs []schema.Document,
 	_ ...vectorstores.Option,
 ) ([]string, error) {
+	ids := make([]string, len(docs))
+	for i := range ids {
+		ids[i] = uuid.NewString()
+	}
+	return s.addDocuments(ctx, ids, docs)
+}
+
+// UpsertDocuments adds the documents to the collection, replacing the points
+// that have the same ID. Qdrant IDs must be UUIDs or unsigned integers;
+// documents without an ID are assigned a new UUID.
+func (s Store) UpsertDocuments(ctx context.Context,
+	docs []schema.Document,
+	_ ...vectorstores.Option,
+) ([]string, error) {
+	ids := make([]string, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.ID
+		if ids[i] == "" {
+			ids[i] = uuid.NewString()
+		}
+	}
+	return s.addDocuments(ctx, ids, docs)
+}
+
+// DeleteDocuments deletes the points with the given IDs from the collection.
+func (s Store) DeleteDocuments(ctx context.Context, ids []string, _ ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return s.deletePoints(ctx, &s.qdrantURL, deleteBody{Points: ids})
+}
+
+// DeleteByFilter deletes the points matching a Qdrant filter from the
+// collection.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, _ ...vectorstores.Option) error {
+	if filter == nil {
+		return errors.New("filter must not be nil")
+	}
+	return s.deletePoints(ctx, &s.qdrantURL, deleteBody{Filter: filter})
+}
+
+// GetDocuments returns the documents stored in the points with the given IDs.
+func (s Store) GetDocuments(ctx context.Context, ids []string, _ ...vectorstores.Option) ([]schema.Document, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return s.retrievePoints(ctx, &s.qdrantURL, ids)
+}
+
+func (s Store) addDocuments(ctx context.Context, ids []string, docs []schema.Document) ([]string, error) {
 	texts := make([]string, 0, len(docs))
 	for _, doc := range docs {
 		texts = append(texts, doc.PageContent)
@@ -58,7 +109,7 @@ func (s Store) AddDocuments(ctx context.Context,
 		metadatas = append(metadatas, metadata)
 	}
 
-	return s.upsertPoints(ctx, &s.qdrantURL, vectors, metadatas)
+	return s.upsertPoints(ctx, &s.qdrantURL, ids, vectors, metadatas)
 }
 
 func (s Store) SimilaritySearch(ctx context.Context,
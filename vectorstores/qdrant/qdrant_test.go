@@ -58,6 +58,69 @@ func TestQdrantStore(t *testing.T) {
 	require.Equal(t, "tokyo", docs[0].PageContent)
 }
 
+func TestQdrantStoreDocuments(t *testing.T) {
+	t.Parallel()
+
+	qdrantURL, apiKey, dimension, distance := getValues(t)
+	collectionName := setupCollection(t, qdrantURL, apiKey, dimension, distance)
+	opts := []openai.Option{
+		openai.WithModel("gpt-3.5-turbo-0125"),
+		openai.WithEmbeddingModel("text-embedding-ada-002"),
+	}
+
+	llm, err := openai.New(opts...)
+	require.NoError(t, err)
+	e, err := embeddings.NewEmbedder(llm)
+	require.NoError(t, err)
+
+	url, err := url.Parse(qdrantURL)
+	require.NoError(t, err)
+	store, err := qdrant.New(
+		qdrant.WithURL(*url),
+		qdrant.WithAPIKey(apiKey),
+		qdrant.WithCollectionName(collectionName),
+		qdrant.WithEmbedder(e),
+	)
+	require.NoError(t, err)
+	require.Equal(t, vectorstores.Capabilities{
+		Delete: true, DeleteByFilter: true, Upsert: true, Get: true,
+	}, vectorstores.CapabilitiesOf(store))
+
+	ctx := context.Background()
+	tokyoID := uuid.NewString()
+	ids, err := store.UpsertDocuments(ctx, []schema.Document{
+		{ID: tokyoID, PageContent: "tokyo", Metadata: map[string]any{"country": "japan"}},
+		{PageContent: "potato", Metadata: map[string]any{"country": "peru"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, ids, 2)
+	require.Equal(t, tokyoID, ids[0])
+
+	// Replace the first document.
+	_, err = store.UpsertDocuments(ctx, []schema.Document{
+		{ID: tokyoID, PageContent: "kyoto", Metadata: map[string]any{"country": "japan"}},
+	})
+	require.NoError(t, err)
+
+	docs, err := store.GetDocuments(ctx, ids)
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	byID := map[string]schema.Document{docs[0].ID: docs[0], docs[1].ID: docs[1]}
+	require.Equal(t, "kyoto", byID[tokyoID].PageContent)
+	require.Equal(t, "potato", byID[ids[1]].PageContent)
+
+	err = store.DeleteDocuments(ctx, []string{tokyoID})
+	require.NoError(t, err)
+	err = store.DeleteByFilter(ctx, map[string]any{
+		"must": []map[string]any{{"key": "country", "match": map[string]any{"value": "peru"}}},
+	})
+	require.NoError(t, err)
+
+	docs, err = store.GetDocuments(ctx, ids)
+	require.NoError(t, err)
+	require.Empty(t, docs)
+}
+
 func TestQdrantStoreWithScoreThreshold(t *testing.T) {
 	t.Parallel()
 
@@ -9,7 +9,6 @@ import (
 	"net/http"
 	"net/url"
 
-	"github.com/google/uuid"
 	"github.com/tmc/langchaingo/schema"
 )
 
@@ -17,14 +16,10 @@ import (
 func (s Store) upsertPoints(
 	ctx context.Context,
 	baseURL *url.URL,
+	ids []string,
 	vectors [][]float32,
 	payloads []map[string]interface{},
 ) ([]string, error) {
-	ids := make([]string, len(vectors))
-	for i := range ids {
-		ids[i] = uuid.NewString()
-	}
-
 	payload := upsertBody{
 		Batch: upsertBatch{
 			IDs:      ids,
@@ -121,6 +116,76 @@ func (s Store) searchPoints(
 	return docs, nil
 }
 
+// deletePoints deletes the points selected by IDs or a filter from the Qdrant
+// collection.
+func (s Store) deletePoints(ctx context.Context, baseURL *url.URL, payload deleteBody) error {
+	url := baseURL.JoinPath("collections", s.collectionName, "points", "delete")
+	body,
+		status,
+		err := DoRequest(
+		ctx, *url,
+		s.apiKey,
+		http.MethodPost,
+		payload,
+	)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	if status != http.StatusOK {
+		return newAPIError("deleting points", body)
+	}
+	return nil
+}
+
+// retrievePoints fetches points by ID from the Qdrant collection.
+func (s Store) retrievePoints(ctx context.Context, baseURL *url.URL, ids []string) ([]schema.Document, error) {
+	payload := retrieveBody{
+		IDs:         ids,
+		WithPayload: true,
+	}
+
+	url := baseURL.JoinPath("collections", s.collectionName, "points")
+	body,
+		status,
+		err := DoRequest(
+		ctx, *url,
+		s.apiKey,
+		http.MethodPost,
+		payload,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	if status != http.StatusOK {
+		return nil, newAPIError("retrieving points", body)
+	}
+
+	var response retrieveResponse
+	if err := json.NewDecoder(body).Decode(&response); err != nil {
+		return nil, err
+	}
+	docs := make([]schema.Document, len(response.Result))
+	for i, point := range response.Result {
+		pageContent, ok := point.Payload[s.contentKey].(string)
+		if !ok {
+			return nil, fmt.Errorf("payload does not contain content key '%s'", s.contentKey)
+		}
+		delete(point.Payload, s.contentKey)
+
+		docs[i] = schema.Document{
+			ID:          string(point.ID),
+			PageContent: pageContent,
+			Metadata:    point.Payload,
+		}
+	}
+
+	return docs, nil
+}
+
 // doRequest performs an HTTP request to the Qdrant API.
 func DoRequest(ctx context.Context,
 	url url.URL,
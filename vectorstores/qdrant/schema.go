@@ -5,6 +5,11 @@
 
 package qdrant
 
+import (
+	"bytes"
+	"encoding/json"
+)
+
 type upsertBatch struct {
 	IDs      []string                 `json:"ids"`
 	Payloads []map[string]interface{} `json:"payloads"`
@@ -32,3 +37,39 @@ type searchBody struct {
 	WithVector     bool      `json:"with_vector"`
 	WithPayload    bool      `json:"with_payload"`
 }
+
+type deleteBody struct {
+	Points []string `json:"points,omitempty"`
+	Filter any      `json:"filter,omitempty"`
+}
+
+type retrieveBody struct {
+	IDs         []string `json:"ids"`
+	WithPayload bool     `json:"with_payload"`
+}
+
+// pointID is a point ID, which Qdrant returns as a string for UUIDs and as a
+// number for integer IDs.
+type pointID string
+
+func (id *pointID) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*id = pointID(s)
+		return nil
+	}
+	*id = pointID(bytes.TrimSpace(data))
+	return nil
+}
+
+type point struct {
+	ID      pointID                `json:"id"`
+	Payload map[string]interface{} `json:"payload"`
+}
+
+type retrieveResponse struct {
+	Result []point `json:"result"`
+}
@@ -22,6 +22,10 @@ type RedisClient interface {
 	CreateIndexIfNotExists(ctx context.Context, index string, schema *IndexSchema) error
 	AddDocWithHash(ctx context.Context, prefix string, doc schema.Document) (string, error)
 	AddDocsWithHash(ctx context.Context, prefix string, docs []schema.Document) ([]string, error)
+	ReplaceDocsWithHash(ctx context.Context, docIDs []string, docs []schema.Document) error
+	GetDocsWithHash(ctx context.Context, docIDs []string) ([]schema.Document, error)
+	DeleteDocs(ctx context.Context, docIDs []string) error
+	DeleteDocsByQuery(ctx context.Context, index string, query string) error
 	// TODO AddDocsWithJSON
 	Search(ctx context.Context, search IndexVectorSearch) (int64, []schema.Document, error)
 }
@@ -99,6 +103,91 @@ func (c RueidisClient) AddDocsWithHash(ctx context.Context, prefix string, docs
 	return docIDs, errors.Join(errs...)
 }
 
+// ReplaceDocsWithHash deletes the existing hashes with the given keys, so that no stale fields are left,
+// and saves the documents under them.
+func (c RueidisClient) ReplaceDocsWithHash(ctx context.Context, docIDs []string, docs []schema.Document) error {
+	cmds := make([]rueidis.Completed, 0, len(docs)*2)
+	for i, doc := range docs {
+		cmds = append(cmds,
+			c.client.B().Del().Key(docIDs[i]).Build(),
+			c.client.B().Arbitrary("Hmset").Keys(docIDs[i]).Args(c.generateHSetArgs(doc)...).Build(),
+		)
+	}
+	errs := make([]error, 0)
+	for _, res := range c.client.DoMulti(ctx, cmds...) {
+		if res.Error() != nil {
+			errs = append(errs, res.Error())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// GetDocsWithHash returns the documents saved under the given keys, skipping the keys that don't exist.
+func (c RueidisClient) GetDocsWithHash(ctx context.Context, docIDs []string) ([]schema.Document, error) {
+	cmds := make([]rueidis.Completed, 0, len(docIDs))
+	for _, docID := range docIDs {
+		cmds = append(cmds, c.client.B().Hgetall().Key(docID).Build())
+	}
+	docs := make([]schema.Document, 0, len(docIDs))
+	for i, res := range c.client.DoMulti(ctx, cmds...) {
+		fields, err := res.AsStrMap()
+		if err != nil {
+			return nil, err
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		doc := convertHashIntoDocSchema(fields)
+		doc.ID = docIDs[i]
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+func (c RueidisClient) DeleteDocs(ctx context.Context, docIDs []string) error {
+	cmds := make([]rueidis.Completed, 0, len(docIDs))
+	for _, docID := range docIDs {
+		cmds = append(cmds, c.client.B().Del().Key(docID).Build())
+	}
+	errs := make([]error, 0)
+	for _, res := range c.client.DoMulti(ctx, cmds...) {
+		if res.Error() != nil {
+			errs = append(errs, res.Error())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// deleteByQueryBatchSize is the number of keys deleted at once by DeleteDocsByQuery.
+const deleteByQueryBatchSize = 1000
+
+// DeleteDocsByQuery deletes the documents of the index matching a redis search query, in batches.
+func (c RueidisClient) DeleteDocsByQuery(ctx context.Context, index string, query string) error {
+	for {
+		cmd := c.client.B().Arbitrary("FT.SEARCH").Keys(index).
+			Args(query, "NOCONTENT", "LIMIT", "0", strconv.Itoa(deleteByQueryBatchSize)).Build()
+		res, err := c.client.Do(ctx, cmd).ToArray()
+		if err != nil {
+			return err
+		}
+		// the reply is the total number of matches followed by the keys.
+		if len(res) <= 1 {
+			return nil
+		}
+		docIDs := make([]string, 0, len(res)-1)
+		for _, msg := range res[1:] {
+			docID, err := msg.ToString()
+			if err != nil {
+				return err
+			}
+			docIDs = append(docIDs, docID)
+		}
+		if err := c.DeleteDocs(ctx, docIDs); err != nil {
+			return err
+		}
+	}
+}
+
 func (c RueidisClient) Search(ctx context.Context, search IndexVectorSearch) (int64, []schema.Document, error) {
 	cmds := search.AsCommand()
 	// fmt.Println(strings.Join(cmds, " "))
@@ -111,6 +200,11 @@ func (c RueidisClient) Search(ctx context.Context, search IndexVectorSearch) (in
 }
 
 func (c RueidisClient) generateHSetCMD(prefix string, doc schema.Document) (string, rueidis.Completed) {
+	docID := getDocIDWithMetaData(prefix, doc.Metadata)
+	return docID, c.client.B().Arbitrary("Hmset").Keys(docID).Args(c.generateHSetArgs(doc)...).Build()
+}
+
+func (c RueidisClient) generateHSetArgs(doc schema.Document) []string {
 	kvs := make([]string, 0, len(maps.Keys(doc.Metadata))*2)
 	for k, v := range doc.Metadata {
 		kvs = append(kvs, k)
@@ -127,8 +221,7 @@ func (c RueidisClient) generateHSetCMD(prefix string, doc schema.Document) (stri
 			kvs = append(kvs, fmt.Sprintf("%v", v))
 		}
 	}
-	docID := getDocIDWithMetaData(prefix, doc.Metadata)
-	return docID, c.client.B().Arbitrary("Hmset").Keys(docID).Args(kvs...).Build()
+	return kvs
 }
 
 // getPrefix get prefix with index name.
@@ -154,24 +247,29 @@ func getDocIDWithMetaData(prefix string, meta map[string]any) string {
 func convertFTSearchResIntoDocSchema(docs []rueidis.FtSearchDoc) []schema.Document {
 	res := make([]schema.Document, 0, len(docs))
 	for _, doc := range docs {
-		_doc := schema.Document{}
-		metadata := make(map[string]any, len(doc.Doc))
-		//nolint: gocritic
-		for k, v := range doc.Doc {
-			if k == defaultContentFieldKey {
-				_doc.PageContent = v
-			} else if k == defaultDistanceFieldKey {
-				score, _ := strconv.ParseFloat(v, 32)
-				_doc.Score = float32(score)
-			} else if k != defaultContentVectorFieldKey {
-				metadata[k] = v
-			}
-		}
-		if _, ok := metadata["id"]; !ok {
-			metadata["id"] = doc.Key
+		_doc := convertHashIntoDocSchema(doc.Doc)
+		if _, ok := _doc.Metadata["id"]; !ok {
+			_doc.Metadata["id"] = doc.Key
 		}
-		_doc.Metadata = metadata
 		res = append(res, _doc)
 	}
 	return res
 }
+
+func convertHashIntoDocSchema(fields map[string]string) schema.Document {
+	doc := schema.Document{}
+	metadata := make(map[string]any, len(fields))
+	//nolint: gocritic
+	for k, v := range fields {
+		if k == defaultContentFieldKey {
+			doc.PageContent = v
+		} else if k == defaultDistanceFieldKey {
+			score, _ := strconv.ParseFloat(v, 32)
+			doc.Score = float32(score)
+		} else if k != defaultContentVectorFieldKey {
+			metadata[k] = v
+		}
+	}
+	doc.Metadata = metadata
+	return doc
+}
@@ -3,6 +3,7 @@ package redisvector
 import (
 	"context"
 	"errors"
+	"strings"
 
 	"github.com/tmc/langchaingo/embeddings"
 	"github.com/tmc/langchaingo/schema"
@@ -36,7 +37,7 @@ type Store struct {
 	schemaGenerator        *schemaGenerator
 }
 
-var _ vectorstores.VectorStore = &Store{}
+var _ vectorstores.ExtendedVectorStore = &Store{}
 
 // New creates a new Store with options.
 func New(ctx context.Context, opts ...Option) (*Store, error) {
@@ -77,14 +78,84 @@ func New(ctx context.Context, opts ...Option) (*Store, error) {
 //	if doc.metadata has `keys` or `ids` field, the docId will use `keys` or `ids` value
 //	if not, the docId is uuid string
 func (s *Store) AddDocuments(ctx context.Context, docs []schema.Document, _ ...vectorstores.Option) ([]string, error) {
-	err := s.appendDocumentsWithVectors(ctx, docs)
+	if err := s.prepareDocuments(ctx, docs); err != nil {
+		return nil, err
+	}
+
+	docIDs, err := s.client.AddDocsWithHash(ctx, getPrefix(s.indexName), docs)
 	if err != nil {
 		return nil, err
 	}
 
+	return docIDs, nil
+}
+
+// UpsertDocuments adds the documents to redis, replacing the documents that have the same ID.
+// Document IDs are the keys returned by AddDocuments; an ID without the `doc:{index_name}` prefix
+// is prefixed with it. Documents without an ID get an ID as in AddDocuments.
+func (s *Store) UpsertDocuments(ctx context.Context, docs []schema.Document, _ ...vectorstores.Option) ([]string, error) {
+	if err := s.prepareDocuments(ctx, docs); err != nil {
+		return nil, err
+	}
+
+	prefix := getPrefix(s.indexName)
+	docIDs := make([]string, len(docs))
+	for i, doc := range docs {
+		switch {
+		case doc.ID == "":
+			docIDs[i] = getDocIDWithMetaData(prefix, doc.Metadata)
+		case strings.HasPrefix(doc.ID, prefix+":"):
+			docIDs[i] = doc.ID
+		default:
+			docIDs[i] = prefix + ":" + doc.ID
+		}
+	}
+
+	if err := s.client.ReplaceDocsWithHash(ctx, docIDs, docs); err != nil {
+		return nil, err
+	}
+	return docIDs, nil
+}
+
+// DeleteDocuments deletes the documents with the given IDs (the keys returned by AddDocuments).
+func (s *Store) DeleteDocuments(ctx context.Context, ids []string, _ ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return s.client.DeleteDocs(ctx, ids)
+}
+
+// DeleteByFilter deletes the documents of the index matching filter, which should be a redis
+// search query like the filters of SimilaritySearch (eg: @title:Dune).
+func (s *Store) DeleteByFilter(ctx context.Context, filter any, _ ...vectorstores.Option) error {
+	query, ok := filter.(string)
+	if !ok || query == "" {
+		return ErrInvalidFilters
+	}
+	return s.client.DeleteDocsByQuery(ctx, s.indexName, query)
+}
+
+// GetDocuments returns the documents with the given IDs (the keys returned by AddDocuments).
+func (s *Store) GetDocuments(ctx context.Context, ids []string, _ ...vectorstores.Option) ([]schema.Document, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return s.client.GetDocsWithHash(ctx, ids)
+}
+
+// prepareDocuments embeds the documents and creates the index if needed.
+func (s *Store) prepareDocuments(ctx context.Context, docs []schema.Document) error {
+	err := s.appendDocumentsWithVectors(ctx, docs)
+	if err != nil {
+		return err
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+
 	indexSchema, err := generateSchemaWithMetadata(docs[0].Metadata)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	if s.indexSchema == nil {
@@ -93,16 +164,10 @@ func (s *Store) AddDocuments(ctx context.Context, docs []schema.Document, _ ...v
 
 	if s.createIndexIfNotExists && !s.client.CheckIndexExists(ctx, s.indexName) {
 		if err := s.client.CreateIndexIfNotExists(ctx, s.indexName, indexSchema); err != nil {
-			return nil, err
+			return err
 		}
 	}
-
-	docIDs, err := s.client.AddDocsWithHash(ctx, getPrefix(s.indexName), docs)
-	if err != nil {
-		return nil, err
-	}
-
-	return docIDs, nil
+	return nil
 }
 
 // SimilaritySearch similarity search docs with `ScoreThreshold` `Filters` `Embedder`
@@ -252,6 +252,55 @@ func TestAddDocuments(t *testing.T) {
 	})
 }
 
+func TestDocuments(t *testing.T) {
+	t.Parallel()
+
+	redisURL, ollamaURL := getValues(t)
+	_, e := getEmbedding(ollamaModel, ollamaURL)
+	ctx := context.Background()
+
+	index := "test_documents"
+	vector, err := redisvector.New(ctx,
+		redisvector.WithConnectionURL(redisURL),
+		redisvector.WithIndexName(index, true),
+		redisvector.WithEmbedder(e),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, vector.DropIndex(ctx, index, true))
+	})
+
+	assert.Equal(t, vectorstores.Capabilities{
+		Delete: true, DeleteByFilter: true, Upsert: true, Get: true,
+	}, vectorstores.CapabilitiesOf(vector))
+
+	docIDs, err := vector.UpsertDocuments(ctx, []schema.Document{
+		{ID: "tokyo", PageContent: "Tokyo", Metadata: map[string]any{"country": "japan", "area": 622}},
+		{ID: "paris", PageContent: "Paris", Metadata: map[string]any{"country": "france", "area": 105}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"doc:" + index + ":tokyo", "doc:" + index + ":paris"}, docIDs)
+
+	_, err = vector.UpsertDocuments(ctx, []schema.Document{
+		{ID: docIDs[0], PageContent: "Kyoto", Metadata: map[string]any{"country": "japan", "area": 828}},
+	})
+	require.NoError(t, err)
+
+	docs, err := vector.GetDocuments(ctx, []string{docIDs[0], "doc:" + index + ":missing"})
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, docIDs[0], docs[0].ID)
+	assert.Equal(t, "Kyoto", docs[0].PageContent)
+	assert.Equal(t, "828", docs[0].Metadata["area"])
+
+	require.NoError(t, vector.DeleteDocuments(ctx, docIDs[:1]))
+	require.NoError(t, vector.DeleteByFilter(ctx, "@country:{france}"))
+
+	docs, err = vector.GetDocuments(ctx, docIDs)
+	require.NoError(t, err)
+	assert.Empty(t, docs)
+}
+
 func TestSimilaritySearch(t *testing.T) {
 	t.Parallel()
 
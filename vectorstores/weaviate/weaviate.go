@@ -34,6 +34,7 @@ var (
 	ErrInvalidScoreThreshold = errors.New(
 		"score threshold must be between 0 and 1")
 	ErrInvalidFilter = errors.New("invalid filter")
+	ErrInvalidID     = errors.New("invalid object id")
 )
 
 // Store is a wrapper around the weaviate client.
@@ -61,7 +62,7 @@ type Store struct {
 	additionalFields []string
 }
 
-var _ vectorstores.VectorStore = Store{}
+var _ vectorstores.ExtendedVectorStore = Store{}
 
 // New creates a new Store with options.
 // When using weaviate,
@@ -94,10 +95,96 @@ func (s Store) AddDocuments(ctx context.Context,
 	options ...vectorstores.Option,
 ) ([]string, error) {
 	opts := s.getOptions(options...)
-	nameSpace := s.getNameSpace(opts)
+	docs = s.deduplicate(ctx, opts, docs)
 
+	ids := make([]string, len(docs))
+	for i := range docs {
+		ids[i] = uuid.New().String()
+	}
+	return s.addDocuments(ctx, opts, ids, docs)
+}
+
+// UpsertDocuments creates vector embeddings from the documents using the embedder
+// and upserts the vectors to the weaviate index, replacing the objects that have the same ID.
+// IDs must be UUIDs; documents without an ID are assigned a new one.
+func (s Store) UpsertDocuments(ctx context.Context,
+	docs []schema.Document,
+	options ...vectorstores.Option,
+) ([]string, error) {
+	opts := s.getOptions(options...)
 	docs = s.deduplicate(ctx, opts, docs)
 
+	ids := make([]string, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.ID
+		if ids[i] == "" {
+			ids[i] = uuid.New().String()
+		} else if _, err := uuid.Parse(ids[i]); err != nil {
+			return nil, fmt.Errorf("%w: %q is not a UUID", ErrInvalidID, ids[i])
+		}
+	}
+	return s.addDocuments(ctx, opts, ids, docs)
+}
+
+// DeleteDocuments deletes the objects with the given ids from the weaviate index.
+func (s Store) DeleteDocuments(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	opts := s.getOptions(options...)
+	return s.deleteObjects(ctx, s.getNameSpace(opts), s.createIDsWhereBuilder(ids))
+}
+
+// DeleteByFilter deletes the objects matching filter, a *filters.WhereBuilder, from the weaviate index.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, options ...vectorstores.Option) error {
+	if filter == nil {
+		return ErrInvalidFilter
+	}
+	opts := s.getOptions(options...)
+	return s.deleteObjects(ctx, s.getNameSpace(opts), filter)
+}
+
+// GetDocuments returns the documents stored in the objects with the given ids.
+func (s Store) GetDocuments(ctx context.Context, ids []string, options ...vectorstores.Option) ([]schema.Document, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	opts := s.getOptions(options...)
+	whereBuilder, err := s.createWhereBuilder(s.getNameSpace(opts), s.createIDsWhereBuilder(ids))
+	if err != nil {
+		return nil, err
+	}
+
+	fields := s.createFields()
+	additional := &fields[len(fields)-1]
+	hasID := false
+	for _, field := range additional.Fields {
+		hasID = hasID || field.Name == "id"
+	}
+	if !hasID {
+		additional.Fields = append(additional.Fields, graphql.Field{Name: "id"})
+	}
+
+	res, err := s.client.GraphQL().
+		Get().
+		WithWhere(whereBuilder).
+		WithClassName(s.indexName).
+		WithLimit(len(ids)).
+		WithFields(fields...).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.parseDocumentsByGraphQLResponse(res)
+}
+
+func (s Store) addDocuments(ctx context.Context,
+	opts vectorstores.Options,
+	ids []string,
+	docs []schema.Document,
+) ([]string, error) {
+	nameSpace := s.getNameSpace(opts)
+
 	if len(docs) == 0 {
 		// nothing to add (perhaps all documents were duplicates). This is not
 		// an error.
@@ -131,13 +218,10 @@ func (s Store) AddDocuments(ctx context.Context,
 	}
 
 	objects := make([]*models.Object, 0, len(docs))
-	ids := make([]string, len(docs))
 	for i := range docs {
-		id := strfmt.UUID(uuid.New().String())
-		ids[i] = id.String()
 		objects = append(objects, &models.Object{
 			Class:      s.indexName,
-			ID:         id,
+			ID:         strfmt.UUID(ids[i]),
 			Vector:     vectors[i],
 			Properties: metadatas[i],
 		})
@@ -148,6 +232,42 @@ func (s Store) AddDocuments(ctx context.Context,
 	return ids, nil
 }
 
+// deleteObjects deletes the objects of the name space matching filter. Weaviate limits the
+// number of objects deleted at once, so the deletion is repeated until nothing is left.
+func (s Store) deleteObjects(ctx context.Context, nameSpace string, filter any) error {
+	whereBuilder, err := s.createWhereBuilder(nameSpace, filter)
+	if err != nil {
+		return err
+	}
+	for {
+		res, err := s.client.Batch().ObjectsBatchDeleter().
+			WithClassName(s.indexName).
+			WithWhere(whereBuilder).
+			Do(ctx)
+		if err != nil {
+			return err
+		}
+		if res == nil || res.Results == nil {
+			return nil
+		}
+		if res.Results.Failed > 0 {
+			return fmt.Errorf("%w: failed to delete %d objects", ErrInvalidResponse, res.Results.Failed)
+		}
+		if res.Results.Successful == 0 || res.Results.Matches <= res.Results.Successful {
+			return nil
+		}
+	}
+}
+
+func (s Store) createIDsWhereBuilder(ids []string) *filters.WhereBuilder {
+	operands := make([]*filters.WhereBuilder, 0, len(ids))
+	for _, id := range ids {
+		operands = append(operands,
+			filters.Where().WithPath([]string{"id"}).WithOperator(filters.Equal).WithValueString(id))
+	}
+	return filters.Where().WithOperator(filters.Or).WithOperands(operands)
+}
+
 func (s Store) SimilaritySearch(
 	ctx context.Context,
 	query string,
@@ -247,14 +367,17 @@ func (s Store) parseDocumentsByGraphQLResponse(res *models.GraphQLResponse) ([]s
 			return nil, ErrMissingTextKey
 		}
 		var score float64
+		var id string
 		if additional, ok := itemMap["_additional"].(map[string]any); ok {
 			score, _ = additional["certainty"].(float64)
+			id, _ = additional["id"].(string)
 		}
 		delete(itemMap, s.textKey)
 		doc := schema.Document{
 			PageContent: pageContent,
 			Metadata:    itemMap,
 			Score:       float32(score),
+			ID:          id,
 		}
 		docs = append(docs, doc)
 	}
@@ -110,6 +110,63 @@ func TestWeaviateStoreRest(t *testing.T) {
 	require.Equal(t, "japan", docs[0].Metadata["country"])
 }
 
+func TestWeaviateStoreDocuments(t *testing.T) {
+	t.Parallel()
+
+	scheme, host := getValues(t)
+
+	llm, err := openai.New()
+	require.NoError(t, err)
+	e, err := embeddings.NewEmbedder(llm)
+	require.NoError(t, err)
+
+	store, err := New(
+		WithScheme(scheme),
+		WithHost(host),
+		WithEmbedder(e),
+		WithNameSpace(uuid.New().String()),
+		WithIndexName(randomizedCamelCaseClass()),
+		WithQueryAttrs([]string{"country"}),
+	)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	err = createTestClass(ctx, store)
+	require.NoError(t, err)
+
+	require.Equal(t, vectorstores.Capabilities{
+		Delete: true, DeleteByFilter: true, Upsert: true, Get: true,
+	}, vectorstores.CapabilitiesOf(store))
+
+	tokyoID := uuid.New().String()
+	ids, err := store.UpsertDocuments(ctx, []schema.Document{
+		{ID: tokyoID, PageContent: "tokyo", Metadata: map[string]any{"country": "japan"}},
+		{PageContent: "potato", Metadata: map[string]any{"country": "peru"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, ids, 2)
+	require.Equal(t, tokyoID, ids[0])
+
+	_, err = store.UpsertDocuments(ctx, []schema.Document{
+		{ID: tokyoID, PageContent: "kyoto", Metadata: map[string]any{"country": "japan"}},
+	})
+	require.NoError(t, err)
+
+	docs, err := store.GetDocuments(ctx, []string{tokyoID})
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, tokyoID, docs[0].ID)
+	require.Equal(t, "kyoto", docs[0].PageContent)
+
+	require.NoError(t, store.DeleteDocuments(ctx, []string{tokyoID}))
+	require.NoError(t, store.DeleteByFilter(ctx,
+		filters.Where().WithPath([]string{"country"}).WithOperator(filters.Equal).WithValueString("peru")))
+
+	docs, err = store.GetDocuments(ctx, ids)
+	require.NoError(t, err)
+	require.Empty(t, docs)
+}
+
 func TestWeaviateStoreRestWithScoreThreshold(t *testing.T) {
 	t.Parallel()
 